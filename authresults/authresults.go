@@ -6,6 +6,8 @@ type AuthResults struct {
 	DKIM       []DKIMResult      `json:"dkim,omitempty"`
 	DMARC      *DMARCResult      `json:"dmarc,omitempty"`
 	ReverseDNS *ReverseDNSResult `json:"reverseDns,omitempty"`
+	ARC        []ARCResult       `json:"arc,omitempty"`
+	BIMI       *BIMIResult       `json:"bimi,omitempty"`
 }
 
 // SPFResult represents an SPF check result.
@@ -34,6 +36,12 @@ type DMARCResult struct {
 	Info    string `json:"info,omitempty"`
 }
 
+// IsAligned reports whether the DMARC identifiers are aligned. It is false
+// for a nil result.
+func (d *DMARCResult) IsAligned() bool {
+	return d != nil && d.Aligned
+}
+
 // ReverseDNSResult represents a reverse DNS check result.
 type ReverseDNSResult struct {
 	Result   string `json:"result"` // pass, fail, none, skipped
@@ -41,6 +49,82 @@ type ReverseDNSResult struct {
 	Hostname string `json:"hostname,omitempty"`
 }
 
+// BIMIResult represents a BIMI (Brand Indicators for Message Identification)
+// evaluation. BIMI is informational: it decides whether a brand logo may be
+// shown, not whether the email is authentic.
+type BIMIResult struct {
+	Result    string `json:"result"` // pass, fail, none, declined, skipped
+	Domain    string `json:"domain,omitempty"`
+	Selector  string `json:"selector,omitempty"`
+	Indicator string `json:"indicator,omitempty"` // logo URL from the BIMI record
+	Authority string `json:"authority,omitempty"` // VMC (Verified Mark Certificate) status: pass, fail, none
+}
+
+// ARCResult represents one ARC (Authenticated Received Chain) set, added by
+// an intermediary such as a mailing list or forwarder that handled the email.
+type ARCResult struct {
+	Instance int          `json:"instance"`         // i= tag, starting at 1 for the first hop
+	CV       string       `json:"cv"`               // chain validation: none, pass, fail
+	Domain   string       `json:"domain,omitempty"` // d= of the ARC-Seal
+	Selector string       `json:"selector,omitempty"`
+	SPF      *SPFResult   `json:"spf,omitempty"`  // SPF as seen by the intermediary
+	DKIM     []DKIMResult `json:"dkim,omitempty"` // DKIM as seen by the intermediary
+	DMARC    *DMARCResult `json:"dmarc,omitempty"`
+}
+
+// ValidateOption configures [AuthResults.Validate] and [AuthResults.IsPassing].
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	requireARC bool
+}
+
+// WithARC makes the ARC chain a primary check: Validate reports an error
+// unless the chain is present and valid (see [ValidateARC]). Without it, ARC
+// results are ignored, since most directly delivered email carries none.
+func WithARC() ValidateOption {
+	return func(c *validateConfig) {
+		c.requireARC = true
+	}
+}
+
+// Severity classifies how serious an authentication issue is.
+type Severity string
+
+const (
+	// SeverityError marks a failed primary check (SPF, DKIM, DMARC).
+	// Any error-severity issue makes AuthValidation.Passed false.
+	SeverityError Severity = "error"
+	// SeverityWarning marks an advisory failure (reverse DNS, BIMI) that
+	// does not affect AuthValidation.Passed.
+	SeverityWarning Severity = "warning"
+)
+
+// Check names used in AuthIssue.Check.
+const (
+	CheckSPF        = "SPF"
+	CheckDKIM       = "DKIM"
+	CheckDMARC      = "DMARC"
+	CheckReverseDNS = "ReverseDNS"
+	CheckARC        = "ARC"
+	CheckBIMI       = "BIMI"
+)
+
+// AuthIssue describes a single failed or missing authentication check.
+type AuthIssue struct {
+	// Check is the name of the check (CheckSPF, CheckDKIM, CheckDMARC,
+	// CheckReverseDNS, CheckARC, CheckBIMI), or empty when no results are
+	// available at all.
+	Check string `json:"check"`
+	// Severity indicates whether the issue fails validation.
+	Severity Severity `json:"severity"`
+	// Result is the raw check result (e.g. "softfail"), or empty if the
+	// check result is missing.
+	Result string `json:"result,omitempty"`
+	// Message is a human-readable description of the issue.
+	Message string `json:"message"`
+}
+
 // AuthValidation provides a summary of email authentication validation.
 type AuthValidation struct {
 	// Passed indicates whether all primary checks (SPF, DKIM, DMARC) passed.
@@ -53,22 +137,67 @@ type AuthValidation struct {
 	DMARCPassed bool `json:"dmarcPassed"`
 	// ReverseDNSPassed indicates whether the reverse DNS check passed.
 	ReverseDNSPassed bool `json:"reverseDnsPassed"`
+	// ARCPassed indicates whether the ARC chain is present and valid. It is
+	// always reported, but only affects Passed with [WithARC].
+	ARCPassed bool `json:"arcPassed"`
+	// BIMIPassed indicates whether the BIMI check passed. It is false for
+	// the neutral results none and declined, which are not reported as
+	// failures. It never affects Passed.
+	BIMIPassed bool `json:"bimiPassed"`
 	// Failures contains descriptive messages for any failed checks.
 	Failures []string `json:"failures"`
+	// Issues lists every failed or missing check with its severity. Unlike
+	// Failures, it also includes missing SPF, DKIM and DMARC results, which
+	// fail validation; a missing ARC chain required with [WithARC] is in
+	// both. Passed is true exactly when no issue has SeverityError.
+	Issues []AuthIssue `json:"issues"`
+}
+
+// HasErrors reports whether any issue has SeverityError.
+func (v AuthValidation) HasErrors() bool {
+	for _, issue := range v.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuesWithSeverity returns the issues with the given severity.
+func (v AuthValidation) IssuesWithSeverity(severity Severity) []AuthIssue {
+	var result []AuthIssue
+	for _, issue := range v.Issues {
+		if issue.Severity == severity {
+			result = append(result, issue)
+		}
+	}
+	return result
 }
 
 // Validate validates the authentication results and provides a summary.
 // It returns an AuthValidation struct with details about each check.
 // Checks with status "skipped" are treated as passed (not a failure).
-func (a *AuthResults) Validate() AuthValidation {
+// Pass [WithARC] to also require a valid ARC chain.
+func (a *AuthResults) Validate(opts ...ValidateOption) AuthValidation {
+	var cfg validateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	if a == nil {
+		msg := "no authentication results available"
 		return AuthValidation{
 			Passed:   false,
-			Failures: []string{"no authentication results available"},
+			Failures: []string{msg},
+			Issues:   []AuthIssue{{Severity: SeverityError, Message: msg}},
 		}
 	}
 
 	var failures []string
+	issues := []AuthIssue{}
+	addIssue := func(check string, severity Severity, result, msg string) {
+		issues = append(issues, AuthIssue{Check: check, Severity: severity, Result: result, Message: msg})
+	}
 
 	// Check SPF (pass or skipped = passed)
 	spfPassed := a.SPF != nil && (a.SPF.Result == "pass" || a.SPF.Result == "skipped")
@@ -78,6 +207,10 @@ func (a *AuthResults) Validate() AuthValidation {
 			msg += " (domain: " + a.SPF.Domain + ")"
 		}
 		failures = append(failures, msg)
+		addIssue(CheckSPF, SeverityError, a.SPF.Result, msg)
+	}
+	if a.SPF == nil {
+		addIssue(CheckSPF, SeverityError, "", "SPF result missing")
 	}
 
 	// Check DKIM (at least one signature must pass, or all skipped)
@@ -99,9 +232,15 @@ func (a *AuthResults) Validate() AuthValidation {
 		}
 		if !dkimPassed {
 			var failedDomains []string
+			failedResult := ""
 			for _, dkim := range a.DKIM {
-				if dkim.Result != "pass" && dkim.Result != "skipped" && dkim.Domain != "" {
-					failedDomains = append(failedDomains, dkim.Domain)
+				if dkim.Result != "pass" && dkim.Result != "skipped" {
+					if failedResult == "" {
+						failedResult = dkim.Result
+					}
+					if dkim.Domain != "" {
+						failedDomains = append(failedDomains, dkim.Domain)
+					}
 				}
 			}
 			msg := "DKIM signature failed"
@@ -109,7 +248,10 @@ func (a *AuthResults) Validate() AuthValidation {
 				msg += ": " + joinStrings(failedDomains, ", ")
 			}
 			failures = append(failures, msg)
+			addIssue(CheckDKIM, SeverityError, failedResult, msg)
 		}
+	} else {
+		addIssue(CheckDKIM, SeverityError, "", "DKIM result missing")
 	}
 
 	// Check DMARC (pass or skipped = passed)
@@ -120,6 +262,10 @@ func (a *AuthResults) Validate() AuthValidation {
 			msg += " (policy: " + a.DMARC.Policy + ")"
 		}
 		failures = append(failures, msg)
+		addIssue(CheckDMARC, SeverityError, a.DMARC.Result, msg)
+	}
+	if a.DMARC == nil {
+		addIssue(CheckDMARC, SeverityError, "", "DMARC result missing")
 	}
 
 	// Check Reverse DNS (pass or skipped = passed)
@@ -130,6 +276,39 @@ func (a *AuthResults) Validate() AuthValidation {
 			msg += " (hostname: " + a.ReverseDNS.Hostname + ")"
 		}
 		failures = append(failures, msg)
+		addIssue(CheckReverseDNS, SeverityWarning, a.ReverseDNS.Result, msg)
+	}
+
+	// Check BIMI (pass or skipped = passed, none or declined = neutral);
+	// informational only
+	bimiPassed := a.BIMI != nil && (a.BIMI.Result == "pass" || a.BIMI.Result == "skipped")
+	if a.BIMI != nil && !bimiPassed && !bimiNeutral(a.BIMI.Result) {
+		msg := "BIMI check failed: " + a.BIMI.Result
+		if a.BIMI.Domain != "" {
+			msg += " (domain: " + a.BIMI.Domain + ")"
+		}
+		failures = append(failures, msg)
+		addIssue(CheckBIMI, SeverityWarning, a.BIMI.Result, msg)
+	}
+
+	// Check ARC (only a failure when required)
+	arcPassed := ValidateARC(a) == nil
+	if cfg.requireARC && !arcPassed {
+		if len(a.ARC) == 0 {
+			// Unlike other missing results, a required ARC chain was asked
+			// for explicitly, so its absence is a failure.
+			msg := "ARC result missing"
+			failures = append(failures, msg)
+			addIssue(CheckARC, SeverityError, "", msg)
+		} else {
+			latest := latestARC(a.ARC)
+			msg := "ARC chain validation failed: " + latest.CV
+			if latest.Domain != "" {
+				msg += " (domain: " + latest.Domain + ")"
+			}
+			failures = append(failures, msg)
+			addIssue(CheckARC, SeverityError, latest.CV, msg)
+		}
 	}
 
 	// Ensure failures is never nil
@@ -137,14 +316,18 @@ func (a *AuthResults) Validate() AuthValidation {
 		failures = []string{}
 	}
 
-	return AuthValidation{
-		Passed:           spfPassed && dkimPassed && dmarcPassed,
+	v := AuthValidation{
 		SPFPassed:        spfPassed,
 		DKIMPassed:       dkimPassed,
 		DMARCPassed:      dmarcPassed,
 		ReverseDNSPassed: reverseDNSPassed,
+		ARCPassed:        arcPassed,
+		BIMIPassed:       bimiPassed,
 		Failures:         failures,
+		Issues:           issues,
 	}
+	v.Passed = !v.HasErrors()
+	return v
 }
 
 // joinStrings joins strings with a separator (helper to avoid strings import).
@@ -160,8 +343,9 @@ func joinStrings(strs []string, sep string) string {
 }
 
 // IsPassing returns true if all primary authentication checks (SPF, DKIM, DMARC) passed.
-// This is a convenience method equivalent to calling Validate().Passed.
-// Note: Reverse DNS is not included in this check.
-func (a *AuthResults) IsPassing() bool {
-	return a.Validate().Passed
+// This is a convenience method equivalent to calling Validate(opts...).Passed.
+// Note: Reverse DNS and BIMI are not included in this check, nor is ARC
+// unless [WithARC] is passed.
+func (a *AuthResults) IsPassing(opts ...ValidateOption) bool {
+	return a.Validate(opts...).Passed
 }
@@ -1,6 +1,10 @@
 package authresults
 
 import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -282,3 +286,334 @@ func TestJoinStrings(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate_IssueSeverity(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		ar         *AuthResults
+		wantPassed bool
+		wantIssues []AuthIssue
+	}{
+		{
+			name: "all pass",
+			ar: &AuthResults{
+				SPF:        &SPFResult{Result: "pass"},
+				DKIM:       []DKIMResult{{Result: "pass"}},
+				DMARC:      &DMARCResult{Result: "pass"},
+				ReverseDNS: &ReverseDNSResult{Result: "pass"},
+			},
+			wantPassed: true,
+			wantIssues: []AuthIssue{},
+		},
+		{
+			name: "reverse DNS failure is a warning",
+			ar: &AuthResults{
+				SPF:        &SPFResult{Result: "pass"},
+				DKIM:       []DKIMResult{{Result: "pass"}},
+				DMARC:      &DMARCResult{Result: "pass"},
+				ReverseDNS: &ReverseDNSResult{Result: "fail", Hostname: "mail.example.com"},
+			},
+			wantPassed: true,
+			wantIssues: []AuthIssue{
+				{Check: CheckReverseDNS, Severity: SeverityWarning, Result: "fail", Message: "Reverse DNS check failed (hostname: mail.example.com)"},
+			},
+		},
+		{
+			name: "soft SPF and DMARC reject are errors",
+			ar: &AuthResults{
+				SPF:   &SPFResult{Result: "softfail"},
+				DKIM:  []DKIMResult{{Result: "skipped"}, {Result: "fail", Domain: "example.com"}},
+				DMARC: &DMARCResult{Result: "fail", Policy: "reject"},
+			},
+			wantPassed: false,
+			wantIssues: []AuthIssue{
+				{Check: CheckSPF, Severity: SeverityError, Result: "softfail", Message: "SPF check failed: softfail"},
+				{Check: CheckDKIM, Severity: SeverityError, Result: "fail", Message: "DKIM signature failed: example.com"},
+				{Check: CheckDMARC, Severity: SeverityError, Result: "fail", Message: "DMARC policy: fail (policy: reject)"},
+			},
+		},
+		{
+			name:       "missing primary results are errors",
+			ar:         &AuthResults{},
+			wantPassed: false,
+			wantIssues: []AuthIssue{
+				{Check: CheckSPF, Severity: SeverityError, Message: "SPF result missing"},
+				{Check: CheckDKIM, Severity: SeverityError, Message: "DKIM result missing"},
+				{Check: CheckDMARC, Severity: SeverityError, Message: "DMARC result missing"},
+			},
+		},
+		{
+			name:       "nil results",
+			ar:         nil,
+			wantPassed: false,
+			wantIssues: []AuthIssue{
+				{Severity: SeverityError, Message: "no authentication results available"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v := tt.ar.Validate()
+			if v.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v", v.Passed, tt.wantPassed)
+			}
+			if v.Passed == v.HasErrors() {
+				t.Errorf("Passed = %v but HasErrors() = %v", v.Passed, v.HasErrors())
+			}
+			if !reflect.DeepEqual(v.Issues, tt.wantIssues) {
+				t.Errorf("Issues = %+v, want %+v", v.Issues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestAuthValidation_IssuesWithSeverity(t *testing.T) {
+	t.Parallel()
+	v := AuthValidation{Issues: []AuthIssue{
+		{Check: CheckSPF, Severity: SeverityError},
+		{Check: CheckReverseDNS, Severity: SeverityWarning},
+	}}
+
+	warnings := v.IssuesWithSeverity(SeverityWarning)
+	if len(warnings) != 1 || warnings[0].Check != CheckReverseDNS {
+		t.Errorf("warnings = %+v, want one ReverseDNS issue", warnings)
+	}
+	errs := v.IssuesWithSeverity(SeverityError)
+	if len(errs) != 1 || errs[0].Check != CheckSPF {
+		t.Errorf("errors = %+v, want one SPF issue", errs)
+	}
+}
+
+func TestAuthResults_UnmarshalARC(t *testing.T) {
+	t.Parallel()
+	data := `{
+		"spf": {"result": "fail", "domain": "list.example.org"},
+		"dkim": [{"result": "fail", "domain": "example.com"}],
+		"dmarc": {"result": "fail", "policy": "reject"},
+		"arc": [
+			{
+				"instance": 1,
+				"cv": "none",
+				"domain": "list.example.org",
+				"selector": "arc-2024",
+				"spf": {"result": "pass", "domain": "example.com"},
+				"dkim": [{"result": "pass", "domain": "example.com", "selector": "s1"}],
+				"dmarc": {"result": "pass", "policy": "reject", "aligned": true}
+			},
+			{"instance": 2, "cv": "pass", "domain": "forwarder.example.net"}
+		]
+	}`
+
+	var ar AuthResults
+	if err := json.Unmarshal([]byte(data), &ar); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(ar.ARC) != 2 {
+		t.Fatalf("len(ARC) = %d, want 2", len(ar.ARC))
+	}
+	first := ar.ARC[0]
+	if first.Instance != 1 || first.CV != "none" || first.Domain != "list.example.org" || first.Selector != "arc-2024" {
+		t.Errorf("ARC[0] = %+v", first)
+	}
+	if first.SPF == nil || first.SPF.Result != "pass" || first.SPF.Domain != "example.com" {
+		t.Errorf("ARC[0].SPF = %+v, want pass for example.com", first.SPF)
+	}
+	if len(first.DKIM) != 1 || first.DKIM[0].Selector != "s1" {
+		t.Errorf("ARC[0].DKIM = %+v, want one signature with selector s1", first.DKIM)
+	}
+	if first.DMARC == nil || !first.DMARC.Aligned {
+		t.Errorf("ARC[0].DMARC = %+v, want aligned", first.DMARC)
+	}
+	if ar.ARC[1].Instance != 2 || ar.ARC[1].CV != "pass" {
+		t.Errorf("ARC[1] = %+v, want instance 2 cv=pass", ar.ARC[1])
+	}
+
+	// Round trip keeps the chain.
+	out, err := json.Marshal(&ar)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var again AuthResults
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(again, ar) {
+		t.Errorf("round trip = %+v, want %+v", again, ar)
+	}
+}
+
+func TestAuthResults_UnmarshalWithoutARC(t *testing.T) {
+	t.Parallel()
+	data := `{
+		"spf": {"result": "pass", "domain": "example.com"},
+		"dkim": [{"result": "pass", "domain": "example.com"}],
+		"dmarc": {"result": "pass"}
+	}`
+
+	var ar AuthResults
+	if err := json.Unmarshal([]byte(data), &ar); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if ar.ARC != nil {
+		t.Errorf("ARC = %+v, want nil", ar.ARC)
+	}
+	if !ar.IsPassing() {
+		t.Error("IsPassing() = false, want true without ARC required")
+	}
+	out, err := json.Marshal(&ar)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(out), `"arc"`) {
+		t.Errorf("Marshal() = %s, want no arc field", out)
+	}
+}
+
+func TestValidate_WithARC(t *testing.T) {
+	t.Parallel()
+	passing := func() *AuthResults {
+		return &AuthResults{
+			SPF:   &SPFResult{Result: "pass"},
+			DKIM:  []DKIMResult{{Result: "pass"}},
+			DMARC: &DMARCResult{Result: "pass"},
+		}
+	}
+	validChain := []ARCResult{{Instance: 1, CV: "none"}, {Instance: 2, CV: "pass"}}
+	brokenChain := []ARCResult{{Instance: 1, CV: "none"}, {Instance: 2, CV: "fail", Domain: "fwd.example.net"}}
+
+	tests := []struct {
+		name          string
+		arc           []ARCResult
+		opts          []ValidateOption
+		wantPassed    bool
+		wantARCPassed bool
+		wantIssue     string
+	}{
+		{"ARC ignored by default when missing", nil, nil, true, false, ""},
+		{"ARC ignored by default when broken", brokenChain, nil, true, false, ""},
+		{"required and valid", validChain, []ValidateOption{WithARC()}, true, true, ""},
+		{"required and missing", nil, []ValidateOption{WithARC()}, false, false, "ARC result missing"},
+		{"required and broken", brokenChain, []ValidateOption{WithARC()}, false, false, "ARC chain validation failed: fail (domain: fwd.example.net)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ar := passing()
+			ar.ARC = tt.arc
+			v := ar.Validate(tt.opts...)
+			if v.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v", v.Passed, tt.wantPassed)
+			}
+			if got := ar.IsPassing(tt.opts...); got != tt.wantPassed {
+				t.Errorf("IsPassing() = %v, want %v", got, tt.wantPassed)
+			}
+			if v.ARCPassed != tt.wantARCPassed {
+				t.Errorf("ARCPassed = %v, want %v", v.ARCPassed, tt.wantARCPassed)
+			}
+			var got string
+			for _, issue := range v.Issues {
+				if issue.Check == CheckARC {
+					got = issue.Message
+				}
+			}
+			if got != tt.wantIssue {
+				t.Errorf("ARC issue = %q, want %q", got, tt.wantIssue)
+			}
+			if tt.wantIssue != "" && !slices.Contains(v.Failures, tt.wantIssue) {
+				t.Errorf("Failures = %q, want it to include %q", v.Failures, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func TestAuthResults_MarshalBIMI(t *testing.T) {
+	t.Parallel()
+	data := `{
+		"spf": {"result": "pass"},
+		"bimi": {
+			"result": "pass",
+			"domain": "brand.example.com",
+			"selector": "default",
+			"indicator": "https://brand.example.com/logo.svg",
+			"authority": "pass"
+		}
+	}`
+
+	var ar AuthResults
+	if err := json.Unmarshal([]byte(data), &ar); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := &BIMIResult{
+		Result:    "pass",
+		Domain:    "brand.example.com",
+		Selector:  "default",
+		Indicator: "https://brand.example.com/logo.svg",
+		Authority: "pass",
+	}
+	if !reflect.DeepEqual(ar.BIMI, want) {
+		t.Errorf("BIMI = %+v, want %+v", ar.BIMI, want)
+	}
+
+	out, err := json.Marshal(&ar)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var again AuthResults
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(again.BIMI, want) {
+		t.Errorf("round trip BIMI = %+v, want %+v", again.BIMI, want)
+	}
+
+	out, err = json.Marshal(&AuthResults{SPF: &SPFResult{Result: "pass"}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(out), `"bimi"`) {
+		t.Errorf("Marshal() = %s, want no bimi field", out)
+	}
+}
+
+func TestValidate_BIMIFailureDoesNotAffectPassed(t *testing.T) {
+	t.Parallel()
+	ar := &AuthResults{
+		SPF:   &SPFResult{Result: "pass"},
+		DKIM:  []DKIMResult{{Result: "pass"}},
+		DMARC: &DMARCResult{Result: "pass"},
+		BIMI:  &BIMIResult{Result: "fail", Domain: "brand.example.com"},
+	}
+
+	v := ar.Validate()
+	if !v.Passed {
+		t.Error("Passed = false, want true when only BIMI fails")
+	}
+	if v.BIMIPassed {
+		t.Error("BIMIPassed = true, want false")
+	}
+	warnings := v.IssuesWithSeverity(SeverityWarning)
+	if len(warnings) != 1 || warnings[0].Check != CheckBIMI || warnings[0].Result != "fail" {
+		t.Errorf("warnings = %+v, want one BIMI warning", warnings)
+	}
+	if !ar.IsPassing() {
+		t.Error("IsPassing() = false, want true")
+	}
+
+	ar.BIMI.Result = "pass"
+	if v := ar.Validate(); !v.BIMIPassed || len(v.Issues) != 0 {
+		t.Errorf("BIMIPassed = %v, issues = %+v; want true and no issues", v.BIMIPassed, v.Issues)
+	}
+
+	for _, result := range []string{"none", "declined"} {
+		ar.BIMI.Result = result
+		v := ar.Validate()
+		if v.BIMIPassed || len(v.Issues) != 0 || len(v.Failures) != 0 {
+			t.Errorf("%s: BIMIPassed = %v, issues = %+v, failures = %v; want false and no issues or failures",
+				result, v.BIMIPassed, v.Issues, v.Failures)
+		}
+	}
+}
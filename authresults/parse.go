@@ -0,0 +1,420 @@
+package authresults
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrMalformedHeader is returned by [ParseHeader] when the header cannot be
+// parsed as an RFC 8601 Authentication-Results value.
+var ErrMalformedHeader = errors.New("malformed Authentication-Results header")
+
+// ParseHeader parses the value of an RFC 8601 Authentication-Results header,
+// such as
+//
+//	mx.example.net; spf=pass smtp.mailfrom=example.com;
+//	 dkim=pass header.d=example.com header.s=s1; dmarc=pass (p=reject) header.from=example.com
+//
+// into an [AuthResults]. A leading "Authentication-Results:" field name is
+// allowed, as is a missing authserv-id. The spf, dkim, dmarc, iprev (reverse DNS), arc and bimi methods
+// are recognized; other methods are ignored. Each DKIM signature becomes one
+// entry in DKIM; for the other methods only the first result is kept.
+// Comments supply details the properties lack, such as the DMARC policy
+// ("p=reject") and the ARC instance ("i=2").
+//
+// A header whose only result is "none" yields an empty AuthResults.
+// Malformed input returns an error wrapping [ErrMalformedHeader].
+func ParseHeader(header string) (*AuthResults, error) {
+	header = strings.TrimSpace(header)
+	if name, value, ok := strings.Cut(header, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Authentication-Results") {
+		header = strings.TrimSpace(value)
+	}
+	if header == "" {
+		return nil, fmt.Errorf("%w: empty header", ErrMalformedHeader)
+	}
+
+	segments, err := splitOutside(header, ';')
+	if err != nil {
+		return nil, err
+	}
+	// The first segment is normally the authserv-id of the host that added
+	// the header, but some providers (notably Microsoft) omit it.
+	id, _, err := extractComments(segments[0])
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("%w: missing authserv-id", ErrMalformedHeader)
+	}
+	if !strings.Contains(id, "=") {
+		segments = segments[1:]
+	}
+
+	results := &AuthResults{}
+	for _, segment := range segments {
+		if s := strings.TrimSpace(segment); s == "" || strings.EqualFold(s, "none") {
+			continue
+		}
+		info, err := parseResInfo(segment)
+		if err != nil {
+			return nil, err
+		}
+		info.apply(results)
+	}
+	return results, nil
+}
+
+// resInfo is one method result from an Authentication-Results header.
+type resInfo struct {
+	method   string
+	result   string
+	reason   string
+	comments []string
+	props    map[string]string // ptype.property (lowercase) -> value
+}
+
+// parseResInfo parses a single "method=result [reason=...] [ptype.prop=value...]"
+// clause.
+func parseResInfo(s string) (*resInfo, error) {
+	text, comments, err := extractComments(s)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := splitTokens(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty result in %q", ErrMalformedHeader, strings.TrimSpace(s))
+	}
+
+	method, result, ok := strings.Cut(tokens[0], "=")
+	if !ok || method == "" || result == "" {
+		return nil, fmt.Errorf("%w: expected method=result, got %q", ErrMalformedHeader, tokens[0])
+	}
+	method, _, _ = strings.Cut(method, "/") // drop method version
+	info := &resInfo{
+		method:   strings.ToLower(method),
+		result:   strings.ToLower(result),
+		comments: comments,
+		props:    make(map[string]string),
+	}
+
+	for _, token := range tokens[1:] {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: expected property=value, got %q", ErrMalformedHeader, token)
+		}
+		value = unquote(value)
+		key = strings.ToLower(key)
+		if key == "reason" {
+			info.reason = value
+			continue
+		}
+		if _, exists := info.props[key]; !exists {
+			info.props[key] = value
+		}
+	}
+	return info, nil
+}
+
+// apply records the result in results according to its method.
+func (r *resInfo) apply(results *AuthResults) {
+	switch r.method {
+	case "spf":
+		if results.SPF != nil {
+			return
+		}
+		domain := r.props["smtp.mailfrom"]
+		if domain == "" {
+			domain = r.props["smtp.helo"]
+		}
+		results.SPF = &SPFResult{
+			Result:  r.result,
+			Domain:  domainOf(domain),
+			IP:      firstNonEmpty(r.props["smtp.remote-ip"], r.props["smtp.client-ip"], r.props["policy.iprev"]),
+			Details: r.details(),
+		}
+	case "dkim":
+		results.DKIM = append(results.DKIM, DKIMResult{
+			Result:    r.result,
+			Domain:    firstNonEmpty(r.props["header.d"], domainOf(r.props["header.i"])),
+			Selector:  r.props["header.s"],
+			Signature: r.props["header.b"],
+			Info:      r.details(),
+		})
+	case "dmarc":
+		if results.DMARC != nil {
+			return
+		}
+		policy := firstNonEmpty(r.props["policy.dmarc"], r.commentTag("p"))
+		results.DMARC = &DMARCResult{
+			Result:  r.result,
+			Policy:  strings.ToLower(policy),
+			Aligned: r.result == "pass",
+			Domain:  r.props["header.from"],
+			Info:    r.details(),
+		}
+	case "iprev":
+		if results.ReverseDNS != nil {
+			return
+		}
+		ip := firstNonEmpty(r.props["policy.iprev"], r.props["smtp.remote-ip"], r.props["smtp.client-ip"])
+		results.ReverseDNS = &ReverseDNSResult{
+			Result:   r.result,
+			IP:       ip,
+			Hostname: r.hostnameFromComments(),
+		}
+	case "arc":
+		if len(results.ARC) > 0 {
+			return
+		}
+		instance, _ := strconv.Atoi(r.commentTag("i"))
+		results.ARC = append(results.ARC, ARCResult{
+			Instance: instance,
+			CV:       r.result,
+			Domain:   r.props["header.d"],
+			Selector: r.props["header.s"],
+		})
+	case "bimi":
+		if results.BIMI != nil {
+			return
+		}
+		results.BIMI = &BIMIResult{
+			Result:    r.result,
+			Domain:    r.props["header.d"],
+			Selector:  r.props["header.selector"],
+			Indicator: r.props["policy.indicator-uri"],
+			Authority: r.props["policy.authority"],
+		}
+	}
+}
+
+// details returns the reason, or the comments if there is no reason.
+func (r *resInfo) details() string {
+	if r.reason != "" {
+		return r.reason
+	}
+	return strings.Join(r.comments, "; ")
+}
+
+// commentTagPattern matches "tag=value" pairs inside comments, such as the
+// "p=REJECT" in "(p=REJECT sp=NONE dis=NONE)".
+var commentTagPattern = regexp.MustCompile(`(?:^|[\s,;])([A-Za-z]+)=([^\s,;)]+)`)
+
+// commentTag returns the value of tag in the clause's comments, or "".
+func (r *resInfo) commentTag(tag string) string {
+	for _, comment := range r.comments {
+		for _, m := range commentTagPattern.FindAllStringSubmatch(comment, -1) {
+			if strings.EqualFold(m[1], tag) {
+				return m[2]
+			}
+		}
+	}
+	return ""
+}
+
+// hostnameFromComments returns the first comment that looks like a hostname,
+// which is how iprev reports the name the IP resolved to.
+func (r *resInfo) hostnameFromComments() string {
+	for _, comment := range r.comments {
+		c := strings.TrimSpace(comment)
+		if c != "" && !strings.ContainsAny(c, " \t=") && strings.Contains(c, ".") {
+			return c
+		}
+	}
+	return ""
+}
+
+// splitOutside splits s on sep, ignoring separators inside comments and
+// quoted strings.
+func splitOutside(s string, sep byte) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	inQuote, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case inQuote:
+			inQuote = c != '"'
+		case c == '"':
+			inQuote = true
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("%w: unbalanced ')'", ErrMalformedHeader)
+			}
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("%w: unterminated quoted string", ErrMalformedHeader)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unbalanced '('", ErrMalformedHeader)
+	}
+	return append(parts, s[start:]), nil
+}
+
+// extractComments removes the top-level parenthesized comments from s,
+// returning the remaining text and the comments' contents.
+func extractComments(s string) (string, []string, error) {
+	var text strings.Builder
+	var comments []string
+	var comment strings.Builder
+	depth := 0
+	inQuote, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if depth > 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+				if depth == 0 {
+					comments = append(comments, strings.TrimSpace(comment.String()))
+					comment.Reset()
+					text.WriteByte(' ')
+					continue
+				}
+			}
+			comment.WriteByte(c)
+			continue
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case inQuote:
+			inQuote = c != '"'
+		case c == '"':
+			inQuote = true
+		case c == '(':
+			depth++
+			continue
+		case c == ')':
+			return "", nil, fmt.Errorf("%w: unbalanced ')'", ErrMalformedHeader)
+		}
+		text.WriteByte(c)
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("%w: unbalanced '('", ErrMalformedHeader)
+	}
+	return text.String(), comments, nil
+}
+
+// splitTokens splits s into whitespace-separated tokens, keeping quoted
+// strings intact and joining "key = value" written with spaces.
+func splitTokens(s string) ([]string, error) {
+	fields, err := splitFields(s)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "=" && len(tokens) > 0 && i+1 < len(fields):
+			tokens[len(tokens)-1] += "=" + fields[i+1]
+			i++
+		case strings.HasPrefix(f, "=") && len(tokens) > 0:
+			tokens[len(tokens)-1] += f
+		case strings.HasSuffix(f, "=") && i+1 < len(fields):
+			tokens = append(tokens, f+fields[i+1])
+			i++
+		default:
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens, nil
+}
+
+// splitFields splits s on whitespace outside quoted strings.
+func splitFields(s string) ([]string, error) {
+	var fields []string
+	var cur strings.Builder
+	inQuote, escaped := false, false
+	flush := func() {
+		if cur.Len() > 0 {
+			fields = append(fields, cur.String())
+			cur.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case inQuote:
+			inQuote = c != '"'
+		case c == '"':
+			inQuote = true
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			flush()
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	if inQuote {
+		return nil, fmt.Errorf("%w: unterminated quoted string", ErrMalformedHeader)
+	}
+	flush()
+	return fields, nil
+}
+
+// unquote removes surrounding double quotes and backslash escapes.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		if !escaped && s[i] == '\\' {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// domainOf returns the domain part of an address or "@domain" identity, or
+// s unchanged if it has no "@".
+func domainOf(s string) string {
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
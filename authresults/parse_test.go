@@ -0,0 +1,137 @@
+package authresults
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		header string
+		want   *AuthResults
+	}{
+		{
+			name: "gmail with two DKIM signatures",
+			header: `Authentication-Results: mx.google.com;
+       dkim=pass header.i=@example.com header.s=s1 header.b=AbCdEf12;
+       dkim=pass header.i=@esp.example.net header.s=k2 header.b=XyZ98765;
+       spf=pass (google.com: domain of bounce@example.com designates 203.0.113.5 as permitted sender) smtp.mailfrom=bounce@example.com;
+       dmarc=pass (p=REJECT sp=REJECT dis=NONE) header.from=example.com`,
+			want: &AuthResults{
+				SPF: &SPFResult{
+					Result:  "pass",
+					Domain:  "example.com",
+					Details: "google.com: domain of bounce@example.com designates 203.0.113.5 as permitted sender",
+				},
+				DKIM: []DKIMResult{
+					{Result: "pass", Domain: "example.com", Selector: "s1", Signature: "AbCdEf12"},
+					{Result: "pass", Domain: "esp.example.net", Selector: "k2", Signature: "XyZ98765"},
+				},
+				DMARC: &DMARCResult{
+					Result:  "pass",
+					Policy:  "reject",
+					Aligned: true,
+					Domain:  "example.com",
+					Info:    "p=REJECT sp=REJECT dis=NONE",
+				},
+			},
+		},
+		{
+			name: "mixed DKIM results with header.d and reason",
+			header: `mx.example.org 1; dkim=fail reason="signature verification failed" header.d=example.com header.s=sel1;
+ dkim=pass header.d=mailer.example.net header.s=sel2; spf=softfail smtp.mailfrom=example.com;
+ dmarc=fail policy.dmarc=quarantine header.from=example.com`,
+			want: &AuthResults{
+				SPF: &SPFResult{Result: "softfail", Domain: "example.com"},
+				DKIM: []DKIMResult{
+					{Result: "fail", Domain: "example.com", Selector: "sel1", Info: "signature verification failed"},
+					{Result: "pass", Domain: "mailer.example.net", Selector: "sel2"},
+				},
+				DMARC: &DMARCResult{Result: "fail", Policy: "quarantine", Domain: "example.com"},
+			},
+		},
+		{
+			name:   "outlook style without authserv-id",
+			header: `spf=pass (sender IP is 198.51.100.7) smtp.mailfrom=news.example.com; dkim=pass (signature was verified) header.d=example.com;dmarc=pass action=none header.from=example.com;compauth=pass reason=100`,
+			want: &AuthResults{
+				SPF:   &SPFResult{Result: "pass", Domain: "news.example.com", Details: "sender IP is 198.51.100.7"},
+				DKIM:  []DKIMResult{{Result: "pass", Domain: "example.com", Info: "signature was verified"}},
+				DMARC: &DMARCResult{Result: "pass", Aligned: true, Domain: "example.com"},
+			},
+		},
+		{
+			name: "iprev, arc and bimi",
+			header: `mx.example.net;
+ iprev=pass (mail.example.com) smtp.remote-ip=192.0.2.10;
+ arc=pass (i=2 spf=pass dkim=pass dmarc=pass) header.d=lists.example.org header.s=arc1;
+ bimi=pass header.d=example.com header.selector=default policy.authority=pass policy.indicator-uri=https://example.com/logo.svg`,
+			want: &AuthResults{
+				ReverseDNS: &ReverseDNSResult{Result: "pass", IP: "192.0.2.10", Hostname: "mail.example.com"},
+				ARC:        []ARCResult{{Instance: 2, CV: "pass", Domain: "lists.example.org", Selector: "arc1"}},
+				BIMI: &BIMIResult{
+					Result:    "pass",
+					Domain:    "example.com",
+					Selector:  "default",
+					Indicator: "https://example.com/logo.svg",
+					Authority: "pass",
+				},
+			},
+		},
+		{
+			name:   "no results",
+			header: "mx.example.net; none",
+			want:   &AuthResults{},
+		},
+		{
+			name:   "authserv-id only",
+			header: "mx.example.net",
+			want:   &AuthResults{},
+		},
+		{
+			name:   "unknown methods ignored and case normalized",
+			header: "MX.Example.NET; auth=pass smtp.auth=user; SPF=PASS smtp.helo=mail.example.com; dkim/1 = Pass header.d=example.com",
+			want: &AuthResults{
+				SPF:  &SPFResult{Result: "pass", Domain: "mail.example.com"},
+				DKIM: []DKIMResult{{Result: "pass", Domain: "example.com"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseHeader(tt.header)
+			if err != nil {
+				t.Fatalf("ParseHeader() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHeader() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeader_Malformed(t *testing.T) {
+	t.Parallel()
+	tests := []string{
+		"",
+		"Authentication-Results:   ",
+		"; spf=pass",
+		"mx.example.net; spf",
+		"mx.example.net; spf=pass smtp.mailfrom",
+		"mx.example.net; spf=pass (unclosed comment",
+		"mx.example.net; spf=pass) smtp.mailfrom=example.com",
+		`mx.example.net; dkim=fail reason="unterminated`,
+	}
+	for _, header := range tests {
+		t.Run(header, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParseHeader(header); !errors.Is(err, ErrMalformedHeader) {
+				t.Errorf("ParseHeader(%q) error = %v, want ErrMalformedHeader", header, err)
+			}
+		})
+	}
+}
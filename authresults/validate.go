@@ -2,6 +2,7 @@ package authresults
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -18,6 +19,20 @@ var (
 	// ErrReverseDNSFailed is returned when reverse DNS check failed.
 	ErrReverseDNSFailed = errors.New("reverse DNS check failed")
 
+	// ErrDMARCPolicyMismatch is returned by [AuthResults.RequireDMARC] when
+	// the published DMARC policy differs from the required one.
+	ErrDMARCPolicyMismatch = errors.New("DMARC policy mismatch")
+
+	// ErrDMARCNotAligned is returned by [AuthResults.RequireDMARC] when the
+	// DMARC identifiers are not aligned.
+	ErrDMARCNotAligned = errors.New("DMARC not aligned")
+
+	// ErrBIMIFailed is returned when the BIMI check failed.
+	ErrBIMIFailed = errors.New("BIMI check failed")
+
+	// ErrARCFailed is returned when the ARC chain is invalid.
+	ErrARCFailed = errors.New("ARC chain validation failed")
+
 	// ErrNoAuthResults is returned when no auth results are available.
 	ErrNoAuthResults = errors.New("no authentication results available")
 )
@@ -126,6 +141,29 @@ func ValidateDMARC(results *AuthResults) error {
 	return nil
 }
 
+// RequireDMARC returns nil if DMARC passed, the domain publishes the given
+// policy (none, quarantine, or reject; compared case-insensitively), and the
+// identifiers are aligned. An empty policy accepts any policy. Unlike
+// [ValidateDMARC], a "skipped" result does not satisfy it. The error wraps
+// [ErrNoAuthResults], [ErrDMARCFailed], [ErrDMARCPolicyMismatch], or
+// [ErrDMARCNotAligned].
+func (a *AuthResults) RequireDMARC(policy string) error {
+	if a == nil || a.DMARC == nil {
+		return ErrNoAuthResults
+	}
+	d := a.DMARC
+	if d.Result != "pass" {
+		return fmt.Errorf("%w: result %q", ErrDMARCFailed, d.Result)
+	}
+	if policy != "" && !strings.EqualFold(d.Policy, policy) {
+		return fmt.Errorf("%w: got %q, want %q", ErrDMARCPolicyMismatch, d.Policy, policy)
+	}
+	if !d.IsAligned() {
+		return ErrDMARCNotAligned
+	}
+	return nil
+}
+
 // ValidateReverseDNS validates only reverse DNS results.
 // Results with status "skipped" are treated as passed.
 func ValidateReverseDNS(results *AuthResults) error {
@@ -137,3 +175,63 @@ func ValidateReverseDNS(results *AuthResults) error {
 	}
 	return nil
 }
+
+// ValidateBIMI validates only BIMI results.
+// Results with status "skipped" are treated as passed, and "none" (the domain
+// publishes no BIMI record) and "declined" (the domain opts out) as neutral.
+// BIMI is informational, so it is never part of [Validate] or
+// [AuthResults.IsPassing].
+func ValidateBIMI(results *AuthResults) error {
+	if results == nil || results.BIMI == nil {
+		return ErrNoAuthResults
+	}
+	r := results.BIMI.Result
+	if r != "pass" && r != "skipped" && !bimiNeutral(r) {
+		return ErrBIMIFailed
+	}
+	return nil
+}
+
+// bimiNeutral reports whether a BIMI result says nothing about the email
+// either way: the domain has no BIMI record or has declined to show a logo.
+func bimiNeutral(result string) bool {
+	return result == "none" || result == "declined"
+}
+
+// ValidateARC validates only the ARC chain. The chain is valid when its sets
+// are numbered 1 through N without gaps or duplicates, the first set has
+// cv=none, and every later set has cv=pass. A cv=fail anywhere invalidates
+// the chain.
+func ValidateARC(results *AuthResults) error {
+	if results == nil || len(results.ARC) == 0 {
+		return ErrNoAuthResults
+	}
+	seen := make(map[int]bool, len(results.ARC))
+	for _, set := range results.ARC {
+		if set.Instance < 1 || set.Instance > len(results.ARC) || seen[set.Instance] {
+			return ErrARCFailed
+		}
+		seen[set.Instance] = true
+
+		want := "pass"
+		if set.Instance == 1 {
+			want = "none"
+		}
+		if set.CV != want {
+			return ErrARCFailed
+		}
+	}
+	return nil
+}
+
+// latestARC returns the ARC set with the highest instance, which carries the
+// validation state of the whole chain.
+func latestARC(sets []ARCResult) ARCResult {
+	latest := sets[0]
+	for _, set := range sets[1:] {
+		if set.Instance > latest.Instance {
+			latest = set
+		}
+	}
+	return latest
+}
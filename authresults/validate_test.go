@@ -16,6 +16,10 @@ func TestSentinelErrors(t *testing.T) {
 		{"ErrDKIMFailed", ErrDKIMFailed},
 		{"ErrDMARCFailed", ErrDMARCFailed},
 		{"ErrReverseDNSFailed", ErrReverseDNSFailed},
+		{"ErrDMARCPolicyMismatch", ErrDMARCPolicyMismatch},
+		{"ErrDMARCNotAligned", ErrDMARCNotAligned},
+		{"ErrBIMIFailed", ErrBIMIFailed},
+		{"ErrARCFailed", ErrARCFailed},
 		{"ErrNoAuthResults", ErrNoAuthResults},
 	}
 
@@ -371,6 +375,95 @@ func TestValidateDMARC(t *testing.T) {
 	}
 }
 
+func TestAuthResults_RequireDMARC(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		results *AuthResults
+		policy  string
+		wantErr error
+	}{
+		{
+			name:    "nil results",
+			results: nil,
+			policy:  "reject",
+			wantErr: ErrNoAuthResults,
+		},
+		{
+			name:    "nil DMARC",
+			results: &AuthResults{},
+			policy:  "reject",
+			wantErr: ErrNoAuthResults,
+		},
+		{
+			name:    "aligned pass",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "pass", Policy: "reject", Aligned: true}},
+			policy:  "reject",
+		},
+		{
+			name:    "policy compared case-insensitively",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "pass", Policy: "reject", Aligned: true}},
+			policy:  "REJECT",
+		},
+		{
+			name:    "empty policy accepts any",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "pass", Policy: "none", Aligned: true}},
+		},
+		{
+			name:    "unaligned pass",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "pass", Policy: "reject"}},
+			policy:  "reject",
+			wantErr: ErrDMARCNotAligned,
+		},
+		{
+			name:    "policy mismatch",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "pass", Policy: "quarantine", Aligned: true}},
+			policy:  "reject",
+			wantErr: ErrDMARCPolicyMismatch,
+		},
+		{
+			name:    "failed",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "fail", Policy: "reject", Aligned: true}},
+			policy:  "reject",
+			wantErr: ErrDMARCFailed,
+		},
+		{
+			name:    "skipped",
+			results: &AuthResults{DMARC: &DMARCResult{Result: "skipped"}},
+			wantErr: ErrDMARCFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.results.RequireDMARC(tt.policy)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("RequireDMARC() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RequireDMARC() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDMARCResult_IsAligned(t *testing.T) {
+	t.Parallel()
+	var nilResult *DMARCResult
+	if nilResult.IsAligned() {
+		t.Error("nil result should not be aligned")
+	}
+	if (&DMARCResult{Result: "pass"}).IsAligned() {
+		t.Error("result without Aligned should not be aligned")
+	}
+	if !(&DMARCResult{Result: "pass", Aligned: true}).IsAligned() {
+		t.Error("result with Aligned should be aligned")
+	}
+}
+
 func TestValidateReverseDNS(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -415,6 +508,60 @@ func TestValidateReverseDNS(t *testing.T) {
 	}
 }
 
+func TestValidateBIMI(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		results *AuthResults
+		wantErr error
+	}{
+		{"nil results", nil, ErrNoAuthResults},
+		{"no BIMI", &AuthResults{}, ErrNoAuthResults},
+		{"pass", &AuthResults{BIMI: &BIMIResult{Result: "pass"}}, nil},
+		{"skipped", &AuthResults{BIMI: &BIMIResult{Result: "skipped"}}, nil},
+		{"fail", &AuthResults{BIMI: &BIMIResult{Result: "fail"}}, ErrBIMIFailed},
+		{"none", &AuthResults{BIMI: &BIMIResult{Result: "none"}}, nil},
+		{"declined", &AuthResults{BIMI: &BIMIResult{Result: "declined"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateBIMI(tt.results); err != tt.wantErr {
+				t.Errorf("ValidateBIMI() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateARC(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		results *AuthResults
+		wantErr error
+	}{
+		{"nil results", nil, ErrNoAuthResults},
+		{"no ARC", &AuthResults{}, ErrNoAuthResults},
+		{"single hop", &AuthResults{ARC: []ARCResult{{Instance: 1, CV: "none"}}}, nil},
+		{"two hops", &AuthResults{ARC: []ARCResult{{Instance: 1, CV: "none"}, {Instance: 2, CV: "pass"}}}, nil},
+		{"out of order", &AuthResults{ARC: []ARCResult{{Instance: 2, CV: "pass"}, {Instance: 1, CV: "none"}}}, nil},
+		{"latest failed", &AuthResults{ARC: []ARCResult{{Instance: 1, CV: "none"}, {Instance: 2, CV: "fail"}}}, ErrARCFailed},
+		{"first hop claims pass", &AuthResults{ARC: []ARCResult{{Instance: 1, CV: "pass"}}}, ErrARCFailed},
+		{"gap in instances", &AuthResults{ARC: []ARCResult{{Instance: 1, CV: "none"}, {Instance: 3, CV: "pass"}}}, ErrARCFailed},
+		{"duplicate instance", &AuthResults{ARC: []ARCResult{{Instance: 1, CV: "none"}, {Instance: 1, CV: "none"}}}, ErrARCFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateARC(tt.results); err != tt.wantErr {
+				t.Errorf("ValidateARC() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestIsPassing(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,58 @@
+package vaultsandbox
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// CallbackPanicError is reported when a user-supplied callback panics.
+// The panic is recovered so the watch keeps delivering subsequent events.
+// It is logged with its stack at error level and passed to the
+// [WithOnSyncError] callback when one is configured.
+type CallbackPanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the goroutine stack trace captured at the time of the panic.
+	Stack []byte
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("callback panicked: %v", e.Value)
+}
+
+// ClientStats contains runtime counters for a client.
+type ClientStats struct {
+	// CallbackPanics is the number of panics recovered from user callbacks
+	// (for example, the functions passed to WatchFunc and WatchInboxesFunc).
+	CallbackPanics uint64
+}
+
+// Stats returns a snapshot of the client's runtime counters.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		CallbackPanics: c.callbackPanics.Load(),
+	}
+}
+
+// invokeCallback calls fn, recovering and reporting any panic it raises.
+// It returns true if fn completed without panicking.
+func (c *Client) invokeCallback(fn func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.recordCallbackPanic(r, debug.Stack())
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+// recordCallbackPanic counts a recovered panic, logs it with its stack at
+// error level and reports it via onSyncError.
+func (c *Client) recordCallbackPanic(value any, stack []byte) {
+	c.callbackPanics.Add(1)
+	c.log().Error("callback panicked", "panic", value, "stack", string(stack))
+	if c.onSyncError != nil {
+		c.onSyncError(&CallbackPanicError{Value: value, Stack: stack})
+	}
+}
@@ -5,15 +5,24 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/crypto"
 	"github.com/vaultsandbox/client-go/internal/delivery"
+	"github.com/vaultsandbox/client-go/internal/tracing"
 )
 
 // TTL constants for inbox creation.
@@ -67,6 +76,11 @@ const (
 )
 
 // ServerInfo contains server configuration.
+//
+// The capability fields SSEEnabled, MaxAttachmentSize, and
+// SupportedAlgorithms are only set by servers that advertise them; check
+// them before relying on a feature. [New] uses SSEEnabled to fall back to
+// [StrategyPolling] when no strategy was chosen.
 type ServerInfo struct {
 	AllowedDomains      []string
 	MaxTTL              time.Duration
@@ -74,6 +88,26 @@ type ServerInfo struct {
 	EncryptionPolicy    EncryptionPolicy
 	SpamAnalysisEnabled bool
 	ChaosEnabled        bool
+
+	// SSEEnabled reports whether the server supports SSE delivery. It is nil
+	// if the server does not say (older servers).
+	SSEEnabled *bool
+	// MaxAttachmentSize is the largest attachment the server accepts, in
+	// bytes, or 0 if the server does not advertise a limit.
+	MaxAttachmentSize int64
+	// SupportedAlgorithms lists the algorithms the server supports, or is
+	// empty if the server does not advertise them.
+	SupportedAlgorithms []string
+}
+
+// InboxSummary describes an inbox owned by the API key, as reported by the
+// server. It has no secret key, so an encrypted inbox listed here can only be
+// read by the client that created it (or one that imported it).
+type InboxSummary struct {
+	EmailAddress string
+	InboxHash    string
+	ExpiresAt    time.Time
+	Encrypted    bool
 }
 
 // Client is the main VaultSandbox client for managing inboxes.
@@ -95,6 +129,61 @@ type Client struct {
 
 	// Error callback for background sync failures
 	onSyncError func(error)
+
+	// Structured logger from WithLogger or WithSlog (nil = disabled)
+	logger Logger
+
+	// Span hooks from WithTracer (nil = disabled)
+	tracer Tracer
+
+	// Number of panics recovered from user callbacks
+	callbackPanics atomic.Uint64
+
+	// Maximum decoded size of a single attachment (0 = unlimited)
+	maxAttachmentSize int
+
+	// Verify attachment checksums during decryption
+	verifyAttachmentChecksums bool
+
+	// Server signing keys accepted besides each inbox's pinned key
+	additionalServerKeys [][]byte
+
+	// Maximum allowed future skew of signed timestamps (0 = disabled)
+	clockSkewTolerance time.Duration
+	strictClockSkew    bool
+
+	// Resolved polling intervals, shared by helpers that poll the API directly
+	pollingConfig PollingConfig
+
+	// Semaphore bounding concurrent decryptions across all inboxes
+	decryptSem chan struct{}
+
+	// Which timestamp wins for Email.ReceivedAt
+	receivedAtSource ReceivedAtSource
+
+	// Closed when the keepalive pinger exits (nil = pinger disabled)
+	keepAliveDone chan struct{}
+
+	// Time source for expiry, timeouts, and backoff (nil = real clock)
+	clk clock.Clock
+}
+
+// clock returns the client's time source. It is safe to call on a nil
+// client, as detached inboxes do.
+func (c *Client) clock() clock.Clock {
+	if c == nil {
+		return clock.Real{}
+	}
+	return clock.OrReal(c.clk)
+}
+
+// resolveMaxConcurrentDecrypts returns the configured decryption limit,
+// defaulting to GOMAXPROCS.
+func resolveMaxConcurrentDecrypts(cfg *clientConfig) int {
+	if cfg.maxConcurrentDecrypts > 0 {
+		return cfg.maxConcurrentDecrypts
+	}
+	return runtime.GOMAXPROCS(0)
 }
 
 // buildAPIClient creates and configures an API client from the given config.
@@ -111,6 +200,33 @@ func buildAPIClient(apiKey string, cfg *clientConfig) (*api.Client, error) {
 	if len(cfg.retryOn) > 0 {
 		apiOpts = append(apiOpts, api.WithRetryOn(cfg.retryOn))
 	}
+	if cfg.retryJitter > 0 {
+		apiOpts = append(apiOpts, api.WithRetryJitter(cfg.retryJitter))
+	}
+	if cfg.maxElapsedTime > 0 {
+		apiOpts = append(apiOpts, api.WithMaxElapsedTime(cfg.maxElapsedTime))
+	}
+	if cfg.rateLimit > 0 {
+		apiOpts = append(apiOpts, api.WithRateLimit(cfg.rateLimit, cfg.rateBurst))
+	}
+	if cfg.retryDecider != nil {
+		apiOpts = append(apiOpts, api.WithRetryDecider(cfg.retryDecider))
+	}
+	if cfg.onRetry != nil {
+		apiOpts = append(apiOpts, api.WithOnRetry(cfg.onRetry))
+	}
+	if cfg.logger != nil {
+		apiOpts = append(apiOpts, api.WithLogger(cfg.logger))
+	}
+	if cfg.tracer != nil {
+		apiOpts = append(apiOpts, api.WithTracer(cfg.tracer))
+	}
+	if cfg.userAgent != "" {
+		apiOpts = append(apiOpts, api.WithUserAgent(cfg.userAgent))
+	}
+	if cfg.clock != nil {
+		apiOpts = append(apiOpts, api.WithClock(cfg.clock))
+	}
 
 	apiClient, err := api.New(apiKey, apiOpts...)
 	if err != nil {
@@ -120,27 +236,143 @@ func buildAPIClient(apiKey string, cfg *clientConfig) (*api.Client, error) {
 	if cfg.httpClient != nil {
 		apiClient.SetHTTPClient(cfg.httpClient)
 	}
+	if cfg.wireLog != nil {
+		apiClient.EnableWireLog(cfg.wireLog)
+	}
 
 	return apiClient, nil
 }
 
 // createDeliveryStrategy creates a delivery strategy based on the config.
-func createDeliveryStrategy(cfg *clientConfig, apiClient *api.Client) delivery.Strategy {
+// serverSigPks are the server's accepted signing keys, used to verify webhook
+// events.
+func createDeliveryStrategy(cfg *clientConfig, apiClient *api.Client, serverSigPks [][]byte) delivery.Strategy {
 	deliveryCfg := delivery.Config{
 		APIClient:                apiClient,
 		PollingInitialInterval:   cfg.pollingInitialInterval,
 		PollingMaxBackoff:        cfg.pollingMaxBackoff,
 		PollingBackoffMultiplier: cfg.pollingBackoffMultiplier,
 		PollingJitterFactor:      cfg.pollingJitterFactor,
+		PollingConcurrency:       cfg.monitorConcurrency,
+		PollMinInterval:          cfg.pollingMinInterval,
+		PollMaxInterval:          cfg.pollingMaxInterval,
+		OnReconnect:              cfg.sseReconnectHook,
+		OnConnected:              cfg.sseConnectedHook,
+		SSEHeartbeatTimeout:      cfg.sseHeartbeatTimeout,
+		WebhookURL:               cfg.webhookURL,
+		ServerSigPks:             serverSigPks,
+		Logger:                   cfg.logger,
+		Clock:                    cfg.clock,
 	}
 	switch cfg.deliveryStrategy {
 	case StrategyPolling:
 		return delivery.NewPollingStrategy(deliveryCfg)
+	case StrategyWebhook:
+		return delivery.NewWebhookStrategy(deliveryCfg)
 	default:
 		return delivery.NewSSEStrategy(deliveryCfg)
 	}
 }
 
+// resolveDeliveryStrategy picks the default strategy when none was set with
+// WithDeliveryStrategy: polling if the server reports that it does not
+// support SSE, otherwise SSE, including when the server does not say.
+// An explicitly chosen strategy is never changed.
+func (c *clientConfig) resolveDeliveryStrategy(info *api.ServerInfo) {
+	if c.deliveryStrategy != "" {
+		return
+	}
+	c.deliveryStrategy = StrategySSE
+	if info != nil && info.SSEEnabled != nil && !*info.SSEEnabled {
+		c.deliveryStrategy = StrategyPolling
+		if c.logger != nil {
+			c.logger.Info("server does not support SSE, using polling delivery")
+		}
+	}
+}
+
+// validateRetryJitter checks the fraction set by WithRetryJitter.
+func (c *clientConfig) validateRetryJitter() error {
+	if c.retryJitter < 0 || c.retryJitter >= 1 || math.IsNaN(c.retryJitter) {
+		return fmt.Errorf("retry jitter must be in [0, 1), got %v", c.retryJitter)
+	}
+	return nil
+}
+
+// validateRateLimit checks the values set by WithRateLimit.
+func (c *clientConfig) validateRateLimit() error {
+	if c.rateLimit < 0 || math.IsNaN(c.rateLimit) || math.IsInf(c.rateLimit, 0) {
+		return fmt.Errorf("rate limit must be finite and non-negative, got %v", c.rateLimit)
+	}
+	if c.rateLimit > 0 && c.rateBurst < 1 {
+		return fmt.Errorf("rate limit burst must be at least 1, got %d", c.rateBurst)
+	}
+	return nil
+}
+
+// decodeAdditionalServerKeys decodes and checks the keys set by
+// WithAdditionalServerKeys.
+func (c *clientConfig) decodeAdditionalServerKeys() ([][]byte, error) {
+	var keys [][]byte
+	for n, key := range c.additionalServerKeys {
+		pk, err := crypto.FromBase64URL(key)
+		if err != nil {
+			return nil, fmt.Errorf("additional server key %d: invalid base64url encoding: %w", n, err)
+		}
+		if len(pk) != crypto.MLDSAPublicKeySize {
+			return nil, fmt.Errorf("additional server key %d: size %d, expected %d", n, len(pk), crypto.MLDSAPublicKeySize)
+		}
+		keys = append(keys, pk)
+	}
+	return keys, nil
+}
+
+// validatePollingBounds checks the bounds set by WithPollingBounds.
+func (c *clientConfig) validatePollingBounds() error {
+	if !c.pollingBoundsSet {
+		return nil
+	}
+	if c.pollingMinInterval <= 0 || c.pollingMaxInterval <= 0 {
+		return fmt.Errorf("polling bounds must be positive, got min %v and max %v", c.pollingMinInterval, c.pollingMaxInterval)
+	}
+	if c.pollingMinInterval > c.pollingMaxInterval {
+		return fmt.Errorf("polling min interval %v exceeds max interval %v", c.pollingMinInterval, c.pollingMaxInterval)
+	}
+	return nil
+}
+
+// resolvePollingConfig returns the configured polling intervals with unset
+// fields replaced by the delivery strategy defaults.
+func resolvePollingConfig(cfg *clientConfig) PollingConfig {
+	pc := PollingConfig{
+		InitialInterval:   cfg.pollingInitialInterval,
+		MaxBackoff:        cfg.pollingMaxBackoff,
+		BackoffMultiplier: cfg.pollingBackoffMultiplier,
+		JitterFactor:      cfg.pollingJitterFactor,
+	}
+	if pc.InitialInterval <= 0 {
+		pc.InitialInterval = delivery.DefaultPollingInitialInterval
+		if cfg.pollingMinInterval > 0 {
+			pc.InitialInterval = cfg.pollingMinInterval
+		}
+	}
+	if pc.MaxBackoff <= 0 {
+		pc.MaxBackoff = delivery.DefaultPollingMaxBackoff
+	}
+	if cfg.pollingMaxInterval > 0 {
+		pc.MaxBackoff = min(pc.MaxBackoff, cfg.pollingMaxInterval)
+		pc.InitialInterval = min(pc.InitialInterval, cfg.pollingMaxInterval)
+	}
+	pc.InitialInterval = max(pc.InitialInterval, cfg.pollingMinInterval)
+	if pc.BackoffMultiplier <= 0 {
+		pc.BackoffMultiplier = delivery.DefaultPollingBackoffMultiplier
+	}
+	if pc.JitterFactor <= 0 {
+		pc.JitterFactor = delivery.DefaultPollingJitterFactor
+	}
+	return pc
+}
+
 // New creates a new VaultSandbox client with the given API key.
 func New(apiKey string, opts ...Option) (*Client, error) {
 	if apiKey == "" {
@@ -148,14 +380,26 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 	}
 
 	cfg := &clientConfig{
-		baseURL:          defaultBaseURL,
-		deliveryStrategy: StrategySSE,
-		timeout:          defaultWaitTimeout,
+		baseURL: defaultBaseURL,
+		timeout: defaultWaitTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if err := cfg.validatePollingBounds(); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateRetryJitter(); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateRateLimit(); err != nil {
+		return nil, err
+	}
+	additionalServerKeys, err := cfg.decodeAdditionalServerKeys()
+	if err != nil {
+		return nil, err
+	}
 
 	apiClient, err := buildAPIClient(apiKey, cfg)
 	if err != nil {
@@ -173,25 +417,59 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 	// Fetch server info
 	serverInfo, err := apiClient.GetServerInfo(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("fetch server info: %w", err)
+		if !cfg.serverInfoOptional {
+			return nil, fmt.Errorf("fetch server info: %w", err)
+		}
+		// Proceed without server info; the server enforces its own limits.
+		if cfg.logger != nil {
+			cfg.logger.Warn("fetch server info failed, continuing without it", "error", err)
+		}
+		if cfg.onSyncError != nil {
+			cfg.onSyncError(fmt.Errorf("fetch server info (continuing without it): %w", err))
+		}
+		serverInfo = nil
 	}
 
-	strategy := createDeliveryStrategy(cfg, apiClient)
+	cfg.resolveDeliveryStrategy(serverInfo)
+
+	var serverSigPks [][]byte
+	if serverInfo != nil {
+		// A malformed key is left out, so without additional keys encrypted
+		// webhook events are rejected rather than accepted unverified.
+		if serverSigPk, err := crypto.FromBase64URL(serverInfo.ServerSigPk); err == nil && len(serverSigPk) > 0 {
+			serverSigPks = append(serverSigPks, serverSigPk)
+		}
+	}
+	serverSigPks = append(serverSigPks, additionalServerKeys...)
+	strategy := createDeliveryStrategy(cfg, apiClient, serverSigPks)
 
 	strategyCtx, strategyCancel := context.WithCancel(context.Background())
 
 	c := &Client{
-		apiClient:      apiClient,
-		strategy:       strategy,
-		serverInfo:     serverInfo,
-		inboxes:        make(map[string]*Inbox),
-		inboxesByHash:  make(map[string]*Inbox),
-		syncStates:     make(map[string]*syncState),
-		subs:           newSubscriptionManager(),
-		strategyCtx:    strategyCtx,
-		strategyCancel: strategyCancel,
-		onSyncError:    cfg.onSyncError,
-	}
+		apiClient:          apiClient,
+		strategy:           strategy,
+		serverInfo:         serverInfo,
+		inboxes:            make(map[string]*Inbox),
+		inboxesByHash:      make(map[string]*Inbox),
+		syncStates:         make(map[string]*syncState),
+		subs:               newSubscriptionManager(),
+		strategyCtx:        strategyCtx,
+		strategyCancel:     strategyCancel,
+		onSyncError:        cfg.onSyncError,
+		logger:             cfg.logger,
+		tracer:             cfg.tracer,
+		maxAttachmentSize:  cfg.maxAttachmentSize,
+		clockSkewTolerance: cfg.clockSkewTolerance,
+		strictClockSkew:    cfg.strictClockSkew,
+		pollingConfig:      resolvePollingConfig(cfg),
+		decryptSem:         make(chan struct{}, resolveMaxConcurrentDecrypts(cfg)),
+		receivedAtSource:   cfg.receivedAtSource,
+		clk:                cfg.clock,
+
+		verifyAttachmentChecksums: cfg.verifyAttachmentChecksums,
+		additionalServerKeys:      additionalServerKeys,
+	}
+	c.subs.onPanic = c.recordCallbackPanic
 
 	// Start the strategy with an event handler
 	if err := strategy.Start(strategyCtx, nil, c.handleSSEEvent); err != nil {
@@ -209,6 +487,10 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 		errHandler.OnError(c.onSyncError)
 	}
 
+	if cfg.keepAliveInterval > 0 {
+		c.startKeepAlive(strategyCtx, cfg.keepAliveInterval)
+	}
+
 	return c, nil
 }
 
@@ -222,6 +504,21 @@ func (c *Client) checkClosed() error {
 	return nil
 }
 
+// pinServerKey returns the server keys an encrypted inbox accepts when
+// serverSigPk is the key pinned at its creation or import: the pinned key,
+// followed by the keys from WithAdditionalServerKeys. It returns nil for a
+// nil key, as plain inboxes have, and is safe to call on a nil client.
+func (c *Client) pinServerKey(serverSigPk []byte) [][]byte {
+	if serverSigPk == nil {
+		return nil
+	}
+	keys := [][]byte{serverSigPk}
+	if c != nil {
+		keys = append(keys, c.additionalServerKeys...)
+	}
+	return keys
+}
+
 // registerInbox adds an inbox to the client's tracking maps and delivery strategy.
 func (c *Client) registerInbox(inbox *Inbox) error {
 	c.mu.Lock()
@@ -242,11 +539,14 @@ func (c *Client) registerInbox(inbox *Inbox) error {
 }
 
 // CreateInbox creates a new temporary email inbox.
-func (c *Client) CreateInbox(ctx context.Context, opts ...InboxOption) (*Inbox, error) {
+func (c *Client) CreateInbox(ctx context.Context, opts ...InboxOption) (_ *Inbox, err error) {
 	if err := c.checkClosed(); err != nil {
 		return nil, err
 	}
 
+	ctx, span := c.startSpan(ctx, "vaultsandbox.CreateInbox")
+	defer func() { tracing.End(span, err) }()
+
 	cfg := &inboxConfig{
 		ttl: time.Hour, // Default 1 hour
 	}
@@ -254,14 +554,13 @@ func (c *Client) CreateInbox(ctx context.Context, opts ...InboxOption) (*Inbox,
 		opt(cfg)
 	}
 
-	// Validate TTL against limits
+	if err := checkSupportedSuite(cfg.requireSuite); err != nil {
+		return nil, err
+	}
+
 	if cfg.ttl > 0 {
-		if cfg.ttl < MinTTL {
-			return nil, fmt.Errorf("TTL %v is below minimum %v", cfg.ttl, MinTTL)
-		}
-		serverMaxTTL := time.Duration(c.serverInfo.MaxTTL) * time.Second
-		if cfg.ttl > serverMaxTTL {
-			return nil, fmt.Errorf("TTL %v exceeds server maximum %v", cfg.ttl, serverMaxTTL)
+		if err := c.validateTTL(cfg.ttl); err != nil {
+			return nil, err
 		}
 	}
 
@@ -273,12 +572,15 @@ func (c *Client) CreateInbox(ctx context.Context, opts ...InboxOption) (*Inbox,
 		SpamAnalysis: cfg.spamAnalysis,
 	}
 
-	resp, err := c.apiClient.CreateInbox(ctx, req)
+	resp, err := c.createInboxWithRetry(ctx, req, cfg.collisionRetry)
 	if err != nil {
 		return nil, err
 	}
 
 	inbox := newInboxFromResult(resp, c)
+	inbox.requireSuite = cfg.requireSuite
+	inbox.aadFunc = cfg.aadFunc
+	span.SetAttributes("vaultsandbox.inbox.hash", inbox.inboxHash)
 
 	if err := c.registerInbox(inbox); err != nil {
 		return nil, err //coverage:ignore
@@ -287,6 +589,44 @@ func (c *Client) CreateInbox(ctx context.Context, opts ...InboxOption) (*Inbox,
 	return inbox, nil
 }
 
+// validateTTL checks an inbox TTL against MinTTL and the server maximum.
+func (c *Client) validateTTL(ttl time.Duration) error {
+	if ttl < MinTTL {
+		return fmt.Errorf("TTL %v is below minimum %v", ttl, MinTTL)
+	}
+	// Without server info the maximum is unknown; let the server reject it.
+	if c.serverInfo != nil {
+		serverMaxTTL := time.Duration(c.serverInfo.MaxTTL) * time.Second
+		if ttl > serverMaxTTL {
+			return fmt.Errorf("TTL %v exceeds server maximum %v", ttl, serverMaxTTL)
+		}
+	}
+	return nil
+}
+
+// createInboxWithRetry creates an inbox, retrying address collisions up to
+// retries times when the server chooses the local part.
+func (c *Client) createInboxWithRetry(ctx context.Context, req *api.CreateInboxParams, retries int) (*api.CreateInboxResult, error) {
+	explicit := strings.Contains(req.EmailAddress, "@")
+	for attempt := 1; ; attempt++ {
+		resp, err := c.apiClient.CreateInbox(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if !errors.Is(err, ErrInboxAlreadyExists) {
+			return nil, err
+		}
+		if explicit || attempt > retries || ctx.Err() != nil {
+			return nil, &InboxCollisionError{
+				EmailAddress: req.EmailAddress,
+				Explicit:     explicit,
+				Attempts:     attempt,
+				Err:          err,
+			}
+		}
+	}
+}
+
 // ImportInbox imports a previously exported inbox.
 func (c *Client) ImportInbox(ctx context.Context, data *ExportedInbox) (*Inbox, error) {
 	if data == nil {
@@ -344,6 +684,47 @@ func (c *Client) ImportInbox(ctx context.Context, data *ExportedInbox) (*Inbox,
 	return inbox, nil
 }
 
+// ImportEncryptedInbox decrypts an export created by [Inbox.ExportEncrypted]
+// with passphrase and imports it as [Client.ImportInbox] does.
+func (c *Client) ImportEncryptedInbox(ctx context.Context, data *EncryptedExport, passphrase string) (*Inbox, error) {
+	if data == nil {
+		return nil, fmt.Errorf("encrypted export cannot be nil")
+	}
+	exported, err := data.Decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return c.ImportInbox(ctx, exported)
+}
+
+// ImportInboxBundle restores an inbox and its emails from a bundle created by
+// [Inbox.ExportWithEmails]. Unlike ImportInbox, it does not contact the
+// server: the returned inbox serves the bundled emails through
+// [Inbox.GetEmailsCached], which allows fully offline replay of a captured
+// inbox. The inbox is not registered for live delivery; use ImportInbox with
+// bundle.ExportedInbox to resume monitoring a live inbox.
+func (c *Client) ImportInboxBundle(bundle *ExportedInboxBundle) (*Inbox, error) {
+	if bundle == nil {
+		return nil, fmt.Errorf("exported inbox bundle cannot be nil")
+	}
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	inbox, err := newInboxFromExport(&bundle.ExportedInbox, c)
+	if err != nil {
+		return nil, err
+	}
+	inbox.cachedEmails = make([]*api.RawEmail, 0, len(bundle.Emails))
+	for _, e := range bundle.Emails {
+		if e == nil {
+			return nil, fmt.Errorf("%w: bundle contains a nil email", ErrInvalidImportData)
+		}
+		inbox.cachedEmails = append(inbox.cachedEmails, e)
+	}
+	return inbox, nil
+}
+
 // DeleteInbox deletes an inbox by email address.
 func (c *Client) DeleteInbox(ctx context.Context, emailAddress string) error {
 	// First, attempt the API deletion
@@ -352,17 +733,55 @@ func (c *Client) DeleteInbox(ctx context.Context, emailAddress string) error {
 	}
 
 	// Only remove from local tracking after successful API call
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.untrackInbox(emailAddress)
+	return nil
+}
 
-	if inbox, exists := c.inboxes[emailAddress]; exists {
-		c.strategy.RemoveInbox(inbox.inboxHash)
-		delete(c.inboxes, emailAddress)
-		delete(c.inboxesByHash, inbox.inboxHash)
-		delete(c.syncStates, inbox.inboxHash)
+// DeleteExpiredInboxes deletes the tracked inboxes whose TTL has passed and
+// stops tracking them. An inbox the server has already removed (404) or
+// reports as expired (410) counts as deleted. It returns the number of inboxes removed; if some deletions fail
+// the others still proceed and the failures are returned joined together.
+func (c *Client) DeleteExpiredInboxes(ctx context.Context) (int, error) {
+	c.mu.RLock()
+	var expired []string
+	for email, inbox := range c.inboxes {
+		if inbox.IsExpired() {
+			expired = append(expired, email)
+		}
 	}
+	c.mu.RUnlock()
 
-	return nil
+	var count int
+	var errs []error
+	for _, email := range expired {
+		err := c.apiClient.DeleteInboxByEmail(ctx, email)
+		if err != nil && !errors.Is(err, ErrInboxNotFound) && !errors.Is(err, ErrInboxExpired) {
+			errs = append(errs, fmt.Errorf("delete inbox %s: %w", email, err))
+			continue
+		}
+		if c.untrackInbox(email) {
+			count++
+		}
+	}
+	return count, errors.Join(errs...)
+}
+
+// untrackInbox removes an inbox from local tracking and from the delivery
+// strategy. It reports whether the inbox was tracked.
+func (c *Client) untrackInbox(emailAddress string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	inbox, exists := c.inboxes[emailAddress]
+	if !exists {
+		return false
+	}
+	c.strategy.RemoveInbox(inbox.inboxHash)
+	delete(c.inboxes, emailAddress)
+	delete(c.inboxesByHash, inbox.inboxHash)
+	delete(c.syncStates, inbox.inboxHash)
+	c.subs.end(inbox.inboxHash, nil)
+	return true
 }
 
 // DeleteAllInboxes deletes all inboxes managed by this client.
@@ -379,6 +798,7 @@ func (c *Client) DeleteAllInboxes(ctx context.Context) (int, error) {
 		delete(c.inboxes, email)
 		delete(c.inboxesByHash, inbox.inboxHash)
 		delete(c.syncStates, inbox.inboxHash)
+		c.subs.end(inbox.inboxHash, nil)
 	}
 	return count, nil
 }
@@ -403,8 +823,55 @@ func (c *Client) Inboxes() []*Inbox {
 	return result
 }
 
+// ListInboxes returns every inbox the server holds for the API key,
+// including inboxes created by other clients or earlier runs. Unlike
+// [Client.Inboxes] it queries the server; the returned summaries are not
+// added to this client. Use it to find and delete orphaned inboxes.
+func (c *Client) ListInboxes(ctx context.Context) ([]InboxSummary, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	items, err := c.apiClient.ListInboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]InboxSummary, len(items))
+	for i, item := range items {
+		result[i] = InboxSummary{
+			EmailAddress: item.EmailAddress,
+			InboxHash:    item.InboxHash,
+			ExpiresAt:    item.ExpiresAt,
+			Encrypted:    item.Encrypted,
+		}
+	}
+	return result, nil
+}
+
+// WebhookHandler returns the http.Handler that receives webhook
+// notifications when the client uses [WithWebhookDelivery]. Mount it so that
+// it serves the URL given to that option. It returns nil for other delivery
+// strategies.
+func (c *Client) WebhookHandler() http.Handler {
+	if webhook, ok := c.strategy.(*delivery.WebhookStrategy); ok {
+		return webhook.Handler()
+	}
+	return nil
+}
+
 // ServerInfo returns the server configuration.
+// It returns nil if the client was created with [WithServerInfoOptional]
+// and the server info could not be fetched.
 func (c *Client) ServerInfo() *ServerInfo {
+	if c.serverInfo == nil {
+		return nil
+	}
+	var sseEnabled *bool
+	if c.serverInfo.SSEEnabled != nil {
+		v := *c.serverInfo.SSEEnabled
+		sseEnabled = &v
+	}
 	return &ServerInfo{
 		AllowedDomains:      c.serverInfo.AllowedDomains,
 		MaxTTL:              time.Duration(c.serverInfo.MaxTTL) * time.Second,
@@ -412,6 +879,9 @@ func (c *Client) ServerInfo() *ServerInfo {
 		EncryptionPolicy:    c.serverInfo.EncryptionPolicy,
 		SpamAnalysisEnabled: c.serverInfo.SpamAnalysisEnabled,
 		ChaosEnabled:        c.serverInfo.ChaosEnabled,
+		SSEEnabled:          sseEnabled,
+		MaxAttachmentSize:   c.serverInfo.MaxAttachmentSize,
+		SupportedAlgorithms: slices.Clone(c.serverInfo.SupportedAlgorithms),
 	}
 }
 
@@ -424,6 +894,19 @@ func (c *Client) CheckKey(ctx context.Context) error {
 	return c.apiClient.CheckKey(ctx)
 }
 
+// Ping checks that the VaultSandbox gateway is up, for example in a
+// readiness check before a test suite starts. Unlike [Client.CheckKey] it
+// does not authenticate, so it works before the key is provisioned and does
+// not use up the key's quota. It makes one request with no retries. Returns
+// a [NetworkError] if the gateway is unreachable or an [APIError] if it
+// answers with a non-2xx status.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.checkClosed(); err != nil {
+		return err
+	}
+	return c.apiClient.Ping(ctx)
+}
+
 // ExportInboxToFile exports an inbox to a JSON file with secure permissions (0600).
 func (c *Client) ExportInboxToFile(inbox *Inbox, filePath string) error {
 	if inbox == nil {
@@ -464,6 +947,99 @@ func (c *Client) ImportInboxFromFile(ctx context.Context, filePath string) (*Inb
 	return c.ImportInbox(ctx, &data)
 }
 
+// ExportAllInboxes exports every inbox managed by this client, ordered by
+// email address.
+func (c *Client) ExportAllInboxes() (*ExportedInboxSet, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	inboxes := c.Inboxes()
+	sort.Slice(inboxes, func(i, j int) bool {
+		return inboxes[i].emailAddress < inboxes[j].emailAddress
+	})
+
+	set := &ExportedInboxSet{
+		Version:    ExportSetVersion,
+		ExportedAt: time.Now().UTC(),
+		Inboxes:    make([]ExportedInbox, 0, len(inboxes)),
+	}
+	for _, inbox := range inboxes {
+		set.Inboxes = append(set.Inboxes, *inbox.Export())
+	}
+	return set, nil
+}
+
+// ImportAllInboxes imports each inbox of set as [Client.ImportInbox] does.
+// An entry that fails to import is skipped and the others are still
+// imported; the returned error joins the failures, each naming its entry.
+// The returned slice holds the inboxes that were imported.
+func (c *Client) ImportAllInboxes(ctx context.Context, set *ExportedInboxSet) ([]*Inbox, error) {
+	if set == nil {
+		return nil, fmt.Errorf("exported inbox set cannot be nil")
+	}
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	if set.Version != ExportSetVersion {
+		return nil, fmt.Errorf("%w: unsupported inbox set version %d, expected %d", ErrInvalidImportData, set.Version, ExportSetVersion)
+	}
+
+	imported := make([]*Inbox, 0, len(set.Inboxes))
+	var errs []error
+	for i := range set.Inboxes {
+		data := &set.Inboxes[i]
+		inbox, err := c.ImportInbox(ctx, data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("import inbox %d (%s): %w", i, data.EmailAddress, err))
+			continue
+		}
+		imported = append(imported, inbox)
+	}
+	return imported, errors.Join(errs...)
+}
+
+// ExportAllInboxesToFile exports every inbox managed by this client to a
+// JSON file with secure permissions (0600).
+func (c *Client) ExportAllInboxesToFile(filePath string) error {
+	set, err := c.ExportAllInboxes()
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal inbox set: %w", err) //coverage:ignore
+	}
+
+	if err := os.WriteFile(filePath, jsonData, 0600); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+// ImportAllInboxesFromFile imports the inboxes of a file written by
+// [Client.ExportAllInboxesToFile]. Partial failures are reported as by
+// [Client.ImportAllInboxes].
+func (c *Client) ImportAllInboxesFromFile(ctx context.Context, filePath string) ([]*Inbox, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	var set ExportedInboxSet
+	if err := json.Unmarshal(jsonData, &set); err != nil {
+		return nil, fmt.Errorf("parse inbox set: %w", err)
+	}
+
+	return c.ImportAllInboxes(ctx, &set)
+}
+
 // InboxEvent represents an email arriving in a specific inbox.
 type InboxEvent struct {
 	Inbox *Inbox
@@ -524,6 +1100,10 @@ func (c *Client) WatchInboxes(ctx context.Context, inboxes ...*Inbox) <-chan *In
 // WatchInboxesFunc calls fn for each event from multiple inboxes until context is cancelled.
 // This is a convenience wrapper around WatchInboxes for simpler use cases.
 //
+// A panic in fn is recovered, counted in [ClientStats.CallbackPanics], and
+// reported to the [WithOnSyncError] callback as a [*CallbackPanicError];
+// subsequent events continue to be delivered.
+//
 // Example:
 //
 //	client.WatchInboxesFunc(ctx, func(event *vaultsandbox.InboxEvent) {
@@ -537,12 +1117,74 @@ func (c *Client) WatchInboxesFunc(ctx context.Context, fn func(*InboxEvent), inb
 			return
 		case event := <-events:
 			if event != nil {
-				fn(event)
+				c.invokeCallback(func() { fn(event) })
 			}
 		}
 	}
 }
 
+// WatchInboxesChan is like [Client.WatchInboxes], but events arrive in the
+// order they were delivered and both returned channels are closed when the
+// watch ends, so the events can be consumed with a plain range loop.
+//
+// The watch ends when ctx is done, when every watched inbox has been deleted
+// through this client, or when the client is closed. Closing the client
+// sends [ErrClientClosed] on the error channel, and passing a detached inbox
+// sends [ErrDetachedInbox] at once; the error channel receives at most one
+// error and is closed after the event channel. Pending events are dropped
+// once ctx is done, so cancelling ctx never leaks the goroutine behind the
+// channels. With no inboxes, both channels are closed immediately.
+//
+// Example:
+//
+//	events, errs := client.WatchInboxesChan(ctx, inbox1, inbox2)
+//	for event := range events {
+//	    fmt.Printf("Email in %s: %s\n", event.Inbox.EmailAddress(), event.Email.Subject)
+//	}
+//	if err := <-errs; err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) WatchInboxesChan(ctx context.Context, inboxes ...*Inbox) (<-chan *InboxEvent, <-chan error) {
+	events := make(chan *InboxEvent)
+	errs := make(chan error, 1)
+	for _, inbox := range inboxes {
+		if inbox.client == nil {
+			errs <- ErrDetachedInbox
+			break
+		}
+	}
+	if len(inboxes) == 0 || len(errs) > 0 {
+		close(events)
+		close(errs)
+		return events, errs
+	}
+
+	q := newWatchQueue[*InboxEvent]()
+	var remaining atomic.Int64
+	remaining.Store(int64(len(inboxes)))
+	unsubscribes := make([]func(), 0, len(inboxes))
+	for _, inbox := range inboxes {
+		unsub := c.subs.subscribeWithEnd(inbox.inboxHash, func(email *Email) {
+			if email != nil {
+				q.push(&InboxEvent{Inbox: inbox, Email: email})
+			}
+		}, func(err error) {
+			// A deleted inbox ends only its own subscription.
+			if err != nil || remaining.Add(-1) == 0 {
+				q.end(err)
+			}
+		})
+		unsubscribes = append(unsubscribes, unsub)
+	}
+	go q.run(ctx, events, errs, func() {
+		for _, unsub := range unsubscribes {
+			unsub()
+		}
+	})
+
+	return events, errs
+}
+
 // syncAllInboxes fetches emails for all tracked inboxes and notifies watchers.
 // This is called after SSE reconnection to catch any emails that arrived
 // during the reconnection window.
@@ -639,8 +1281,13 @@ func (c *Client) syncInbox(ctx context.Context, inbox *Inbox) {
 
 	// Fetch full email data only for new emails
 	for _, emailID := range newEmailIDs {
+		// Like live events, sync stops delivering once StopAndDrain is called
+		if !inbox.drain.begin() {
+			return
+		}
 		email, err := inbox.GetEmail(ctx, emailID)
 		if err != nil {
+			inbox.drain.end(nil)
 			if c.onSyncError != nil {
 				c.onSyncError(err)
 			}
@@ -651,16 +1298,36 @@ func (c *Client) syncInbox(ctx context.Context, inbox *Inbox) {
 		c.mu.Lock()
 		state = c.syncStates[inbox.inboxHash]
 		if state == nil {
-			c.mu.Unlock() //coverage:ignore
-			return        //coverage:ignore
+			c.mu.Unlock()        //coverage:ignore
+			inbox.drain.end(nil) //coverage:ignore
+			return               //coverage:ignore
 		}
 		state.seenEmails[email.ID] = struct{}{}
 		c.mu.Unlock()
 
+		// A drained email is returned by StopAndDrain instead of being delivered
+		if inbox.drain.end(email) {
+			continue
+		}
 		c.subs.notify(inbox.inboxHash, email)
 	}
 }
 
+// forgetEmails removes deleted emails from an inbox's sync state so its
+// hash matches the server's again.
+func (c *Client) forgetEmails(inboxHash string, emailIDs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state := c.syncStates[inboxHash]
+	if state == nil {
+		return
+	}
+	for _, id := range emailIDs {
+		delete(state.seenEmails, id)
+	}
+}
+
 // handleSSEEvent processes incoming SSE events from the delivery strategy.
 func (c *Client) handleSSEEvent(ctx context.Context, event *api.SSEEvent) error {
 	if event == nil {
@@ -677,14 +1344,23 @@ func (c *Client) handleSSEEvent(ctx context.Context, event *api.SSEEvent) error
 		return nil
 	}
 
+	// Refuse events once StopAndDrain has been called for the inbox
+	if !inbox.drain.begin() {
+		return nil
+	}
+
 	// Fetch and decrypt the email
 	ctx, cancel := context.WithTimeout(ctx, sseEventTimeout)
 	defer cancel()
 
 	email, err := inbox.GetEmail(ctx, event.EmailID)
 	if err != nil {
+		c.log().Warn("fetch notified email failed", "inbox", inbox.emailAddress, "email_id", event.EmailID, "error", err)
+		inbox.drain.end(nil)
 		return err
 	}
+	c.log().Debug("email received", "inbox", inbox.emailAddress, "email_id", email.ID)
+	email.eventID = event.ID
 
 	// Mark email as seen to avoid duplicate notifications on reconnection sync
 	if state != nil {
@@ -696,6 +1372,11 @@ func (c *Client) handleSSEEvent(ctx context.Context, event *api.SSEEvent) error
 		c.mu.Unlock()
 	}
 
+	// A drained email is returned by StopAndDrain instead of being delivered
+	if inbox.drain.end(email) {
+		return nil
+	}
+
 	// Notify all subscribers
 	c.subs.notify(inbox.inboxHash, email)
 
@@ -738,9 +1419,8 @@ func (c *Client) GetWebhookMetrics(ctx context.Context) (*WebhookMetrics, error)
 // Close closes the client and releases resources.
 func (c *Client) Close() error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	if c.closed {
+		c.mu.Unlock()
 		return nil
 	}
 
@@ -750,18 +1430,28 @@ func (c *Client) Close() error {
 	if c.strategyCancel != nil {
 		c.strategyCancel()
 	}
-
-	// Stop delivery strategy
-	if c.strategy != nil {
-		if err := c.strategy.Stop(); err != nil {
-			return err //coverage:ignore
-		}
-	}
+	strategy := c.strategy
+	keepAliveDone := c.keepAliveDone
 
 	// Clear inboxes and subscriptions
 	c.inboxes = make(map[string]*Inbox)
 	c.inboxesByHash = make(map[string]*Inbox)
 	c.subs.clear()
+	c.mu.Unlock()
+
+	// Stop the strategy and wait for the pinger without holding c.mu: both
+	// may be waiting on goroutines (a webhook registration's sync, a ping)
+	// that need the lock, and stopping can make network calls.
+	if strategy != nil {
+		if err := strategy.Stop(); err != nil {
+			return err //coverage:ignore
+		}
+	}
+
+	// Wait for the keepalive pinger so no request outlives Close
+	if keepAliveDone != nil {
+		<-keepAliveDone
+	}
 
 	return nil
 }
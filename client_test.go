@@ -1,14 +1,17 @@
 package vaultsandbox
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,6 +19,7 @@ import (
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/crypto"
 )
 
 func TestNew_RequiresAPIKey(t *testing.T) {
@@ -537,7 +541,7 @@ func TestCreateDeliveryStrategy_SSE(t *testing.T) {
 	apiCfg := &clientConfig{baseURL: "https://test.example.com"}
 	apiClient, _ := buildAPIClient("test-key", apiCfg)
 
-	strategy := createDeliveryStrategy(cfg, apiClient)
+	strategy := createDeliveryStrategy(cfg, apiClient, nil)
 	if strategy == nil {
 		t.Fatal("createDeliveryStrategy() returned nil")
 	}
@@ -551,7 +555,7 @@ func TestCreateDeliveryStrategy_Polling(t *testing.T) {
 	apiCfg := &clientConfig{baseURL: "https://test.example.com"}
 	apiClient, _ := buildAPIClient("test-key", apiCfg)
 
-	strategy := createDeliveryStrategy(cfg, apiClient)
+	strategy := createDeliveryStrategy(cfg, apiClient, nil)
 	if strategy == nil {
 		t.Fatal("createDeliveryStrategy() returned nil")
 	}
@@ -566,7 +570,7 @@ func TestCreateDeliveryStrategy_Default(t *testing.T) {
 	apiCfg := &clientConfig{baseURL: "https://test.example.com"}
 	apiClient, _ := buildAPIClient("test-key", apiCfg)
 
-	strategy := createDeliveryStrategy(cfg, apiClient)
+	strategy := createDeliveryStrategy(cfg, apiClient, nil)
 	if strategy == nil {
 		t.Fatal("createDeliveryStrategy() returned nil for unknown strategy")
 	}
@@ -901,6 +905,38 @@ func TestClient_CheckKey_WhenClosed(t *testing.T) {
 	}
 }
 
+func TestClient_Ping(t *testing.T) {
+	t.Parallel()
+	c := &Client{closed: true}
+	if err := c.Ping(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Ping() on closed client = %v, want ErrClientClosed", err)
+	}
+
+	for _, status := range []int{http.StatusOK, http.StatusServiceUnavailable} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		apiClient, err := api.New("test-key", api.WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("api.New() error = %v", err)
+		}
+		c := &Client{apiClient: apiClient}
+
+		err = c.Ping(context.Background())
+		if status == http.StatusOK {
+			if err != nil {
+				t.Errorf("Ping() error = %v", err)
+			}
+			continue
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Errorf("Ping() error = %v, want APIError %d", err, status)
+		}
+	}
+}
+
 func TestClient_WatchInboxesFunc_ContextCancel(t *testing.T) {
 	c := &Client{
 		subs: newSubscriptionManager(),
@@ -1585,6 +1621,306 @@ func TestClient_DeleteInbox_NonExistent(t *testing.T) {
 	}
 }
 
+func TestClient_DeleteExpiredInboxes(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/inboxes/"):
+			email := strings.TrimPrefix(r.URL.Path, "/api/inboxes/")
+			mu.Lock()
+			deleted = append(deleted, email)
+			mu.Unlock()
+			switch email {
+			case "gone@test.com":
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"error": "inbox not found"})
+			case "lapsed@test.com":
+				w.WriteHeader(http.StatusGone)
+				json.NewEncoder(w).Encode(map[string]string{"error": "inbox expired"})
+			case "broken@test.com":
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+			default:
+				w.WriteHeader(http.StatusNoContent)
+			}
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	for _, inbox := range []*Inbox{
+		{emailAddress: "expired@test.com", inboxHash: "h1", expiresAt: past, client: client},
+		{emailAddress: "gone@test.com", inboxHash: "h2", expiresAt: past, client: client},
+		{emailAddress: "lapsed@test.com", inboxHash: "h5", expiresAt: past, client: client},
+		{emailAddress: "broken@test.com", inboxHash: "h3", expiresAt: past, client: client},
+		{emailAddress: "live@test.com", inboxHash: "h4", expiresAt: future, client: client},
+	} {
+		if err := client.registerInbox(inbox); err != nil {
+			t.Fatalf("registerInbox() error = %v", err)
+		}
+	}
+
+	count, err := client.DeleteExpiredInboxes(context.Background())
+	if count != 3 {
+		t.Errorf("DeleteExpiredInboxes() count = %d, want 3", count)
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("DeleteExpiredInboxes() error = %v, want ErrUnauthorized for broken@test.com", err)
+	}
+
+	for email, want := range map[string]bool{
+		"expired@test.com": false,
+		"gone@test.com":    false,
+		"lapsed@test.com":  false,
+		"broken@test.com":  true,
+		"live@test.com":    true,
+	} {
+		if _, exists := client.GetInbox(email); exists != want {
+			t.Errorf("GetInbox(%s) exists = %v, want %v", email, exists, want)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, email := range deleted {
+		if email == "live@test.com" {
+			t.Error("DeleteExpiredInboxes deleted an inbox that has not expired")
+		}
+	}
+}
+
+// logRecord is one record captured by captureLogger.
+type logRecord struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// captureLogger is a Logger that records everything logged to it.
+type captureLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *captureLogger) log(level, msg string, kv []any) {
+	fields := make(map[string]any, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fields[kv[i].(string)] = kv[i+1]
+	}
+	l.mu.Lock()
+	l.records = append(l.records, logRecord{level: level, msg: msg, fields: fields})
+	l.mu.Unlock()
+}
+
+func (l *captureLogger) Debug(msg string, kv ...any) { l.log("debug", msg, kv) }
+func (l *captureLogger) Info(msg string, kv ...any)  { l.log("info", msg, kv) }
+func (l *captureLogger) Warn(msg string, kv ...any)  { l.log("warn", msg, kv) }
+func (l *captureLogger) Error(msg string, kv ...any) { l.log("error", msg, kv) }
+
+// find returns the first record with msg whose fields include want.
+func (l *captureLogger) find(msg string, want map[string]any) (logRecord, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+next:
+	for _, r := range l.records {
+		if r.msg != msg {
+			continue
+		}
+		for k, v := range want {
+			if r.fields[k] != v {
+				continue next
+			}
+		}
+		return r, true
+	}
+	return logRecord{}, false
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodPost:
+			mockCreateInboxResponse(w)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	logger := &captureLogger{}
+	client, err := New("test-api-key", WithBaseURL(server.URL), WithDeliveryStrategy(StrategyPolling), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CreateInbox(context.Background()); err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+
+	record, ok := logger.find("request completed", map[string]any{
+		"method": http.MethodPost,
+		"path":   "/api/inboxes",
+		"status": http.StatusOK,
+	})
+	if !ok {
+		t.Fatal("no request log for POST /api/inboxes")
+	}
+	if record.level != "debug" {
+		t.Errorf("level = %s, want debug", record.level)
+	}
+	if record.fields["attempt"] != 1 {
+		t.Errorf("attempt = %v, want 1", record.fields["attempt"])
+	}
+	if _, ok := record.fields["duration"].(time.Duration); !ok {
+		t.Errorf("duration = %v, want a time.Duration", record.fields["duration"])
+	}
+	if _, ok := logger.find("polling started", nil); !ok {
+		t.Error("no log for the delivery strategy starting")
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestClient_WithSlog(t *testing.T) {
+	t.Parallel()
+	var createCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodPost:
+			if createCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			mockCreateInboxResponse(w)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	var buf lockedBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := New("test-api-key", WithBaseURL(server.URL), WithDeliveryStrategy(StrategyPolling), WithSlog(logger))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CreateInbox(context.Background()); err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+
+	type record struct {
+		Level   string `json:"level"`
+		Msg     string `json:"msg"`
+		Method  string `json:"method"`
+		Path    string `json:"path"`
+		Status  int    `json:"status"`
+		Attempt int    `json:"attempt"`
+	}
+	var got []record
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var r record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("log line is not JSON: %q: %v", line, err)
+		}
+		if r.Path == "/api/inboxes" {
+			got = append(got, r)
+		}
+	}
+
+	want := []record{
+		{Level: "DEBUG", Msg: "request completed", Method: "POST", Path: "/api/inboxes", Status: 503, Attempt: 1},
+		{Level: "DEBUG", Msg: "retrying request", Method: "POST", Path: "/api/inboxes", Status: 503, Attempt: 2},
+		{Level: "DEBUG", Msg: "request completed", Method: "POST", Path: "/api/inboxes", Status: 200, Attempt: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records for /api/inboxes, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWithSlog_Nil(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{logger: &captureLogger{}}
+	WithSlog(nil)(cfg)
+	if cfg.logger != nil {
+		t.Errorf("WithSlog(nil) logger = %v, want nil", cfg.logger)
+	}
+}
+
 // TestClient_DeleteAllInboxes tests the DeleteAllInboxes method
 func TestClient_DeleteAllInboxes(t *testing.T) {
 	// Create a mock server
@@ -1644,6 +1980,73 @@ func TestClient_DeleteAllInboxes(t *testing.T) {
 	}
 }
 
+func TestClient_ListInboxes(t *testing.T) {
+	t.Parallel()
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"inboxes": []map[string]interface{}{
+					{"emailAddress": "orphan@test.com", "inboxHash": "h1", "expiresAt": expiresAt, "encrypted": true},
+					{"emailAddress": "plain@test.com", "inboxHash": "h2", "expiresAt": expiresAt, "encrypted": false},
+				},
+			})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	summaries, err := client.ListInboxes(context.Background())
+	if err != nil {
+		t.Fatalf("ListInboxes() error = %v", err)
+	}
+	want := []InboxSummary{
+		{EmailAddress: "orphan@test.com", InboxHash: "h1", ExpiresAt: expiresAt, Encrypted: true},
+		{EmailAddress: "plain@test.com", InboxHash: "h2", ExpiresAt: expiresAt},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("ListInboxes() returned %d inboxes, want %d", len(summaries), len(want))
+	}
+	for i := range want {
+		got := summaries[i]
+		if got.EmailAddress != want[i].EmailAddress || got.InboxHash != want[i].InboxHash ||
+			!got.ExpiresAt.Equal(want[i].ExpiresAt) || got.Encrypted != want[i].Encrypted {
+			t.Errorf("summaries[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// Listing does not start tracking the inboxes.
+	if n := len(client.Inboxes()); n != 0 {
+		t.Errorf("Inboxes() = %d after ListInboxes, want 0", n)
+	}
+
+	client.Close()
+	if _, err := client.ListInboxes(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("ListInboxes() after Close error = %v, want ErrClientClosed", err)
+	}
+}
+
 // TestClient_ServerInfo tests the ServerInfo method
 func TestClient_ServerInfo(t *testing.T) {
 	// Create a mock server
@@ -1689,6 +2092,146 @@ func TestClient_ServerInfo(t *testing.T) {
 	}
 }
 
+func TestClient_ServerInfo_Capabilities(t *testing.T) {
+	t.Parallel()
+	sseDisabled := false
+	tests := []struct {
+		name           string
+		info           map[string]any
+		wantSSE        *bool
+		wantMaxAttach  int64
+		wantAlgorithms []string
+	}{
+		{
+			name: "advertised",
+			info: map[string]any{
+				"allowedDomains":      []string{"example.com"},
+				"sseEnabled":          false,
+				"maxAttachmentSize":   10 << 20,
+				"supportedAlgorithms": []string{"ML-KEM-768", "ML-DSA-65", "AES-256-GCM"},
+			},
+			wantSSE:        &sseDisabled,
+			wantMaxAttach:  10 << 20,
+			wantAlgorithms: []string{"ML-KEM-768", "ML-DSA-65", "AES-256-GCM"},
+		},
+		{
+			name: "older server",
+			info: map[string]any{"allowedDomains": []string{"example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/check-key":
+					json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+				case "/api/server-info":
+					json.NewEncoder(w).Encode(tt.info)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client, err := New("test-api-key", WithBaseURL(server.URL), WithDeliveryStrategy(StrategyPolling))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer client.Close()
+
+			info := client.ServerInfo()
+			if (info.SSEEnabled == nil) != (tt.wantSSE == nil) ||
+				(info.SSEEnabled != nil && *info.SSEEnabled != *tt.wantSSE) {
+				t.Errorf("SSEEnabled = %v, want %v", info.SSEEnabled, tt.wantSSE)
+			}
+			if info.MaxAttachmentSize != tt.wantMaxAttach {
+				t.Errorf("MaxAttachmentSize = %d, want %d", info.MaxAttachmentSize, tt.wantMaxAttach)
+			}
+			if !slices.Equal(info.SupportedAlgorithms, tt.wantAlgorithms) {
+				t.Errorf("SupportedAlgorithms = %v, want %v", info.SupportedAlgorithms, tt.wantAlgorithms)
+			}
+
+			// The returned value is a copy.
+			if info.SSEEnabled != nil {
+				*info.SSEEnabled = !*info.SSEEnabled
+				if *client.ServerInfo().SSEEnabled == *info.SSEEnabled {
+					t.Error("modifying ServerInfo().SSEEnabled changed the client's copy")
+				}
+			}
+		})
+	}
+}
+
+func TestResolveDeliveryStrategy(t *testing.T) {
+	t.Parallel()
+	enabled, disabled := true, false
+	tests := []struct {
+		name     string
+		explicit DeliveryStrategy
+		info     *api.ServerInfo
+		want     DeliveryStrategy
+	}{
+		{name: "SSE disabled", info: &api.ServerInfo{SSEEnabled: &disabled}, want: StrategyPolling},
+		{name: "SSE enabled", info: &api.ServerInfo{SSEEnabled: &enabled}, want: StrategySSE},
+		{name: "SSE unknown", info: &api.ServerInfo{}, want: StrategySSE},
+		{name: "no server info", info: nil, want: StrategySSE},
+		{name: "explicit SSE kept", explicit: StrategySSE, info: &api.ServerInfo{SSEEnabled: &disabled}, want: StrategySSE},
+		{name: "explicit webhook kept", explicit: StrategyWebhook, info: &api.ServerInfo{SSEEnabled: &disabled}, want: StrategyWebhook},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &clientConfig{deliveryStrategy: tt.explicit}
+			cfg.resolveDeliveryStrategy(tt.info)
+			if cfg.deliveryStrategy != tt.want {
+				t.Errorf("deliveryStrategy = %q, want %q", cfg.deliveryStrategy, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_DefaultStrategyFollowsSSEEnabled(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		info map[string]any
+		want string
+	}{
+		{name: "SSE disabled", info: map[string]any{"sseEnabled": false}, want: "polling"},
+		{name: "SSE enabled", info: map[string]any{"sseEnabled": true}, want: "sse"},
+		{name: "SSE unknown", info: map[string]any{}, want: "sse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/check-key":
+					json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+				case "/api/server-info":
+					json.NewEncoder(w).Encode(tt.info)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client, err := New("test-api-key", WithBaseURL(server.URL))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer client.Close()
+
+			if got := client.strategy.Name(); got != tt.want {
+				t.Errorf("strategy = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestClient_ExportInboxToFile_Success tests successful export to file
 func TestClient_ExportInboxToFile_Success(t *testing.T) {
 	// Create a mock server
@@ -1852,6 +2395,51 @@ func TestClient_CreateInbox_TTLBelowMinimum(t *testing.T) {
 	}
 }
 
+// TestClient_CreateInbox_UnsupportedSuite tests that a suite the client cannot
+// decrypt is rejected before the inbox is created.
+func TestClient_CreateInbox_UnsupportedSuite(t *testing.T) {
+	var created atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTTL":         3600,
+				"defaultTTL":     300,
+			})
+
+		case r.URL.Path == "/api/inboxes":
+			created.Store(true)
+			http.Error(w, "unexpected", http.StatusInternalServerError)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	suite := DefaultAlgorithmSuite()
+	suite.KDF = "HKDF-SHA-256"
+	_, err = client.CreateInbox(context.Background(), WithRequireSuite(suite))
+	if !errors.Is(err, ErrUnsupportedSuite) {
+		t.Errorf("CreateInbox() error = %v, want ErrUnsupportedSuite", err)
+	}
+	if created.Load() {
+		t.Error("CreateInbox() reached the server with an unsupported suite")
+	}
+}
+
 // TestClient_CreateInbox_TTLAboveServerMax tests TTL validation against server max
 func TestClient_CreateInbox_TTLAboveServerMax(t *testing.T) {
 	// Create a mock server with low maxTTL
@@ -2441,6 +3029,252 @@ func TestClient_ImportInbox_Success(t *testing.T) {
 	}
 }
 
+func TestInbox_ExportEncrypted(t *testing.T) {
+	t.Parallel()
+	inbox, _, err := newMockInbox("sealed@test.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	plain := inbox.Export()
+
+	if _, err := inbox.ExportEncrypted(""); err == nil {
+		t.Error("ExportEncrypted(\"\") should return error")
+	}
+
+	sealed, err := inbox.ExportEncrypted("correct horse")
+	if err != nil {
+		t.Fatalf("ExportEncrypted() error = %v", err)
+	}
+	data, err := json.Marshal(sealed)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), plain.SecretKey) {
+		t.Error("encrypted export contains the plain secret key")
+	}
+
+	var parsed EncryptedExport
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	opened, err := parsed.Decrypt("correct horse")
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if opened.EmailAddress != plain.EmailAddress || opened.InboxHash != plain.InboxHash ||
+		opened.SecretKey != plain.SecretKey || opened.ServerSigPk != plain.ServerSigPk {
+		t.Errorf("Decrypt() = %+v, want %+v", opened, plain)
+	}
+
+	if _, err := parsed.Decrypt("battery staple"); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("Decrypt(wrong passphrase) error = %v, want ErrDecryptionFailed", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(e *EncryptedExport)
+		want   error
+	}{
+		{"unsupported version", func(e *EncryptedExport) { e.Version = 2 }, ErrInvalidImportData},
+		{"unsupported kdf", func(e *EncryptedExport) { e.KDF = "scrypt" }, ErrInvalidImportData},
+		{"excessive memory", func(e *EncryptedExport) { e.Memory = maxArgon2Memory + 1 }, ErrInvalidImportData},
+		{"excessive time", func(e *EncryptedExport) { e.Time = maxArgon2Time + 1 }, ErrInvalidImportData},
+		{"invalid salt", func(e *EncryptedExport) { e.Salt = "!!!" }, ErrInvalidImportData},
+		{"mismatched address", func(e *EncryptedExport) { e.EmailAddress = "other@test.com" }, ErrInvalidImportData},
+		{"tampered ciphertext", func(e *EncryptedExport) {
+			raw, _ := crypto.FromBase64URL(e.Ciphertext)
+			raw[len(raw)-1] ^= 0xff
+			e.Ciphertext = crypto.ToBase64URL(raw)
+		}, ErrDecryptionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			modified := parsed
+			tt.modify(&modified)
+			if _, err := modified.Decrypt("correct horse"); !errors.Is(err, tt.want) {
+				t.Errorf("Decrypt() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ImportEncryptedInbox(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case strings.HasSuffix(r.URL.Path, "/sync"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"emailsHash": "47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU",
+				"emailCount": 0,
+			})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	inbox, _, err := newMockInbox("sealed@test.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	sealed, err := inbox.ExportEncrypted("correct horse")
+	if err != nil {
+		t.Fatalf("ExportEncrypted() error = %v", err)
+	}
+
+	client, err := New("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if _, err := client.ImportEncryptedInbox(ctx, nil, "correct horse"); err == nil {
+		t.Error("ImportEncryptedInbox(nil) should return error")
+	}
+	if _, err := client.ImportEncryptedInbox(ctx, sealed, "wrong"); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("ImportEncryptedInbox(wrong passphrase) error = %v, want ErrDecryptionFailed", err)
+	}
+	if _, exists := client.GetInbox("sealed@test.com"); exists {
+		t.Error("inbox should not be tracked after a failed import")
+	}
+
+	imported, err := client.ImportEncryptedInbox(ctx, sealed, "correct horse")
+	if err != nil {
+		t.Fatalf("ImportEncryptedInbox() error = %v", err)
+	}
+	if imported.InboxHash() != inbox.InboxHash() {
+		t.Errorf("imported hash = %q, want %q", imported.InboxHash(), inbox.InboxHash())
+	}
+	if _, exists := client.GetInbox("sealed@test.com"); !exists {
+		t.Error("imported inbox should be tracked by client")
+	}
+}
+
+func TestClient_ExportImportAllInboxes(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case strings.HasSuffix(r.URL.Path, "/sync"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"emailsHash": "47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU",
+				"emailCount": 0,
+			})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	newTestClient := func() *Client {
+		t.Helper()
+		client, err := New("test-api-key", WithBaseURL(server.URL))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+	ctx := context.Background()
+
+	source := newTestClient()
+	for _, email := range []string{"c@test.com", "a@test.com", "b@test.com"} {
+		inbox, _, err := newMockInbox(email)
+		if err != nil {
+			t.Fatalf("newMockInbox() error = %v", err)
+		}
+		if err := source.registerInbox(inbox); err != nil {
+			t.Fatalf("registerInbox() error = %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "inboxes.json")
+	if err := source.ExportAllInboxesToFile(path); err != nil {
+		t.Fatalf("ExportAllInboxesToFile() error = %v", err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	} else if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("file permissions = %o, want 0600", perm)
+	}
+
+	restored, err := newTestClient().ImportAllInboxesFromFile(ctx, path)
+	if err != nil {
+		t.Fatalf("ImportAllInboxesFromFile() error = %v", err)
+	}
+	var got []string
+	for _, inbox := range restored {
+		got = append(got, inbox.EmailAddress())
+	}
+	if want := []string{"a@test.com", "b@test.com", "c@test.com"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("imported inboxes = %v, want %v", got, want)
+	}
+
+	t.Run("partial failure", func(t *testing.T) {
+		t.Parallel()
+		set, err := source.ExportAllInboxes()
+		if err != nil {
+			t.Fatalf("ExportAllInboxes() error = %v", err)
+		}
+		set.Inboxes[1].SecretKey = "invalid"
+
+		target := newTestClient()
+		imported, err := target.ImportAllInboxes(ctx, set)
+		if !errors.Is(err, ErrInvalidImportData) {
+			t.Errorf("ImportAllInboxes() error = %v, want ErrInvalidImportData", err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "b@test.com") {
+			t.Errorf("error %q does not name the failed inbox", err)
+		}
+		if len(imported) != 2 {
+			t.Fatalf("imported %d inboxes, want 2", len(imported))
+		}
+		if _, exists := target.GetInbox("b@test.com"); exists {
+			t.Error("inbox with an invalid secret key should not be imported")
+		}
+		for _, email := range []string{"a@test.com", "c@test.com"} {
+			if _, exists := target.GetInbox(email); !exists {
+				t.Errorf("GetInbox(%s) should exist after partial import", email)
+			}
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+		_, err := newTestClient().ImportAllInboxes(ctx, &ExportedInboxSet{Version: 2})
+		if !errors.Is(err, ErrInvalidImportData) {
+			t.Errorf("ImportAllInboxes() error = %v, want ErrInvalidImportData", err)
+		}
+	})
+}
+
 // TestClient_ImportInbox_APIVerifyError tests import when API verify fails
 func TestClient_ImportInbox_APIVerifyError(t *testing.T) {
 	// Create a mock server that fails on sync (verify)
@@ -2805,3 +3639,207 @@ func TestClient_SyncInbox_NewEmailsFoundGetEmailError(t *testing.T) {
 	// Log whether GetEmail was reached for debugging
 	t.Logf("GetEmail endpoint called: %v (may be false if metadata decryption fails first)", getEmailCalled.Load())
 }
+
+func TestNew_ServerInfoOptional(t *testing.T) {
+	t.Parallel()
+	var createdTTL atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodPost:
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if ttl, ok := body["ttl"].(float64); ok {
+				createdTTL.Store(int64(ttl))
+			}
+			mockCreateInboxResponse(w)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("required by default", func(t *testing.T) {
+		t.Parallel()
+		_, err := New("test-api-key", WithBaseURL(server.URL))
+		if err == nil {
+			t.Fatal("New() should fail when server info is unavailable")
+		}
+		if !strings.Contains(err.Error(), "fetch server info") {
+			t.Errorf("error = %v, want fetch server info error", err)
+		}
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		t.Parallel()
+		var reported atomic.Int32
+		logger := &captureLogger{}
+		client, err := New("test-api-key",
+			WithBaseURL(server.URL),
+			WithDeliveryStrategy(StrategyPolling),
+			WithServerInfoOptional(),
+			WithOnSyncError(func(error) { reported.Add(1) }),
+			WithLogger(logger),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		defer client.Close()
+
+		if info := client.ServerInfo(); info != nil {
+			t.Errorf("ServerInfo() = %+v, want nil", info)
+		}
+		if reported.Load() != 1 {
+			t.Errorf("onSyncError called %d times, want 1", reported.Load())
+		}
+		if r, ok := logger.find("fetch server info failed, continuing without it", nil); !ok || r.level != "warn" {
+			t.Errorf("server info failure logged as %+v, %v; want a warn record", r, ok)
+		}
+
+		// TTL above any server maximum is passed through unvalidated.
+		if _, err := client.CreateInbox(context.Background(), WithTTL(30*24*time.Hour)); err != nil {
+			t.Fatalf("CreateInbox() error = %v", err)
+		}
+		if got := createdTTL.Load(); got != int64((30 * 24 * time.Hour).Seconds()) {
+			t.Errorf("sent ttl = %d, want %d", got, int64((30 * 24 * time.Hour).Seconds()))
+		}
+	})
+}
+
+func TestCreateInboxWithRetry_Collisions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		address      string
+		collisions   int32
+		status       int
+		retries      int
+		wantAttempts int32
+		wantErr      bool
+		wantExplicit bool
+	}{
+		{name: "random retried until free", collisions: 2, status: http.StatusConflict, retries: 2, wantAttempts: 3},
+		{name: "random retries exhausted", collisions: 5, status: http.StatusConflict, retries: 1, wantAttempts: 2, wantErr: true},
+		{name: "no retry by default", collisions: 1, status: http.StatusConflict, wantAttempts: 1, wantErr: true},
+		{name: "domain-only address is random", address: "test.com", collisions: 1, status: http.StatusConflict, retries: 1, wantAttempts: 2},
+		{name: "explicit address not retried", address: "taken@test.com", collisions: 1, status: http.StatusConflict, retries: 3, wantAttempts: 1, wantErr: true, wantExplicit: true},
+		{name: "other errors not retried", collisions: 1, status: http.StatusBadRequest, retries: 3, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if attempts.Add(1) <= tt.collisions {
+					w.WriteHeader(tt.status)
+					json.NewEncoder(w).Encode(map[string]string{"error": "rejected"})
+					return
+				}
+				mockCreateInboxResponse(w)
+			}))
+			t.Cleanup(server.Close)
+
+			apiClient, err := api.New("test-key", api.WithBaseURL(server.URL), api.WithRetries(0))
+			if err != nil {
+				t.Fatalf("api.New() error = %v", err)
+			}
+			c := &Client{apiClient: apiClient}
+
+			_, err = c.createInboxWithRetry(context.Background(),
+				&api.CreateInboxParams{EmailAddress: tt.address, Encryption: "plain"}, tt.retries)
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil || tt.status != http.StatusConflict {
+				return
+			}
+			var collision *InboxCollisionError
+			if !errors.As(err, &collision) {
+				t.Fatalf("error = %T, want *InboxCollisionError", err)
+			}
+			if !errors.Is(err, ErrInboxAlreadyExists) {
+				t.Error("errors.Is(err, ErrInboxAlreadyExists) = false")
+			}
+			if collision.Explicit != tt.wantExplicit || collision.Attempts != int(tt.wantAttempts) {
+				t.Errorf("got Explicit=%v Attempts=%d, want %v %d", collision.Explicit, collision.Attempts, tt.wantExplicit, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// recordingTransport records the path of every request it forwards.
+type recordingTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.paths = append(rt.paths, req.URL.Path)
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (rt *recordingTransport) saw(path string) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, p := range rt.paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNew_WithHTTPClient_UsesTransport(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{"allowedDomains": []string{"test.com"}, "maxTtl": 86400})
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodPost:
+			mockCreateInboxResponse(w)
+		case r.URL.Path == "/api/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	rt := &recordingTransport{}
+	client, err := New("test-api-key", WithBaseURL(server.URL), WithHTTPClient(&http.Client{Transport: rt}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CreateInbox(context.Background()); err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+
+	for _, path := range []string{"/api/check-key", "/api/server-info", "/api/inboxes"} {
+		if !rt.saw(path) {
+			t.Errorf("request to %s did not use the custom transport", path)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !rt.saw("/api/events") {
+		if time.Now().After(deadline) {
+			t.Fatal("SSE stream did not use the custom transport")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
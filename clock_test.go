@@ -0,0 +1,152 @@
+package vaultsandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
+)
+
+// withClock sets the client's time source, so tests can drive expiry,
+// wait timeouts, and backoff with a fake clock instead of sleeping.
+func withClock(c clock.Clock) Option {
+	return func(cfg *clientConfig) {
+		cfg.clock = c
+	}
+}
+
+var testEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/check-key":
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			w.Write([]byte(`{"allowedDomains":["test.com"],"maxTtl":3600,"defaultTtl":300}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	fake := clock.NewFake(testEpoch)
+	client, err := New("test-key", WithBaseURL(server.URL), withClock(fake))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if got := client.clock(); got != fake {
+		t.Errorf("client.clock() = %v, want the fake clock", got)
+	}
+}
+
+func TestClient_ClockDefaultsToReal(t *testing.T) {
+	t.Parallel()
+	var nilClient *Client
+	if _, ok := nilClient.clock().(clock.Real); !ok {
+		t.Error("nil client should use the real clock")
+	}
+	if _, ok := (&Client{}).clock().(clock.Real); !ok {
+		t.Error("client without a clock should use the real clock")
+	}
+}
+
+func TestInbox_IsExpired_FakeClock(t *testing.T) {
+	t.Parallel()
+	fake := clock.NewFake(testEpoch)
+	inbox := &Inbox{
+		expiresAt: testEpoch.Add(time.Hour),
+		client:    &Client{clk: fake},
+	}
+
+	if inbox.IsExpired() {
+		t.Fatal("IsExpired() = true before the TTL elapsed")
+	}
+	fake.Advance(time.Hour)
+	if inbox.IsExpired() {
+		t.Fatal("IsExpired() = true exactly at the expiry time")
+	}
+	fake.Advance(time.Second)
+	if !inbox.IsExpired() {
+		t.Fatal("IsExpired() = false after the TTL elapsed")
+	}
+}
+
+func TestWaitForEmail_TimeoutOnFakeClock(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{})
+	})
+	fake := clock.NewFake(testEpoch)
+	inbox.client.clk = fake
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := inbox.WaitForEmail(context.Background(), WithWaitTimeout(time.Hour))
+		done <- err
+	}()
+
+	fake.BlockUntil(1)
+	select {
+	case err := <-done:
+		t.Fatalf("WaitForEmail() returned before the timeout: %v", err)
+	default:
+	}
+	fake.Advance(time.Hour)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("WaitForEmail() error = %v, want DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForEmail() did not return after the fake clock passed the timeout")
+	}
+}
+
+func TestWaitUntilEmpty_PollsOnFakeClock(t *testing.T) {
+	t.Parallel()
+	var polls int
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		count := 1
+		if polls >= 3 {
+			count = 0
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"emailCount": count, "emailsHash": "h"})
+	})
+	fake := clock.NewFake(testEpoch)
+	inbox.client.clk = fake
+	inbox.client.pollingConfig = PollingConfig{InitialInterval: time.Minute, MaxBackoff: time.Minute, BackoffMultiplier: 1}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := inbox.WaitUntilEmpty(context.Background(), time.Hour)
+		done <- err
+	}()
+
+	// One waiter for the timeout, one for the poll interval.
+	for range 2 {
+		fake.BlockUntil(2)
+		fake.Advance(time.Minute)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WaitUntilEmpty() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitUntilEmpty() did not return")
+	}
+}
@@ -11,13 +11,9 @@ import (
 	vaultsandbox "github.com/vaultsandbox/client-go"
 )
 
-// ClientInterface defines the client operations used by testhelper.
-// This allows for easy mocking in tests.
-type ClientInterface interface {
-	CreateInbox(ctx context.Context, opts ...vaultsandbox.InboxOption) (*vaultsandbox.Inbox, error)
-	ImportInbox(ctx context.Context, data *vaultsandbox.ExportedInbox) (*vaultsandbox.Inbox, error)
-	DeleteInbox(ctx context.Context, emailAddress string) error
-}
+// Client is the client interface used by testhelper, so that tests can
+// replace the client with a mock.
+type Client = vaultsandbox.ClientAPI[*vaultsandbox.Inbox]
 
 // Config holds the I/O configuration for the testhelper commands.
 type Config struct {
@@ -36,7 +32,7 @@ func DefaultConfig() *Config {
 }
 
 // clientFactory creates a vaultsandbox client. Can be replaced in tests.
-var clientFactory = func() (ClientInterface, error) {
+var clientFactory = func() (Client, error) {
 	return vaultsandbox.New(
 		os.Getenv("VAULTSANDBOX_API_KEY"),
 		vaultsandbox.WithBaseURL(os.Getenv("VAULTSANDBOX_URL")),
@@ -73,7 +69,7 @@ func run(args []string, cfg *Config) error {
 	}
 }
 
-func runCreateInbox(ctx context.Context, client ClientInterface, cfg *Config) error {
+func runCreateInbox(ctx context.Context, client Client, cfg *Config) error {
 	inbox, err := client.CreateInbox(ctx)
 	if err != nil {
 		return fmt.Errorf("create inbox: %w", err)
@@ -86,7 +82,7 @@ func runCreateInbox(ctx context.Context, client ClientInterface, cfg *Config) er
 	return nil
 }
 
-func runImportInbox(ctx context.Context, client ClientInterface, cfg *Config) error {
+func runImportInbox(ctx context.Context, client Client, cfg *Config) error {
 	data, err := io.ReadAll(cfg.Stdin)
 	if err != nil {
 		return fmt.Errorf("read stdin: %w", err)
@@ -106,49 +102,20 @@ func runImportInbox(ctx context.Context, client ClientInterface, cfg *Config) er
 	return nil
 }
 
-type EmailOutput struct {
-	ID          string             `json:"id"`
-	Subject     string             `json:"subject"`
-	From        string             `json:"from"`
-	To          []string           `json:"to"`
-	Text        string             `json:"text"`
-	HTML        string             `json:"html,omitempty"`
-	Attachments []AttachmentOutput `json:"attachments,omitempty"`
-	ReceivedAt  string             `json:"receivedAt"`
-}
-
-type AttachmentOutput struct {
-	Filename    string `json:"filename"`
-	ContentType string `json:"contentType"`
-	Size        int    `json:"size"`
-}
-
-// convertEmails converts vaultsandbox.Email slice to EmailOutput slice.
-func convertEmails(emails []*vaultsandbox.Email) []EmailOutput {
-	output := make([]EmailOutput, 0, len(emails))
+// outputEmails prepares emails for output in their canonical JSON form (see
+// [vaultsandbox.Email.MarshalJSON]). Attachment content is excluded; each
+// attachment keeps its filename, content type and size.
+func outputEmails(emails []*vaultsandbox.Email) []*vaultsandbox.Email {
+	output := make([]*vaultsandbox.Email, 0, len(emails))
 	for _, email := range emails {
-		e := EmailOutput{
-			ID:         email.ID,
-			Subject:    email.Subject,
-			From:       email.From,
-			To:         email.To,
-			Text:       email.Text,
-			HTML:       email.HTML,
-			ReceivedAt: email.ReceivedAt.Format(time.RFC3339),
-		}
-		for _, att := range email.Attachments {
-			e.Attachments = append(e.Attachments, AttachmentOutput{
-				Filename:    att.Filename,
-				ContentType: att.ContentType,
-				Size:        len(att.Content),
-			})
-		}
-		output = append(output, e)
+		e := *email
+		e.ExcludeAttachmentContent = true
+		output = append(output, &e)
 	}
 	return output
 }
 
-func runReadEmails(ctx context.Context, client ClientInterface, cfg *Config) error {
+func runReadEmails(ctx context.Context, client Client, cfg *Config) error {
 	data, err := io.ReadAll(cfg.Stdin)
 	if err != nil {
 		return fmt.Errorf("read stdin: %w", err)
@@ -170,9 +137,9 @@ func runReadEmails(ctx context.Context, client ClientInterface, cfg *Config) err
 	}
 
 	output := struct {
-		Emails []EmailOutput `json:"emails"`
+		Emails []*vaultsandbox.Email `json:"emails"`
 	}{
-		Emails: convertEmails(emails),
+		Emails: outputEmails(emails),
 	}
 
 	if err := json.NewEncoder(cfg.Stdout).Encode(output); err != nil {
@@ -181,7 +148,7 @@ func runReadEmails(ctx context.Context, client ClientInterface, cfg *Config) err
 	return nil
 }
 
-func runCleanup(ctx context.Context, client ClientInterface, cfg *Config, address string) error {
+func runCleanup(ctx context.Context, client Client, cfg *Config, address string) error {
 	if err := client.DeleteInbox(ctx, address); err != nil {
 		return fmt.Errorf("delete inbox: %w", err)
 	}
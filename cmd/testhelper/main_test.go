@@ -36,17 +36,57 @@ func TestDefaultConfig(t *testing.T) {
 	}
 }
 
+// EmailOutput is the part of the read-emails output that cross-SDK tests
+// read: the canonical email JSON without attachment content.
+type EmailOutput struct {
+	ID          string             `json:"id"`
+	Subject     string             `json:"subject"`
+	From        string             `json:"from"`
+	To          []string           `json:"to"`
+	Text        string             `json:"text"`
+	HTML        string             `json:"html,omitempty"`
+	Attachments []AttachmentOutput `json:"attachments,omitempty"`
+	ReceivedAt  string             `json:"receivedAt"`
+}
+
+// AttachmentOutput is the attachment part of EmailOutput.
+type AttachmentOutput struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+// marshalOutput encodes emails as read-emails does.
+func marshalOutput(t *testing.T, emails []*vaultsandbox.Email) []byte {
+	t.Helper()
+	data, err := json.Marshal(outputEmails(emails))
+	if err != nil {
+		t.Fatalf("json.Marshal(outputEmails) error = %v", err)
+	}
+	return data
+}
+
+// decodeOutput encodes emails as read-emails does and decodes the result.
+func decodeOutput(t *testing.T, emails []*vaultsandbox.Email) []EmailOutput {
+	t.Helper()
+	var output []EmailOutput
+	if err := json.Unmarshal(marshalOutput(t, emails), &output); err != nil {
+		t.Fatalf("json.Unmarshal(output) error = %v", err)
+	}
+	return output
+}
+
 func TestEmailOutput_JSONMarshal(t *testing.T) {
 	receivedAt := time.Now().Round(time.Second)
-	email := EmailOutput{
+	email := &vaultsandbox.Email{
 		ID:         "email-123",
 		Subject:    "Test Subject",
 		From:       "sender@example.com",
 		To:         []string{"recipient@example.com"},
 		Text:       "Hello, World!",
 		HTML:       "<p>Hello, World!</p>",
-		ReceivedAt: receivedAt.Format(time.RFC3339),
-		Attachments: []AttachmentOutput{
+		ReceivedAt: receivedAt,
+		Attachments: []vaultsandbox.Attachment{
 			{
 				Filename:    "file.txt",
 				ContentType: "text/plain",
@@ -55,15 +95,7 @@ func TestEmailOutput_JSONMarshal(t *testing.T) {
 		},
 	}
 
-	data, err := json.Marshal(email)
-	if err != nil {
-		t.Fatalf("json.Marshal(EmailOutput) error = %v", err)
-	}
-
-	var parsed EmailOutput
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("json.Unmarshal(EmailOutput) error = %v", err)
-	}
+	parsed := decodeOutput(t, []*vaultsandbox.Email{email})[0]
 
 	if parsed.ID != email.ID {
 		t.Errorf("ID = %q, want %q", parsed.ID, email.ID)
@@ -83,8 +115,8 @@ func TestEmailOutput_JSONMarshal(t *testing.T) {
 	if parsed.HTML != email.HTML {
 		t.Errorf("HTML = %q, want %q", parsed.HTML, email.HTML)
 	}
-	if parsed.ReceivedAt != email.ReceivedAt {
-		t.Errorf("ReceivedAt = %q, want %q", parsed.ReceivedAt, email.ReceivedAt)
+	if want := receivedAt.Format(time.RFC3339); parsed.ReceivedAt != want {
+		t.Errorf("ReceivedAt = %q, want %q", parsed.ReceivedAt, want)
 	}
 	if len(parsed.Attachments) != 1 {
 		t.Fatalf("Attachments len = %d, want 1", len(parsed.Attachments))
@@ -95,22 +127,17 @@ func TestEmailOutput_JSONMarshal(t *testing.T) {
 }
 
 func TestEmailOutput_JSONOmitEmpty(t *testing.T) {
-	email := EmailOutput{
+	email := &vaultsandbox.Email{
 		ID:         "email-123",
 		Subject:    "Test",
 		From:       "sender@example.com",
 		To:         []string{"recipient@example.com"},
 		Text:       "Hello",
-		ReceivedAt: time.Now().Format(time.RFC3339),
+		ReceivedAt: time.Now(),
 		// HTML and Attachments intentionally empty
 	}
 
-	data, err := json.Marshal(email)
-	if err != nil {
-		t.Fatalf("json.Marshal error = %v", err)
-	}
-
-	jsonStr := string(data)
+	jsonStr := string(marshalOutput(t, []*vaultsandbox.Email{email}))
 
 	// HTML should be omitted when empty
 	if strings.Contains(jsonStr, `"html":""`) {
@@ -124,21 +151,18 @@ func TestEmailOutput_JSONOmitEmpty(t *testing.T) {
 }
 
 func TestAttachmentOutput_JSONMarshal(t *testing.T) {
-	att := AttachmentOutput{
+	att := vaultsandbox.Attachment{
 		Filename:    "document.pdf",
 		ContentType: "application/pdf",
 		Size:        1024,
+		Content:     []byte("%PDF"),
 	}
+	emails := []*vaultsandbox.Email{{ID: "email-1", Attachments: []vaultsandbox.Attachment{att}}}
 
-	data, err := json.Marshal(att)
-	if err != nil {
-		t.Fatalf("json.Marshal(AttachmentOutput) error = %v", err)
-	}
-
-	var parsed AttachmentOutput
-	if err := json.Unmarshal(data, &parsed); err != nil {
-		t.Fatalf("json.Unmarshal(AttachmentOutput) error = %v", err)
+	if data := marshalOutput(t, emails); strings.Contains(string(data), `"content"`) {
+		t.Errorf("attachment content should be excluded: %s", data)
 	}
+	parsed := decodeOutput(t, emails)[0].Attachments[0]
 
 	if parsed.Filename != att.Filename {
 		t.Errorf("Filename = %q, want %q", parsed.Filename, att.Filename)
@@ -149,28 +173,26 @@ func TestAttachmentOutput_JSONMarshal(t *testing.T) {
 	if parsed.Size != att.Size {
 		t.Errorf("Size = %d, want %d", parsed.Size, att.Size)
 	}
+	if emails[0].Attachments[0].Content == nil {
+		t.Error("outputEmails must not modify the emails")
+	}
 }
 
 func TestEmailOutput_JSONFieldNames(t *testing.T) {
-	email := EmailOutput{
+	email := &vaultsandbox.Email{
 		ID:         "id",
 		Subject:    "subj",
 		From:       "from",
 		To:         []string{"to"},
 		Text:       "text",
 		HTML:       "html",
-		ReceivedAt: "2024-01-01T00:00:00Z",
-		Attachments: []AttachmentOutput{
+		ReceivedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Attachments: []vaultsandbox.Attachment{
 			{Filename: "f", ContentType: "c", Size: 1},
 		},
 	}
 
-	data, err := json.Marshal(email)
-	if err != nil {
-		t.Fatalf("json.Marshal error = %v", err)
-	}
-
-	jsonStr := string(data)
+	jsonStr := string(marshalOutput(t, []*vaultsandbox.Email{email}))
 
 	expectedFields := []string{
 		`"id"`,
@@ -193,8 +215,11 @@ func TestEmailOutput_JSONFieldNames(t *testing.T) {
 	}
 }
 
-// mockClient implements ClientInterface for testing
+// mockClient implements Client for testing. Methods testhelper does not use
+// come from the embedded nil interface and panic if called.
 type mockClient struct {
+	Client
+
 	createInboxFn  func(ctx context.Context, opts ...vaultsandbox.InboxOption) (*vaultsandbox.Inbox, error)
 	importInboxFn  func(ctx context.Context, data *vaultsandbox.ExportedInbox) (*vaultsandbox.Inbox, error)
 	deleteInboxFn  func(ctx context.Context, emailAddress string) error
@@ -836,24 +861,28 @@ func TestConfig_CustomIO(t *testing.T) {
 	}
 }
 
-func TestClientInterface_Implemented(t *testing.T) {
-	// Verify that vaultsandbox.Client implements ClientInterface
-	var _ ClientInterface = (*vaultsandbox.Client)(nil)
+func TestClient_Implemented(t *testing.T) {
+	// Verify that vaultsandbox.Client and the mock implement Client
+	var _ Client = (*vaultsandbox.Client)(nil)
+	var _ Client = (*mockClient)(nil)
 }
 
-func TestConvertEmails_Empty(t *testing.T) {
-	result := convertEmails(nil)
+func TestOutputEmails_Empty(t *testing.T) {
+	result := decodeOutput(t, nil)
 	if len(result) != 0 {
-		t.Errorf("convertEmails(nil) len = %d, want 0", len(result))
+		t.Errorf("outputEmails(nil) len = %d, want 0", len(result))
+	}
+	if data := marshalOutput(t, nil); string(data) != "[]" {
+		t.Errorf("outputEmails(nil) = %s, want []", data)
 	}
 
-	result = convertEmails([]*vaultsandbox.Email{})
+	result = decodeOutput(t, []*vaultsandbox.Email{})
 	if len(result) != 0 {
-		t.Errorf("convertEmails([]) len = %d, want 0", len(result))
+		t.Errorf("outputEmails([]) len = %d, want 0", len(result))
 	}
 }
 
-func TestConvertEmails_SingleEmail(t *testing.T) {
+func TestOutputEmails_SingleEmail(t *testing.T) {
 	receivedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 	emails := []*vaultsandbox.Email{
 		{
@@ -867,10 +896,10 @@ func TestConvertEmails_SingleEmail(t *testing.T) {
 		},
 	}
 
-	result := convertEmails(emails)
+	result := decodeOutput(t, emails)
 
 	if len(result) != 1 {
-		t.Fatalf("convertEmails len = %d, want 1", len(result))
+		t.Fatalf("outputEmails len = %d, want 1", len(result))
 	}
 
 	e := result[0]
@@ -897,7 +926,7 @@ func TestConvertEmails_SingleEmail(t *testing.T) {
 	}
 }
 
-func TestConvertEmails_WithAttachments(t *testing.T) {
+func TestOutputEmails_WithAttachments(t *testing.T) {
 	receivedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 	emails := []*vaultsandbox.Email{
 		{
@@ -922,10 +951,10 @@ func TestConvertEmails_WithAttachments(t *testing.T) {
 		},
 	}
 
-	result := convertEmails(emails)
+	result := decodeOutput(t, emails)
 
 	if len(result) != 1 {
-		t.Fatalf("convertEmails len = %d, want 1", len(result))
+		t.Fatalf("outputEmails len = %d, want 1", len(result))
 	}
 
 	e := result[0]
@@ -958,7 +987,7 @@ func TestConvertEmails_WithAttachments(t *testing.T) {
 	}
 }
 
-func TestConvertEmails_MultipleEmails(t *testing.T) {
+func TestOutputEmails_MultipleEmails(t *testing.T) {
 	receivedAt1 := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
 	receivedAt2 := time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)
 	receivedAt3 := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
@@ -994,10 +1023,10 @@ func TestConvertEmails_MultipleEmails(t *testing.T) {
 		},
 	}
 
-	result := convertEmails(emails)
+	result := decodeOutput(t, emails)
 
 	if len(result) != 3 {
-		t.Fatalf("convertEmails len = %d, want 3", len(result))
+		t.Fatalf("outputEmails len = %d, want 3", len(result))
 	}
 
 	// Verify order is preserved
@@ -1022,7 +1051,7 @@ func TestConvertEmails_MultipleEmails(t *testing.T) {
 	}
 }
 
-func TestConvertEmails_EmptyAttachment(t *testing.T) {
+func TestOutputEmails_EmptyAttachment(t *testing.T) {
 	receivedAt := time.Now()
 	emails := []*vaultsandbox.Email{
 		{
@@ -1042,7 +1071,7 @@ func TestConvertEmails_EmptyAttachment(t *testing.T) {
 		},
 	}
 
-	result := convertEmails(emails)
+	result := decodeOutput(t, emails)
 
 	if len(result[0].Attachments) != 1 {
 		t.Fatalf("Attachments len = %d, want 1", len(result[0].Attachments))
@@ -1131,7 +1160,7 @@ func TestRun_UnknownCommand(t *testing.T) {
 	originalFactory := clientFactory
 	defer func() { clientFactory = originalFactory }()
 
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return &mockClient{}, nil
 	}
 
@@ -1149,7 +1178,7 @@ func TestRun_ClientFactoryError(t *testing.T) {
 	originalFactory := clientFactory
 	defer func() { clientFactory = originalFactory }()
 
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return nil, errors.New("factory error")
 	}
 
@@ -1168,7 +1197,7 @@ func TestRun_CreateInbox(t *testing.T) {
 	defer func() { clientFactory = originalFactory }()
 
 	var createCalled bool
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return &mockClient{
 			createInboxFn: func(ctx context.Context, opts ...vaultsandbox.InboxOption) (*vaultsandbox.Inbox, error) {
 				createCalled = true
@@ -1193,7 +1222,7 @@ func TestRun_ImportInbox(t *testing.T) {
 	defer func() { clientFactory = originalFactory }()
 
 	var importCalled bool
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return &mockClient{
 			importInboxFn: func(ctx context.Context, data *vaultsandbox.ExportedInbox) (*vaultsandbox.Inbox, error) {
 				importCalled = true
@@ -1231,7 +1260,7 @@ func TestRun_ReadEmails(t *testing.T) {
 	defer func() { clientFactory = originalFactory }()
 
 	var importCalled bool
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return &mockClient{
 			importInboxFn: func(ctx context.Context, data *vaultsandbox.ExportedInbox) (*vaultsandbox.Inbox, error) {
 				importCalled = true
@@ -1268,7 +1297,7 @@ func TestRun_Cleanup_NoAddress(t *testing.T) {
 	originalFactory := clientFactory
 	defer func() { clientFactory = originalFactory }()
 
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return &mockClient{}, nil
 	}
 
@@ -1288,7 +1317,7 @@ func TestRun_Cleanup_Success(t *testing.T) {
 	defer func() { clientFactory = originalFactory }()
 
 	var deletedAddress string
-	clientFactory = func() (ClientInterface, error) {
+	clientFactory = func() (Client, error) {
 		return &mockClient{
 			deleteInboxFn: func(ctx context.Context, emailAddress string) error {
 				deletedAddress = emailAddress
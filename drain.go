@@ -0,0 +1,145 @@
+package vaultsandbox
+
+import (
+	"context"
+	"sync"
+)
+
+// drainTracker counts the live delivery events an inbox is processing so
+// that StopAndDrain can wait for them. Once stopped, new events are refused
+// and the emails produced by in-flight events are captured instead of being
+// delivered to watchers. The zero value is ready to use.
+type drainTracker struct {
+	mu       sync.Mutex
+	inflight int
+	stopped  bool
+	drained  []*Email
+	idle     chan struct{} // Closed when stopped and inflight reaches zero.
+}
+
+// begin registers an in-flight event. It returns false if the inbox was
+// stopped and the event must be dropped.
+func (d *drainTracker) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return false
+	}
+	d.inflight++
+	return true
+}
+
+// end completes an in-flight event. If the tracker was stopped meanwhile,
+// email (if non-nil) is captured and end returns true; the caller must then
+// not deliver it.
+func (d *drainTracker) end(email *Email) (captured bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inflight--
+	if !d.stopped {
+		return false
+	}
+	if email != nil {
+		d.drained = append(d.drained, email)
+		captured = true
+	}
+	if d.inflight == 0 && d.idle != nil {
+		close(d.idle)
+		d.idle = nil
+	}
+	return captured
+}
+
+// stop refuses further events and returns a channel that is closed once all
+// in-flight events have completed.
+func (d *drainTracker) stop() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopped = true
+	if d.idle != nil {
+		return d.idle
+	}
+	ch := make(chan struct{})
+	if d.inflight == 0 {
+		close(ch)
+	} else {
+		d.idle = ch
+	}
+	return ch
+}
+
+// take returns and clears the captured emails.
+func (d *drainTracker) take() []*Email {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	emails := d.drained
+	d.drained = nil
+	return emails
+}
+
+// StopAndDrain stops live delivery for the inbox and returns the emails whose
+// events were already received from the server but not yet delivered to
+// watchers when it was called.
+//
+// New events for the inbox are refused immediately. Events already being
+// processed (the email is being fetched and decrypted) are allowed to finish,
+// and their emails are returned instead of being sent to Watch, WatchFunc, or
+// WaitForEmail, so nothing received during the last moments of a watch is
+// lost or delivered twice. StopAndDrain blocks until those events complete or
+// ctx is done; on ctx expiry it returns the emails drained so far together
+// with ctx.Err().
+//
+// Afterwards the inbox no longer receives live emails, but GetEmails and the
+// other fetch methods keep working.
+func (i *Inbox) StopAndDrain(ctx context.Context) ([]*Email, error) {
+	if i.client != nil && i.client.strategy != nil {
+		i.client.strategy.RemoveInbox(i.inboxHash)
+	}
+	idle := i.drain.stop()
+
+	select {
+	case <-idle:
+		return i.drain.take(), nil
+	case <-ctx.Done():
+		return i.drain.take(), ctx.Err()
+	}
+}
+
+// StopAndDrain stops live delivery for every inbox managed by the client, as
+// [Inbox.StopAndDrain] does for one inbox, and returns the drained emails as
+// events. It blocks until all in-flight events complete or ctx is done; on
+// ctx expiry it returns the events drained so far together with ctx.Err().
+//
+// The client stays open: inboxes can still be fetched from, but no longer
+// receive live emails. Call [Client.Close] to release the client.
+func (c *Client) StopAndDrain(ctx context.Context) ([]*InboxEvent, error) {
+	inboxes := c.Inboxes()
+
+	idles := make([]<-chan struct{}, len(inboxes))
+	for j, inbox := range inboxes {
+		if c.strategy != nil {
+			c.strategy.RemoveInbox(inbox.inboxHash)
+		}
+		idles[j] = inbox.drain.stop()
+	}
+
+	var err error
+	for _, idle := range idles {
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	var events []*InboxEvent
+	for _, inbox := range inboxes {
+		for _, email := range inbox.drain.take() {
+			events = append(events, &InboxEvent{Inbox: inbox, Email: email})
+		}
+	}
+	return events, err
+}
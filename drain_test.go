@@ -0,0 +1,176 @@
+package vaultsandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+)
+
+// newDrainTestInbox returns a registered inbox whose email fetches block
+// until release is closed. fetching receives a value when a fetch starts.
+func newDrainTestInbox(t *testing.T) (inbox *Inbox, fetching <-chan struct{}, release chan struct{}) {
+	t.Helper()
+	started := make(chan struct{}, 4)
+	release = make(chan struct{})
+	inbox = newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "late"}, nil))
+	})
+	inbox.client.inboxesByHash = map[string]*Inbox{inbox.inboxHash: inbox}
+	inbox.client.inboxes = map[string]*Inbox{inbox.emailAddress: inbox}
+	inbox.client.syncStates = map[string]*syncState{inbox.inboxHash: {seenEmails: map[string]struct{}{}}}
+	return inbox, started, release
+}
+
+// waitForDrainStop waits until StopAndDrain has stopped the inbox.
+func waitForDrainStop(t *testing.T, inbox *Inbox) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		inbox.drain.mu.Lock()
+		stopped := inbox.drain.stopped
+		inbox.drain.mu.Unlock()
+		if stopped {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("inbox was not stopped")
+}
+
+func TestInbox_StopAndDrain_ReturnsInFlightEmails(t *testing.T) {
+	t.Parallel()
+	inbox, fetching, release := newDrainTestInbox(t)
+	c := inbox.client
+
+	var delivered []*Email
+	unsub := c.subs.subscribe(inbox.inboxHash, func(e *Email) { delivered = append(delivered, e) })
+	defer unsub()
+
+	eventErr := make(chan error, 1)
+	go func() {
+		eventErr <- c.handleSSEEvent(context.Background(), &api.SSEEvent{InboxID: inbox.inboxHash, EmailID: "e1"})
+	}()
+	<-fetching
+
+	drained := make(chan []*Email, 1)
+	go func() {
+		emails, err := inbox.StopAndDrain(context.Background())
+		if err != nil {
+			t.Errorf("StopAndDrain() error = %v", err)
+		}
+		drained <- emails
+	}()
+
+	// StopAndDrain must wait for the in-flight event.
+	select {
+	case <-drained:
+		t.Fatal("StopAndDrain() returned before the in-flight event completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	waitForDrainStop(t, inbox)
+	close(release)
+
+	emails := <-drained
+	if err := <-eventErr; err != nil {
+		t.Fatalf("handleSSEEvent() error = %v", err)
+	}
+	if len(emails) != 1 || emails[0].ID != "e1" {
+		t.Errorf("drained = %v, want [e1]", emails)
+	}
+	if len(delivered) != 0 {
+		t.Errorf("watchers received %d drained emails, want 0", len(delivered))
+	}
+
+	// New events are refused after stopping.
+	if err := c.handleSSEEvent(context.Background(), &api.SSEEvent{InboxID: inbox.inboxHash, EmailID: "e2"}); err != nil {
+		t.Errorf("handleSSEEvent() after stop error = %v", err)
+	}
+	if emails, err := inbox.StopAndDrain(context.Background()); err != nil || len(emails) != 0 {
+		t.Errorf("second StopAndDrain() = %v, %v, want empty", emails, err)
+	}
+}
+
+func TestInbox_StopAndDrain_ContextExpires(t *testing.T) {
+	t.Parallel()
+	inbox, fetching, release := newDrainTestInbox(t)
+	defer close(release)
+
+	go inbox.client.handleSSEEvent(context.Background(), &api.SSEEvent{InboxID: inbox.inboxHash, EmailID: "e1"})
+	<-fetching
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := inbox.StopAndDrain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("StopAndDrain() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestClient_StopAndDrain(t *testing.T) {
+	t.Parallel()
+	inbox, fetching, release := newDrainTestInbox(t)
+	c := inbox.client
+
+	go c.handleSSEEvent(context.Background(), &api.SSEEvent{InboxID: inbox.inboxHash, EmailID: "e1"})
+	<-fetching
+	go func() {
+		waitForDrainStop(t, inbox)
+		close(release)
+	}()
+
+	events, err := c.StopAndDrain(context.Background())
+	if err != nil {
+		t.Fatalf("StopAndDrain() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Email.ID != "e1" || !events[0].Inbox.Equal(inbox) {
+		t.Errorf("events = %+v, want one event for e1", events)
+	}
+}
+
+func TestClient_SyncAfterStopAndDrain(t *testing.T) {
+	t.Parallel()
+	// A reconnect after StopAndDrain runs the sync hook; the email it finds
+	// must not reach watchers of the stopped inbox.
+	var fetched atomic.Int32
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sync"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"emailsHash": "changed", "emailCount": 1})
+		case strings.HasSuffix(r.URL.Path, "/emails"):
+			json.NewEncoder(w).Encode([]*api.RawEmail{newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "late"}, nil)})
+		default:
+			fetched.Add(1)
+			json.NewEncoder(w).Encode(newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "late"}, map[string]interface{}{"text": "body"}))
+		}
+	})
+	c := inbox.client
+	c.inboxesByHash = map[string]*Inbox{inbox.inboxHash: inbox}
+	c.inboxes = map[string]*Inbox{inbox.emailAddress: inbox}
+	c.syncStates = map[string]*syncState{inbox.inboxHash: {seenEmails: map[string]struct{}{}}}
+
+	var delivered atomic.Int32
+	unsub := c.subs.subscribe(inbox.inboxHash, func(*Email) { delivered.Add(1) })
+	defer unsub()
+
+	if _, err := inbox.StopAndDrain(context.Background()); err != nil {
+		t.Fatalf("StopAndDrain() error = %v", err)
+	}
+	c.syncAllInboxes(context.Background())
+
+	if n := delivered.Load(); n != 0 {
+		t.Errorf("watchers received %d emails after StopAndDrain, want 0", n)
+	}
+	if n := fetched.Load(); n != 0 {
+		t.Errorf("sync fetched %d emails for a stopped inbox, want 0", n)
+	}
+}
@@ -0,0 +1,326 @@
+package vaultsandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SeenStore records which emails have been handled by [Client.DurableWatch].
+// Implementations must be safe for concurrent use. To survive a restart, the
+// store must persist its state, as [FileSeenStore] does.
+type SeenStore interface {
+	// Seen reports whether the email was already handled.
+	Seen(emailAddress, emailID string) (bool, error)
+	// MarkSeen records that the email was handled.
+	MarkSeen(emailAddress, emailID string) error
+}
+
+// EventIDStore is implemented by a [SeenStore] that also records the ID of
+// the last SSE event whose email was handled. [Client.DurableWatch] resumes
+// the event stream from that ID after a restart, so the server replays the
+// events missed while the process was down. Both [MemorySeenStore] and
+// [FileSeenStore] implement it.
+type EventIDStore interface {
+	// LastEventID returns the recorded event ID, or "" if there is none.
+	LastEventID() (string, error)
+	// SetLastEventID records the event ID.
+	SetLastEventID(id string) error
+}
+
+// MemorySeenStore is an in-memory [SeenStore]. It deduplicates within a
+// process but forgets everything on restart.
+type MemorySeenStore struct {
+	mu          sync.Mutex
+	seen        map[string]map[string]struct{}
+	lastEventID string
+}
+
+// NewMemorySeenStore returns an empty in-memory seen store.
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: make(map[string]map[string]struct{})}
+}
+
+// Seen reports whether the email was marked as seen.
+func (s *MemorySeenStore) Seen(emailAddress, emailID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.seen[emailAddress][emailID]
+	return ok, nil
+}
+
+// MarkSeen marks the email as seen.
+func (s *MemorySeenStore) MarkSeen(emailAddress, emailID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := s.seen[emailAddress]
+	if ids == nil {
+		ids = make(map[string]struct{})
+		s.seen[emailAddress] = ids
+	}
+	ids[emailID] = struct{}{}
+	return nil
+}
+
+// LastEventID returns the recorded event ID.
+func (s *MemorySeenStore) LastEventID() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastEventID, nil
+}
+
+// SetLastEventID records the event ID.
+func (s *MemorySeenStore) SetLastEventID(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastEventID = id
+	return nil
+}
+
+// FileSeenStore is a [SeenStore] persisted as a JSON file holding each inbox
+// address's handled email IDs and the last handled event ID. The file is
+// rewritten atomically on every change, so a crash never leaves it
+// half-written.
+type FileSeenStore struct {
+	path        string
+	mu          sync.Mutex
+	seen        map[string]map[string]struct{}
+	lastEventID string
+}
+
+// fileSeenStoreData is the on-disk form of a FileSeenStore.
+type fileSeenStoreData struct {
+	Seen        map[string][]string `json:"seen"`
+	LastEventID string              `json:"lastEventId,omitempty"`
+}
+
+// NewFileSeenStore opens the seen store at path, loading any existing state.
+// A missing file is treated as an empty store and created on the first
+// MarkSeen.
+func NewFileSeenStore(path string) (*FileSeenStore, error) {
+	s := &FileSeenStore{path: path, seen: make(map[string]map[string]struct{})}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read seen store: %w", err)
+	}
+
+	var stored fileSeenStoreData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parse seen store: %w", err)
+	}
+	if stored.Seen == nil && stored.LastEventID == "" {
+		// Stores written before event IDs were recorded map addresses to
+		// IDs at the top level.
+		if err := json.Unmarshal(data, &stored.Seen); err != nil {
+			return nil, fmt.Errorf("parse seen store: %w", err)
+		}
+	}
+	s.lastEventID = stored.LastEventID
+	for addr, ids := range stored.Seen {
+		set := make(map[string]struct{}, len(ids))
+		for _, id := range ids {
+			set[id] = struct{}{}
+		}
+		s.seen[addr] = set
+	}
+	return s, nil
+}
+
+// Seen reports whether the email was marked as seen.
+func (s *FileSeenStore) Seen(emailAddress, emailID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.seen[emailAddress][emailID]
+	return ok, nil
+}
+
+// MarkSeen marks the email as seen and writes the store to disk.
+func (s *FileSeenStore) MarkSeen(emailAddress, emailID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := s.seen[emailAddress]
+	if ids == nil {
+		ids = make(map[string]struct{})
+		s.seen[emailAddress] = ids
+	}
+	if _, ok := ids[emailID]; ok {
+		return nil
+	}
+	ids[emailID] = struct{}{}
+	if err := s.save(); err != nil {
+		delete(ids, emailID)
+		return err
+	}
+	return nil
+}
+
+// LastEventID returns the recorded event ID.
+func (s *FileSeenStore) LastEventID() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastEventID, nil
+}
+
+// SetLastEventID records the event ID and writes the store to disk.
+func (s *FileSeenStore) SetLastEventID(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id == s.lastEventID {
+		return nil
+	}
+	prev := s.lastEventID
+	s.lastEventID = id
+	if err := s.save(); err != nil {
+		s.lastEventID = prev
+		return err
+	}
+	return nil
+}
+
+// save writes the store to a temporary file and renames it into place.
+// The caller must hold s.mu.
+func (s *FileSeenStore) save() error {
+	stored := fileSeenStoreData{
+		Seen:        make(map[string][]string, len(s.seen)),
+		LastEventID: s.lastEventID,
+	}
+	for addr, set := range s.seen {
+		ids := make([]string, 0, len(set))
+		for id := range set {
+			ids = append(ids, id)
+		}
+		stored.Seen[addr] = ids
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("marshal seen store: %w", err) //coverage:ignore
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("write seen store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write seen store: %w", err) //coverage:ignore
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write seen store: %w", err) //coverage:ignore
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write seen store: %w", err) //coverage:ignore
+	}
+	return nil
+}
+
+// DurableWatch delivers every email in the given inboxes to handler exactly
+// once across process restarts, using store to remember handled emails.
+//
+// It subscribes to live delivery first, then passes each existing email not
+// yet in store to handler, then handles live emails as they arrive, so emails
+// that arrived while the process was down are caught up without gaps. An
+// email is marked seen only after handler returns nil; if handler returns an
+// error or panics, the error is reported to the [WithOnSyncError] callback and
+// the email is delivered again on the next DurableWatch. Emails missed during
+// an SSE reconnect are recovered by the client's reconnection sync.
+//
+// If store implements [EventIDStore], the ID of the SSE event behind each
+// handled email is recorded too, and a later DurableWatch reconnects the
+// event stream with it as Last-Event-ID so the server replays what was missed.
+// The catch-up of existing emails still runs, so emails are not lost when the
+// server no longer holds the events.
+//
+// Handler calls are sequential. DurableWatch blocks until ctx is cancelled,
+// returning ctx.Err(), or until fetching existing emails or the store fails.
+func (c *Client) DurableWatch(ctx context.Context, inboxes []*Inbox, store SeenStore, handler func(*InboxEvent) error) error {
+	if len(inboxes) == 0 {
+		return fmt.Errorf("at least one inbox is required")
+	}
+	if store == nil {
+		return fmt.Errorf("seen store cannot be nil")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := c.WatchInboxes(ctx, inboxes...)
+
+	if ids, ok := store.(EventIDStore); ok {
+		id, err := ids.LastEventID()
+		if err != nil {
+			return fmt.Errorf("seen store: %w", err)
+		}
+		if resumer, ok := c.strategy.(interface{ ResumeFrom(string) }); ok && id != "" {
+			resumer.ResumeFrom(id)
+		}
+	}
+
+	for _, inbox := range inboxes {
+		existing, err := inbox.GetEmails(ctx)
+		if err != nil {
+			return fmt.Errorf("fetch existing emails for %s: %w", inbox.emailAddress, err)
+		}
+		for _, email := range existing {
+			if err := c.deliverDurable(store, &InboxEvent{Inbox: inbox, Email: email}, handler); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-events:
+			if event == nil {
+				continue
+			}
+			if err := c.deliverDurable(store, event, handler); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// deliverDurable passes an unseen event to handler and marks it seen on
+// success. Only store failures are returned.
+func (c *Client) deliverDurable(store SeenStore, event *InboxEvent, handler func(*InboxEvent) error) error {
+	addr := event.Inbox.emailAddress
+	seen, err := store.Seen(addr, event.Email.ID)
+	if err != nil {
+		return fmt.Errorf("seen store: %w", err)
+	}
+	if seen {
+		return nil
+	}
+
+	var handlerErr error
+	if !c.invokeCallback(func() { handlerErr = handler(event) }) {
+		return nil // Panic already reported.
+	}
+	if handlerErr != nil {
+		if c.onSyncError != nil {
+			c.onSyncError(fmt.Errorf("handle email %s: %w", event.Email.ID, handlerErr))
+		}
+		return nil
+	}
+
+	if err := store.MarkSeen(addr, event.Email.ID); err != nil {
+		return fmt.Errorf("seen store: %w", err)
+	}
+	if ids, ok := store.(EventIDStore); ok && event.Email.eventID != "" {
+		if err := ids.SetLastEventID(event.Email.eventID); err != nil {
+			return fmt.Errorf("seen store: %w", err)
+		}
+	}
+	return nil
+}
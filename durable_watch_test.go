@@ -0,0 +1,256 @@
+package vaultsandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/delivery"
+)
+
+func TestFileSeenStore_Persists(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "seen.json")
+
+	store, err := NewFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() error = %v", err)
+	}
+	if err := store.MarkSeen("a@example.com", "e1"); err != nil {
+		t.Fatalf("MarkSeen() error = %v", err)
+	}
+
+	reopened, err := NewFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() reopen error = %v", err)
+	}
+	tests := []struct {
+		addr, id string
+		want     bool
+	}{
+		{"a@example.com", "e1", true},
+		{"a@example.com", "e2", false},
+		{"b@example.com", "e1", false},
+	}
+	for _, tt := range tests {
+		got, err := reopened.Seen(tt.addr, tt.id)
+		if err != nil {
+			t.Fatalf("Seen() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Seen(%q, %q) = %v, want %v", tt.addr, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestFileSeenStore_LastEventID(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "seen.json")
+
+	store, err := NewFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() error = %v", err)
+	}
+	if err := store.MarkSeen("a@example.com", "e1"); err != nil {
+		t.Fatalf("MarkSeen() error = %v", err)
+	}
+	if err := store.SetLastEventID("evt-9"); err != nil {
+		t.Fatalf("SetLastEventID() error = %v", err)
+	}
+
+	reopened, err := NewFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() reopen error = %v", err)
+	}
+	if id, err := reopened.LastEventID(); err != nil || id != "evt-9" {
+		t.Errorf("LastEventID() = %q, %v; want evt-9", id, err)
+	}
+	if seen, _ := reopened.Seen("a@example.com", "e1"); !seen {
+		t.Error("Seen(a@example.com, e1) = false after reopen")
+	}
+}
+
+func TestNewFileSeenStore_LegacyFormat(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "seen.json")
+	if err := os.WriteFile(path, []byte(`{"a@example.com":["e1"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("NewFileSeenStore() error = %v", err)
+	}
+	if seen, _ := store.Seen("a@example.com", "e1"); !seen {
+		t.Error("Seen(a@example.com, e1) = false for a legacy store")
+	}
+	if id, _ := store.LastEventID(); id != "" {
+		t.Errorf("LastEventID() = %q, want empty", id)
+	}
+}
+
+func TestNewFileSeenStore_InvalidFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "seen.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSeenStore(path); err == nil {
+		t.Error("NewFileSeenStore() error = nil, want parse error")
+	}
+}
+
+func TestDurableWatch_CatchUpThenLive(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "old"}, nil),
+			newPlainRawEmail(t, "e2", map[string]interface{}{"subject": "missed"}, nil),
+			newPlainRawEmail(t, "e3", map[string]interface{}{"subject": "fails"}, nil),
+		})
+	})
+	var syncErrs []error
+	var errMu sync.Mutex
+	inbox.client.onSyncError = func(err error) {
+		errMu.Lock()
+		syncErrs = append(syncErrs, err)
+		errMu.Unlock()
+	}
+
+	store := NewMemorySeenStore()
+	store.MarkSeen(inbox.emailAddress, "e1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var handled []string
+	handler := func(ev *InboxEvent) error {
+		if ev.Email.ID == "e3" {
+			return errors.New("downstream unavailable")
+		}
+		mu.Lock()
+		handled = append(handled, ev.Email.ID)
+		done := len(handled) == 2
+		mu.Unlock()
+		if done {
+			cancel()
+		}
+		return nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- inbox.client.DurableWatch(ctx, []*Inbox{inbox}, store, handler)
+	}()
+
+	waitForSubscribers(t, inbox.client.subs, inbox.inboxHash, 1)
+	// A duplicate of an already-handled email must be skipped.
+	inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e1"})
+	inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e4"})
+
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("DurableWatch() error = %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 2 || handled[0] != "e2" || handled[1] != "e4" {
+		t.Errorf("handled = %v, want [e2 e4]", handled)
+	}
+	if seen, _ := store.Seen(inbox.emailAddress, "e3"); seen {
+		t.Error("email whose handler failed was marked seen")
+	}
+	errMu.Lock()
+	defer errMu.Unlock()
+	if len(syncErrs) != 1 {
+		t.Errorf("sync errors = %v, want 1 handler error", syncErrs)
+	}
+}
+
+// resumingStrategy is a delivery strategy that records ResumeFrom calls.
+// DurableWatch uses no other strategy method, so the rest are left to the
+// nil embedded Strategy.
+type resumingStrategy struct {
+	delivery.Strategy
+	mu          sync.Mutex
+	resumedFrom string
+}
+
+func (r *resumingStrategy) ResumeFrom(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resumedFrom = id
+}
+
+func TestDurableWatch_ResumesFromLastEventID(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/emails") {
+			json.NewEncoder(w).Encode([]*api.RawEmail{})
+			return
+		}
+		id := path.Base(r.URL.Path)
+		json.NewEncoder(w).Encode(newPlainRawEmail(t, id, map[string]interface{}{"subject": id}, nil))
+	})
+	c := inbox.client
+	strategy := &resumingStrategy{}
+	c.strategy = strategy
+	c.inboxes = map[string]*Inbox{inbox.emailAddress: inbox}
+	c.inboxesByHash = map[string]*Inbox{inbox.inboxHash: inbox}
+	c.syncStates = map[string]*syncState{inbox.inboxHash: {seenEmails: map[string]struct{}{}}}
+
+	store := NewMemorySeenStore()
+	store.SetLastEventID("evt-1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.DurableWatch(ctx, []*Inbox{inbox}, store, func(*InboxEvent) error {
+			cancel()
+			return nil
+		})
+	}()
+
+	waitForSubscribers(t, c.subs, inbox.inboxHash, 1)
+	if err := c.handleSSEEvent(context.Background(), &api.SSEEvent{ID: "evt-2", InboxID: inbox.inboxHash, EmailID: "e2"}); err != nil {
+		t.Fatalf("handleSSEEvent() error = %v", err)
+	}
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("DurableWatch() error = %v, want context.Canceled", err)
+	}
+
+	strategy.mu.Lock()
+	resumed := strategy.resumedFrom
+	strategy.mu.Unlock()
+	if resumed != "evt-1" {
+		t.Errorf("ResumeFrom(%q), want evt-1", resumed)
+	}
+	if id, _ := store.LastEventID(); id != "evt-2" {
+		t.Errorf("LastEventID() = %q, want evt-2", id)
+	}
+}
+
+func TestDurableWatch_InvalidArgs(t *testing.T) {
+	t.Parallel()
+	c := &Client{subs: newSubscriptionManager()}
+	handler := func(*InboxEvent) error { return nil }
+
+	if err := c.DurableWatch(context.Background(), nil, NewMemorySeenStore(), handler); err == nil {
+		t.Error("DurableWatch() with no inboxes error = nil, want error")
+	}
+	if err := c.DurableWatch(context.Background(), []*Inbox{{}}, nil, handler); err == nil {
+		t.Error("DurableWatch() with nil store error = nil, want error")
+	}
+}
@@ -1,7 +1,26 @@
 package vaultsandbox
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/vaultsandbox/client-go/authresults"
 	"github.com/vaultsandbox/client-go/spamanalysis"
@@ -14,21 +33,50 @@ import (
 //   - inbox.MarkEmailAsRead(ctx, emailID) — Marks email as read
 //   - inbox.DeleteEmail(ctx, emailID) — Deletes an email
 type Email struct {
-	ID          string
-	From        string
-	To          []string
-	Subject     string
-	Text        string
-	HTML        string
-	ReceivedAt  time.Time
+	ID   string   `json:"id"`
+	From string   `json:"from"`
+	To   []string `json:"to"`
+	// EnvelopeTo is the SMTP envelope recipient (RCPT TO) the email was
+	// delivered to. For catch-all inboxes this identifies the sub-address.
+	// Falls back to the primary recipient when the server does not report it.
+	EnvelopeTo string    `json:"envelopeTo,omitempty"`
+	Subject    string    `json:"subject"`
+	Text       string    `json:"text"`
+	HTML       string    `json:"html,omitempty"`
+	ReceivedAt time.Time `json:"receivedAt"`
 	// Headers contains email headers as string key-value pairs.
-	// Non-string header values from the server are omitted during parsing.
-	Headers      map[string]string
-	Attachments  []Attachment
-	Links        []string
-	AuthResults  *authresults.AuthResults
-	SpamAnalysis *spamanalysis.SpamAnalysis
-	IsRead       bool
+	// Non-string header values from the server are omitted during parsing;
+	// headers that occurred more than once are kept in MultiHeaders.
+	Headers map[string]string `json:"headers,omitempty"`
+	// MultiHeaders contains headers that occurred more than once, such as
+	// Received, with every value in header order. Use [Email.HeaderValues]
+	// to read a header regardless of how many times it occurred.
+	MultiHeaders map[string][]string        `json:"multiHeaders,omitempty"`
+	Attachments  []Attachment               `json:"attachments,omitempty"`
+	Links        []string                   `json:"links,omitempty"`
+	AuthResults  *authresults.AuthResults   `json:"authResults,omitempty"`
+	SpamAnalysis *spamanalysis.SpamAnalysis `json:"spamAnalysis,omitempty"`
+	IsRead       bool                       `json:"isRead"`
+
+	// ListUnsubscribe contains the URIs from the List-Unsubscribe header
+	// (RFC 2369) in header order, without angle brackets. Nil if the header
+	// is absent.
+	ListUnsubscribe []string `json:"listUnsubscribe,omitempty"`
+	// ListUnsubscribePost is the raw List-Unsubscribe-Post header value
+	// (RFC 8058), typically "List-Unsubscribe=One-Click". Empty if absent.
+	ListUnsubscribePost string `json:"listUnsubscribePost,omitempty"`
+
+	// BodyTruncated reports whether Text or HTML was shortened because the
+	// email was fetched with [WithMaxBodyBytes].
+	BodyTruncated bool `json:"bodyTruncated,omitempty"`
+
+	// BodyReady reports whether the server had finished processing the
+	// email body when it was fetched. An email can be delivered before its
+	// body is parsed; then Text and HTML are empty even though the message
+	// has a body. False also when the body was not fetched, as with
+	// [WithFields] without [FieldBody].
+	// Wait with [WithWaitForParsed] to receive only ready emails.
+	BodyReady bool `json:"bodyReady,omitempty"`
 
 	// AuthResultsError contains any error that occurred parsing auth results.
 	// This is set instead of AuthResults if parsing failed.
@@ -37,17 +85,432 @@ type Email struct {
 	// SpamAnalysisError contains any error that occurred parsing spam analysis.
 	// This is set instead of SpamAnalysis if parsing failed.
 	SpamAnalysisError error `json:"-"`
+
+	// ExcludeAttachmentContent omits attachment content from MarshalJSON
+	// output, keeping the remaining attachment metadata. An attachment
+	// without a declared Size reports the length of its content instead. It
+	// is not itself serialized.
+	ExcludeAttachmentContent bool `json:"-"`
+
+	// eventID is the ID of the SSE event that delivered the email, if any.
+	eventID string
+
+	// headerOrder lists the Headers and MultiHeaders keys in message order,
+	// when known from the server response.
+	headerOrder []string
+}
+
+// MarshalJSON encodes the email in its canonical JSON form: camelCase field
+// names, receivedAt in RFC 3339 format, and attachment content as standard
+// base64 (omitted when ExcludeAttachmentContent is set). The shape is stable
+// and is decoded losslessly by UnmarshalJSON. It is also the email shape the
+// cmd/testhelper tool emits for cross-SDK tests, with attachment content
+// excluded.
+func (e Email) MarshalJSON() ([]byte, error) {
+	type plain Email
+	p := plain(e)
+	if e.ExcludeAttachmentContent && len(e.Attachments) > 0 {
+		p.Attachments = make([]Attachment, len(e.Attachments))
+		for j, a := range e.Attachments {
+			if a.Size == 0 {
+				a.Size = len(a.Content)
+			}
+			a.Content = nil
+			p.Attachments[j] = a
+		}
+	}
+	return json.Marshal(p)
+}
+
+// UnmarshalJSON decodes an email from the canonical JSON form produced by
+// MarshalJSON.
+func (e *Email) UnmarshalJSON(data []byte) error {
+	type plain Email
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// Header returns the value of the named header. The lookup is
+// case-insensitive, so "message-id" finds a "Message-ID" header. For a
+// header that occurred more than once it returns the first value. The ok
+// result reports whether the header was present, distinguishing a missing
+// header from one with an empty value.
+func (e *Email) Header(name string) (value string, ok bool) {
+	if v, ok := e.Headers[name]; ok {
+		return v, true
+	}
+	if v, ok := e.Headers[textproto.CanonicalMIMEHeaderKey(name)]; ok {
+		return v, true
+	}
+	if values := e.HeaderValues(name); len(values) > 0 {
+		return values[0], true
+	}
+	return "", false
+}
+
+// HeaderValues returns every value of the named header, matched
+// case-insensitively, including each occurrence of a repeated header from
+// MultiHeaders. When the header is stored under several spellings, values are
+// grouped by spelling in the order the spellings first occur in the message,
+// or in sorted order for an email not decoded from a server response. It
+// returns nil if the header is not present.
+func (e *Email) HeaderValues(name string) []string {
+	var keys []string
+	for k := range e.Headers {
+		if strings.EqualFold(k, name) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range e.MultiHeaders {
+		if _, dup := e.Headers[k]; !dup && strings.EqualFold(k, name) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		pa, pb := slices.Index(e.headerOrder, keys[a]), slices.Index(e.headerOrder, keys[b])
+		if pa != pb {
+			return pa >= 0 && (pb < 0 || pa < pb)
+		}
+		return keys[a] < keys[b]
+	})
+	var values []string
+	for _, k := range keys {
+		if multi, ok := e.MultiHeaders[k]; ok {
+			values = append(values, multi...)
+		} else {
+			values = append(values, e.Headers[k])
+		}
+	}
+	return values
+}
+
+// UnsubscribeLinks returns the https and mailto targets from
+// ListUnsubscribe, dropping other schemes such as plain http. RFC 8058
+// one-click unsubscribe requires an https link together with
+// ListUnsubscribePost.
+func (e *Email) UnsubscribeLinks() []string {
+	var links []string
+	for _, uri := range e.ListUnsubscribe {
+		lower := strings.ToLower(uri)
+		if strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:") {
+			links = append(links, uri)
+		}
+	}
+	return links
+}
+
+// defaultOTPPattern matches a standalone run of 4 to 8 digits.
+var defaultOTPPattern = regexp.MustCompile(`\b(\d{4,8})\b`)
+
+var (
+	htmlBlockPattern = regexp.MustCompile(`(?is)<style\b.*?</style>|<script\b.*?</script>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</(?:p|div|li|tr|h[1-6])\s*>`)
+	htmlBoxPattern   = regexp.MustCompile(`(?i)</?(?:p|div|li|ul|ol|tr|td|th|table|h[1-6]|hr|blockquote|body|head|html)\b[^>]*>`)
+)
+
+// BodyText returns the plain-text body: Text if it is non-empty, otherwise
+// HTML rendered as text. The rendering drops tags, style, and script blocks,
+// decodes entities, turns <br> and closing paragraph-level tags into line
+// breaks, collapses other whitespace, and omits blank lines.
+func (e *Email) BodyText() string {
+	if e.Text != "" {
+		return e.Text
+	}
+	return htmlToText(e.HTML)
+}
+
+// Age returns how long ago the email was received, measured from
+// ReceivedAt to now on the local wall clock, not the client's clock: an
+// Email does not know the client that fetched it. ReceivedAt is an instant,
+// so its time zone does not matter. Age is zero if ReceivedAt is unset, and
+// may be slightly negative if the server's clock runs ahead of the local one.
+func (e *Email) Age() time.Duration {
+	if e.ReceivedAt.IsZero() {
+		return 0
+	}
+	return time.Since(e.ReceivedAt)
+}
+
+// ExtractCode searches the body for pattern, trying Text first and then HTML
+// with its markup stripped, and returns the first capture group of the first
+// match, or the whole match if the pattern has no groups. ok is false if
+// neither body matches.
+func (e *Email) ExtractCode(pattern *regexp.Regexp) (code string, ok bool) {
+	for _, body := range []string{e.Text, stripHTML(e.HTML)} {
+		m := pattern.FindStringSubmatch(body)
+		if m == nil {
+			continue
+		}
+		if len(m) > 1 {
+			return m[1], true
+		}
+		return m[0], true
+	}
+	return "", false
+}
+
+// ExtractOTP returns the first standalone 4 to 8 digit number in the body,
+// such as a one-time passcode. See [Email.ExtractCode] for the search order.
+func (e *Email) ExtractOTP() (code string, ok bool) {
+	return e.ExtractCode(defaultOTPPattern)
+}
+
+// stripHTML reduces an HTML body to its text on a single line: style and
+// script blocks are dropped, block and table tags become spaces so adjacent
+// cells stay separate while inline tags such as <b> vanish, entities are
+// unescaped, and runs of whitespace collapse to a single space.
+func stripHTML(s string) string {
+	return strings.Join(strings.Fields(htmlToText(s)), " ")
+}
+
+// htmlToText is like stripHTML but keeps line structure: <br> and closing
+// paragraph-level tags end a line, whitespace within each line collapses to
+// a single space, and blank lines are dropped.
+func htmlToText(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = htmlBlockPattern.ReplaceAllString(s, " ")
+	s = htmlBreakPattern.ReplaceAllString(s, "\n")
+	s = htmlBoxPattern.ReplaceAllString(s, " ")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	var lines []string
+	for _, line := range strings.Split(html.UnescapeString(s), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseListUnsubscribe extracts the URIs from a List-Unsubscribe header
+// value of the form "<mailto:a@b>, <https://c/d>". Values that do not use
+// angle brackets are split on commas instead.
+func parseListUnsubscribe(value string) []string {
+	var uris []string
+	if !strings.Contains(value, "<") {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				uris = append(uris, part)
+			}
+		}
+		return uris
+	}
+	for {
+		start := strings.IndexByte(value, '<')
+		if start < 0 {
+			return uris
+		}
+		end := strings.IndexByte(value[start:], '>')
+		if end < 0 {
+			return uris
+		}
+		// Folded headers may leave whitespace inside the brackets.
+		uri := strings.Join(strings.Fields(value[start+1:start+end]), "")
+		if uri != "" {
+			uris = append(uris, uri)
+		}
+		value = value[start+end+1:]
+	}
+}
+
+// project clears the fields not selected by fields.
+func (e *Email) project(fields Field) {
+	if fields&FieldSubject == 0 {
+		e.Subject = ""
+	}
+	if fields&FieldFrom == 0 {
+		e.From = ""
+	}
+	if fields&FieldBody == 0 {
+		e.Text, e.HTML, e.Links, e.Headers, e.MultiHeaders, e.headerOrder = "", "", nil, nil, nil, nil
+		e.ListUnsubscribe, e.ListUnsubscribePost = nil, ""
+	}
+	if fields&FieldAttachments == 0 {
+		e.Attachments = nil
+	}
+	if fields&FieldAuth == 0 {
+		e.AuthResults, e.AuthResultsError = nil, nil
+		e.SpamAnalysis, e.SpamAnalysisError = nil, nil
+	}
+}
+
+// truncateBody shortens Text and HTML to at most n bytes on a UTF-8
+// character boundary, recording whether anything was cut.
+func (e *Email) truncateBody(n int) {
+	if n <= 0 {
+		return
+	}
+	var cut bool
+	e.Text, cut = truncateUTF8(e.Text, n)
+	e.BodyTruncated = e.BodyTruncated || cut
+	e.HTML, cut = truncateUTF8(e.HTML, n)
+	e.BodyTruncated = e.BodyTruncated || cut
+}
+
+// truncateUTF8 returns s cut to at most n bytes without splitting a
+// multi-byte character, and whether it was shortened.
+func truncateUTF8(s string, n int) (string, bool) {
+	if len(s) <= n {
+		return s, false
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n], true
 }
 
 // Attachment represents an email attachment.
 type Attachment struct {
-	Filename           string
-	ContentType        string
-	Size               int
-	ContentID          string
-	ContentDisposition string
-	Content            []byte
-	Checksum           string
+	Filename           string `json:"filename"`
+	ContentType        string `json:"contentType"`
+	Size               int    `json:"size"`
+	ContentID          string `json:"contentId,omitempty"`
+	ContentDisposition string `json:"contentDisposition,omitempty"`
+	Content            []byte `json:"content,omitempty"`
+	Checksum           string `json:"checksum,omitempty"`
+}
+
+// defaultAttachmentFilename is used by SaveTo when the sanitized filename
+// is empty.
+const defaultAttachmentFilename = "attachment"
+
+// SaveTo writes the attachment content to a file in dir and returns the path
+// written. The filename is sanitized first: directory components and ".."
+// are stripped, so a name like "../../etc/passwd" is saved as "passwd" inside
+// dir. An existing file with the same name is overwritten. It returns
+// [ErrAttachmentNoContent] if Content is nil.
+func (a *Attachment) SaveTo(dir string) (string, error) {
+	if a.Content == nil {
+		return "", fmt.Errorf("save %q: %w", a.Filename, ErrAttachmentNoContent)
+	}
+	path := filepath.Join(dir, sanitizeFilename(a.Filename))
+	if err := os.WriteFile(path, a.Content, 0600); err != nil {
+		return "", fmt.Errorf("save attachment: %w", err)
+	}
+	return path, nil
+}
+
+// WriteTo writes the attachment content to w, implementing [io.WriterTo]. It
+// returns [ErrAttachmentNoContent] if Content is nil.
+func (a *Attachment) WriteTo(w io.Writer) (int64, error) {
+	if a.Content == nil {
+		return 0, fmt.Errorf("write %q: %w", a.Filename, ErrAttachmentNoContent)
+	}
+	n, err := w.Write(a.Content)
+	return int64(n), err
+}
+
+// VerifyChecksum checks Content against Checksum, the SHA-256 digest the
+// server computed when the email was received. The digest may be hex or
+// base64 (standard or URL alphabet, padded or not), optionally prefixed with
+// "sha256:" or "sha256-". A mismatch, or a checksum in none of these forms,
+// returns a [*ChecksumError]. An empty Checksum is not verified and returns
+// nil.
+func (a *Attachment) VerifyChecksum() error {
+	return verifyChecksum(a.Filename, a.Content, a.Checksum)
+}
+
+// verifyChecksum implements [Attachment.VerifyChecksum].
+func verifyChecksum(filename string, content []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	want := strings.TrimSpace(checksum)
+	if len(want) > 7 && strings.EqualFold(want[:6], "sha256") && (want[6] == ':' || want[6] == '-') {
+		want = want[7:]
+	}
+	if digest := decodeDigest(want); digest == nil || subtle.ConstantTimeCompare(digest, sum[:]) != 1 {
+		return &ChecksumError{Filename: filename, Expected: checksum, Actual: hex.EncodeToString(sum[:])}
+	}
+	return nil
+}
+
+// decodeDigest decodes a hex or base64 SHA-256 digest, returning nil if s is
+// neither.
+func decodeDigest(s string) []byte {
+	if len(s) == hex.EncodedLen(sha256.Size) {
+		if b, err := hex.DecodeString(s); err == nil {
+			return b
+		}
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil && len(b) == sha256.Size {
+			return b
+		}
+	}
+	return nil
+}
+
+// Category returns the top-level media type of the attachment, such as
+// "image", "text", "application", "audio", or "video", taken from
+// ContentType. When ContentType is empty or the generic
+// "application/octet-stream", the type is sniffed from Content with
+// [http.DetectContentType] instead, so a PNG sent as octet-stream is still
+// reported as "image". Unknown content falls back to "application".
+func (a *Attachment) Category() string {
+	category, _, _ := strings.Cut(a.mediaType(), "/")
+	return category
+}
+
+// IsImage reports whether the attachment is an image; see [Attachment.Category].
+func (a *Attachment) IsImage() bool {
+	return a.Category() == "image"
+}
+
+// IsText reports whether the attachment is text, such as text/plain or
+// text/csv; see [Attachment.Category].
+func (a *Attachment) IsText() bool {
+	return a.Category() == "text"
+}
+
+// mediaType returns the attachment's lowercase media type without
+// parameters, sniffing Content when ContentType is missing or generic.
+func (a *Attachment) mediaType() string {
+	mediaType := parseMediaType(a.ContentType)
+	if (mediaType == "" || mediaType == "application/octet-stream") && len(a.Content) > 0 {
+		mediaType = parseMediaType(http.DetectContentType(a.Content))
+	}
+	if mediaType == "" {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// parseMediaType returns the lowercase media type of a Content-Type value,
+// dropping parameters such as charset.
+func parseMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// sanitizeFilename reduces an attachment filename to a safe single path
+// element: the last component of either slash style, with ".." sequences and
+// control characters removed.
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.ReplaceAll(name, "..", "")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." {
+		return defaultAttachmentFilename
+	}
+	return name
 }
 
 // EmailMetadata represents email metadata without full content.
@@ -0,0 +1,322 @@
+package vaultsandbox
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// rfc5322Generated lists the headers ToRFC5322 writes itself, in output
+// order, ahead of the email's remaining headers. MIME structure headers are
+// also generated and never copied from the parsed headers.
+var rfc5322Generated = []string{"Date", "From", "To", "Subject"}
+
+// rfc5322Skipped holds the canonical names of parsed headers that ToRFC5322
+// does not copy because it generates them.
+var rfc5322Skipped = map[string]bool{
+	"Date":                      true,
+	"From":                      true,
+	"To":                        true,
+	"Subject":                   true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// mimeEntity is a MIME header block and its encoded body.
+type mimeEntity struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// ToRFC5322 assembles an RFC 5322 message from the parsed fields, for golden
+// files and for feeding other mail tools. Unlike [Inbox.GetRawEmail], which
+// fetches the server's copy of the original message, it works offline, so
+// the result is equivalent to the original rather than identical to it.
+//
+// The message starts with Date (from the Date header, or ReceivedAt), From,
+// To, and Subject, followed by the remaining parsed headers sorted by name.
+// Text and HTML become a text/plain and text/html part (multipart/alternative
+// when both are set) in quoted-printable, and attachments are added as
+// base64 parts of a multipart/mixed message. Attachments without content
+// are written with an empty body. Multipart boundaries are derived from the
+// content, so the same email always produces the same bytes.
+//
+// It returns an error if a header name is not a valid RFC 5322 field name.
+func (e *Email) ToRFC5322() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, name := range rfc5322Generated {
+		if value := e.rfc5322Value(name); value != "" {
+			writeHeader(&buf, name, value)
+		}
+	}
+
+	names := make([]string, 0, len(e.Headers)+len(e.MultiHeaders))
+	for name := range e.Headers {
+		names = append(names, name)
+	}
+	for name := range e.MultiHeaders {
+		if _, dup := e.Headers[name]; !dup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !validHeaderName(name) {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		if rfc5322Skipped[textproto.CanonicalMIMEHeaderKey(name)] {
+			continue
+		}
+		values, ok := e.MultiHeaders[name]
+		if !ok {
+			values = []string{e.Headers[name]}
+		}
+		for _, value := range values {
+			writeHeader(&buf, name, encodeHeaderValue(value))
+		}
+	}
+
+	entity, err := e.mimeBody()
+	if err != nil {
+		return nil, err
+	}
+	writeHeader(&buf, "MIME-Version", "1.0")
+	writeMIMEHeader(&buf, entity.header)
+	buf.WriteString("\r\n")
+	buf.Write(entity.body)
+	return buf.Bytes(), nil
+}
+
+// rfc5322Value returns the value of a generated header (one of
+// rfc5322Generated), preferring the parsed field over the raw header.
+func (e *Email) rfc5322Value(name string) string {
+	raw, _ := e.Header(name)
+	switch name {
+	case "Date":
+		if raw == "" && !e.ReceivedAt.IsZero() {
+			return e.ReceivedAt.Format(time.RFC1123Z)
+		}
+		return sanitizeHeaderValue(raw)
+	case "From":
+		if e.From != "" {
+			return formatAddressList([]string{e.From})
+		}
+		return sanitizeHeaderValue(raw)
+	case "To":
+		if len(e.To) > 0 {
+			return formatAddressList(e.To)
+		}
+		return sanitizeHeaderValue(raw)
+	}
+	if e.Subject != "" {
+		return encodeHeaderValue(e.Subject)
+	}
+	return encodeHeaderValue(raw)
+}
+
+// mimeBody builds the MIME entity holding the bodies and attachments.
+func (e *Email) mimeBody() (*mimeEntity, error) {
+	seed := e.boundarySeed()
+
+	var body *mimeEntity
+	switch {
+	case e.Text != "" && e.HTML != "":
+		var err error
+		body, err = multipartEntity("alternative", "alt-"+seed,
+			textEntity("text/plain", e.Text), textEntity("text/html", e.HTML))
+		if err != nil {
+			return nil, err
+		}
+	case e.HTML != "":
+		body = textEntity("text/html", e.HTML)
+	default:
+		body = textEntity("text/plain", e.Text)
+	}
+	if len(e.Attachments) == 0 {
+		return body, nil
+	}
+
+	parts := []*mimeEntity{body}
+	for i := range e.Attachments {
+		parts = append(parts, attachmentEntity(&e.Attachments[i]))
+	}
+	return multipartEntity("mixed", "mixed-"+seed, parts...)
+}
+
+// boundarySeed returns a short hash of the email content, making boundaries
+// deterministic and vanishingly unlikely to occur in the content.
+func (e *Email) boundarySeed() string {
+	h := sha256.New()
+	for _, s := range []string{e.ID, e.Subject, e.Text, e.HTML} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	for _, a := range e.Attachments {
+		h.Write([]byte(a.Filename))
+		h.Write(a.Content)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// textEntity encodes a UTF-8 text body as quoted-printable.
+func textEntity(mediaType, text string) *mimeEntity {
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	qp.Write([]byte(text))
+	qp.Close()
+	return &mimeEntity{
+		header: textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"})},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		},
+		body: body.Bytes(),
+	}
+}
+
+// attachmentEntity encodes an attachment as base64 in 76-column lines.
+func attachmentEntity(a *Attachment) *mimeEntity {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+	disposition := a.ContentDisposition
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	dispParams := map[string]string{}
+	if a.Filename != "" {
+		params["name"] = a.Filename
+		dispParams["filename"] = a.Filename
+	}
+
+	header := textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType, params)},
+		"Content-Disposition":       {mime.FormatMediaType(disposition, dispParams)},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if a.ContentID != "" {
+		header.Set("Content-Id", "<"+strings.Trim(a.ContentID, "<>")+">")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(a.Content)
+	var body bytes.Buffer
+	for len(encoded) > 76 {
+		body.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	if encoded != "" {
+		body.WriteString(encoded + "\r\n")
+	}
+	return &mimeEntity{header: header, body: body.Bytes()}
+}
+
+// multipartEntity joins parts into a multipart entity of the given subtype.
+func multipartEntity(subtype, boundary string, parts ...*mimeEntity) (*mimeEntity, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.SetBoundary(boundary); err != nil {
+		return nil, fmt.Errorf("set boundary: %w", err)
+	}
+	for _, part := range parts {
+		pw, err := w.CreatePart(part.header)
+		if err != nil {
+			return nil, fmt.Errorf("create part: %w", err)
+		}
+		if _, err := pw.Write(part.body); err != nil {
+			return nil, fmt.Errorf("write part: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart: %w", err)
+	}
+	return &mimeEntity{
+		header: textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary})},
+		},
+		body: body.Bytes(),
+	}, nil
+}
+
+// writeMIMEHeader writes header with its keys sorted.
+func writeMIMEHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			writeHeader(buf, k, v)
+		}
+	}
+}
+
+func writeHeader(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteString("\r\n")
+}
+
+// formatAddressList formats addresses for an address header, encoding
+// non-ASCII display names. Unparseable addresses are written as given.
+func formatAddressList(addrs []string) string {
+	formatted := make([]string, len(addrs))
+	for i, addr := range addrs {
+		if a, err := mail.ParseAddress(addr); err == nil {
+			formatted[i] = a.String()
+		} else {
+			formatted[i] = sanitizeHeaderValue(addr)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// encodeHeaderValue makes value safe for a header line, using RFC 2047
+// encoded-words when it is not plain ASCII.
+func encodeHeaderValue(value string) string {
+	value = sanitizeHeaderValue(value)
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return mime.QEncoding.Encode("utf-8", value)
+		}
+	}
+	return value
+}
+
+// sanitizeHeaderValue replaces line breaks, which would end the header or
+// inject new ones, with spaces.
+func sanitizeHeaderValue(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value)
+}
+
+// validHeaderName reports whether name is an RFC 5322 field name: printable
+// ASCII other than colon.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c < '!' || c > '~' || c == ':' {
+			return false
+		}
+	}
+	return true
+}
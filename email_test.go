@@ -1,8 +1,26 @@
 package vaultsandbox
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/vaultsandbox/client-go/authresults"
+	"github.com/vaultsandbox/client-go/spamanalysis"
 )
 
 func TestEmail_Fields(t *testing.T) {
@@ -100,6 +118,669 @@ func TestEmail_WithAttachments(t *testing.T) {
 	}
 }
 
+func TestAttachment_SaveTo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+	}{
+		{name: "plain name", filename: "report.pdf", want: "report.pdf"},
+		{name: "unix traversal", filename: "../../etc/passwd", want: "passwd"},
+		{name: "windows traversal", filename: `..\..\Windows\system.ini`, want: "system.ini"},
+		{name: "dot dot only", filename: "..", want: "attachment"},
+		{name: "embedded dot dot", filename: "a..b.txt", want: "ab.txt"},
+		{name: "control characters", filename: "in\x00voice\n.txt", want: "invoice.txt"},
+		{name: "empty", filename: "", want: "attachment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			a := &Attachment{Filename: tt.filename, Content: []byte("data")}
+
+			path, err := a.SaveTo(dir)
+			if err != nil {
+				t.Fatalf("SaveTo() error = %v", err)
+			}
+			if want := filepath.Join(dir, tt.want); path != want {
+				t.Errorf("SaveTo() path = %q, want %q", path, want)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if string(got) != "data" {
+				t.Errorf("file content = %q, want %q", got, "data")
+			}
+		})
+	}
+}
+
+func TestAttachment_SaveTo_NoContent(t *testing.T) {
+	t.Parallel()
+	a := &Attachment{Filename: "report.pdf"}
+	if _, err := a.SaveTo(t.TempDir()); !errors.Is(err, ErrAttachmentNoContent) {
+		t.Errorf("SaveTo() error = %v, want ErrAttachmentNoContent", err)
+	}
+}
+
+func TestAttachment_WriteTo(t *testing.T) {
+	t.Parallel()
+	var _ io.WriterTo = (*Attachment)(nil)
+
+	var buf bytes.Buffer
+	n, err := (&Attachment{Content: []byte("hello")}).WriteTo(&buf)
+	if err != nil || n != 5 || buf.String() != "hello" {
+		t.Errorf("WriteTo() = %d, %v, wrote %q; want 5, nil, hello", n, err, buf.String())
+	}
+
+	if _, err := (&Attachment{}).WriteTo(&buf); !errors.Is(err, ErrAttachmentNoContent) {
+		t.Errorf("WriteTo() without content error = %v, want ErrAttachmentNoContent", err)
+	}
+}
+
+func TestAttachment_VerifyChecksum(t *testing.T) {
+	t.Parallel()
+	content := []byte("invoice contents")
+	sum := sha256.Sum256(content)
+	hexSum := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  bool
+	}{
+		{"hex", hexSum, false},
+		{"uppercase hex", strings.ToUpper(hexSum), false},
+		{"base64", base64.StdEncoding.EncodeToString(sum[:]), false},
+		{"raw base64url", base64.RawURLEncoding.EncodeToString(sum[:]), false},
+		{"sha256 prefix", "sha256:" + hexSum, false},
+		{"empty checksum is skipped", "", false},
+		{"mismatch", strings.Repeat("0", 64), true},
+		{"unrecognized format", "not-a-checksum", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := &Attachment{Filename: "invoice.pdf", Content: content, Checksum: tt.checksum}
+			err := a.VerifyChecksum()
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("VerifyChecksum() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrChecksumMismatch) {
+				t.Fatalf("VerifyChecksum() error = %v, want ErrChecksumMismatch", err)
+			}
+			var csErr *ChecksumError
+			if !errors.As(err, &csErr) || csErr.Actual != hexSum || csErr.Expected != tt.checksum {
+				t.Errorf("ChecksumError = %+v, want Actual %s and Expected %s", csErr, hexSum, tt.checksum)
+			}
+		})
+	}
+}
+
+func TestAttachment_Category(t *testing.T) {
+	t.Parallel()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name        string
+		contentType string
+		content     []byte
+		want        string
+		wantImage   bool
+		wantText    bool
+	}{
+		{"declared image", "image/jpeg", nil, "image", true, false},
+		{"declared text with charset", "Text/Plain; charset=utf-8", nil, "text", false, true},
+		{"declared audio", "audio/mpeg", nil, "audio", false, false},
+		{"declared pdf", "application/pdf", []byte("%PDF-1.7"), "application", false, false},
+		{"png misdeclared as octet-stream", "application/octet-stream", png, "image", true, false},
+		{"png without content type", "", png, "image", true, false},
+		{"text without content type", "", []byte("plain words"), "text", false, true},
+		{"declared type wins over content", "text/csv", png, "text", false, true},
+		{"unknown without content", "", nil, "application", false, false},
+		{"octet-stream binary", "application/octet-stream", []byte{0x00, 0x01, 0x02}, "application", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := &Attachment{ContentType: tt.contentType, Content: tt.content}
+			if got := a.Category(); got != tt.want {
+				t.Errorf("Category() = %q, want %q", got, tt.want)
+			}
+			if got := a.IsImage(); got != tt.wantImage {
+				t.Errorf("IsImage() = %v, want %v", got, tt.wantImage)
+			}
+			if got := a.IsText(); got != tt.wantText {
+				t.Errorf("IsText() = %v, want %v", got, tt.wantText)
+			}
+		})
+	}
+}
+
 // Note: Full email tests require a real API connection
 // These tests verify the data structures
 // Integration tests are in the integration/ directory
+
+func TestEmail_Header(t *testing.T) {
+	t.Parallel()
+	email := &Email{
+		Headers: map[string]string{
+			"Message-ID":      "<abc@example.com>",
+			"x-custom-header": "custom",
+			"X-Empty":         "",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+		wantOK bool
+	}{
+		{"exact", "Message-ID", "<abc@example.com>", true},
+		{"different case", "message-id", "<abc@example.com>", true},
+		{"canonical form", "Message-Id", "<abc@example.com>", true},
+		{"stored lowercase", "X-Custom-Header", "custom", true},
+		{"empty value", "x-empty", "", true},
+		{"missing", "X-Missing", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := email.Header(tt.header)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Header(%q) = (%q, %v), want (%q, %v)", tt.header, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEmail_Header_NilHeaders(t *testing.T) {
+	t.Parallel()
+	email := &Email{}
+	if _, ok := email.Header("Subject"); ok {
+		t.Error("Header() ok = true for email without headers")
+	}
+	if got := email.HeaderValues("Subject"); got != nil {
+		t.Errorf("HeaderValues() = %v, want nil", got)
+	}
+}
+
+func TestEmail_HeaderValues(t *testing.T) {
+	t.Parallel()
+	email := &Email{
+		Headers: map[string]string{
+			"X-Tag": "one",
+			"x-tag": "two",
+			"Other": "three",
+		},
+	}
+
+	got := email.HeaderValues("X-TAG")
+	if len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("HeaderValues() = %v, want [one two]", got)
+	}
+}
+
+func TestEmail_HeaderValues_MultiHeaders(t *testing.T) {
+	t.Parallel()
+	email := &Email{
+		Headers: map[string]string{"X-Custom-Header": "custom"},
+		MultiHeaders: map[string][]string{
+			"Received": {"from a by b", "from c by a"},
+		},
+	}
+
+	if got, ok := email.Header("x-custom-header"); !ok || got != "custom" {
+		t.Errorf("Header(x-custom-header) = (%q, %v), want (custom, true)", got, ok)
+	}
+	if got := email.HeaderValues("x-custom-header"); !reflect.DeepEqual(got, []string{"custom"}) {
+		t.Errorf("HeaderValues(x-custom-header) = %v, want [custom]", got)
+	}
+	if got := email.HeaderValues("received"); !reflect.DeepEqual(got, []string{"from a by b", "from c by a"}) {
+		t.Errorf("HeaderValues(received) = %v, want both Received values", got)
+	}
+	if got, ok := email.Header("RECEIVED"); !ok || got != "from a by b" {
+		t.Errorf("Header(RECEIVED) = (%q, %v), want first value", got, ok)
+	}
+}
+
+func TestEmail_HeaderValues_MessageOrder(t *testing.T) {
+	t.Parallel()
+	email := &Email{
+		Headers:      map[string]string{"x-tag": "first", "X-TAG": "third"},
+		MultiHeaders: map[string][]string{"X-Tag": {"second-a", "second-b"}},
+		headerOrder:  []string{"Subject", "x-tag", "X-Tag", "X-TAG"},
+	}
+
+	want := []string{"first", "second-a", "second-b", "third"}
+	if got := email.HeaderValues("x-tag"); !reflect.DeepEqual(got, want) {
+		t.Errorf("HeaderValues() = %v, want %v", got, want)
+	}
+	if got, _ := email.Header("X-Tag"); got != "first" {
+		t.Errorf("Header(X-Tag) = %q, want first", got)
+	}
+}
+
+func TestEmail_Age(t *testing.T) {
+	t.Parallel()
+	tokyo := time.FixedZone("JST", 9*60*60)
+	e := &Email{ReceivedAt: time.Now().Add(-time.Hour).In(tokyo)}
+	if age := e.Age(); age < time.Hour || age > time.Hour+time.Minute {
+		t.Errorf("Age() = %v, want about 1h", age)
+	}
+	if age := (&Email{}).Age(); age != 0 {
+		t.Errorf("Age() with zero ReceivedAt = %v, want 0", age)
+	}
+}
+
+func TestEmail_JSONRoundtrip(t *testing.T) {
+	t.Parallel()
+	original := &Email{
+		ID:         "email-1",
+		From:       "sender@example.com",
+		To:         []string{"a@example.com", "b@example.com"},
+		EnvelopeTo: "a@example.com",
+		Subject:    "Round trip",
+		Text:       "Plain text",
+		HTML:       "<p>HTML</p>",
+		ReceivedAt: time.Date(2024, 1, 15, 10, 30, 0, 123000000, time.UTC),
+		Headers:    map[string]string{"Message-ID": "<abc@example.com>"},
+		Attachments: []Attachment{{
+			Filename:           "report.pdf",
+			ContentType:        "application/pdf",
+			Size:               4,
+			ContentID:          "cid-1",
+			ContentDisposition: "attachment",
+			Content:            []byte{0x00, 0x01, 0xfe, 0xff},
+			Checksum:           "sha256:abc",
+		}},
+		Links: []string{"https://example.com"},
+		AuthResults: &authresults.AuthResults{
+			SPF:  &authresults.SPFResult{Result: "pass", Domain: "example.com"},
+			DKIM: []authresults.DKIMResult{{Result: "pass", Domain: "example.com"}},
+		},
+		SpamAnalysis: &spamanalysis.SpamAnalysis{Status: spamanalysis.StatusAnalyzed},
+		IsRead:       true,
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded Email
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(original, &decoded) {
+		t.Errorf("round trip mismatch\noriginal: %+v\ndecoded:  %+v", original, &decoded)
+	}
+
+	// Marshaling the decoded value must produce identical output.
+	again, err := json.Marshal(&decoded)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("canonical form not stable:\n%s\n%s", data, again)
+	}
+}
+
+func TestEmail_MarshalJSON_AttachmentBase64(t *testing.T) {
+	t.Parallel()
+	email := &Email{Attachments: []Attachment{{Filename: "a.bin", Content: []byte{0x00, 0x01, 0xfe, 0xff}}}}
+	data, err := json.Marshal(email)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"content":"AAH+/w=="`) {
+		t.Errorf("attachment content not standard base64 in %s", data)
+	}
+}
+
+func TestEmail_ToRFC5322(t *testing.T) {
+	t.Parallel()
+	email := &Email{
+		ID:         "email-1",
+		From:       "Zoë Sender <sender@example.com>",
+		To:         []string{"a@example.com", "b@example.com"},
+		Subject:    "Grüße",
+		Text:       "Hello, world",
+		HTML:       "<p>Hello, world</p>",
+		ReceivedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		Headers: map[string]string{
+			"Message-ID":   "<abc@example.com>",
+			"Content-Type": "text/plain",
+			"X-Injected":   "a\r\nBcc: evil@example.com",
+		},
+		MultiHeaders: map[string][]string{"Received": {"from a", "from b"}},
+		Attachments: []Attachment{{
+			Filename:    "report.pdf",
+			ContentType: "application/pdf",
+			ContentID:   "cid-1",
+			Content:     []byte{0x00, 0x01, 0xfe, 0xff},
+		}},
+	}
+
+	raw, err := email.ToRFC5322()
+	if err != nil {
+		t.Fatalf("ToRFC5322() error = %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("mail.ReadMessage() error = %v\n%s", err, raw)
+	}
+
+	dec := new(mime.WordDecoder)
+	subject, _ := dec.DecodeHeader(msg.Header.Get("Subject"))
+	from, _ := msg.Header.AddressList("From")
+	to, _ := msg.Header.AddressList("To")
+	headerTests := []struct {
+		name, got, want string
+	}{
+		{"Subject", subject, "Grüße"},
+		{"From", from[0].Name + " " + from[0].Address, "Zoë Sender sender@example.com"},
+		{"To", to[0].Address + "," + to[1].Address, "a@example.com,b@example.com"},
+		{"Date", msg.Header.Get("Date"), "Mon, 15 Jan 2024 10:30:00 +0000"},
+		{"Message-ID", msg.Header.Get("Message-ID"), "<abc@example.com>"},
+		{"Received", strings.Join(msg.Header["Received"], ";"), "from a;from b"},
+		{"X-Injected", msg.Header.Get("X-Injected"), "a Bcc: evil@example.com"},
+		{"Bcc", msg.Header.Get("Bcc"), ""},
+	}
+	for _, tt := range headerTests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, want multipart/mixed", msg.Header.Get("Content-Type"))
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+
+	alt, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("NextPart() error = %v", err)
+	}
+	altType, altParams, _ := mime.ParseMediaType(alt.Header.Get("Content-Type"))
+	if altType != "multipart/alternative" {
+		t.Fatalf("first part type = %q, want multipart/alternative", altType)
+	}
+	ar := multipart.NewReader(alt, altParams["boundary"])
+	for _, want := range []struct{ mediaType, body string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	} {
+		part, err := ar.NextPart()
+		if err != nil {
+			t.Fatalf("alternative NextPart() error = %v", err)
+		}
+		body, _ := io.ReadAll(part) // multipart decodes quoted-printable
+		if got, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type")); got != want.mediaType || string(body) != want.body {
+			t.Errorf("alternative part = %s %q, want %s %q", got, body, want.mediaType, want.body)
+		}
+	}
+
+	att, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("attachment NextPart() error = %v", err)
+	}
+	if att.FileName() != "report.pdf" || att.Header.Get("Content-Id") != "<cid-1>" {
+		t.Errorf("attachment filename %q, Content-Id %q", att.FileName(), att.Header.Get("Content-Id"))
+	}
+	encoded, _ := io.ReadAll(att)
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || !bytes.Equal(content, email.Attachments[0].Content) {
+		t.Errorf("attachment content = %x (%v), want %x", content, err, email.Attachments[0].Content)
+	}
+
+	// The output is deterministic, so it can be used as a golden file.
+	again, _ := email.ToRFC5322()
+	if !bytes.Equal(raw, again) {
+		t.Error("ToRFC5322() output differs between calls")
+	}
+}
+
+func TestEmail_ToRFC5322_TextOnly(t *testing.T) {
+	t.Parallel()
+	raw, err := (&Email{Subject: "Plain", Text: "body"}).ToRFC5322()
+	if err != nil {
+		t.Fatalf("ToRFC5322() error = %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("mail.ReadMessage() error = %v", err)
+	}
+	if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/plain; charset=utf-8", got)
+	}
+	if msg.Header.Get("Date") != "" || msg.Header.Get("From") != "" {
+		t.Error("Date and From should be omitted when unknown")
+	}
+}
+
+func TestEmail_ToRFC5322_InvalidHeaderName(t *testing.T) {
+	t.Parallel()
+	email := &Email{Headers: map[string]string{"Bad Name": "x"}}
+	if _, err := email.ToRFC5322(); err == nil {
+		t.Error("ToRFC5322() error = nil, want error for invalid header name")
+	}
+}
+
+func TestEmail_MarshalJSON_ExcludeAttachmentContent(t *testing.T) {
+	t.Parallel()
+	email := Email{
+		ID:                       "email-1",
+		Attachments:              []Attachment{{Filename: "a.txt", Size: 5, Content: []byte("hello")}},
+		ExcludeAttachmentContent: true,
+	}
+
+	data, err := json.Marshal(email)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), `"content"`) {
+		t.Errorf("content should be excluded: %s", data)
+	}
+	if !strings.Contains(string(data), `"size":5`) {
+		t.Errorf("attachment metadata should be kept: %s", data)
+	}
+	if string(email.Attachments[0].Content) != "hello" {
+		t.Error("MarshalJSON must not modify the email's attachments")
+	}
+
+	// Without a declared size, the size of the dropped content is reported.
+	email.Attachments[0].Size = 0
+	data, err = json.Marshal(email)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"size":5`) {
+		t.Errorf("size should fall back to the content length: %s", data)
+	}
+}
+
+func TestEmail_TruncateBody(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		text, html    string
+		n             int
+		wantText      string
+		wantHTML      string
+		wantTruncated bool
+	}{
+		{name: "disabled", text: "hello", html: "<p>hi</p>", n: 0, wantText: "hello", wantHTML: "<p>hi</p>"},
+		{name: "under limit", text: "hello", html: "<p>", n: 5, wantText: "hello", wantHTML: "<p>"},
+		{name: "text truncated", text: "hello world", n: 5, wantText: "hello", wantTruncated: true},
+		{name: "html truncated", text: "hi", html: "<p>hello</p>", n: 4, wantText: "hi", wantHTML: "<p>h", wantTruncated: true},
+		// "é" is two bytes; cutting at 2 would split it.
+		{name: "utf-8 boundary", text: "aé", n: 2, wantText: "a", wantTruncated: true},
+		{name: "multi-byte rune at start", text: "日本", n: 2, wantText: "", wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &Email{Text: tt.text, HTML: tt.html}
+			e.truncateBody(tt.n)
+			if e.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", e.Text, tt.wantText)
+			}
+			if e.HTML != tt.wantHTML {
+				t.Errorf("HTML = %q, want %q", e.HTML, tt.wantHTML)
+			}
+			if e.BodyTruncated != tt.wantTruncated {
+				t.Errorf("BodyTruncated = %v, want %v", e.BodyTruncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestParseListUnsubscribe(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "mailto and https", value: "<mailto:unsub@example.com?subject=unsubscribe>, <https://example.com/u/123>",
+			want: []string{"mailto:unsub@example.com?subject=unsubscribe", "https://example.com/u/123"}},
+		{name: "folded", value: "<https://example.com/\r\n u/123>", want: []string{"https://example.com/u/123"}},
+		{name: "no brackets", value: "https://example.com/u, mailto:u@example.com",
+			want: []string{"https://example.com/u", "mailto:u@example.com"}},
+		{name: "unterminated bracket", value: "<https://example.com/u>, <mailto:u@", want: []string{"https://example.com/u"}},
+		{name: "empty", value: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := parseListUnsubscribe(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseListUnsubscribe(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmail_UnsubscribeLinks(t *testing.T) {
+	t.Parallel()
+	e := &Email{ListUnsubscribe: []string{"MAILTO:u@example.com", "http://example.com/u", "https://example.com/u"}}
+	want := []string{"MAILTO:u@example.com", "https://example.com/u"}
+	if got := e.UnsubscribeLinks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnsubscribeLinks() = %q, want %q", got, want)
+	}
+	if got := (&Email{}).UnsubscribeLinks(); got != nil {
+		t.Errorf("UnsubscribeLinks() without header = %q, want nil", got)
+	}
+}
+
+func TestEmail_ExtractOTP(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		email  *Email
+		want   string
+		wantOK bool
+	}{
+		{name: "plain text", email: &Email{Text: "Your code is 482913"}, want: "482913", wantOK: true},
+		{name: "surrounding punctuation", email: &Email{Text: "Code: (4829). Expires soon."}, want: "4829", wantOK: true},
+		{name: "html markup", email: &Email{HTML: `<p>Your code is <strong>73910452</strong></p>`}, want: "73910452", wantOK: true},
+		{name: "html digits split by tags", email: &Email{HTML: `<td>12</td><td>345678</td>`}, want: "345678", wantOK: true},
+		{name: "html ignores style", email: &Email{HTML: `<style>p{color:#123456}</style><p>Code&nbsp;9876</p>`}, want: "9876", wantOK: true},
+		{name: "text before html", email: &Email{Text: "1111", HTML: "<b>2222</b>"}, want: "1111", wantOK: true},
+		{name: "too short and too long", email: &Email{Text: "Call 123 or 1234567890"}, wantOK: false},
+		{name: "no code", email: &Email{Text: "Welcome aboard", HTML: "<p>Welcome aboard</p>"}, wantOK: false},
+		{name: "empty email", email: &Email{}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := tt.email.ExtractOTP()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ExtractOTP() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEmail_ExtractCode(t *testing.T) {
+	t.Parallel()
+	e := &Email{HTML: `<p>Reset token: <code>AB-12CD</code></p>`}
+
+	if got, ok := e.ExtractCode(regexp.MustCompile(`token: ([A-Z0-9-]+)`)); !ok || got != "AB-12CD" {
+		t.Errorf("ExtractCode() with group = %q, %v; want AB-12CD, true", got, ok)
+	}
+	if got, ok := e.ExtractCode(regexp.MustCompile(`[A-Z]{2}-\w+`)); !ok || got != "AB-12CD" {
+		t.Errorf("ExtractCode() without group = %q, %v; want AB-12CD, true", got, ok)
+	}
+	if got, ok := e.ExtractCode(regexp.MustCompile(`code=(\d+)`)); ok || got != "" {
+		t.Errorf("ExtractCode() no match = %q, %v; want empty, false", got, ok)
+	}
+}
+
+func TestEmail_BodyText(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		email *Email
+		want  string
+	}{
+		{name: "text only", email: &Email{Text: "Hello,\n\nWelcome aboard."}, want: "Hello,\n\nWelcome aboard."},
+		{name: "multipart prefers text", email: &Email{Text: "Plain body", HTML: "<p>HTML body</p>"}, want: "Plain body"},
+		{
+			name: "html only",
+			email: &Email{HTML: `<html><head><style>p { margin: 0 }</style></head><body>
+				<p>Hi   <b>Ada</b>,</p>
+				<p>Your code is&nbsp;<strong>482913</strong>.<br>It expires in 10&nbsp;minutes.</p>
+				<div>Tom &amp; Jerry</div>
+				<script>track()</script>
+			</body></html>`},
+			want: "Hi Ada,\nYour code is 482913.\nIt expires in 10 minutes.\nTom & Jerry",
+		},
+		{name: "self-closing br", email: &Email{HTML: "one<br/>two<BR />three"}, want: "one\ntwo\nthree"},
+		{name: "empty email", email: &Email{}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.email.BodyText(); got != tt.want {
+				t.Errorf("BodyText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmail_Project(t *testing.T) {
+	t.Parallel()
+	full := func() *Email {
+		return &Email{
+			ID: "e1", Subject: "s", From: "f", Text: "t", HTML: "h", Links: []string{"l"},
+			Headers: map[string]string{"k": "v"}, Attachments: []Attachment{{Filename: "a"}},
+			AuthResults: &authresults.AuthResults{}, SpamAnalysis: &spamanalysis.SpamAnalysis{},
+		}
+	}
+
+	e := full()
+	e.project(FieldAll)
+	if !reflect.DeepEqual(e, full()) {
+		t.Errorf("project(FieldAll) changed the email: %+v", e)
+	}
+
+	e = full()
+	e.project(FieldAttachments | FieldAuth)
+	if e.ID != "e1" || e.Subject != "" || e.From != "" || e.Text != "" || e.Headers != nil ||
+		len(e.Attachments) != 1 || e.AuthResults == nil || e.SpamAnalysis == nil {
+		t.Errorf("project(FieldAttachments|FieldAuth) = %+v", e)
+	}
+}
@@ -1,7 +1,12 @@
 package vaultsandbox
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/vaultsandbox/client-go/internal/apierrors"
+	"github.com/vaultsandbox/client-go/internal/crypto"
 )
 
 // Sentinel errors for errors.Is() checks - re-exported from internal package
@@ -18,6 +23,11 @@ var (
 	// ErrInboxNotFound is returned when an inbox is not found.
 	ErrInboxNotFound = apierrors.ErrInboxNotFound
 
+	// ErrInboxExpired is returned when an inbox has expired and been removed
+	// by the server (HTTP 410, or a 404 whose message reports expiry).
+	// Unlike ErrInboxNotFound, it means the inbox did exist.
+	ErrInboxExpired = apierrors.ErrInboxExpired
+
 	// ErrEmailNotFound is returned when an email is not found.
 	ErrEmailNotFound = apierrors.ErrEmailNotFound
 
@@ -41,6 +51,58 @@ var (
 
 	// ErrChaosDisabled is returned when chaos is disabled globally on the server.
 	ErrChaosDisabled = apierrors.ErrChaosDisabled
+
+	// ErrMaxElapsedTime is returned when an API call and its retries exceed
+	// the budget set with WithMaxElapsedTime. The error also wraps the last
+	// attempt's error.
+	ErrMaxElapsedTime = apierrors.ErrMaxElapsedTime
+
+	// ErrAttachmentTooLarge is returned when an attachment exceeds the size
+	// configured with WithMaxAttachmentSize.
+	ErrAttachmentTooLarge = crypto.ErrAttachmentTooLarge
+
+	// ErrChecksumMismatch is returned when an attachment's content does not
+	// match its declared SHA-256 checksum.
+	ErrChecksumMismatch = errors.New("attachment checksum mismatch")
+
+	// ErrAttachmentNotFound is returned when an email has no attachment with
+	// the requested filename.
+	ErrAttachmentNotFound = errors.New("attachment not found")
+
+	// ErrAttachmentNoContent is returned when saving or writing an attachment
+	// whose content is not available.
+	ErrAttachmentNoContent = errors.New("attachment has no content")
+
+	// ErrUnexpectedSuite is returned when an encrypted payload's algorithm
+	// suite differs from the suite required by the inbox (see WithRequireSuite).
+	ErrUnexpectedSuite = crypto.ErrUnexpectedSuite
+
+	// ErrUnexpectedAAD is returned when an encrypted payload's associated
+	// data differs from the value expected by the inbox (see WithAAD).
+	ErrUnexpectedAAD = crypto.ErrUnexpectedAAD
+
+	// ErrClockSkew is returned when an email's signed timestamp is further in
+	// the future than the tolerance configured with WithClockSkewTolerance and
+	// strict mode is enabled.
+	ErrClockSkew = errors.New("clock skew detected")
+
+	// ErrDetachedInbox is returned by network methods of an inbox that has no
+	// client, such as one created with NewDetachedInbox. Bind it to a client
+	// with Inbox.Attach first.
+	ErrDetachedInbox = errors.New("inbox is detached from any client")
+
+	// ErrUnsupportedContentEncoding is returned when an email's parsed content
+	// uses a content encoding the client cannot decode.
+	ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+	// ErrDecompressedTooLarge is returned when compressed parsed content
+	// expands beyond the decompression limit, as a decompression bomb would.
+	ErrDecompressedTooLarge = errors.New("decompressed content too large")
+
+	// ErrUnsupportedSuite is returned when WithRequireSuite, or the
+	// requireSuite of imported inbox data, names an algorithm suite the
+	// client cannot decrypt. Only DefaultAlgorithmSuite is supported.
+	ErrUnsupportedSuite = errors.New("unsupported algorithm suite")
 )
 
 // ResourceType indicates which type of resource an error relates to.
@@ -60,9 +122,95 @@ const (
 // APIError represents an HTTP error from the VaultSandbox API.
 type APIError = apierrors.APIError
 
+// RateLimitError is returned when the server keeps answering 429 Too Many
+// Requests after all retries are exhausted. RetryAfter and ResetAt carry the
+// server's back-off hints. errors.Is(err, ErrRateLimited) reports true.
+type RateLimitError = apierrors.RateLimitError
+
 // NetworkError represents a network-level failure.
 type NetworkError = apierrors.NetworkError
 
 // SignatureVerificationError indicates signature verification failed,
 // including server key mismatch (potential MITM attack).
 type SignatureVerificationError = apierrors.SignatureVerificationError
+
+// ClockSkewWarning describes an email whose signed receivedAt timestamp is
+// later than the local clock plus the configured tolerance. This usually
+// indicates a misconfigured server or client clock, or a replayed payload.
+//
+// In strict mode it is returned from decryption; otherwise it is reported to
+// the [WithOnSyncError] callback and the email is delivered normally.
+// errors.Is(w, ErrClockSkew) reports true.
+type ClockSkewWarning struct {
+	// EmailID is the ID of the affected email.
+	EmailID string
+	// ReceivedAt is the signed timestamp from the email metadata.
+	ReceivedAt time.Time
+	// Skew is how far ReceivedAt is ahead of the local clock.
+	Skew time.Duration
+	// Tolerance is the configured maximum allowed skew.
+	Tolerance time.Duration
+}
+
+func (w *ClockSkewWarning) Error() string {
+	return fmt.Sprintf("email %s: receivedAt %s is %v ahead of local clock (tolerance %v)",
+		w.EmailID, w.ReceivedAt.Format(time.RFC3339), w.Skew, w.Tolerance)
+}
+
+// Unwrap returns ErrClockSkew so errors.Is works with the sentinel.
+func (w *ClockSkewWarning) Unwrap() error {
+	return ErrClockSkew
+}
+
+// ChecksumError describes an attachment whose content does not match its
+// Checksum field. It is returned by [Attachment.VerifyChecksum] and, with
+// [WithVerifyAttachmentChecksums], from decryption.
+// errors.Is(err, ErrChecksumMismatch) reports true.
+type ChecksumError struct {
+	// Filename is the attachment's filename.
+	Filename string
+	// Expected is the checksum declared by the server.
+	Expected string
+	// Actual is the hex-encoded SHA-256 of the attachment content.
+	Actual string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("attachment %q: content SHA-256 %s does not match checksum %s", e.Filename, e.Actual, e.Expected)
+}
+
+// Unwrap returns ErrChecksumMismatch so errors.Is works with the sentinel.
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// InboxCollisionError is returned by CreateInbox when the server rejects the
+// inbox address as already taken. errors.Is(err, ErrInboxAlreadyExists)
+// reports true.
+//
+// Explicit distinguishes the two causes: an address requested with
+// [WithEmailAddress] is never retried, since retrying cannot free it, while a
+// server-generated address is retried up to the [WithCollisionRetry] limit and
+// Attempts records how many creation requests were made.
+type InboxCollisionError struct {
+	// EmailAddress is the requested address, or empty for a random inbox.
+	EmailAddress string
+	// Explicit reports whether the caller requested a specific local part.
+	Explicit bool
+	// Attempts is the number of creation requests made.
+	Attempts int
+	// Err is the underlying API error.
+	Err error
+}
+
+func (e *InboxCollisionError) Error() string {
+	if e.Explicit {
+		return fmt.Sprintf("email address %s is already taken: %v", e.EmailAddress, e.Err)
+	}
+	return fmt.Sprintf("generated inbox address collided on all %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the underlying API error.
+func (e *InboxCollisionError) Unwrap() error {
+	return e.Err
+}
@@ -18,6 +18,7 @@ func TestSentinelErrors(t *testing.T) {
 		{"ErrClientClosed", ErrClientClosed},
 		{"ErrUnauthorized", ErrUnauthorized},
 		{"ErrInboxNotFound", ErrInboxNotFound},
+		{"ErrInboxExpired", ErrInboxExpired},
 		{"ErrEmailNotFound", ErrEmailNotFound},
 		{"ErrInboxAlreadyExists", ErrInboxAlreadyExists},
 		{"ErrInvalidImportData", ErrInvalidImportData},
@@ -89,6 +90,7 @@ func TestAPIError_Is(t *testing.T) {
 		{"404 matches ErrInboxNotFound", 404, ErrInboxNotFound, true},
 		{"404 matches ErrEmailNotFound", 404, ErrEmailNotFound, true},
 		{"409 matches ErrInboxAlreadyExists", 409, ErrInboxAlreadyExists, true},
+		{"410 matches ErrInboxExpired", 410, ErrInboxExpired, true},
 		{"429 matches ErrRateLimited", 429, ErrRateLimited, true},
 		{"500 does not match ErrUnauthorized", 500, ErrUnauthorized, false},
 		{"200 does not match anything", 200, ErrUnauthorized, false},
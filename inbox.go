@@ -2,6 +2,8 @@ package vaultsandbox
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
@@ -13,11 +15,16 @@ type Inbox struct {
 	emailAddress string
 	expiresAt    time.Time
 	inboxHash    string
-	serverSigPk  []byte          // Only set for encrypted inboxes
+	serverSigPks [][]byte        // Accepted server keys, pinned key first; only set for encrypted inboxes
 	keypair      *crypto.Keypair // Only set for encrypted inboxes
 	client       *Client
 	emailAuth    bool
 	encrypted    bool
+	cachedEmails []*api.RawEmail // Set for inboxes imported from a bundle
+	requireSuite *AlgorithmSuite // Pinned algorithm suite; nil means DefaultAlgorithmSuite()
+	aadFunc      AADFunc         // Expected AAD per payload; nil leaves AAD unchecked
+	mu           sync.RWMutex    // Protects expiresAt, serverSigPks and aadFunc, and client during Attach
+	drain        drainTracker    // In-flight live events, for StopAndDrain
 }
 
 // SyncStatus is a type alias for api.SyncStatus.
@@ -31,6 +38,8 @@ func (i *Inbox) EmailAddress() string {
 
 // ExpiresAt returns when the inbox expires.
 func (i *Inbox) ExpiresAt() time.Time {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	return i.expiresAt
 }
 
@@ -39,9 +48,25 @@ func (i *Inbox) InboxHash() string {
 	return i.inboxHash
 }
 
+// Key returns a stable identity for the inbox, suitable as a map key. It is
+// the inbox hash, so two *Inbox values for the same inbox (for example one
+// created and one imported) have the same key.
+func (i *Inbox) Key() string {
+	return i.inboxHash
+}
+
+// Equal reports whether i and other refer to the same inbox, comparing
+// inbox hashes. Two nil inboxes are equal; a nil and a non-nil inbox are not.
+func (i *Inbox) Equal(other *Inbox) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+	return i.inboxHash == other.inboxHash
+}
+
 // IsExpired checks if the inbox has expired.
 func (i *Inbox) IsExpired() bool {
-	return time.Now().After(i.expiresAt)
+	return i.client.clock().Now().After(i.ExpiresAt())
 }
 
 // EmailAuth returns whether email authentication (SPF, DKIM, DMARC, PTR) is enabled.
@@ -57,24 +82,132 @@ func (i *Inbox) Encrypted() bool {
 	return i.encrypted
 }
 
+// NewDetachedInbox reconstructs an inbox from exported data without a client.
+// A detached inbox works offline: accessors such as EmailAddress, IsExpired,
+// and Export behave normally, while methods that need the server return
+// [ErrDetachedInbox] and Watch never delivers. Use [Inbox.Attach] to bind it
+// to a live client later.
+func NewDetachedInbox(data *ExportedInbox) (*Inbox, error) {
+	if data == nil {
+		return nil, fmt.Errorf("exported inbox data cannot be nil")
+	}
+	return newInboxFromExport(data, nil)
+}
+
+// Detached reports whether the inbox has no client, as returned by
+// [NewDetachedInbox].
+func (i *Inbox) Detached() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.client == nil
+}
+
+// Attach binds a detached inbox to client and registers it for live
+// delivery, as if it had been imported with [Client.ImportInbox]. Unlike
+// ImportInbox, Attach does not check that the inbox still exists on the
+// server. It returns an error if the inbox is already attached, the client is
+// closed, or the client already manages an inbox with the same address.
+//
+// Concurrent Attach calls are safe: at most one succeeds. Other methods of
+// the inbox read its client without locking, so Attach must not run at the
+// same time as any method other than Attach and Detached.
+func (i *Inbox) Attach(client *Client) error {
+	if client == nil {
+		return fmt.Errorf("client cannot be nil")
+	}
+
+	// Claim the inbox first. i.mu is released before the client's lock is
+	// taken, as the client takes its own lock before the inbox's.
+	i.mu.Lock()
+	if i.client != nil {
+		i.mu.Unlock()
+		return fmt.Errorf("inbox %s is already attached to a client", i.emailAddress)
+	}
+	i.client = client
+	pinned := i.serverSigPks
+	if len(pinned) > 0 {
+		i.serverSigPks = client.pinServerKey(pinned[0])
+	}
+	i.mu.Unlock()
+
+	client.mu.Lock()
+	_, exists := client.inboxes[i.emailAddress]
+	client.mu.Unlock()
+	err := ErrInboxAlreadyExists
+	if !exists {
+		err = client.registerInbox(i)
+	}
+	if err != nil {
+		i.mu.Lock()
+		i.client = nil
+		i.serverSigPks = pinned
+		i.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// serverKeys returns the server keys the inbox accepts, pinned key first.
+func (i *Inbox) serverKeys() [][]byte {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.serverSigPks
+}
+
+// checkAttached returns ErrDetachedInbox if the inbox has no client.
+func (i *Inbox) checkAttached() error {
+	if i.client == nil {
+		return ErrDetachedInbox
+	}
+	return nil
+}
+
 // GetSyncStatus retrieves the synchronization status of the inbox.
 // This includes the number of emails and a hash of the email list,
 // which can be used to efficiently check for changes.
 func (i *Inbox) GetSyncStatus(ctx context.Context) (*SyncStatus, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	return i.client.apiClient.GetInboxSync(ctx, i.emailAddress)
 }
 
 // Delete deletes the inbox.
 func (i *Inbox) Delete(ctx context.Context) error {
+	if err := i.checkAttached(); err != nil {
+		return err
+	}
 	return i.client.DeleteInbox(ctx, i.emailAddress)
 }
 
+// ExtendTTL pushes out the inbox expiry so that it expires ttl from now, for
+// long test runs that would otherwise outlive the inbox. ttl is validated
+// like [WithTTL] in [Client.CreateInbox]: it must be at least [MinTTL] and at
+// most the server's MaxTTL. On success ExpiresAt reports the new expiry. It
+// returns [ErrInboxNotFound] if the inbox no longer exists.
+func (i *Inbox) ExtendTTL(ctx context.Context, ttl time.Duration) error {
+	if err := i.checkAttached(); err != nil {
+		return err
+	}
+	if err := i.client.validateTTL(ttl); err != nil {
+		return err
+	}
+	expiresAt, err := i.client.apiClient.ExtendInboxTTL(ctx, i.emailAddress, ttl)
+	if err != nil {
+		return err
+	}
+	i.mu.Lock()
+	i.expiresAt = expiresAt
+	i.mu.Unlock()
+	return nil
+}
+
 func newInboxFromResult(resp *api.CreateInboxResult, c *Client) *Inbox {
 	return &Inbox{
 		emailAddress: resp.EmailAddress,
 		expiresAt:    resp.ExpiresAt,
 		inboxHash:    resp.InboxHash,
-		serverSigPk:  resp.ServerSigPk,
+		serverSigPks: c.pinServerKey(resp.ServerSigPk),
 		keypair:      resp.Keypair,
 		client:       c,
 		emailAuth:    resp.EmailAuth,
@@ -2,33 +2,85 @@ package vaultsandbox
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 
+	"github.com/vaultsandbox/client-go/internal/api"
 	"github.com/vaultsandbox/client-go/internal/crypto"
+	"github.com/vaultsandbox/client-go/internal/tracing"
 )
 
 // GetEmails fetches all emails in the inbox with full content.
-func (i *Inbox) GetEmails(ctx context.Context) ([]*Email, error) {
-	resp, err := i.client.apiClient.GetEmails(ctx, i.emailAddress, true)
+// Use [WithServerFilter] or [WithUnreadOnly] to restrict the result,
+// [WithMaxBodyBytes] to cap body sizes, and [WithFields] to decrypt only the
+// fields you need.
+func (i *Inbox) GetEmails(ctx context.Context, opts ...FetchOption) (_ []*Email, err error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
+	ctx, span := i.client.startSpan(ctx, "vaultsandbox.GetEmails", "vaultsandbox.inbox.hash", i.inboxHash)
+	defer func() { tracing.End(span, err) }()
+	cfg := &fetchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	fields := cfg.resolvedFields()
+	params := &api.ListEmailsParams{IncludeContent: fields&fieldsParsed != 0}
+	// Only plain inboxes can be filtered server-side; encrypted metadata is
+	// opaque to the server.
+	if cfg.serverFilter != nil && !i.encrypted {
+		params.Subject = cfg.serverFilter.Subject
+		params.From = cfg.serverFilter.From
+	}
+	params.Read = cfg.readState
+
+	resp, err := i.client.apiClient.ListEmails(ctx, i.emailAddress, params)
 	if err != nil {
 		return nil, err
 	}
 
-	emails := make([]*Email, 0, len(resp.Emails))
-	for _, e := range resp.Emails {
-		email, err := i.decryptEmail(e)
-		if err != nil {
-			return nil, err //coverage:ignore
+	raw := resp.Emails
+	if cfg.readState != nil {
+		// Skip decrypting emails a server without read filtering returned.
+		raw = slices.DeleteFunc(slices.Clone(raw), func(e *api.RawEmail) bool {
+			return !cfg.matchesReadState(e.IsRead)
+		})
+	}
+
+	decrypted, err := i.decryptEmails(ctx, raw, cfg.decryptWorkers, cfg.maxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]*Email, 0, len(decrypted))
+	for _, email := range decrypted {
+		if cfg.serverFilter != nil && !cfg.serverFilter.matches(email) {
+			continue
 		}
+		email.project(fields)
+		email.truncateBody(cfg.maxBodyBytes)
 		emails = append(emails, email)
 	}
 
 	return emails, nil
 }
 
+// GetUnreadEmails fetches the emails in the inbox that have not been marked
+// as read. It is shorthand for GetEmails with [WithUnreadOnly].
+func (i *Inbox) GetUnreadEmails(ctx context.Context, opts ...FetchOption) ([]*Email, error) {
+	return i.GetEmails(ctx, append(opts, WithUnreadOnly())...)
+}
+
 // GetEmailsMetadataOnly fetches email metadata without full content.
 // This is more efficient when you only need to display email summaries.
 func (i *Inbox) GetEmailsMetadataOnly(ctx context.Context) ([]*EmailMetadata, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	resp, err := i.client.apiClient.GetEmails(ctx, i.emailAddress, false)
 	if err != nil {
 		return nil, err
@@ -36,7 +88,7 @@ func (i *Inbox) GetEmailsMetadataOnly(ctx context.Context) ([]*EmailMetadata, er
 
 	emails := make([]*EmailMetadata, 0, len(resp.Emails))
 	for _, e := range resp.Emails {
-		metadata, err := i.decryptMetadata(e)
+		metadata, err := i.decryptMetadata(ctx, e)
 		if err != nil {
 			return nil, err
 		}
@@ -47,18 +99,171 @@ func (i *Inbox) GetEmailsMetadataOnly(ctx context.Context) ([]*EmailMetadata, er
 }
 
 // GetEmail fetches a specific email by ID.
-func (i *Inbox) GetEmail(ctx context.Context, emailID string) (*Email, error) {
+// [WithMaxBodyBytes] and [WithFields] apply; [WithServerFilter] is ignored.
+func (i *Inbox) GetEmail(ctx context.Context, emailID string, opts ...FetchOption) (*Email, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
+	cfg := &fetchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	resp, err := i.client.apiClient.GetEmail(ctx, i.emailAddress, emailID)
 	if err != nil {
 		return nil, err
 	}
 
-	return i.decryptEmail(resp)
+	fields := cfg.resolvedFields()
+	if fields&fieldsParsed == 0 {
+		// Skip decrypting content that would be discarded.
+		resp.EncryptedParsed = nil
+		resp.Parsed = ""
+	}
+
+	email, err := i.decryptEmailWithin(ctx, resp, cfg.maxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	email.project(fields)
+	email.truncateBody(cfg.maxBodyBytes)
+	return email, nil
+}
+
+// GetEmailByMessageID fetches the email whose Message-ID header equals
+// messageID. Unlike the server-assigned ID, the Message-ID is set by the
+// sending system, so it can be used to cross-reference the two. The match
+// ignores case and surrounding angle brackets, so "abc@example.com" finds
+// "<ABC@example.com>". Every email is fetched and decrypted to find it;
+// [ErrEmailNotFound] is returned if none matches.
+func (i *Inbox) GetEmailByMessageID(ctx context.Context, messageID string) (*Email, error) {
+	emails, err := i.GetEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	want := normalizeMessageID(messageID)
+	for _, email := range emails {
+		if v, ok := email.Header("Message-ID"); ok && normalizeMessageID(v) == want {
+			return email, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: message ID %q", ErrEmailNotFound, messageID)
+}
+
+// normalizeMessageID strips the whitespace and angle brackets around a
+// Message-ID and lowercases it for comparison.
+func normalizeMessageID(id string) string {
+	id = strings.TrimSpace(id)
+	id = strings.TrimSuffix(strings.TrimPrefix(id, "<"), ">")
+	return strings.ToLower(id)
+}
+
+// OldestEmail fetches the inbox's emails and returns the one with the
+// earliest ReceivedAt. Of emails received at the same instant, the first
+// listed by the server wins. Returns [ErrEmailNotFound] if the inbox is
+// empty.
+func (i *Inbox) OldestEmail(ctx context.Context) (*Email, error) {
+	emails, err := i.GetEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extremeEmail(emails, false)
+}
+
+// NewestEmail fetches the inbox's emails and returns the one with the latest
+// ReceivedAt. Ties and the empty case are handled as in [Inbox.OldestEmail].
+func (i *Inbox) NewestEmail(ctx context.Context) (*Email, error) {
+	emails, err := i.GetEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extremeEmail(emails, true)
+}
+
+// extremeEmail returns the oldest email of emails, or the newest if newest is
+// set. Times are compared as instants, so emails whose ReceivedAt carries
+// different time zones order correctly.
+func extremeEmail(emails []*Email, newest bool) (*Email, error) {
+	var best *Email
+	for _, e := range emails {
+		if best == nil ||
+			(newest && e.ReceivedAt.After(best.ReceivedAt)) ||
+			(!newest && e.ReceivedAt.Before(best.ReceivedAt)) {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: inbox is empty", ErrEmailNotFound)
+	}
+	return best, nil
+}
+
+// GetAttachmentPreview returns the first n bytes of the named attachment of
+// an email, for example to sniff its real content type before processing it.
+// The filename match is exact; if several attachments share the name, the
+// first is used. A non-positive n returns the whole attachment.
+//
+// Attachments are stored inline in the email's encrypted payload, and AES-GCM
+// can only authenticate the payload as a whole, so the full email is fetched
+// and decrypted; only the returned slice is truncated. The preview is a copy
+// and does not retain the full attachment in memory. Returns
+// [ErrAttachmentNotFound] if no attachment has the given filename.
+func (i *Inbox) GetAttachmentPreview(ctx context.Context, emailID, filename string, n int) ([]byte, error) {
+	email, err := i.GetEmail(ctx, emailID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range email.Attachments {
+		if a.Filename != filename {
+			continue
+		}
+		content := a.Content
+		if n > 0 && len(content) > n {
+			content = content[:n]
+		}
+		return append([]byte(nil), content...), nil
+	}
+	return nil, fmt.Errorf("%w: %q in email %s", ErrAttachmentNotFound, filename, emailID)
+}
+
+// DownloadAttachment writes the content of the named attachment of an email
+// to w. The filename match is exact; if several attachments share the name,
+// the first is used. Returns [ErrAttachmentNotFound] if no attachment has the
+// given filename.
+//
+// The protocol encrypts the email's parsed content, attachments included, as
+// a single AES-GCM payload that can only be authenticated as a whole, so it
+// cannot be decrypted in chunks: the email is fetched and decrypted in full
+// before anything is written, and peak memory is roughly twice the email's
+// size. Only the attachment fields are kept and the content is written
+// without further copies. If [WithVerifyAttachmentChecksums] is set, a
+// corrupted attachment fails before any bytes reach w.
+func (i *Inbox) DownloadAttachment(ctx context.Context, emailID, filename string, w io.Writer) error {
+	email, err := i.GetEmail(ctx, emailID, WithFields(FieldAttachments))
+	if err != nil {
+		return err
+	}
+
+	for j := range email.Attachments {
+		a := &email.Attachments[j]
+		if a.Filename != filename {
+			continue
+		}
+		if _, err := a.WriteTo(w); err != nil {
+			return fmt.Errorf("download attachment %q: %w", filename, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %q in email %s", ErrAttachmentNotFound, filename, emailID)
 }
 
 // GetRawEmail fetches the raw RFC 5322 email source for a specific email.
 // Returns the raw email content as a string.
 func (i *Inbox) GetRawEmail(ctx context.Context, emailID string) (string, error) {
+	if err := i.checkAttached(); err != nil {
+		return "", err
+	}
 	resp, err := i.client.apiClient.GetEmailRaw(ctx, i.emailAddress, emailID)
 	if err != nil {
 		return "", err
@@ -69,7 +274,7 @@ func (i *Inbox) GetRawEmail(ctx context.Context, emailID string) (string, error)
 		if resp.EncryptedRaw == nil {
 			return "", fmt.Errorf("encrypted email has no raw content")
 		}
-		plaintext, err := i.verifyAndDecrypt(resp.EncryptedRaw)
+		plaintext, err := i.verifyAndDecryptPart(ctx, resp.EncryptedRaw, emailID, partRaw)
 		if err != nil {
 			return "", err
 		}
@@ -94,10 +299,87 @@ func (i *Inbox) GetRawEmail(ctx context.Context, emailID string) (string, error)
 
 // MarkEmailAsRead marks a specific email as read.
 func (i *Inbox) MarkEmailAsRead(ctx context.Context, emailID string) error {
+	if err := i.checkAttached(); err != nil {
+		return err
+	}
 	return i.client.apiClient.MarkEmailAsRead(ctx, i.emailAddress, emailID)
 }
 
+// MarkAllAsRead marks every unread email in the inbox as read and returns
+// how many were changed. The server has no bulk endpoint, so the unread
+// emails are listed (without decrypting them) and marked one at a time.
+// Emails deleted concurrently are skipped; other failures do not stop the
+// remaining emails and are returned joined together. If ctx is done, the
+// emails marked so far are counted and ctx's error is returned.
+func (i *Inbox) MarkAllAsRead(ctx context.Context) (int, error) {
+	if err := i.checkAttached(); err != nil {
+		return 0, err
+	}
+	unread := false
+	resp, err := i.client.apiClient.ListEmails(ctx, i.emailAddress, &api.ListEmailsParams{Read: &unread})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	var errs []error
+	for _, e := range resp.Emails {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		if e.IsRead {
+			continue
+		}
+		if err := i.client.apiClient.MarkEmailAsRead(ctx, i.emailAddress, e.ID); err != nil {
+			if !errors.Is(err, ErrEmailNotFound) {
+				errs = append(errs, fmt.Errorf("mark email %s as read: %w", e.ID, err))
+			}
+			continue
+		}
+		count++
+	}
+	return count, errors.Join(errs...)
+}
+
 // DeleteEmail deletes a specific email.
 func (i *Inbox) DeleteEmail(ctx context.Context, emailID string) error {
+	if err := i.checkAttached(); err != nil {
+		return err
+	}
 	return i.client.apiClient.DeleteEmail(ctx, i.emailAddress, emailID)
 }
+
+// DeleteAllEmails deletes every email in the inbox while keeping the inbox,
+// its address, and its keypair, and returns how many were deleted. Emails
+// are listed (without decrypting them) and deleted one at a time; emails
+// already deleted concurrently are skipped, and other failures do not stop
+// the remaining deletions and are returned joined together.
+//
+// The client's record of emails already delivered for the inbox is updated
+// so the next sync sees the inbox as unchanged instead of re-fetching it.
+func (i *Inbox) DeleteAllEmails(ctx context.Context) (int, error) {
+	if err := i.checkAttached(); err != nil {
+		return 0, err
+	}
+	resp, err := i.client.apiClient.ListEmails(ctx, i.emailAddress, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	var errs []error
+	gone := make([]string, 0, len(resp.Emails))
+	for _, e := range resp.Emails {
+		if err := i.client.apiClient.DeleteEmail(ctx, i.emailAddress, e.ID); err != nil {
+			if !errors.Is(err, ErrEmailNotFound) {
+				errs = append(errs, fmt.Errorf("delete email %s: %w", e.ID, err))
+				continue
+			}
+		} else {
+			count++
+		}
+		gone = append(gone, e.ID)
+	}
+	i.client.forgetEmails(i.inboxHash, gone)
+	return count, errors.Join(errs...)
+}
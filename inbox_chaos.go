@@ -6,6 +6,9 @@ import "context"
 // Returns the chaos settings including latency, connection drop, random error,
 // greylist, and blackhole configurations.
 func (i *Inbox) GetChaosConfig(ctx context.Context) (*ChaosConfig, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -21,6 +24,9 @@ func (i *Inbox) GetChaosConfig(ctx context.Context) (*ChaosConfig, error) {
 // SetChaosConfig creates or updates the chaos configuration for this inbox.
 // This allows you to inject various failure scenarios for testing email delivery resilience.
 func (i *Inbox) SetChaosConfig(ctx context.Context, config *ChaosConfig) (*ChaosConfig, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -37,6 +43,9 @@ func (i *Inbox) SetChaosConfig(ctx context.Context, config *ChaosConfig) (*Chaos
 // DisableChaos disables all chaos for this inbox.
 // This is equivalent to calling SetChaosConfig with Enabled: false.
 func (i *Inbox) DisableChaos(ctx context.Context) error {
+	if err := i.checkAttached(); err != nil {
+		return err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return err
 	}
@@ -1,9 +1,17 @@
 package vaultsandbox
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/vaultsandbox/client-go/authresults"
@@ -12,10 +20,122 @@ import (
 	"github.com/vaultsandbox/client-go/spamanalysis"
 )
 
-func (i *Inbox) decryptEmail(raw *api.RawEmail) (*Email, error) {
+// Email payload names reported in SignatureVerificationError.Part.
+const (
+	partMetadata = "metadata"
+	partParsed   = "parsed"
+	partRaw      = "raw"
+)
+
+// AADContext identifies the email payload whose associated data an [AADFunc]
+// is asked for.
+type AADContext struct {
+	InboxHash string // Hash of the receiving inbox
+	EmailID   string // ID of the email the payload belongs to
+	Part      string // "metadata", "parsed", or "raw"
+}
+
+// AADFunc returns the associated data expected in the payload its argument
+// describes. See [WithAAD].
+type AADFunc func(AADContext) []byte
+
+// SetAAD sets the hook that derives the associated data the inbox's
+// encrypted payloads must carry, as [WithAAD] does at creation. Use it for
+// inboxes from [Client.ImportInbox], [Client.ImportInboxFromFile],
+// [Client.ImportInboxBundle], or [NewDetachedInbox], which take no
+// [InboxOption]. A nil fn turns the check off.
+func (i *Inbox) SetAAD(fn AADFunc) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.aadFunc = fn
+}
+
+// aad returns the inbox's AADFunc, or nil.
+func (i *Inbox) aad() AADFunc {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.aadFunc
+}
+
+// decryptEmails decrypts raws with up to workers goroutines, defaulting to
+// GOMAXPROCS, and returns the emails in the same order. The first failure
+// stops the remaining work and is returned wrapped with the email ID.
+// maxDecoded bounds decompressed parsed content as in decryptEmailWithin.
+func (i *Inbox) decryptEmails(ctx context.Context, raws []*api.RawEmail, workers, maxDecoded int) ([]*Email, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(raws))
+
+	emails := make([]*Email, len(raws))
+	if workers <= 1 {
+		for j, raw := range raws {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			email, err := i.decryptEmailWithin(ctx, raw, maxDecoded)
+			if err != nil {
+				return nil, fmt.Errorf("email %s: %w", raw.ID, err)
+			}
+			emails[j] = email
+		}
+		return emails, nil
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for workCtx.Err() == nil {
+				j := int(next.Add(1) - 1)
+				if j >= len(raws) {
+					return
+				}
+				email, err := i.decryptEmailWithin(workCtx, raws[j], maxDecoded)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("email %s: %w", raws[j].ID, err)
+						cancel()
+					})
+					return
+				}
+				emails[j] = email
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// decryptEmail verifies, decrypts and parses raw. A canceled ctx aborts it
+// before each payload is decrypted.
+func (i *Inbox) decryptEmail(ctx context.Context, raw *api.RawEmail) (*Email, error) {
+	return i.decryptEmailWithin(ctx, raw, 0)
+}
+
+// decryptEmailWithin is decryptEmail with compressed parsed content limited
+// to maxDecoded bytes once decompressed, the fetch's [WithMaxBodyBytes]
+// value. Values <= 0 apply maxDecompressedSize.
+func (i *Inbox) decryptEmailWithin(ctx context.Context, raw *api.RawEmail, maxDecoded int) (*Email, error) {
 	// Handle plain emails (no encryption)
 	if !raw.IsEncrypted() {
-		return i.decodePlainEmail(raw)
+		return i.decodePlainEmail(raw, maxDecoded)
 	}
 
 	// Handle encrypted emails
@@ -24,7 +144,7 @@ func (i *Inbox) decryptEmail(raw *api.RawEmail) (*Email, error) {
 	}
 
 	// Verify and decrypt metadata
-	metadataPlaintext, err := i.verifyAndDecrypt(raw.EncryptedMetadata)
+	metadataPlaintext, err := i.verifyAndDecryptPart(ctx, raw.EncryptedMetadata, raw.ID, partMetadata)
 	if err != nil {
 		return nil, err
 	}
@@ -35,20 +155,28 @@ func (i *Inbox) decryptEmail(raw *api.RawEmail) (*Email, error) {
 	}
 
 	// Build decrypted email from metadata
-	decrypted := buildDecryptedEmail(raw, metadata)
+	decrypted := buildDecryptedEmail(raw, metadata, i.receivedAtSource())
+
+	if err := i.checkClockSkew(raw.ID, metadata); err != nil {
+		return nil, err
+	}
 
 	// Decrypt and apply parsed content if available
 	if raw.EncryptedParsed != nil {
-		if err := i.applyParsedContent(raw.EncryptedParsed, decrypted); err != nil {
+		if err := i.applyParsedContent(ctx, raw.EncryptedParsed, raw.ParsedEncoding, maxDecoded, decrypted); err != nil {
 			return nil, err
 		}
 	}
 
-	return i.convertDecryptedEmail(decrypted), nil
+	email := i.convertDecryptedEmail(decrypted)
+	email.BodyReady = raw.BodyReady()
+	return email, nil
 }
 
-// decodePlainEmail decodes a plain (unencrypted) email from Base64-encoded JSON.
-func (i *Inbox) decodePlainEmail(raw *api.RawEmail) (*Email, error) {
+// decodePlainEmail decodes a plain (unencrypted) email from Base64-encoded
+// JSON. Nothing in a plain email is signed, so the parsed content is
+// decompressed as raw.ParsedEncoding says, up to maxDecoded bytes.
+func (i *Inbox) decodePlainEmail(raw *api.RawEmail, maxDecoded int) (*Email, error) {
 	if raw.Metadata == "" {
 		return nil, fmt.Errorf("plain email has no metadata")
 	}
@@ -65,7 +193,7 @@ func (i *Inbox) decodePlainEmail(raw *api.RawEmail) (*Email, error) {
 	}
 
 	// Build email from metadata
-	decrypted := buildDecryptedEmail(raw, metadata)
+	decrypted := buildDecryptedEmail(raw, metadata, i.receivedAtSource())
 
 	// Decode and apply parsed content if available
 	if raw.Parsed != "" {
@@ -73,11 +201,18 @@ func (i *Inbox) decodePlainEmail(raw *api.RawEmail) (*Email, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode plain parsed content: %w", err)
 		}
+		parsedJSON, err = decodeContentEncoding(parsedJSON, raw.ParsedEncoding, maxDecoded)
+		if err != nil {
+			return nil, err
+		}
 
-		parsed, headers, err := parseParsedContent(parsedJSON)
+		parsed, headers, err := parseParsedContentLimited(parsedJSON, i.maxAttachmentSize())
 		if err != nil {
 			return nil, err
 		}
+		if err := i.checkAttachmentChecksums(parsed.Attachments); err != nil {
+			return nil, err
+		}
 
 		decrypted.Text = parsed.Text
 		decrypted.HTML = parsed.HTML
@@ -86,14 +221,18 @@ func (i *Inbox) decodePlainEmail(raw *api.RawEmail) (*Email, error) {
 		decrypted.AuthResults = parsed.AuthResults
 		decrypted.SpamAnalysis = parsed.SpamAnalysis
 		decrypted.Headers = headers
+		decrypted.MultiHeaders = multiValueHeaders(parsed.Headers)
+		decrypted.HeaderOrder = parsed.HeaderOrder
 	}
 
-	return i.convertDecryptedEmail(decrypted), nil
+	email := i.convertDecryptedEmail(decrypted)
+	email.BodyReady = raw.BodyReady()
+	return email, nil
 }
 
 // decryptMetadata decrypts only the metadata from an email.
 // For plain emails, this decodes the Base64-encoded metadata.
-func (i *Inbox) decryptMetadata(raw *api.RawEmail) (*EmailMetadata, error) {
+func (i *Inbox) decryptMetadata(ctx context.Context, raw *api.RawEmail) (*EmailMetadata, error) {
 	var metadataPlaintext []byte
 	var err error
 
@@ -102,7 +241,7 @@ func (i *Inbox) decryptMetadata(raw *api.RawEmail) (*EmailMetadata, error) {
 		if raw.EncryptedMetadata == nil {
 			return nil, fmt.Errorf("email has no encrypted metadata")
 		}
-		metadataPlaintext, err = i.verifyAndDecrypt(raw.EncryptedMetadata)
+		metadataPlaintext, err = i.verifyAndDecryptPart(ctx, raw.EncryptedMetadata, raw.ID, partMetadata)
 		if err != nil {
 			return nil, err
 		}
@@ -122,34 +261,41 @@ func (i *Inbox) decryptMetadata(raw *api.RawEmail) (*EmailMetadata, error) {
 		return nil, err
 	}
 
-	// Parse receivedAt from metadata, fallback to API timestamp
-	receivedAt := raw.ReceivedAt
-	if metadata.ReceivedAt != "" {
-		if t, err := time.Parse(time.RFC3339, metadata.ReceivedAt); err == nil {
-			receivedAt = t
-		}
-	}
-
 	return &EmailMetadata{
 		ID:         raw.ID,
 		From:       metadata.From,
 		Subject:    metadata.Subject,
-		ReceivedAt: receivedAt,
+		ReceivedAt: resolveReceivedAt(metadata.ReceivedAt, raw.ReceivedAt, i.receivedAtSource()),
 		IsRead:     raw.IsRead,
 	}, nil
 }
 
-// applyParsedContent decrypts parsed content and applies it to the decrypted email.
-func (i *Inbox) applyParsedContent(encrypted *crypto.EncryptedPayload, decrypted *crypto.DecryptedEmail) error {
-	parsedPlaintext, err := i.verifyAndDecrypt(encrypted)
+// applyParsedContent decrypts parsed content, decompresses it up to
+// maxDecoded bytes, and applies it to the decrypted email.
+//
+// The server's encoding hint is not covered by the payload signature, so it
+// only has to name a supported encoding; whether the content is actually
+// decompressed is decided from the authenticated plaintext.
+func (i *Inbox) applyParsedContent(ctx context.Context, encrypted *crypto.EncryptedPayload, encoding string, maxDecoded int, decrypted *crypto.DecryptedEmail) error {
+	if err := checkContentEncoding(encoding); err != nil {
+		return err
+	}
+	parsedPlaintext, err := i.verifyAndDecryptPart(ctx, encrypted, decrypted.ID, partParsed)
+	if err != nil {
+		return err
+	}
+	parsedPlaintext, err = decodeContentEncoding(parsedPlaintext, sniffContentEncoding(parsedPlaintext), maxDecoded)
 	if err != nil {
 		return err
 	}
 
-	parsed, headers, err := parseParsedContent(parsedPlaintext)
+	parsed, headers, err := parseParsedContentLimited(parsedPlaintext, i.maxAttachmentSize())
 	if err != nil {
 		return err
 	}
+	if err := i.checkAttachmentChecksums(parsed.Attachments); err != nil {
+		return err
+	}
 
 	decrypted.Text = parsed.Text
 	decrypted.HTML = parsed.HTML
@@ -158,6 +304,8 @@ func (i *Inbox) applyParsedContent(encrypted *crypto.EncryptedPayload, decrypted
 	decrypted.AuthResults = parsed.AuthResults
 	decrypted.SpamAnalysis = parsed.SpamAnalysis
 	decrypted.Headers = headers
+	decrypted.MultiHeaders = multiValueHeaders(parsed.Headers)
+	decrypted.HeaderOrder = parsed.HeaderOrder
 
 	return nil
 }
@@ -177,18 +325,25 @@ func (i *Inbox) convertDecryptedEmail(d *crypto.DecryptedEmail) *Email {
 	}
 
 	email := &Email{
-		ID:          d.ID,
-		From:        d.From,
-		To:          d.To,
-		Subject:     d.Subject,
-		Text:        d.Text,
-		HTML:        d.HTML,
-		ReceivedAt:  d.ReceivedAt,
-		Headers:     d.Headers,
-		Attachments: attachments,
-		Links:       d.Links,
-		IsRead:      d.IsRead,
+		ID:           d.ID,
+		From:         d.From,
+		To:           d.To,
+		EnvelopeTo:   d.EnvelopeTo,
+		Subject:      d.Subject,
+		Text:         d.Text,
+		HTML:         d.HTML,
+		ReceivedAt:   d.ReceivedAt,
+		Headers:      d.Headers,
+		MultiHeaders: d.MultiHeaders,
+		Attachments:  attachments,
+		Links:        d.Links,
+		IsRead:       d.IsRead,
+		headerOrder:  d.HeaderOrder,
 	}
+	if v, ok := email.Header("List-Unsubscribe"); ok {
+		email.ListUnsubscribe = parseListUnsubscribe(v)
+	}
+	email.ListUnsubscribePost, _ = email.Header("List-Unsubscribe-Post")
 
 	// Unmarshal AuthResults if present
 	if len(d.AuthResults) > 0 {
@@ -213,9 +368,116 @@ func (i *Inbox) convertDecryptedEmail(d *crypto.DecryptedEmail) *Email {
 	return email
 }
 
+// EncryptedPayload is an encrypted payload as delivered by the server.
+type EncryptedPayload = crypto.EncryptedPayload
+
+// AlgorithmSuite describes the cryptographic algorithms of an [EncryptedPayload].
+type AlgorithmSuite = crypto.AlgorithmSuite
+
+// DefaultAlgorithmSuite returns the suite defined by the VaultSandbox
+// specification: ML-KEM-768, ML-DSA-65, AES-256-GCM and HKDF-SHA-512.
+func DefaultAlgorithmSuite() AlgorithmSuite {
+	return crypto.DefaultSuite
+}
+
+// checkSupportedSuite returns ErrUnsupportedSuite if suite is set to anything
+// but DefaultAlgorithmSuite, the only suite payloads can be decrypted with.
+func checkSupportedSuite(suite *AlgorithmSuite) error {
+	if suite != nil && *suite != crypto.DefaultSuite {
+		return fmt.Errorf("%w: %s, only %s can be decrypted", ErrUnsupportedSuite, *suite, crypto.DefaultSuite)
+	}
+	return nil
+}
+
+// VerifyEmailSignature verifies the server's ML-DSA-65 signature on an
+// encrypted payload against the server key pinned for this inbox (and any
+// rotation keys from [WithAdditionalServerKeys]), without decrypting it. This
+// is the cheap first half of the "verify before decrypt" pattern: callers can
+// make routing decisions on authenticity alone and skip the KEM/AES cost for
+// payloads they do not need to read.
+//
+// Returns a [*SignatureVerificationError] if the signature is invalid or the
+// payload was signed by a different server key, and [ErrUnexpectedSuite] if
+// the payload's algorithms differ from the inbox's required suite (see
+// [WithRequireSuite]). Structurally invalid payloads (wrong version,
+// algorithms, or field sizes) return a descriptive error.
+func (i *Inbox) VerifyEmailSignature(payload *EncryptedPayload) error {
+	if payload == nil {
+		return fmt.Errorf("payload is nil")
+	}
+	if !i.encrypted {
+		return fmt.Errorf("inbox %s is not encrypted", i.emailAddress)
+	}
+	return i.verifySignature(payload)
+}
+
+// DecryptPayload verifies and decrypts an encrypted payload with the inbox's
+// keypair and pinned server key, as the email-fetch path does for each part
+// of an email. It is meant for debugging payloads captured from the wire; the
+// plaintext is returned as is, without parsing or decompression.
+//
+// The signature is always checked first, as by [Inbox.VerifyEmailSignature],
+// and its failures are reported the same way. A payload that verifies but
+// cannot be decrypted with the inbox's keypair returns an error wrapping
+// [ErrDecryptionFailed].
+//
+// DecryptPayload does not know which email the payload belongs to, so it
+// cannot apply a [WithAAD] or [Inbox.SetAAD] check; use
+// [Inbox.DecryptEmailPayload] for that.
+func (i *Inbox) DecryptPayload(payload *EncryptedPayload) ([]byte, error) {
+	return i.decryptPayload(payload, func() ([]byte, error) {
+		return i.verifyAndDecrypt(context.Background(), payload)
+	})
+}
+
+// DecryptEmailPayload is like [Inbox.DecryptPayload], but for a payload of a
+// known email: part is "metadata", "parsed", or "raw". If the inbox has an
+// AAD hook (see [WithAAD]), the payload's AAD is checked first, exactly as
+// when the email is fetched, and a mismatch returns [ErrUnexpectedAAD].
+func (i *Inbox) DecryptEmailPayload(payload *EncryptedPayload, emailID, part string) ([]byte, error) {
+	return i.decryptPayload(payload, func() ([]byte, error) {
+		return i.verifyAndDecryptPart(context.Background(), payload, emailID, part)
+	})
+}
+
+// decryptPayload validates the arguments of the public decrypt helpers, runs
+// decrypt, and maps decryption failures to ErrDecryptionFailed.
+func (i *Inbox) decryptPayload(payload *EncryptedPayload, decrypt func() ([]byte, error)) ([]byte, error) {
+	if payload == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+	if !i.encrypted {
+		return nil, fmt.Errorf("inbox %s is not encrypted", i.emailAddress)
+	}
+	plaintext, err := decrypt()
+	if errors.Is(err, crypto.ErrDecryptionFailed) {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	return plaintext, err
+}
+
+// verifySignature checks the payload signature against the pinned server
+// keys.
+func (i *Inbox) verifySignature(payload *crypto.EncryptedPayload) error {
+	serverSigPks := i.serverKeys()
+	if len(serverSigPks) == 0 {
+		return fmt.Errorf("server signature public key is nil")
+	}
+	required := crypto.DefaultSuite
+	if i.requireSuite != nil {
+		required = *i.requireSuite
+	}
+	if err := crypto.RequireSuite(payload, required); err != nil {
+		return err
+	}
+	return wrapCryptoError(crypto.VerifySignatureAny(payload, serverSigPks))
+}
+
 // verifyAndDecrypt verifies the signature and decrypts an encrypted payload.
 // It returns the decrypted plaintext or an error if verification/decryption fails.
-func (i *Inbox) verifyAndDecrypt(payload *crypto.EncryptedPayload) ([]byte, error) {
+// It returns ctx.Err() without decrypting if ctx is canceled, including while
+// waiting for a slot in the client's decryption budget.
+func (i *Inbox) verifyAndDecrypt(ctx context.Context, payload *crypto.EncryptedPayload) ([]byte, error) {
 	// Guard against misuse on plain inboxes
 	if !i.encrypted {
 		return nil, fmt.Errorf("verifyAndDecrypt called on plain (unencrypted) inbox")
@@ -223,16 +485,43 @@ func (i *Inbox) verifyAndDecrypt(payload *crypto.EncryptedPayload) ([]byte, erro
 	if i.keypair == nil {
 		return nil, fmt.Errorf("keypair is nil for encrypted inbox")
 	}
-	if i.serverSigPk == nil {
-		return nil, fmt.Errorf("server signature public key is nil")
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if i.client != nil && i.client.decryptSem != nil {
+		select {
+		case i.client.decryptSem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-i.client.decryptSem }()
 	}
 
-	if err := crypto.VerifySignature(payload, i.serverSigPk); err != nil {
-		return nil, wrapCryptoError(err)
+	if err := i.verifySignature(payload); err != nil {
+		return nil, err
 	}
 	return crypto.Decrypt(payload, i.keypair)
 }
 
+// verifyAndDecryptPart is verifyAndDecrypt for one payload of an email,
+// checking its AAD against the inbox's AADFunc, if any, and recording part in
+// a returned SignatureVerificationError.
+func (i *Inbox) verifyAndDecryptPart(ctx context.Context, payload *crypto.EncryptedPayload, emailID, part string) ([]byte, error) {
+	if aadFunc := i.aad(); aadFunc != nil {
+		expected := aadFunc(AADContext{InboxHash: i.inboxHash, EmailID: emailID, Part: part})
+		if err := crypto.RequireAAD(payload, expected); err != nil {
+			return nil, err
+		}
+	}
+	plaintext, err := i.verifyAndDecrypt(ctx, payload)
+	var sigErr *SignatureVerificationError
+	if errors.As(err, &sigErr) {
+		sigErr.Part = part
+	}
+	return plaintext, err
+}
+
 // parseMetadata unmarshals decrypted metadata JSON into a DecryptedMetadata struct.
 func parseMetadata(plaintext []byte) (*crypto.DecryptedMetadata, error) {
 	var metadata crypto.DecryptedMetadata
@@ -245,8 +534,17 @@ func parseMetadata(plaintext []byte) (*crypto.DecryptedMetadata, error) {
 // parseParsedContent unmarshals decrypted parsed content JSON and converts headers.
 // Headers are converted from interface{} to string map, preserving only string values.
 func parseParsedContent(plaintext []byte) (*crypto.DecryptedParsed, map[string]string, error) {
-	var parsed crypto.DecryptedParsed
-	if err := json.Unmarshal(plaintext, &parsed); err != nil {
+	return parseParsedContentLimited(plaintext, 0)
+}
+
+// parseParsedContentLimited is like parseParsedContent but rejects attachments
+// larger than maxAttachmentSize bytes. Zero means no limit.
+func parseParsedContentLimited(plaintext []byte, maxAttachmentSize int) (*crypto.DecryptedParsed, map[string]string, error) {
+	parsed, err := crypto.ParseDecryptedParsed(plaintext, maxAttachmentSize)
+	if err != nil {
+		if errors.Is(err, crypto.ErrAttachmentTooLarge) {
+			return nil, nil, err
+		}
 		return nil, nil, fmt.Errorf("failed to parse decrypted parsed content: %w", err)
 	}
 
@@ -263,12 +561,180 @@ func parseParsedContent(plaintext []byte) (*crypto.DecryptedParsed, map[string]s
 		}
 	}
 
-	return &parsed, headers, nil
+	return parsed, headers, nil
+}
+
+// multiValueHeaders collects the headers the server sent as arrays, one
+// element per occurrence. Non-string elements are dropped, and headers with
+// no string elements are omitted. It returns nil if there are none.
+func multiValueHeaders(raw map[string]interface{}) map[string][]string {
+	var multi map[string][]string
+	for k, v := range raw {
+		list, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+		var values []string
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if multi == nil {
+			multi = make(map[string][]string)
+		}
+		multi[k] = values
+	}
+	return multi
+}
+
+// checkAttachmentChecksums verifies each attachment's checksum when
+// WithVerifyAttachmentChecksums is configured.
+func (i *Inbox) checkAttachmentChecksums(attachments []crypto.DecryptedAttachment) error {
+	if i.client == nil || !i.client.verifyAttachmentChecksums {
+		return nil
+	}
+	for _, a := range attachments {
+		if err := verifyChecksum(a.Filename, a.Content, a.Checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkClockSkew compares the signed receivedAt timestamp against the local
+// clock when WithClockSkewTolerance is configured. In strict mode an excessive
+// skew is returned as an error; otherwise it is reported via onSyncError.
+func (i *Inbox) checkClockSkew(emailID string, metadata *crypto.DecryptedMetadata) error {
+	if i.client == nil || i.client.clockSkewTolerance <= 0 || metadata.ReceivedAt == "" {
+		return nil
+	}
+	receivedAt, err := time.Parse(time.RFC3339, metadata.ReceivedAt)
+	if err != nil {
+		return nil
+	}
+	skew := receivedAt.Sub(i.client.clock().Now())
+	if skew <= i.client.clockSkewTolerance {
+		return nil
+	}
+
+	warning := &ClockSkewWarning{
+		EmailID:    emailID,
+		ReceivedAt: receivedAt,
+		Skew:       skew,
+		Tolerance:  i.client.clockSkewTolerance,
+	}
+	if i.client.strictClockSkew {
+		return warning
+	}
+	if i.client.onSyncError != nil {
+		i.client.onSyncError(warning)
+	}
+	return nil
+}
+
+// maxDecompressedSize caps how large compressed parsed content may expand
+// when the fetch sets no [WithMaxBodyBytes] limit, guarding against
+// decompression bombs.
+const maxDecompressedSize = 64 << 20
+
+// gzipMagic starts every gzip stream. Parsed content is otherwise JSON, which
+// cannot start with it.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// checkContentEncoding reports whether encoding names a supported content
+// encoding: gzip, or none ("" or "identity").
+func checkContentEncoding(encoding string) error {
+	switch strings.ToLower(encoding) {
+	case "", "identity", "gzip":
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedContentEncoding, encoding)
+	}
+}
+
+// sniffContentEncoding returns the encoding of parsed content as recognized
+// from the data itself: "gzip" for a gzip stream, "" otherwise.
+func sniffContentEncoding(data []byte) string {
+	if bytes.HasPrefix(data, gzipMagic) {
+		return "gzip"
+	}
+	return ""
+}
+
+// decodeContentEncoding reverses the content encoding of parsed content.
+// Only gzip is supported; an empty or "identity" encoding returns data
+// unchanged. Decompressed output is limited to limit bytes, or to
+// maxDecompressedSize if limit <= 0.
+func decodeContentEncoding(data []byte, encoding string, limit int) ([]byte, error) {
+	if err := checkContentEncoding(encoding); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(encoding, "gzip") {
+		return data, nil
+	}
+	if limit <= 0 || limit > maxDecompressedSize {
+		limit = maxDecompressedSize
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress parsed content: %w", err)
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(io.LimitReader(zr, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress parsed content: %w", err)
+	}
+	if len(out) > limit {
+		return nil, fmt.Errorf("%w: parsed content exceeds %d bytes", ErrDecompressedTooLarge, limit)
+	}
+	return out, nil
+}
+
+// receivedAtSource returns the client's ReceivedAt priority, defaulting to
+// SourceMetadata.
+func (i *Inbox) receivedAtSource() ReceivedAtSource {
+	if i.client == nil {
+		return SourceMetadata
+	}
+	return i.client.receivedAtSource
+}
+
+// resolveReceivedAt picks the email timestamp from the signed metadata value
+// and the API timestamp according to source, falling back to the other when
+// the preferred one is missing or unparsable.
+func resolveReceivedAt(metadataValue string, apiTime time.Time, source ReceivedAtSource) time.Time {
+	var metadataTime time.Time
+	if metadataValue != "" {
+		if t, err := time.Parse(time.RFC3339, metadataValue); err == nil {
+			metadataTime = t
+		}
+	}
+	if source == SourceAPI && !apiTime.IsZero() {
+		return apiTime
+	}
+	if !metadataTime.IsZero() {
+		return metadataTime
+	}
+	return apiTime
+}
+
+// maxAttachmentSize returns the client's attachment size limit, or zero if unset.
+func (i *Inbox) maxAttachmentSize() int {
+	if i.client == nil {
+		return 0
+	}
+	return i.client.maxAttachmentSize
 }
 
 // buildDecryptedEmail constructs a DecryptedEmail from raw email data and metadata.
 // It handles receivedAt fallback logic when metadata timestamp is missing or invalid.
-func buildDecryptedEmail(emailData *api.RawEmail, metadata *crypto.DecryptedMetadata) *crypto.DecryptedEmail {
+func buildDecryptedEmail(emailData *api.RawEmail, metadata *crypto.DecryptedMetadata, source ReceivedAtSource) *crypto.DecryptedEmail {
 	decrypted := &crypto.DecryptedEmail{
 		ID:      emailData.ID,
 		From:    metadata.From,
@@ -277,16 +743,15 @@ func buildDecryptedEmail(emailData *api.RawEmail, metadata *crypto.DecryptedMeta
 		IsRead:  emailData.IsRead,
 	}
 
-	// Parse receivedAt from metadata, fallback to API timestamp
-	if metadata.ReceivedAt != "" {
-		if t, err := time.Parse(time.RFC3339, metadata.ReceivedAt); err == nil {
-			decrypted.ReceivedAt = t
-		}
-	}
-	if decrypted.ReceivedAt.IsZero() {
-		decrypted.ReceivedAt = emailData.ReceivedAt
+	// The metadata recipient is the address the server delivered to, so it
+	// stands in for the envelope recipient when not reported separately.
+	decrypted.EnvelopeTo = metadata.EnvelopeTo
+	if decrypted.EnvelopeTo == "" {
+		decrypted.EnvelopeTo = metadata.To
 	}
 
+	decrypted.ReceivedAt = resolveReceivedAt(metadata.ReceivedAt, emailData.ReceivedAt, source)
+
 	return decrypted
 }
 
@@ -1,23 +1,27 @@
 package vaultsandbox
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
 	"crypto/sha256"
-	"encoding/binary"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
 	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
 	"github.com/vaultsandbox/client-go/internal/crypto"
+	"github.com/vaultsandbox/client-go/internal/mockinbox"
 )
 
 // createTestEncryptedPayload creates a valid encrypted payload for testing.
@@ -26,102 +30,11 @@ import (
 func createTestEncryptedPayload(t *testing.T, plaintext []byte, kp *crypto.Keypair) (*crypto.EncryptedPayload, []byte) {
 	t.Helper()
 
-	// Generate server signing keypair
 	serverPub, serverPriv, err := mldsa65.GenerateKey(nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	serverPubBytes, err := serverPub.MarshalBinary()
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	// 1. Encapsulate to get shared secret and KEM ciphertext
-	var pubKey mlkem768.PublicKey
-	pubKey.Unpack(kp.PublicKey)
-
-	ctKem := make([]byte, crypto.MLKEMCiphertextSize)
-	sharedSecret := make([]byte, crypto.MLKEMSharedKeySize)
-	pubKey.EncapsulateTo(ctKem, sharedSecret, nil)
-
-	// 2. Derive AES key using the same method as the crypto package
-	aad := []byte("test-aad")
-	aesKey := deriveTestKey(sharedSecret, aad, ctKem)
-
-	// 3. Encrypt with AES-GCM
-	nonce := make([]byte, crypto.AESNonceSize)
-	if _, err := rand.Read(nonce); err != nil {
-		t.Fatal(err)
-	}
-
-	block, _ := aes.NewCipher(aesKey)
-	gcm, _ := cipher.NewGCM(block)
-	ciphertext := gcm.Seal(nil, nonce, plaintext, aad)
-
-	// 4. Create the encrypted payload
-	algs := crypto.AlgorithmSuite{
-		KEM:  "ML-KEM-768",
-		Sig:  "ML-DSA-65",
-		AEAD: "AES-256-GCM",
-		KDF:  "HKDF-SHA-512",
-	}
-
-	payload := &crypto.EncryptedPayload{
-		V:           1,
-		Algs:        algs,
-		CtKem:       crypto.ToBase64URL(ctKem),
-		Nonce:       crypto.ToBase64URL(nonce),
-		AAD:         crypto.ToBase64URL(aad),
-		Ciphertext:  crypto.ToBase64URL(ciphertext),
-		ServerSigPk: crypto.ToBase64URL(serverPubBytes),
-	}
-
-	// 5. Sign the payload
-	transcript := buildTestTranscript(payload.V, algs, ctKem, nonce, aad, ciphertext, serverPubBytes)
-	sig := make([]byte, mldsa65.SignatureSize)
-	mldsa65.SignTo(serverPriv, transcript, nil, false, sig)
-	payload.Sig = crypto.ToBase64URL(sig)
-
-	return payload, serverPubBytes
-}
-
-// deriveTestKey mirrors the internal deriveKey function for test purposes.
-func deriveTestKey(sharedSecret, aad, ctKem []byte) []byte {
-	// Salt is SHA-256 hash of KEM ciphertext
-	saltHash := sha256.Sum256(ctKem)
-	salt := saltHash[:]
-
-	// Info construction: context || aad_length (4 bytes BE) || aad
-	// Must match crypto.HKDFContext = "vaultsandbox:email:v1"
-	context := []byte(crypto.HKDFContext)
-	aadLength := make([]byte, 4)
-	binary.BigEndian.PutUint32(aadLength, uint32(len(aad)))
-
-	info := make([]byte, 0, len(context)+4+len(aad))
-	info = append(info, context...)
-	info = append(info, aadLength...)
-	info = append(info, aad...)
-
-	key, _ := crypto.DeriveKey(sharedSecret, salt, info, crypto.AESKeySize)
-	return key
-}
-
-// buildTestTranscript constructs the signature transcript.
-// Must match crypto/verify.go buildTranscript function.
-func buildTestTranscript(version int, algs crypto.AlgorithmSuite, ctKem, nonce, aad, ciphertext, serverSigPk []byte) []byte {
-	transcript := []byte{byte(version)}
-
-	algsCiphersuite := algs.KEM + ":" + algs.Sig + ":" + algs.AEAD + ":" + algs.KDF
-	transcript = append(transcript, []byte(algsCiphersuite)...)
-	// Must use crypto.HKDFContext = "vaultsandbox:email:v1"
-	transcript = append(transcript, []byte(crypto.HKDFContext)...)
-	transcript = append(transcript, ctKem...)
-	transcript = append(transcript, nonce...)
-	transcript = append(transcript, aad...)
-	transcript = append(transcript, ciphertext...)
-	transcript = append(transcript, serverSigPk...)
-
-	return transcript
+	return createTestEncryptedPayloadWithServerKeyPair(t, plaintext, kp, serverPub, serverPriv)
 }
 
 func TestWrapCryptoError_Nil(t *testing.T) {
@@ -246,7 +159,7 @@ func TestDecryptMetadata_NilEncryptedMetadata(t *testing.T) {
 			Metadata:          "", // No plain metadata either
 		}
 
-		_, err := inbox.decryptMetadata(rawEmail)
+		_, err := inbox.decryptMetadata(context.Background(), rawEmail)
 		if err == nil {
 			t.Error("expected error for plain email with no metadata")
 		}
@@ -266,7 +179,7 @@ func TestDecryptEmail_NilEncryptedMetadata(t *testing.T) {
 		Metadata:          "", // No plain metadata either
 	}
 
-	_, err := inbox.decryptEmail(rawEmail)
+	_, err := inbox.decryptEmail(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for plain email with no metadata")
 	}
@@ -294,9 +207,9 @@ func TestDecryptMetadata_Success(t *testing.T) {
 	encryptedMetadata, serverPk := createTestEncryptedPayload(t, metadataJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
 	apiReceivedAt := time.Now().Truncate(time.Second)
@@ -307,7 +220,7 @@ func TestDecryptMetadata_Success(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	result, err := inbox.decryptMetadata(rawEmail)
+	result, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptMetadata() error = %v", err)
 	}
@@ -351,9 +264,9 @@ func TestDecryptMetadata_InvalidReceivedAtFallback(t *testing.T) {
 	encryptedMetadata, serverPk := createTestEncryptedPayload(t, metadataJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
 	apiReceivedAt := time.Now().Truncate(time.Second)
@@ -364,7 +277,7 @@ func TestDecryptMetadata_InvalidReceivedAtFallback(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	result, err := inbox.decryptMetadata(rawEmail)
+	result, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptMetadata() error = %v", err)
 	}
@@ -393,9 +306,9 @@ func TestDecryptMetadata_EmptyReceivedAtFallback(t *testing.T) {
 	encryptedMetadata, serverPk := createTestEncryptedPayload(t, metadataJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
 	apiReceivedAt := time.Now().Truncate(time.Second)
@@ -406,7 +319,7 @@ func TestDecryptMetadata_EmptyReceivedAtFallback(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	result, err := inbox.decryptMetadata(rawEmail)
+	result, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptMetadata() error = %v", err)
 	}
@@ -438,9 +351,9 @@ func TestDecryptMetadata_VerifyAndDecryptError(t *testing.T) {
 	differentServerPk := make([]byte, crypto.MLDSAPublicKeySize)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: differentServerPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{differentServerPk},
+		encrypted:    true,
 	}
 
 	rawEmail := &api.RawEmail{
@@ -448,7 +361,7 @@ func TestDecryptMetadata_VerifyAndDecryptError(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	_, err = inbox.decryptMetadata(rawEmail)
+	_, err = inbox.decryptMetadata(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for mismatched server key")
 	}
@@ -466,9 +379,9 @@ func TestDecryptMetadata_ParseMetadataError(t *testing.T) {
 	encryptedMetadata, serverPk := createTestEncryptedPayload(t, invalidJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
 	rawEmail := &api.RawEmail{
@@ -476,7 +389,7 @@ func TestDecryptMetadata_ParseMetadataError(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	_, err = inbox.decryptMetadata(rawEmail)
+	_, err = inbox.decryptMetadata(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for invalid JSON")
 	}
@@ -500,9 +413,9 @@ func TestDecryptEmail_Success(t *testing.T) {
 	encryptedMetadata, serverPk := createTestEncryptedPayload(t, metadataJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
 	rawEmail := &api.RawEmail{
@@ -513,7 +426,7 @@ func TestDecryptEmail_Success(t *testing.T) {
 		EncryptedParsed:   nil, // No parsed content
 	}
 
-	result, err := inbox.decryptEmail(rawEmail)
+	result, err := inbox.decryptEmail(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptEmail() error = %v", err)
 	}
@@ -529,6 +442,78 @@ func TestDecryptEmail_Success(t *testing.T) {
 	}
 }
 
+func TestDecryptEmail_ClockSkew(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	metadataJSON, _ := json.Marshal(map[string]interface{}{
+		"from":       "sender@example.com",
+		"to":         "recipient@example.com",
+		"subject":    "From the future",
+		"receivedAt": future,
+	})
+	encryptedMetadata, serverPk := createTestEncryptedPayload(t, metadataJSON, kp)
+	rawEmail := &api.RawEmail{
+		ID:                "email-123",
+		ReceivedAt:        time.Now(),
+		EncryptedMetadata: encryptedMetadata,
+	}
+
+	tests := []struct {
+		name       string
+		tolerance  time.Duration
+		strict     bool
+		wantErr    bool
+		wantReport bool
+	}{
+		{name: "disabled", tolerance: 0},
+		{name: "within tolerance", tolerance: 2 * time.Hour},
+		{name: "tolerant mode reports", tolerance: time.Minute, wantReport: true},
+		{name: "strict mode rejects", tolerance: time.Minute, strict: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var reported error
+			inbox := &Inbox{
+				keypair:      kp,
+				serverSigPks: [][]byte{serverPk},
+				encrypted:    true,
+				client: &Client{
+					clockSkewTolerance: tt.tolerance,
+					strictClockSkew:    tt.strict,
+					onSyncError:        func(err error) { reported = err },
+				},
+			}
+
+			email, err := inbox.decryptEmail(context.Background(), rawEmail)
+			if tt.wantErr {
+				if !errors.Is(err, ErrClockSkew) {
+					t.Fatalf("decryptEmail() error = %v, want ErrClockSkew", err)
+				}
+				var warning *ClockSkewWarning
+				if !errors.As(err, &warning) || warning.EmailID != "email-123" || warning.Skew <= time.Minute {
+					t.Errorf("warning = %+v, want skew > 1m for email-123", warning)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decryptEmail() error = %v", err)
+			}
+			if email.Subject != "From the future" {
+				t.Errorf("Subject = %q, want %q", email.Subject, "From the future")
+			}
+			if got := errors.Is(reported, ErrClockSkew); got != tt.wantReport {
+				t.Errorf("reported clock skew = %v, want %v (err = %v)", got, tt.wantReport, reported)
+			}
+		})
+	}
+}
+
 func TestDecryptEmail_WithParsedContent(t *testing.T) {
 	t.Parallel()
 	kp, err := crypto.GenerateKeypair()
@@ -567,9 +552,9 @@ func TestDecryptEmail_WithParsedContent(t *testing.T) {
 	encryptedParsed, _ := createTestEncryptedPayloadWithServerKeyPair(t, parsedJSON, kp, serverPub, serverPriv)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPubBytes,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPubBytes},
+		encrypted:    true,
 	}
 
 	rawEmail := &api.RawEmail{
@@ -580,7 +565,7 @@ func TestDecryptEmail_WithParsedContent(t *testing.T) {
 		EncryptedParsed:   encryptedParsed,
 	}
 
-	result, err := inbox.decryptEmail(rawEmail)
+	result, err := inbox.decryptEmail(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptEmail() error = %v", err)
 	}
@@ -607,53 +592,14 @@ func createTestEncryptedPayloadWithServerKeyPair(t *testing.T, plaintext []byte,
 	if err != nil {
 		t.Fatal(err)
 	}
-
-	// 1. Encapsulate to get shared secret and KEM ciphertext
-	var pubKey mlkem768.PublicKey
-	pubKey.Unpack(kp.PublicKey)
-
-	ctKem := make([]byte, crypto.MLKEMCiphertextSize)
-	sharedSecret := make([]byte, crypto.MLKEMSharedKeySize)
-	pubKey.EncapsulateTo(ctKem, sharedSecret, nil)
-
-	// 2. Derive AES key
-	aad := []byte("test-aad")
-	aesKey := deriveTestKey(sharedSecret, aad, ctKem)
-
-	// 3. Encrypt with AES-GCM
-	nonce := make([]byte, crypto.AESNonceSize)
-	if _, err := rand.Read(nonce); err != nil {
+	serverPrivBytes, err := serverPriv.MarshalBinary()
+	if err != nil {
 		t.Fatal(err)
 	}
-
-	block, _ := aes.NewCipher(aesKey)
-	gcm, _ := cipher.NewGCM(block)
-	ciphertext := gcm.Seal(nil, nonce, plaintext, aad)
-
-	// 4. Create the encrypted payload
-	algs := crypto.AlgorithmSuite{
-		KEM:  "ML-KEM-768",
-		Sig:  "ML-DSA-65",
-		AEAD: "AES-256-GCM",
-		KDF:  "HKDF-SHA-512",
-	}
-
-	payload := &crypto.EncryptedPayload{
-		V:           1,
-		Algs:        algs,
-		CtKem:       crypto.ToBase64URL(ctKem),
-		Nonce:       crypto.ToBase64URL(nonce),
-		AAD:         crypto.ToBase64URL(aad),
-		Ciphertext:  crypto.ToBase64URL(ciphertext),
-		ServerSigPk: crypto.ToBase64URL(serverPubBytes),
+	payload, err := crypto.Encrypt(plaintext, kp.PublicKey, serverPrivBytes, []byte("test-aad"))
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	// 5. Sign the payload
-	transcript := buildTestTranscript(payload.V, algs, ctKem, nonce, aad, ciphertext, serverPubBytes)
-	sig := make([]byte, mldsa65.SignatureSize)
-	mldsa65.SignTo(serverPriv, transcript, nil, false, sig)
-	payload.Sig = crypto.ToBase64URL(sig)
-
 	return payload, serverPubBytes
 }
 
@@ -676,8 +622,8 @@ func TestDecryptEmail_VerifyAndDecryptError(t *testing.T) {
 	differentServerPk := make([]byte, crypto.MLDSAPublicKeySize)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: differentServerPk,
+		keypair:      kp,
+		serverSigPks: [][]byte{differentServerPk},
 	}
 
 	rawEmail := &api.RawEmail{
@@ -685,7 +631,7 @@ func TestDecryptEmail_VerifyAndDecryptError(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	_, err = inbox.decryptEmail(rawEmail)
+	_, err = inbox.decryptEmail(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for mismatched server key")
 	}
@@ -702,8 +648,8 @@ func TestDecryptEmail_ParseMetadataError(t *testing.T) {
 	encryptedMetadata, serverPk := createTestEncryptedPayload(t, invalidJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
 	}
 
 	rawEmail := &api.RawEmail{
@@ -711,7 +657,7 @@ func TestDecryptEmail_ParseMetadataError(t *testing.T) {
 		EncryptedMetadata: encryptedMetadata,
 	}
 
-	_, err = inbox.decryptEmail(rawEmail)
+	_, err = inbox.decryptEmail(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for invalid metadata JSON")
 	}
@@ -739,8 +685,8 @@ func TestDecryptEmail_ApplyParsedContentError(t *testing.T) {
 
 	// Use a server key that matches metadata but not parsed
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
 	}
 
 	// Set the parsed content to use a different server key
@@ -752,12 +698,75 @@ func TestDecryptEmail_ApplyParsedContentError(t *testing.T) {
 		EncryptedParsed:   encryptedParsed,
 	}
 
-	_, err = inbox.decryptEmail(rawEmail)
+	_, err = inbox.decryptEmail(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for mismatched server key in parsed content")
 	}
 }
 
+func TestDecryptEmail_SignatureErrorPart(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedPub, pinnedPriv, err := mldsa65.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, otherPriv, err := mldsa65.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedPubBytes, _ := pinnedPub.MarshalBinary()
+
+	metadataJSON, _ := json.Marshal(map[string]interface{}{"from": "sender@example.com", "subject": "Test"})
+	parsedJSON, _ := json.Marshal(map[string]interface{}{"text": "Body"})
+
+	tests := []struct {
+		name           string
+		metadataSigner *mldsa65.PrivateKey
+		metadataPub    *mldsa65.PublicKey
+		parsedSigner   *mldsa65.PrivateKey
+		parsedPub      *mldsa65.PublicKey
+		wantPart       string
+	}{
+		{"metadata mismatch", otherPriv, otherPub, pinnedPriv, pinnedPub, "metadata"},
+		{"parsed mismatch", pinnedPriv, pinnedPub, otherPriv, otherPub, "parsed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			encryptedMetadata, _ := createTestEncryptedPayloadWithServerKeyPair(t, metadataJSON, kp, tt.metadataPub, tt.metadataSigner)
+			encryptedParsed, _ := createTestEncryptedPayloadWithServerKeyPair(t, parsedJSON, kp, tt.parsedPub, tt.parsedSigner)
+			inbox := &Inbox{
+				keypair:      kp,
+				serverSigPks: [][]byte{pinnedPubBytes},
+				encrypted:    true,
+			}
+
+			_, err := inbox.decryptEmail(context.Background(), &api.RawEmail{
+				ID:                "email-123",
+				EncryptedMetadata: encryptedMetadata,
+				EncryptedParsed:   encryptedParsed,
+			})
+			var sigErr *SignatureVerificationError
+			if !errors.As(err, &sigErr) {
+				t.Fatalf("decryptEmail() error = %v, want SignatureVerificationError", err)
+			}
+			if sigErr.Part != tt.wantPart {
+				t.Errorf("Part = %q, want %q", sigErr.Part, tt.wantPart)
+			}
+			if !sigErr.IsKeyMismatch {
+				t.Error("IsKeyMismatch = false, want true")
+			}
+			if !errors.Is(err, ErrSignatureInvalid) {
+				t.Errorf("errors.Is(err, ErrSignatureInvalid) = false for %v", err)
+			}
+		})
+	}
+}
+
 func TestApplyParsedContent_VerifyAndDecryptError(t *testing.T) {
 	t.Parallel()
 	kp, err := crypto.GenerateKeypair()
@@ -776,12 +785,12 @@ func TestApplyParsedContent_VerifyAndDecryptError(t *testing.T) {
 	differentServerPk := make([]byte, crypto.MLDSAPublicKeySize)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: differentServerPk,
+		keypair:      kp,
+		serverSigPks: [][]byte{differentServerPk},
 	}
 
 	decrypted := &crypto.DecryptedEmail{}
-	err = inbox.applyParsedContent(encryptedParsed, decrypted)
+	err = inbox.applyParsedContent(context.Background(), encryptedParsed, "", 0, decrypted)
 	if err == nil {
 		t.Error("expected error for mismatched server key")
 	}
@@ -799,12 +808,12 @@ func TestApplyParsedContent_ParseError(t *testing.T) {
 	encryptedParsed, serverPk := createTestEncryptedPayload(t, invalidJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
 	}
 
 	decrypted := &crypto.DecryptedEmail{}
-	err = inbox.applyParsedContent(encryptedParsed, decrypted)
+	err = inbox.applyParsedContent(context.Background(), encryptedParsed, "", 0, decrypted)
 	if err == nil {
 		t.Error("expected error for invalid JSON")
 	}
@@ -844,13 +853,13 @@ func TestApplyParsedContent_Success(t *testing.T) {
 	encryptedParsed, serverPk := createTestEncryptedPayload(t, parsedJSON, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
 	decrypted := &crypto.DecryptedEmail{}
-	err = inbox.applyParsedContent(encryptedParsed, decrypted)
+	err = inbox.applyParsedContent(context.Background(), encryptedParsed, "", 0, decrypted)
 	if err != nil {
 		t.Fatalf("applyParsedContent() error = %v", err)
 	}
@@ -893,12 +902,12 @@ func TestVerifyAndDecrypt_Success(t *testing.T) {
 	payload, serverPk := createTestEncryptedPayload(t, plaintext, kp)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: serverPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
 	}
 
-	result, err := inbox.verifyAndDecrypt(payload)
+	result, err := inbox.verifyAndDecrypt(context.Background(), payload)
 	if err != nil {
 		t.Fatalf("verifyAndDecrypt() error = %v", err)
 	}
@@ -922,12 +931,12 @@ func TestVerifyAndDecrypt_SignatureError(t *testing.T) {
 	wrongServerPk := make([]byte, crypto.MLDSAPublicKeySize)
 
 	inbox := &Inbox{
-		keypair:     kp,
-		serverSigPk: wrongServerPk,
-		encrypted:   true,
+		keypair:      kp,
+		serverSigPks: [][]byte{wrongServerPk},
+		encrypted:    true,
 	}
 
-	_, err = inbox.verifyAndDecrypt(payload)
+	_, err = inbox.verifyAndDecrypt(context.Background(), payload)
 	if err == nil {
 		t.Error("expected error for wrong server key")
 	}
@@ -939,10 +948,381 @@ func TestVerifyAndDecrypt_SignatureError(t *testing.T) {
 	}
 }
 
+func TestInbox_VerifyEmailSignature(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, serverPk := createTestEncryptedPayload(t, []byte("payload"), kp)
+
+	inbox := &Inbox{serverSigPks: [][]byte{serverPk}, encrypted: true}
+	if err := inbox.VerifyEmailSignature(payload); err != nil {
+		t.Fatalf("VerifyEmailSignature() error = %v", err)
+	}
+
+	t.Run("key mismatch", func(t *testing.T) {
+		other := &Inbox{serverSigPks: [][]byte{make([]byte, crypto.MLDSAPublicKeySize)}, encrypted: true}
+		err := other.VerifyEmailSignature(payload)
+		var sigErr *SignatureVerificationError
+		if !errors.As(err, &sigErr) || !sigErr.IsKeyMismatch {
+			t.Fatalf("VerifyEmailSignature() error = %v, want key mismatch SignatureVerificationError", err)
+		}
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		tampered := *payload
+		ct, _ := crypto.FromBase64URL(tampered.Ciphertext)
+		ct[0] ^= 0xff
+		tampered.Ciphertext = crypto.ToBase64URL(ct)
+
+		err := inbox.VerifyEmailSignature(&tampered)
+		var sigErr *SignatureVerificationError
+		if !errors.As(err, &sigErr) || sigErr.IsKeyMismatch {
+			t.Fatalf("VerifyEmailSignature() error = %v, want signature SignatureVerificationError", err)
+		}
+		if !errors.Is(err, ErrSignatureInvalid) {
+			t.Error("error should match ErrSignatureInvalid")
+		}
+	})
+
+	t.Run("plain inbox", func(t *testing.T) {
+		plain := &Inbox{emailAddress: "plain@example.com"}
+		if err := plain.VerifyEmailSignature(payload); err == nil {
+			t.Error("VerifyEmailSignature() on plain inbox should return error")
+		}
+	})
+
+	t.Run("nil payload", func(t *testing.T) {
+		if err := inbox.VerifyEmailSignature(nil); err == nil {
+			t.Error("VerifyEmailSignature(nil) should return error")
+		}
+	})
+}
+
+func TestInbox_DecryptPayload(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("test plaintext data")
+	payload, serverPk := createTestEncryptedPayload(t, plaintext, kp)
+
+	inbox := &Inbox{
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
+	}
+
+	result, err := inbox.DecryptPayload(payload)
+	if err != nil {
+		t.Fatalf("DecryptPayload() error = %v", err)
+	}
+	if string(result) != string(plaintext) {
+		t.Errorf("DecryptPayload() = %s, want %s", string(result), string(plaintext))
+	}
+
+	t.Run("wrong server key", func(t *testing.T) {
+		other := &Inbox{keypair: kp, serverSigPks: [][]byte{make([]byte, crypto.MLDSAPublicKeySize)}, encrypted: true}
+		_, err := other.DecryptPayload(payload)
+		var sigErr *SignatureVerificationError
+		if !errors.As(err, &sigErr) || !sigErr.IsKeyMismatch {
+			t.Errorf("DecryptPayload() error = %v, want key mismatch SignatureVerificationError", err)
+		}
+	})
+
+	t.Run("wrong keypair", func(t *testing.T) {
+		otherKp, err := crypto.GenerateKeypair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		other := &Inbox{keypair: otherKp, serverSigPks: [][]byte{serverPk}, encrypted: true}
+		if _, err := other.DecryptPayload(payload); !errors.Is(err, ErrDecryptionFailed) {
+			t.Errorf("DecryptPayload() error = %v, want ErrDecryptionFailed", err)
+		}
+	})
+
+	t.Run("plain inbox", func(t *testing.T) {
+		plain := &Inbox{emailAddress: "plain@example.com"}
+		if _, err := plain.DecryptPayload(payload); err == nil {
+			t.Error("DecryptPayload() on plain inbox should return error")
+		}
+	})
+
+	t.Run("nil payload", func(t *testing.T) {
+		if _, err := inbox.DecryptPayload(nil); err == nil {
+			t.Error("DecryptPayload(nil) should return error")
+		}
+	})
+}
+
 // =============================================================================
 // Plain Email Tests (non-encrypted)
 // =============================================================================
 
+func TestInbox_AdditionalServerKeys(t *testing.T) {
+	t.Parallel()
+	source, mock, err := newMockInbox("rotation@example.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	exported := source.Export()
+	_, other, err := newMockInbox("other@example.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	rotatedPk, rotatedPriv, err := crypto.GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &Client{additionalServerKeys: [][]byte{rotatedPk}}
+	inbox, err := newInboxFromExport(exported, client)
+	if err != nil {
+		t.Fatalf("newInboxFromExport() error = %v", err)
+	}
+
+	metadataJSON, _ := json.Marshal(map[string]string{"from": "sender@example.com", "subject": "Rotated"})
+	tests := []struct {
+		name         string
+		signer       []byte
+		wantMismatch bool
+	}{
+		{"pinned key", mock.SigningKey, false},
+		{"additional key", rotatedPriv, false},
+		{"unknown key", other.SigningKey, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			payload, err := crypto.Encrypt(metadataJSON, inbox.keypair.PublicKey, tt.signer, []byte(inbox.inboxHash))
+			if err != nil {
+				t.Fatal(err)
+			}
+			email, err := inbox.decryptEmail(context.Background(), &api.RawEmail{ID: "email-1", EncryptedMetadata: payload})
+			if tt.wantMismatch {
+				var sigErr *SignatureVerificationError
+				if !errors.As(err, &sigErr) || !sigErr.IsKeyMismatch {
+					t.Fatalf("decryptEmail() error = %v, want key mismatch SignatureVerificationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decryptEmail() error = %v", err)
+			}
+			if email.Subject != "Rotated" {
+				t.Errorf("Subject = %q, want Rotated", email.Subject)
+			}
+		})
+	}
+
+	t.Run("export keeps only the pinned key", func(t *testing.T) {
+		t.Parallel()
+		if got := inbox.Export().ServerSigPk; got != exported.ServerSigPk {
+			t.Errorf("Export().ServerSigPk = %q, want the pinned key %q", got, exported.ServerSigPk)
+		}
+	})
+
+	t.Run("detached inbox accepts only the pinned key", func(t *testing.T) {
+		t.Parallel()
+		detached, err := NewDetachedInbox(exported)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload, err := crypto.Encrypt(metadataJSON, detached.keypair.PublicKey, rotatedPriv, []byte(detached.inboxHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := detached.VerifyEmailSignature(payload); !errors.Is(err, ErrSignatureInvalid) {
+			t.Errorf("VerifyEmailSignature() error = %v, want ErrSignatureInvalid", err)
+		}
+	})
+}
+
+func TestInbox_RequireSuite(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, serverPk := createTestEncryptedPayload(t, []byte(`{"subject":"pinned"}`), kp)
+
+	t.Run("default suite accepted", func(t *testing.T) {
+		t.Parallel()
+		inbox := &Inbox{keypair: kp, serverSigPks: [][]byte{serverPk}, encrypted: true}
+		if _, err := inbox.verifyAndDecrypt(context.Background(), payload); err != nil {
+			t.Errorf("verifyAndDecrypt() error = %v", err)
+		}
+	})
+
+	t.Run("explicit default accepted", func(t *testing.T) {
+		t.Parallel()
+		suite := DefaultAlgorithmSuite()
+		inbox := &Inbox{keypair: kp, serverSigPks: [][]byte{serverPk}, encrypted: true, requireSuite: &suite}
+		if err := inbox.VerifyEmailSignature(payload); err != nil {
+			t.Errorf("VerifyEmailSignature() error = %v", err)
+		}
+	})
+
+	t.Run("different suite rejected", func(t *testing.T) {
+		t.Parallel()
+		cfg := &inboxConfig{}
+		pinned := DefaultAlgorithmSuite()
+		pinned.AEAD = "AES-128-GCM"
+		WithRequireSuite(pinned)(cfg)
+		inbox := &Inbox{keypair: kp, serverSigPks: [][]byte{serverPk}, encrypted: true, requireSuite: cfg.requireSuite}
+
+		if _, err := inbox.verifyAndDecrypt(context.Background(), payload); !errors.Is(err, ErrUnexpectedSuite) {
+			t.Errorf("verifyAndDecrypt() error = %v, want ErrUnexpectedSuite", err)
+		}
+	})
+
+	t.Run("default is not shared", func(t *testing.T) {
+		t.Parallel()
+		suite := DefaultAlgorithmSuite()
+		suite.KEM = "X25519"
+		if DefaultAlgorithmSuite().KEM == "X25519" {
+			t.Error("modifying a returned suite changed DefaultAlgorithmSuite()")
+		}
+	})
+}
+
+func TestInbox_RequireSuite_SurvivesExport(t *testing.T) {
+	t.Parallel()
+	inbox, _, err := newMockInbox("pinned@example.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	pinned := DefaultAlgorithmSuite()
+	inbox.requireSuite = &pinned
+
+	data, err := json.Marshal(inbox.Export())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var exported ExportedInbox
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	imported, err := NewDetachedInbox(&exported)
+	if err != nil {
+		t.Fatalf("NewDetachedInbox() error = %v", err)
+	}
+	if imported.requireSuite == nil || *imported.requireSuite != pinned {
+		t.Errorf("imported requireSuite = %v, want %v", imported.requireSuite, pinned)
+	}
+
+	unsupported := DefaultAlgorithmSuite()
+	unsupported.AEAD = "AES-128-GCM"
+	for name, suite := range map[string]*AlgorithmSuite{
+		"partial":     {KEM: "ML-KEM-768"},
+		"unsupported": &unsupported,
+	} {
+		exported.RequireSuite = suite
+		if _, err := NewDetachedInbox(&exported); !errors.Is(err, ErrInvalidImportData) || !errors.Is(err, ErrUnsupportedSuite) {
+			t.Errorf("NewDetachedInbox() with %s suite error = %v, want ErrInvalidImportData and ErrUnsupportedSuite", name, err)
+		}
+	}
+}
+
+func TestInbox_WithAAD(t *testing.T) {
+	t.Parallel()
+	inbox, mock, err := newMockInbox("mock@example.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	// Mock payloads carry the inbox hash as AAD.
+	body, err := mock.EmailJSON("email-1",
+		map[string]string{"from": "sender@example.com", "subject": "AAD"},
+		map[string]string{"text": "hello"},
+	)
+	if err != nil {
+		t.Fatalf("EmailJSON() error = %v", err)
+	}
+	var raw api.RawEmail
+	if err := json.Unmarshal(body, &raw); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	t.Run("default leaves AAD unchecked", func(t *testing.T) {
+		if _, err := inbox.decryptEmail(context.Background(), &raw); err != nil {
+			t.Errorf("decryptEmail() error = %v", err)
+		}
+	})
+
+	t.Run("custom AAD accepted", func(t *testing.T) {
+		var got []AADContext
+		cfg := &inboxConfig{}
+		WithAAD(func(c AADContext) []byte {
+			got = append(got, c)
+			return []byte(c.InboxHash)
+		})(cfg)
+		custom := &Inbox{inboxHash: inbox.inboxHash, keypair: inbox.keypair, serverSigPks: inbox.serverSigPks, encrypted: true, aadFunc: cfg.aadFunc}
+
+		email, err := custom.decryptEmail(context.Background(), &raw)
+		if err != nil {
+			t.Fatalf("decryptEmail() error = %v", err)
+		}
+		if email.Text != "hello" {
+			t.Errorf("Text = %q, want hello", email.Text)
+		}
+		want := []AADContext{
+			{InboxHash: inbox.inboxHash, EmailID: "email-1", Part: "metadata"},
+			{InboxHash: inbox.inboxHash, EmailID: "email-1", Part: "parsed"},
+		}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("AADFunc called with %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("custom AAD mismatch rejected", func(t *testing.T) {
+		custom := &Inbox{inboxHash: inbox.inboxHash, keypair: inbox.keypair, serverSigPks: inbox.serverSigPks, encrypted: true,
+			aadFunc: func(c AADContext) []byte { return []byte(c.InboxHash + ":" + c.EmailID) }}
+		if _, err := custom.decryptEmail(context.Background(), &raw); !errors.Is(err, ErrUnexpectedAAD) {
+			t.Errorf("decryptEmail() error = %v, want ErrUnexpectedAAD", err)
+		}
+	})
+}
+
+func TestInbox_SetAAD_ImportedInbox(t *testing.T) {
+	t.Parallel()
+	source, mock, err := newMockInbox("mock@example.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	body, err := mock.EmailJSON("email-1", map[string]string{"subject": "AAD"}, nil)
+	if err != nil {
+		t.Fatalf("EmailJSON() error = %v", err)
+	}
+	var raw api.RawEmail
+	if err := json.Unmarshal(body, &raw); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	imported, err := NewDetachedInbox(source.Export())
+	if err != nil {
+		t.Fatalf("NewDetachedInbox() error = %v", err)
+	}
+	// Mock payloads carry the inbox hash as AAD.
+	imported.SetAAD(func(c AADContext) []byte { return []byte(c.InboxHash + ":" + c.EmailID) })
+	if _, err := imported.decryptEmail(context.Background(), &raw); !errors.Is(err, ErrUnexpectedAAD) {
+		t.Errorf("decryptEmail() error = %v, want ErrUnexpectedAAD", err)
+	}
+	if _, err := imported.DecryptEmailPayload(raw.EncryptedMetadata, "email-1", "metadata"); !errors.Is(err, ErrUnexpectedAAD) {
+		t.Errorf("DecryptEmailPayload() error = %v, want ErrUnexpectedAAD", err)
+	}
+
+	imported.SetAAD(func(c AADContext) []byte { return []byte(c.InboxHash) })
+	if _, err := imported.decryptEmail(context.Background(), &raw); err != nil {
+		t.Errorf("decryptEmail() with matching AAD error = %v", err)
+	}
+	if _, err := imported.DecryptEmailPayload(raw.EncryptedMetadata, "email-1", "metadata"); err != nil {
+		t.Errorf("DecryptEmailPayload() with matching AAD error = %v", err)
+	}
+}
+
 func TestDecodePlainEmail_Success(t *testing.T) {
 	t.Parallel()
 	inbox := &Inbox{}
@@ -966,7 +1346,7 @@ func TestDecodePlainEmail_Success(t *testing.T) {
 		// No Parsed content - metadata only
 	}
 
-	result, err := inbox.decodePlainEmail(rawEmail)
+	result, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err != nil {
 		t.Fatalf("decodePlainEmail() error = %v", err)
 	}
@@ -1033,7 +1413,7 @@ func TestDecodePlainEmail_WithParsedContent(t *testing.T) {
 		Parsed:     parsedB64,
 	}
 
-	result, err := inbox.decodePlainEmail(rawEmail)
+	result, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err != nil {
 		t.Fatalf("decodePlainEmail() error = %v", err)
 	}
@@ -1089,7 +1469,7 @@ func TestDecodePlainEmail_WithAuthResults(t *testing.T) {
 		Parsed:     parsedB64,
 	}
 
-	result, err := inbox.decodePlainEmail(rawEmail)
+	result, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err != nil {
 		t.Fatalf("decodePlainEmail() error = %v", err)
 	}
@@ -1114,7 +1494,7 @@ func TestDecodePlainEmail_NoMetadata(t *testing.T) {
 		Metadata: "",
 	}
 
-	_, err := inbox.decodePlainEmail(rawEmail)
+	_, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err == nil {
 		t.Error("expected error for plain email with no metadata")
 	}
@@ -1132,7 +1512,7 @@ func TestDecodePlainEmail_InvalidBase64Metadata(t *testing.T) {
 		Metadata: "!!!invalid-base64!!!",
 	}
 
-	_, err := inbox.decodePlainEmail(rawEmail)
+	_, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err == nil {
 		t.Error("expected error for invalid Base64 metadata")
 	}
@@ -1150,7 +1530,7 @@ func TestDecodePlainEmail_InvalidJSONMetadata(t *testing.T) {
 		Metadata: invalidJSON,
 	}
 
-	_, err := inbox.decodePlainEmail(rawEmail)
+	_, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err == nil {
 		t.Error("expected error for invalid JSON metadata")
 	}
@@ -1175,7 +1555,7 @@ func TestDecodePlainEmail_InvalidBase64Parsed(t *testing.T) {
 		Parsed:   "!!!invalid-base64!!!",
 	}
 
-	_, err := inbox.decodePlainEmail(rawEmail)
+	_, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err == nil {
 		t.Error("expected error for invalid Base64 parsed content")
 	}
@@ -1203,7 +1583,7 @@ func TestDecodePlainEmail_InvalidJSONParsed(t *testing.T) {
 		Parsed:   invalidJSON,
 	}
 
-	_, err := inbox.decodePlainEmail(rawEmail)
+	_, err := inbox.decodePlainEmail(rawEmail, 0)
 	if err == nil {
 		t.Error("expected error for invalid JSON parsed content")
 	}
@@ -1232,7 +1612,7 @@ func TestDecryptMetadata_PlainEmail_Success(t *testing.T) {
 		Metadata:          metadataB64,
 	}
 
-	result, err := inbox.decryptMetadata(rawEmail)
+	result, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptMetadata() error = %v", err)
 	}
@@ -1267,7 +1647,7 @@ func TestDecryptMetadata_PlainEmail_InvalidBase64(t *testing.T) {
 		Metadata:          "!!!invalid-base64!!!",
 	}
 
-	_, err := inbox.decryptMetadata(rawEmail)
+	_, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for invalid Base64 metadata")
 	}
@@ -1285,7 +1665,7 @@ func TestDecryptMetadata_PlainEmail_InvalidJSON(t *testing.T) {
 		Metadata:          invalidJSON,
 	}
 
-	_, err := inbox.decryptMetadata(rawEmail)
+	_, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err == nil {
 		t.Error("expected error for invalid JSON metadata")
 	}
@@ -1312,7 +1692,7 @@ func TestDecryptMetadata_PlainEmail_ReceivedAtFallback(t *testing.T) {
 		Metadata:          metadataB64,
 	}
 
-	result, err := inbox.decryptMetadata(rawEmail)
+	result, err := inbox.decryptMetadata(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptMetadata() error = %v", err)
 	}
@@ -1353,7 +1733,7 @@ func TestDecryptEmail_PlainEmail_ViaDecryptEmail(t *testing.T) {
 		Parsed:            parsedB64,
 	}
 
-	result, err := inbox.decryptEmail(rawEmail)
+	result, err := inbox.decryptEmail(context.Background(), rawEmail)
 	if err != nil {
 		t.Fatalf("decryptEmail() error = %v", err)
 	}
@@ -1500,7 +1880,7 @@ func TestGetRawEmail_EncryptedEmail_Success(t *testing.T) {
 		emailAddress: "test@example.com",
 		client:       client,
 		keypair:      kp,
-		serverSigPk:  serverPk,
+		serverSigPks: [][]byte{serverPk},
 		encrypted:    true,
 	}
 
@@ -1540,7 +1920,7 @@ func TestGetRawEmail_EncryptedEmail_DecryptionError(t *testing.T) {
 		emailAddress: "test@example.com",
 		client:       client,
 		keypair:      kp,
-		serverSigPk:  differentServerPk, // Wrong key
+		serverSigPks: [][]byte{differentServerPk}, // Wrong key
 		encrypted:    true,
 	}
 
@@ -1552,7 +1932,10 @@ func TestGetRawEmail_EncryptedEmail_DecryptionError(t *testing.T) {
 	// Should be a signature verification error
 	var sigErr *SignatureVerificationError
 	if !errors.As(err, &sigErr) {
-		t.Errorf("expected SignatureVerificationError, got %T: %v", err, err)
+		t.Fatalf("expected SignatureVerificationError, got %T: %v", err, err)
+	}
+	if sigErr.Part != "raw" {
+		t.Errorf("Part = %q, want raw", sigErr.Part)
 	}
 }
 
@@ -1576,3 +1959,544 @@ func TestGetRawEmail_APIError(t *testing.T) {
 		t.Fatal("GetRawEmail() expected error for API error")
 	}
 }
+
+func TestDecodePlainEmail_AttachmentExceedsLimit(t *testing.T) {
+	t.Parallel()
+	inbox := &Inbox{client: &Client{maxAttachmentSize: 4}}
+
+	metadataJSON, _ := json.Marshal(map[string]interface{}{"from": "a@example.com", "subject": "Files"})
+	parsedJSON, _ := json.Marshal(map[string]interface{}{
+		"attachments": []map[string]interface{}{
+			{"filename": "big.bin", "size": 16, "content": crypto.ToBase64(make([]byte, 16))},
+		},
+	})
+	raw := &api.RawEmail{
+		ID:       "email-1",
+		Metadata: crypto.ToBase64URL(metadataJSON),
+		Parsed:   crypto.ToBase64URL(parsedJSON),
+	}
+
+	_, err := inbox.decodePlainEmail(raw, 0)
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("decodePlainEmail() error = %v, want ErrAttachmentTooLarge", err)
+	}
+
+	inbox.client.maxAttachmentSize = 16
+	email, err := inbox.decodePlainEmail(raw, 0)
+	if err != nil {
+		t.Fatalf("decodePlainEmail() error = %v", err)
+	}
+	if len(email.Attachments) != 1 || len(email.Attachments[0].Content) != 16 {
+		t.Errorf("Attachments = %+v, want one 16-byte attachment", email.Attachments)
+	}
+}
+
+func TestInbox_ExportWithEmails_ImportBundleRoundtrip(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadataJSON, _ := json.Marshal(map[string]interface{}{
+		"from":       "sender@example.com",
+		"to":         "test@example.com",
+		"subject":    "Captured",
+		"receivedAt": "2024-01-15T10:30:00Z",
+	})
+	encryptedMetadata, serverPk := createTestEncryptedPayload(t, metadataJSON, kp)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{{
+			ID:                "email-1",
+			ReceivedAt:        time.Now(),
+			EncryptedMetadata: encryptedMetadata,
+		}})
+	}))
+	defer server.Close()
+	apiClient, err := api.New("test-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inbox := &Inbox{
+		emailAddress: "test@example.com",
+		expiresAt:    time.Now().Add(time.Hour),
+		inboxHash:    "hash123",
+		keypair:      kp,
+		serverSigPks: [][]byte{serverPk},
+		encrypted:    true,
+		client:       &Client{apiClient: apiClient},
+	}
+
+	bundle, err := inbox.ExportWithEmails(context.Background())
+	if err != nil {
+		t.Fatalf("ExportWithEmails() error = %v", err)
+	}
+	if len(bundle.Emails) != 1 {
+		t.Fatalf("len(Emails) = %d, want 1", len(bundle.Emails))
+	}
+
+	// Round-trip through JSON as a fixture file would.
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored ExportedInboxBundle
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.EmailAddress != "test@example.com" {
+		t.Errorf("EmailAddress = %q, want test@example.com", restored.EmailAddress)
+	}
+
+	// Import into a client with no server access.
+	offline := &Client{subs: newSubscriptionManager()}
+	imported, err := offline.ImportInboxBundle(&restored)
+	if err != nil {
+		t.Fatalf("ImportInboxBundle() error = %v", err)
+	}
+	emails, err := imported.GetEmailsCached()
+	if err != nil {
+		t.Fatalf("GetEmailsCached() error = %v", err)
+	}
+	if len(emails) != 1 || emails[0].Subject != "Captured" {
+		t.Fatalf("emails = %+v, want one email with subject Captured", emails)
+	}
+
+	filtered, err := imported.GetEmailsCached(WithServerFilter(ServerFilter{Subject: "other"}))
+	if err != nil {
+		t.Fatalf("GetEmailsCached() error = %v", err)
+	}
+	if len(filtered) != 0 {
+		t.Errorf("filtered = %d emails, want 0", len(filtered))
+	}
+
+	// Tampered payloads fail signature verification.
+	restored.Emails[0].EncryptedMetadata.Ciphertext = restored.Emails[0].EncryptedMetadata.Ciphertext[:len(restored.Emails[0].EncryptedMetadata.Ciphertext)-4] + "AAAA"
+	tampered, err := offline.ImportInboxBundle(&restored)
+	if err != nil {
+		t.Fatalf("ImportInboxBundle() error = %v", err)
+	}
+	if _, err := tampered.GetEmailsCached(); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("GetEmailsCached() error = %v, want ErrSignatureInvalid", err)
+	}
+}
+
+func TestClient_ImportInboxBundle_Invalid(t *testing.T) {
+	t.Parallel()
+	c := &Client{}
+	if _, err := c.ImportInboxBundle(nil); err == nil {
+		t.Error("ImportInboxBundle(nil) should fail")
+	}
+	if _, err := c.ImportInboxBundle(&ExportedInboxBundle{}); !errors.Is(err, ErrInvalidImportData) {
+		t.Errorf("ImportInboxBundle() error = %v, want ErrInvalidImportData", err)
+	}
+}
+
+func TestVerifyAndDecrypt_SharesClientDecryptBudget(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, serverPk := createTestEncryptedPayload(t, []byte(`{"subject":"hi"}`), kp)
+
+	client := &Client{decryptSem: make(chan struct{}, 1)}
+	inbox := &Inbox{keypair: kp, serverSigPks: [][]byte{serverPk}, encrypted: true, client: client}
+
+	// Occupy the only slot, as another decryption on the client would.
+	client.decryptSem <- struct{}{}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := inbox.verifyAndDecrypt(context.Background(), payload)
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("verifyAndDecrypt() ran while the decrypt budget was exhausted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-client.decryptSem
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("verifyAndDecrypt() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("verifyAndDecrypt() did not run after a slot was released")
+	}
+	if len(client.decryptSem) != 0 {
+		t.Errorf("decrypt slots held after return = %d, want 0", len(client.decryptSem))
+	}
+}
+
+func TestDecodeContentEncoding(t *testing.T) {
+	t.Parallel()
+	gzipped := func(data []byte) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		return buf.Bytes()
+	}
+	plain := []byte(`{"text":"hello"}`)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding string
+		limit    int
+		want     []byte
+		wantErr  error
+	}{
+		{name: "no encoding", data: plain, want: plain},
+		{name: "identity", data: plain, encoding: "identity", want: plain},
+		{name: "gzip", data: gzipped(plain), encoding: "gzip", want: plain},
+		{name: "gzip case-insensitive", data: gzipped(plain), encoding: "GZIP", want: plain},
+		{name: "unsupported", data: plain, encoding: "br", wantErr: ErrUnsupportedContentEncoding},
+		{name: "bomb", data: gzipped(make([]byte, maxDecompressedSize+1)), encoding: "gzip", wantErr: ErrDecompressedTooLarge},
+		{name: "within limit", data: gzipped(plain), encoding: "gzip", limit: len(plain), want: plain},
+		{name: "over limit", data: gzipped(plain), encoding: "gzip", limit: len(plain) - 1, wantErr: ErrDecompressedTooLarge},
+		{name: "limit above default", data: gzipped(make([]byte, maxDecompressedSize+1)), encoding: "gzip", limit: 2 * maxDecompressedSize, wantErr: ErrDecompressedTooLarge},
+		{name: "identity ignores limit", data: plain, limit: 1, want: plain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := decodeContentEncoding(tt.data, tt.encoding, tt.limit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := decodeContentEncoding(plain, "gzip", 0); err == nil {
+		t.Error("decodeContentEncoding() on non-gzip data error = nil, want error")
+	}
+}
+
+func TestDecodePlainEmail_GzipParsed(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`{"text":"compressed body"}`))
+	zw.Close()
+
+	raw := newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Hi"}, nil)
+	raw.Parsed = crypto.ToBase64URL(buf.Bytes())
+	raw.ParsedEncoding = "gzip"
+
+	email, err := (&Inbox{}).decryptEmail(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("decryptEmail() error = %v", err)
+	}
+	if email.Text != "compressed body" {
+		t.Errorf("Text = %q, want %q", email.Text, "compressed body")
+	}
+}
+
+func TestDecryptEmail_GzipParsedUsesAuthenticatedEncoding(t *testing.T) {
+	t.Parallel()
+	inbox, mock, raws := newMockRawEmails(t, 1)
+	parsed := []byte(`{"text":"compressed body"}`)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(parsed)
+	zw.Close()
+
+	gzipPayload, err := mock.EncryptedPayload(buf.Bytes())
+	if err != nil {
+		t.Fatalf("EncryptedPayload() error = %v", err)
+	}
+	plainPayload, err := mock.EncryptedPayload(parsed)
+	if err != nil {
+		t.Fatalf("EncryptedPayload() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		payload    *EncryptedPayload
+		encoding   string
+		maxDecoded int
+		wantErr    error
+	}{
+		{name: "gzip declared", payload: gzipPayload, encoding: "gzip"},
+		{name: "gzip undeclared", payload: gzipPayload},
+		{name: "gzip declared on plain content", payload: plainPayload, encoding: "gzip"},
+		{name: "unsupported hint", payload: gzipPayload, encoding: "br", wantErr: ErrUnsupportedContentEncoding},
+		{name: "over WithMaxBodyBytes", payload: gzipPayload, encoding: "gzip", maxDecoded: len(parsed) - 1, wantErr: ErrDecompressedTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			raw := *raws[0]
+			raw.EncryptedParsed = tt.payload
+			raw.ParsedEncoding = tt.encoding
+
+			email, err := inbox.decryptEmailWithin(context.Background(), &raw, tt.maxDecoded)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("decryptEmailWithin() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decryptEmailWithin() error = %v", err)
+			}
+			if email.Text != "compressed body" {
+				t.Errorf("Text = %q, want %q", email.Text, "compressed body")
+			}
+		})
+	}
+}
+
+func TestDecryptEmail_BodyReady(t *testing.T) {
+	t.Parallel()
+	notReady, ready := false, true
+
+	tests := []struct {
+		name        string
+		parsed      map[string]interface{}
+		parsedReady *bool
+		want        bool
+	}{
+		{name: "parsed without flag", parsed: map[string]interface{}{"text": "Hi"}, want: true},
+		{name: "parsed and ready", parsed: map[string]interface{}{"text": "Hi"}, parsedReady: &ready, want: true},
+		{name: "parsed but not ready", parsed: map[string]interface{}{"text": ""}, parsedReady: &notReady, want: false},
+		{name: "metadata only", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			raw := newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Hi"}, tt.parsed)
+			raw.ParsedReady = tt.parsedReady
+
+			email, err := (&Inbox{}).decryptEmail(context.Background(), raw)
+			if err != nil {
+				t.Fatalf("decryptEmail() error = %v", err)
+			}
+			if email.BodyReady != tt.want {
+				t.Errorf("BodyReady = %v, want %v", email.BodyReady, tt.want)
+			}
+		})
+	}
+}
+
+// newMockRawEmails returns an encrypted mock inbox, its key material and n
+// raw emails for it, with IDs email-0 through email-(n-1) and matching
+// subjects.
+func newMockRawEmails(tb testing.TB, n int) (*Inbox, *mockinbox.Inbox, []*api.RawEmail) {
+	tb.Helper()
+	inbox, mock, err := newMockInbox("mock@example.com")
+	if err != nil {
+		tb.Fatalf("newMockInbox() error = %v", err)
+	}
+	raws := make([]*api.RawEmail, n)
+	for j := range raws {
+		id := fmt.Sprintf("email-%d", j)
+		body, err := mock.EmailJSON(id,
+			map[string]string{"from": "sender@example.com", "to": "mock@example.com", "subject": id},
+			map[string]string{"text": "body of " + id},
+		)
+		if err != nil {
+			tb.Fatalf("EmailJSON() error = %v", err)
+		}
+		raws[j] = &api.RawEmail{}
+		if err := json.Unmarshal(body, raws[j]); err != nil {
+			tb.Fatalf("Unmarshal() error = %v", err)
+		}
+	}
+	return inbox, mock, raws
+}
+
+func TestDecryptEmails_PreservesOrder(t *testing.T) {
+	t.Parallel()
+	inbox, _, raws := newMockRawEmails(t, 20)
+
+	for _, workers := range []int{0, 1, 4, 50} {
+		emails, err := inbox.decryptEmails(context.Background(), raws, workers, 0)
+		if err != nil {
+			t.Fatalf("decryptEmails(workers=%d) error = %v", workers, err)
+		}
+		if len(emails) != len(raws) {
+			t.Fatalf("decryptEmails(workers=%d) returned %d emails, want %d", workers, len(emails), len(raws))
+		}
+		for j, email := range emails {
+			if email.ID != raws[j].ID || email.Subject != raws[j].ID {
+				t.Errorf("workers=%d: emails[%d] = %s/%s, want %s", workers, j, email.ID, email.Subject, raws[j].ID)
+			}
+		}
+	}
+}
+
+func TestDecryptEmails_Error(t *testing.T) {
+	t.Parallel()
+	inbox, _, raws := newMockRawEmails(t, 20)
+	raws[7].EncryptedMetadata.Ciphertext = raws[7].EncryptedMetadata.Ciphertext[:len(raws[7].EncryptedMetadata.Ciphertext)-1]
+
+	for _, workers := range []int{1, 4} {
+		emails, err := inbox.decryptEmails(context.Background(), raws, workers, 0)
+		if err == nil {
+			t.Fatalf("decryptEmails(workers=%d) expected error", workers)
+		}
+		if emails != nil {
+			t.Errorf("decryptEmails(workers=%d) returned emails with error", workers)
+		}
+		if !strings.Contains(err.Error(), "email email-7:") {
+			t.Errorf("decryptEmails(workers=%d) error = %v, want it to name email-7", workers, err)
+		}
+	}
+}
+
+func TestDecryptEmails_ContextCanceled(t *testing.T) {
+	t.Parallel()
+	inbox, _, raws := newMockRawEmails(t, 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, workers := range []int{1, 4} {
+		if _, err := inbox.decryptEmails(ctx, raws, workers, 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("decryptEmails(workers=%d) error = %v, want context.Canceled", workers, err)
+		}
+	}
+}
+
+// cancelingClock cancels a context on its nth call to Now. The clock skew
+// check calls Now once per decrypted email, so it marks progress through a
+// batch.
+type cancelingClock struct {
+	clock.Real
+	calls  atomic.Int64
+	n      int64
+	cancel context.CancelFunc
+}
+
+func (c *cancelingClock) Now() time.Time {
+	if c.calls.Add(1) == c.n {
+		c.cancel()
+	}
+	return time.Now()
+}
+
+func TestInbox_GetEmails_CanceledMidway(t *testing.T) {
+	t.Parallel()
+	inbox, mock, err := newMockInbox("mock@example.com")
+	if err != nil {
+		t.Fatalf("newMockInbox() error = %v", err)
+	}
+	const total = 100
+	emails := make([]json.RawMessage, total)
+	for j := range emails {
+		id := fmt.Sprintf("email-%d", j)
+		emails[j], err = mock.EmailJSON(id,
+			map[string]string{"from": "sender@example.com", "subject": id, "receivedAt": time.Now().UTC().Format(time.RFC3339)},
+			map[string]string{"text": "body of " + id},
+		)
+		if err != nil {
+			t.Fatalf("EmailJSON() error = %v", err)
+		}
+	}
+	inbox.client = newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(emails)
+	}).client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := &cancelingClock{n: 10, cancel: cancel}
+	inbox.client.clk = clk
+	inbox.client.clockSkewTolerance = time.Hour
+	inbox.client.decryptSem = make(chan struct{}, 1)
+
+	if _, err := inbox.GetEmails(ctx, WithDecryptWorkers(1)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetEmails() error = %v, want context.Canceled", err)
+	}
+	if calls := clk.calls.Load(); calls != 10 {
+		t.Errorf("decrypted metadata of %d emails, want to stop at 10 of %d", calls, total)
+	}
+	if len(inbox.client.decryptSem) != 0 {
+		t.Errorf("decrypt slots held after return = %d, want 0", len(inbox.client.decryptSem))
+	}
+}
+
+func BenchmarkDecryptEmails(b *testing.B) {
+	inbox, _, raws := newMockRawEmails(b, 50)
+	for _, bm := range []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := inbox.decryptEmails(context.Background(), raws, bm.workers, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestInbox_VerifyAttachmentChecksums(t *testing.T) {
+	t.Parallel()
+	content := []byte("hello")
+	sum := sha256.Sum256(content)
+	attachment := func(checksum string) map[string]interface{} {
+		return map[string]interface{}{"attachments": []map[string]interface{}{{
+			"filename":    "hello.txt",
+			"contentType": "text/plain",
+			"size":        len(content),
+			"content":     base64.StdEncoding.EncodeToString(content),
+			"checksum":    checksum,
+		}}}
+	}
+
+	tests := []struct {
+		name     string
+		verify   bool
+		checksum string
+		wantErr  bool
+	}{
+		{"valid checksum", true, hex.EncodeToString(sum[:]), false},
+		{"mismatch fails decryption", true, strings.Repeat("ab", 32), true},
+		{"mismatch ignored when disabled", false, strings.Repeat("ab", 32), false},
+		{"missing checksum is skipped", true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inbox := &Inbox{client: &Client{verifyAttachmentChecksums: tt.verify}}
+			raw := newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Hi"}, attachment(tt.checksum))
+
+			email, err := inbox.decodePlainEmail(raw, 0)
+			if tt.wantErr {
+				var csErr *ChecksumError
+				if !errors.As(err, &csErr) || csErr.Filename != "hello.txt" {
+					t.Fatalf("decodePlainEmail() error = %v, want *ChecksumError for hello.txt", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodePlainEmail() error = %v", err)
+			}
+			if len(email.Attachments) != 1 || string(email.Attachments[0].Content) != "hello" {
+				t.Errorf("attachments = %+v, want hello.txt", email.Attachments)
+			}
+		})
+	}
+}
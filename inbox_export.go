@@ -1,10 +1,14 @@
 package vaultsandbox
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/vaultsandbox/client-go/internal/api"
 	"github.com/vaultsandbox/client-go/internal/crypto"
 )
 
@@ -38,10 +42,16 @@ type ExportedInbox struct {
 	EmailAuth bool `json:"emailAuth"`
 	// Encrypted indicates whether this is an encrypted inbox.
 	Encrypted bool `json:"encrypted"`
+	// RequireSuite is the algorithm suite pinned with [WithRequireSuite], if
+	// any. Only set for encrypted inboxes.
+	RequireSuite *AlgorithmSuite `json:"requireSuite,omitempty"`
 }
 
 // Validate checks that the exported data is valid per VaultSandbox spec Section 10.
-// Validation steps are performed in the order specified.
+// Validation steps are performed in the order specified. For encrypted
+// inboxes it also checks that the public key embedded in the secret key
+// matches the hash of it stored in the key, catching truncated or spliced
+// keys before they fail to decrypt.
 func (e *ExportedInbox) Validate() error {
 	// Step 2: Validate version == 1
 	if e.Version != ExportVersion {
@@ -74,6 +84,12 @@ func (e *ExportedInbox) Validate() error {
 		if len(secretKey) != crypto.MLKEMSecretKeySize {
 			return fmt.Errorf("%w: secretKey size %d, expected %d", ErrInvalidImportData, len(secretKey), crypto.MLKEMSecretKeySize)
 		}
+		// The public key is derived from the copy embedded in the secret key;
+		// check it against the hash stored next to it so a corrupt key fails
+		// here rather than at the first decryption.
+		if err := crypto.CheckSecretKey(secretKey); err != nil {
+			return fmt.Errorf("%w: secretKey is corrupt: %v", ErrInvalidImportData, err)
+		}
 
 		// Step 7: Validate and decode serverSigPk (1952 bytes)
 		if e.ServerSigPk == "" {
@@ -86,6 +102,10 @@ func (e *ExportedInbox) Validate() error {
 		if len(serverSigPk) != crypto.MLDSAPublicKeySize {
 			return fmt.Errorf("%w: serverSigPk size %d, expected %d", ErrInvalidImportData, len(serverSigPk), crypto.MLDSAPublicKeySize)
 		}
+
+		if err := checkSupportedSuite(e.RequireSuite); err != nil {
+			return fmt.Errorf("%w: requireSuite: %w", ErrInvalidImportData, err)
+		}
 	}
 
 	// Step 8: Validate timestamps (Go's time.Time handles ISO 8601 via JSON unmarshaling)
@@ -106,7 +126,7 @@ func (i *Inbox) Export() *ExportedInbox {
 	exported := &ExportedInbox{
 		Version:      ExportVersion,
 		EmailAddress: i.emailAddress,
-		ExpiresAt:    i.expiresAt,
+		ExpiresAt:    i.ExpiresAt(),
 		InboxHash:    i.inboxHash,
 		ExportedAt:   time.Now().UTC(),
 		EmailAuth:    i.emailAuth,
@@ -114,14 +134,261 @@ func (i *Inbox) Export() *ExportedInbox {
 	}
 
 	// Only include cryptographic material for encrypted inboxes
-	if i.encrypted && i.serverSigPk != nil && i.keypair != nil {
-		exported.ServerSigPk = crypto.ToBase64URL(i.serverSigPk)
+	if serverSigPks := i.serverKeys(); i.encrypted && len(serverSigPks) > 0 && i.keypair != nil {
+		exported.ServerSigPk = crypto.ToBase64URL(serverSigPks[0])
 		exported.SecretKey = crypto.ToBase64URL(i.keypair.SecretKey)
+		if i.requireSuite != nil {
+			suite := *i.requireSuite
+			exported.RequireSuite = &suite
+		}
 	}
 
 	return exported
 }
 
+// ExportSetVersion is the current [ExportedInboxSet] format version.
+const ExportSetVersion = 1
+
+// ExportedInboxSet holds the exports of several inboxes, as produced by
+// [Client.ExportAllInboxes].
+// WARNING: For encrypted inboxes, this contains private key material - handle securely.
+type ExportedInboxSet struct {
+	// Version is the set format version. MUST be 1.
+	Version int `json:"version"`
+	// ExportedAt is the export timestamp (ISO 8601). Informational only.
+	ExportedAt time.Time `json:"exportedAt"`
+	// Inboxes holds one export per inbox.
+	Inboxes []ExportedInbox `json:"inboxes"`
+}
+
+// EncryptedExportVersion is the current passphrase-encrypted export format
+// version.
+const EncryptedExportVersion = 1
+
+// KDFArgon2id identifies Argon2id as the key derivation function of an
+// [EncryptedExport].
+const KDFArgon2id = "argon2id"
+
+// Bounds on the Argon2id parameters accepted on import, so a crafted export
+// cannot make Decrypt allocate or compute without limit. They leave room for
+// exports made with costs several times the defaults.
+const (
+	// maxArgon2Memory is the largest memory cost in KiB (256 MiB).
+	maxArgon2Memory = 256 * 1024
+	// maxArgon2Time is the largest number of passes.
+	maxArgon2Time = 16
+)
+
+// EncryptedExport is an [ExportedInbox] sealed with a passphrase, safe to
+// store where the plain export would leak the inbox's secret key. The
+// export's JSON is encrypted with AES-256-GCM under a key derived from the
+// passphrase with Argon2id; the KDF parameters and salt travel with it.
+// Create one with [Inbox.ExportEncrypted] and restore it with
+// [Client.ImportEncryptedInbox].
+type EncryptedExport struct {
+	// Version is the encrypted export format version. MUST be 1.
+	Version int `json:"version"`
+	// EmailAddress is the inbox email address, in clear for identification.
+	// It must match the address inside the ciphertext.
+	EmailAddress string `json:"emailAddress"`
+	// KDF is the key derivation function. MUST be "argon2id".
+	KDF string `json:"kdf"`
+	// Salt is the KDF salt (base64url).
+	Salt string `json:"salt"`
+	// Time is the Argon2id number of passes.
+	Time uint32 `json:"time"`
+	// Memory is the Argon2id memory cost in KiB.
+	Memory uint32 `json:"memory"`
+	// Threads is the Argon2id degree of parallelism.
+	Threads uint8 `json:"threads"`
+	// Ciphertext is the nonce, the AES-256-GCM encrypted ExportedInbox JSON,
+	// and the tag (base64url).
+	Ciphertext string `json:"ciphertext"`
+	// ExportedAt is the export timestamp (ISO 8601). Informational only.
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// ExportEncrypted returns the inbox export sealed with passphrase. Unlike
+// [Inbox.Export], the result does not expose the secret key and can be
+// stored in CI artifacts or other shared locations.
+func (i *Inbox) ExportEncrypted(passphrase string) (*EncryptedExport, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	plaintext, err := json.Marshal(i.Export())
+	if err != nil {
+		return nil, fmt.Errorf("marshal export: %w", err) //coverage:ignore
+	}
+
+	salt := make([]byte, crypto.Argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err) //coverage:ignore
+	}
+	nonce := make([]byte, crypto.AESNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err) //coverage:ignore
+	}
+
+	key := crypto.DeriveKeyFromPassphrase(passphrase, salt, crypto.Argon2Time, crypto.Argon2Memory, crypto.Argon2Threads)
+	ciphertext, err := crypto.EncryptAES(key, plaintext, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt export: %w", err) //coverage:ignore
+	}
+
+	return &EncryptedExport{
+		Version:      EncryptedExportVersion,
+		EmailAddress: i.emailAddress,
+		KDF:          KDFArgon2id,
+		Salt:         crypto.ToBase64URL(salt),
+		Time:         crypto.Argon2Time,
+		Memory:       crypto.Argon2Memory,
+		Threads:      crypto.Argon2Threads,
+		Ciphertext:   crypto.ToBase64URL(ciphertext),
+		ExportedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// Decrypt opens the export with passphrase and returns the validated
+// [ExportedInbox]. A wrong passphrase or a modified export returns an error
+// wrapping [ErrDecryptionFailed]; malformed fields return an error wrapping
+// [ErrInvalidImportData].
+func (e *EncryptedExport) Decrypt(passphrase string) (*ExportedInbox, error) {
+	if e.Version != EncryptedExportVersion {
+		return nil, fmt.Errorf("%w: unsupported encrypted export version %d, expected %d", ErrInvalidImportData, e.Version, EncryptedExportVersion)
+	}
+	if e.KDF != KDFArgon2id {
+		return nil, fmt.Errorf("%w: unsupported kdf %q", ErrInvalidImportData, e.KDF)
+	}
+	if e.Time == 0 || e.Time > maxArgon2Time || e.Threads == 0 || e.Memory == 0 || e.Memory > maxArgon2Memory {
+		return nil, fmt.Errorf("%w: invalid kdf parameters", ErrInvalidImportData)
+	}
+	salt, err := crypto.FromBase64URL(e.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("%w: invalid salt encoding", ErrInvalidImportData)
+	}
+	ciphertext, err := crypto.FromBase64URL(e.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ciphertext encoding", ErrInvalidImportData)
+	}
+
+	key := crypto.DeriveKeyFromPassphrase(passphrase, salt, e.Time, e.Memory, e.Threads)
+	plaintext, err := crypto.DecryptAES(key, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong passphrase or corrupted export", ErrDecryptionFailed)
+	}
+
+	var exported ExportedInbox
+	if err := json.Unmarshal(plaintext, &exported); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportData, err)
+	}
+	if exported.EmailAddress != e.EmailAddress {
+		return nil, fmt.Errorf("%w: emailAddress %q does not match encrypted export %q", ErrInvalidImportData, e.EmailAddress, exported.EmailAddress)
+	}
+	if err := exported.Validate(); err != nil {
+		return nil, err
+	}
+	return &exported, nil
+}
+
+// ExportedEmail is an email in the server's wire format. Encrypted emails
+// keep their signed payloads, so authenticity is re-verified when they are
+// decrypted after import.
+type ExportedEmail = api.RawEmail
+
+// ExportedInboxBundle is an exported inbox together with the emails it held
+// at export time. It serializes as the ExportedInbox fields plus an "emails"
+// array, so it can also be passed to ImportInbox via its ExportedInbox field.
+// WARNING: For encrypted inboxes, this contains private key material - handle securely.
+type ExportedInboxBundle struct {
+	ExportedInbox
+	// Emails holds the inbox's emails as returned by the server, including
+	// full content.
+	Emails []*ExportedEmail `json:"emails"`
+}
+
+// ExportWithEmails returns the inbox export together with all of its emails.
+// Emails are stored in their original encrypted (or Base64 plain) form rather
+// than decrypted, preserving the server signatures.
+func (i *Inbox) ExportWithEmails(ctx context.Context) (*ExportedInboxBundle, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
+	resp, err := i.client.apiClient.GetEmails(ctx, i.emailAddress, true)
+	if err != nil {
+		return nil, err
+	}
+	emails := resp.Emails
+	if emails == nil {
+		emails = []*ExportedEmail{}
+	}
+	return &ExportedInboxBundle{
+		ExportedInbox: *i.Export(),
+		Emails:        emails,
+	}, nil
+}
+
+// GetEmailsCached decrypts and returns the emails stored with an inbox
+// imported via [Client.ImportInboxBundle], without contacting the server.
+// [WithServerFilter], [WithUnreadOnly], [WithReadOnly], [WithMaxBodyBytes],
+// and [WithFields] are applied locally. Inboxes not imported from a bundle have no cached emails and
+// return an empty slice.
+func (i *Inbox) GetEmailsCached(opts ...FetchOption) ([]*Email, error) {
+	cfg := &fetchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	decrypted, err := i.decryptEmails(context.Background(), i.cachedEmails, cfg.decryptWorkers, cfg.maxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]*Email, 0, len(decrypted))
+	for _, email := range decrypted {
+		if cfg.serverFilter != nil && !cfg.serverFilter.matches(email) {
+			continue
+		}
+		if !cfg.matchesReadState(email.IsRead) {
+			continue
+		}
+		email.project(cfg.resolvedFields())
+		email.truncateBody(cfg.maxBodyBytes)
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+// Keypair is an inbox's ML-KEM-768 keypair, as returned by [KeypairFromExport].
+type Keypair = crypto.Keypair
+
+// KeypairFromExport reconstructs the ML-KEM-768 keypair of an encrypted inbox
+// from its export. The public key is not stored in exports; it is taken from
+// the secret key, which embeds it at offset 1152. Unlike [Client.ImportInbox]
+// and [NewDetachedInbox], it only decodes the keys, so tools can inspect an
+// export or check its integrity (for example, that the inbox hash matches the
+// public key) without a client.
+//
+// It returns an error wrapping [ErrInvalidImportData] if data is nil, is for
+// a plain inbox, or holds a malformed or corrupt secret key (see
+// [ExportedInbox.Validate]).
+func KeypairFromExport(data *ExportedInbox) (*Keypair, error) {
+	if data == nil {
+		return nil, fmt.Errorf("%w: export is nil", ErrInvalidImportData)
+	}
+	if !data.Encrypted {
+		return nil, fmt.Errorf("%w: plain inbox %s has no keypair", ErrInvalidImportData, data.EmailAddress)
+	}
+	keypair, err := crypto.KeypairFromSecretKeyB64(data.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportData, err)
+	}
+	if err := crypto.CheckSecretKey(keypair.SecretKey); err != nil {
+		return nil, fmt.Errorf("%w: secretKey is corrupt: %v", ErrInvalidImportData, err)
+	}
+	return keypair, nil
+}
+
 // newInboxFromExport reconstructs an inbox from exported data.
 // For encrypted inboxes, the public key is derived from the secret key per VaultSandbox spec Section 10.2.
 func newInboxFromExport(data *ExportedInbox, c *Client) (*Inbox, error) {
@@ -150,8 +417,12 @@ func newInboxFromExport(data *ExportedInbox, c *Client) (*Inbox, error) {
 			return nil, fmt.Errorf("%w: failed to reconstruct keypair: %v", ErrInvalidImportData, err)
 		}
 
-		inbox.serverSigPk = serverSigPk
+		inbox.serverSigPks = c.pinServerKey(serverSigPk)
 		inbox.keypair = keypair
+		if data.RequireSuite != nil {
+			suite := *data.RequireSuite
+			inbox.requireSuite = &suite
+		}
 	}
 
 	return inbox, nil
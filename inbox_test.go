@@ -1,14 +1,27 @@
 package vaultsandbox
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
 	"github.com/vaultsandbox/client-go/internal/crypto"
+	"github.com/vaultsandbox/client-go/internal/delivery"
 )
 
 func TestExportedInbox_Validate(t *testing.T) {
@@ -198,6 +211,49 @@ func TestExportedInbox_Validate(t *testing.T) {
 	}
 }
 
+func TestExportedInbox_Validate_CorruptSecretKey(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+	}{
+		{"embedded public key", crypto.PublicKeyOffset},
+		{"public key hash", crypto.PublicKeyHashOffset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			secretKey := bytes.Clone(kp.SecretKey)
+			secretKey[tt.offset] ^= 0x01
+			data := &ExportedInbox{
+				Version:      ExportVersion,
+				EmailAddress: "test@example.com",
+				ExpiresAt:    time.Now().Add(time.Hour),
+				InboxHash:    "hash123",
+				ServerSigPk:  crypto.ToBase64URL(make([]byte, crypto.MLDSAPublicKeySize)),
+				SecretKey:    crypto.ToBase64URL(secretKey),
+				ExportedAt:   time.Now(),
+				Encrypted:    true,
+			}
+
+			if err := data.Validate(); !errors.Is(err, ErrInvalidImportData) {
+				t.Errorf("Validate() error = %v, want ErrInvalidImportData", err)
+			}
+			if _, err := NewDetachedInbox(data); !errors.Is(err, ErrInvalidImportData) {
+				t.Errorf("NewDetachedInbox() error = %v, want ErrInvalidImportData", err)
+			}
+			if _, err := KeypairFromExport(data); !errors.Is(err, ErrInvalidImportData) {
+				t.Errorf("KeypairFromExport() error = %v, want ErrInvalidImportData", err)
+			}
+		})
+	}
+}
+
 func TestExportedInbox_Fields(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
@@ -222,6 +278,51 @@ func TestExportedInbox_Fields(t *testing.T) {
 	}
 }
 
+func TestKeypairFromExport(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+	inbox := &Inbox{
+		emailAddress: "test@example.com",
+		expiresAt:    time.Now().Add(time.Hour),
+		inboxHash:    "hash123",
+		serverSigPks: [][]byte{make([]byte, crypto.MLDSAPublicKeySize)},
+		keypair:      kp,
+		encrypted:    true,
+	}
+
+	derived, err := KeypairFromExport(inbox.Export())
+	if err != nil {
+		t.Fatalf("KeypairFromExport() error = %v", err)
+	}
+	if !bytes.Equal(derived.PublicKey, kp.PublicKey) {
+		t.Error("derived public key does not match the generated keypair")
+	}
+	if !bytes.Equal(derived.SecretKey, kp.SecretKey) || derived.PublicKeyB64 != kp.PublicKeyB64 {
+		t.Error("derived keypair does not match the generated keypair")
+	}
+
+	tests := []struct {
+		name string
+		data *ExportedInbox
+	}{
+		{"nil export", nil},
+		{"plain inbox", &ExportedInbox{EmailAddress: "plain@example.com"}},
+		{"invalid encoding", &ExportedInbox{Encrypted: true, SecretKey: "!!!not-base64!!!"}},
+		{"wrong size", &ExportedInbox{Encrypted: true, SecretKey: crypto.ToBase64URL([]byte("short"))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := KeypairFromExport(tt.data); !errors.Is(err, ErrInvalidImportData) {
+				t.Errorf("KeypairFromExport() error = %v, want ErrInvalidImportData", err)
+			}
+		})
+	}
+}
+
 func TestInbox_Export(t *testing.T) {
 	t.Parallel()
 	// Generate valid keypair
@@ -238,7 +339,7 @@ func TestInbox_Export(t *testing.T) {
 		emailAddress: "test@example.com",
 		expiresAt:    expiresAt,
 		inboxHash:    "hash123abc",
-		serverSigPk:  serverSigPk,
+		serverSigPks: [][]byte{serverSigPk},
 		keypair:      kp,
 		encrypted:    true, // Set as encrypted inbox to export keys
 	}
@@ -335,7 +436,7 @@ func TestInbox_Export_Roundtrip(t *testing.T) {
 		emailAddress: "test@example.com",
 		expiresAt:    expiresAt,
 		inboxHash:    "hash123",
-		serverSigPk:  serverSigPk,
+		serverSigPks: [][]byte{serverSigPk},
 		keypair:      kp,
 	}
 
@@ -566,6 +667,216 @@ func TestWaitConfig_MatchesEmail(t *testing.T) {
 	}
 }
 
+func TestInbox_ExtendTTL(t *testing.T) {
+	t.Parallel()
+	newExpiry := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		status     int
+		wantErr    error
+		wantCalled bool
+	}{
+		{name: "success", ttl: 2 * time.Hour, status: http.StatusOK, wantCalled: true},
+		{name: "inbox gone", ttl: 2 * time.Hour, status: http.StatusNotFound, wantErr: ErrInboxNotFound, wantCalled: true},
+		{name: "below minimum", ttl: 30 * time.Second},
+		{name: "above server maximum", ttl: 48 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var called atomic.Bool
+			var gotTTL int
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				called.Store(true)
+				if r.Method != http.MethodPost || r.URL.Path != "/api/inboxes/test@example.com/extend" {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				var body struct {
+					TTL int `json:"ttl"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				gotTTL = body.TTL
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					json.NewEncoder(w).Encode(map[string]interface{}{"expiresAt": newExpiry})
+				}
+			})
+			inbox.client.serverInfo = &api.ServerInfo{MaxTTL: 86400}
+			oldExpiry := time.Now().Add(time.Minute)
+			inbox.expiresAt = oldExpiry
+
+			err := inbox.ExtendTTL(context.Background(), tt.ttl)
+			if called.Load() != tt.wantCalled {
+				t.Errorf("server called = %v, want %v", called.Load(), tt.wantCalled)
+			}
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ExtendTTL() error = %v, want %v", err, tt.wantErr)
+				}
+			case !tt.wantCalled:
+				if err == nil {
+					t.Error("ExtendTTL() expected validation error")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("ExtendTTL() error = %v", err)
+				}
+				if gotTTL != 7200 {
+					t.Errorf("requested ttl = %d, want 7200", gotTTL)
+				}
+				if !inbox.ExpiresAt().Equal(newExpiry) {
+					t.Errorf("ExpiresAt() = %v, want %v", inbox.ExpiresAt(), newExpiry)
+				}
+				return
+			}
+			if !inbox.ExpiresAt().Equal(oldExpiry) {
+				t.Errorf("ExpiresAt() changed on failure: %v", inbox.ExpiresAt())
+			}
+		})
+	}
+}
+
+func TestInbox_ExtendTTL_ConcurrentReads(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"expiresAt": time.Now().Add(2 * time.Hour)})
+	})
+	inbox.client.serverInfo = &api.ServerInfo{MaxTTL: 86400}
+
+	// Run with -race: ExtendTTL writes the expiry that readers see.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				inbox.IsExpired()
+				inbox.ExpiresAt()
+				inbox.Export()
+			}
+		}()
+	}
+	for range 5 {
+		if err := inbox.ExtendTTL(context.Background(), 2*time.Hour); err != nil {
+			t.Fatalf("ExtendTTL() error = %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestWaitConfig_MatchesTo(t *testing.T) {
+	t.Parallel()
+	multi := &Email{To: []string{"inbox@example.com", "inbox+Signup@example.com"}}
+
+	tests := []struct {
+		name     string
+		email    *Email
+		opt      WaitOption
+		expected bool
+	}{
+		{name: "exact first recipient", email: multi, opt: WithTo("inbox@example.com"), expected: true},
+		{name: "exact second recipient", email: multi, opt: WithTo("inbox+signup@example.com"), expected: true},
+		{name: "exact is case-insensitive", email: multi, opt: WithTo("INBOX+SIGNUP@EXAMPLE.COM"), expected: true},
+		{name: "exact no match", email: multi, opt: WithTo("inbox+reset@example.com"), expected: false},
+		{name: "exact no recipients", email: &Email{}, opt: WithTo("inbox@example.com"), expected: false},
+		{name: "exact ignores envelope", email: &Email{EnvelopeTo: "inbox@example.com"}, opt: WithTo("inbox@example.com"), expected: false},
+		{name: "regex any recipient", email: multi, opt: WithToRegex(regexp.MustCompile(`\+Signup@`)), expected: true},
+		{name: "regex no match", email: multi, opt: WithToRegex(regexp.MustCompile(`\+reset@`)), expected: false},
+		{name: "regex no recipients", email: &Email{}, opt: WithToRegex(regexp.MustCompile(`.*`)), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &waitConfig{}
+			tt.opt(cfg)
+			if got := cfg.Matches(tt.email); got != tt.expected {
+				t.Errorf("waitConfig.Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWaitConfig_MatchesBody(t *testing.T) {
+	t.Parallel()
+	textOnly := &Email{Text: "Your verification code is 123456"}
+	htmlOnly := &Email{HTML: "<p>Your verification code is <b>123456</b></p>"}
+	neither := &Email{Text: "Welcome aboard", HTML: "<p>Welcome aboard</p>"}
+	codeRegex := regexp.MustCompile(`code is (<b>)?\d{6}`)
+
+	tests := []struct {
+		name     string
+		email    *Email
+		opt      WaitOption
+		expected bool
+	}{
+		{name: "contains text only", email: textOnly, opt: WithBodyContains("verification code"), expected: true},
+		{name: "contains html only", email: htmlOnly, opt: WithBodyContains("verification code"), expected: true},
+		{name: "contains neither", email: neither, opt: WithBodyContains("verification code"), expected: false},
+		{name: "contains is case-sensitive", email: textOnly, opt: WithBodyContains("Verification Code"), expected: false},
+		{name: "regex text only", email: textOnly, opt: WithBodyRegex(codeRegex), expected: true},
+		{name: "regex html only", email: htmlOnly, opt: WithBodyRegex(codeRegex), expected: true},
+		{name: "regex neither", email: neither, opt: WithBodyRegex(codeRegex), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &waitConfig{}
+			tt.opt(cfg)
+			if got := cfg.Matches(tt.email); got != tt.expected {
+				t.Errorf("waitConfig.Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWaitConfig_MatchesMinReceivedAt(t *testing.T) {
+	t.Parallel()
+	threshold := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		email    *Email
+		opt      WaitOption
+		expected bool
+	}{
+		{name: "before threshold", email: &Email{ReceivedAt: threshold.Add(-time.Second)}, opt: WithMinReceivedAt(threshold), expected: false},
+		{name: "at threshold", email: &Email{ReceivedAt: threshold}, opt: WithMinReceivedAt(threshold), expected: true},
+		{name: "after threshold", email: &Email{ReceivedAt: threshold.Add(time.Second)}, opt: WithMinReceivedAt(threshold), expected: true},
+		{name: "zero threshold", email: &Email{}, opt: WithMinReceivedAt(time.Time{}), expected: true},
+		{name: "since excludes older", email: &Email{ReceivedAt: now.Add(-time.Hour)}, opt: WithSince(time.Minute), expected: false},
+		{name: "since includes recent", email: &Email{ReceivedAt: now.Add(-10 * time.Second)}, opt: WithSince(time.Minute), expected: true},
+		{name: "since zero excludes pre-existing", email: &Email{ReceivedAt: now.Add(-time.Second)}, opt: WithSince(0), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &waitConfig{}
+			tt.opt(cfg)
+			if got := cfg.Matches(tt.email); got != tt.expected {
+				t.Errorf("waitConfig.Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithSince_UsesWaitClock(t *testing.T) {
+	t.Parallel()
+	fake := clock.NewFake(testEpoch)
+	cfg := &waitConfig{clock: fake}
+	WithSince(time.Minute)(cfg)
+	if want := testEpoch.Add(-time.Minute); !cfg.minReceivedAt.Equal(want) {
+		t.Errorf("minReceivedAt = %v, want %v", cfg.minReceivedAt, want)
+	}
+}
+
 func TestParseMetadata_Valid(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -710,6 +1021,28 @@ func TestParseParsedContent_NonStringHeaders(t *testing.T) {
 	}
 }
 
+func TestMultiValueHeaders(t *testing.T) {
+	t.Parallel()
+	raw := map[string]interface{}{
+		"Subject":  "hello",
+		"Received": []interface{}{"from a", "from b"},
+		"X-Mixed":  []interface{}{"kept", 42.0},
+		"X-Nums":   []interface{}{1.0, 2.0},
+	}
+
+	got := multiValueHeaders(raw)
+	want := map[string][]string{
+		"Received": {"from a", "from b"},
+		"X-Mixed":  {"kept"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("multiValueHeaders() = %v, want %v", got, want)
+	}
+	if got := multiValueHeaders(map[string]interface{}{"Subject": "hello"}); got != nil {
+		t.Errorf("multiValueHeaders() without arrays = %v, want nil", got)
+	}
+}
+
 func TestParseParsedContent_EmptyHeaders(t *testing.T) {
 	t.Parallel()
 	jsonData := `{"text": "body", "html": "", "headers": {}}`
@@ -748,7 +1081,7 @@ func TestBuildDecryptedEmail_Complete(t *testing.T) {
 		ReceivedAt: "2024-01-15T10:30:00Z",
 	}
 
-	result := buildDecryptedEmail(rawEmail, metadata)
+	result := buildDecryptedEmail(rawEmail, metadata, SourceMetadata)
 
 	if result.ID != "email-123" {
 		t.Errorf("ID = %q, want %q", result.ID, "email-123")
@@ -759,6 +1092,9 @@ func TestBuildDecryptedEmail_Complete(t *testing.T) {
 	if len(result.To) != 1 || result.To[0] != "recipient@example.com" {
 		t.Errorf("To = %v, want [recipient@example.com]", result.To)
 	}
+	if result.EnvelopeTo != "recipient@example.com" {
+		t.Errorf("EnvelopeTo = %q, want fallback to To", result.EnvelopeTo)
+	}
 	if result.Subject != "Test Subject" {
 		t.Errorf("Subject = %q, want %q", result.Subject, "Test Subject")
 	}
@@ -772,6 +1108,21 @@ func TestBuildDecryptedEmail_Complete(t *testing.T) {
 	}
 }
 
+func TestBuildDecryptedEmail_EnvelopeTo(t *testing.T) {
+	t.Parallel()
+	rawEmail := &api.RawEmail{ID: "email-123"}
+	metadata := &crypto.DecryptedMetadata{
+		To:         "list@example.com",
+		EnvelopeTo: "order-42@example.com",
+	}
+
+	result := buildDecryptedEmail(rawEmail, metadata, SourceMetadata)
+
+	if result.EnvelopeTo != "order-42@example.com" {
+		t.Errorf("EnvelopeTo = %q, want %q", result.EnvelopeTo, "order-42@example.com")
+	}
+}
+
 func TestBuildDecryptedEmail_ReceivedAtFallback(t *testing.T) {
 	t.Parallel()
 	now := time.Now().Truncate(time.Second)
@@ -807,7 +1158,7 @@ func TestBuildDecryptedEmail_ReceivedAtFallback(t *testing.T) {
 				ReceivedAt: tt.metadataReceivedAt,
 			}
 
-			result := buildDecryptedEmail(rawEmail, metadata)
+			result := buildDecryptedEmail(rawEmail, metadata, SourceMetadata)
 
 			if !result.ReceivedAt.Equal(tt.expectedTime) {
 				t.Errorf("ReceivedAt = %v, want %v", result.ReceivedAt, tt.expectedTime)
@@ -816,6 +1167,35 @@ func TestBuildDecryptedEmail_ReceivedAtFallback(t *testing.T) {
 	}
 }
 
+func TestResolveReceivedAt(t *testing.T) {
+	t.Parallel()
+	apiTime := time.Date(2024, 1, 15, 10, 30, 5, 0, time.UTC)
+	metaTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	meta := metaTime.Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		metadata string
+		apiTime  time.Time
+		source   ReceivedAtSource
+		want     time.Time
+	}{
+		{name: "metadata preferred", metadata: meta, apiTime: apiTime, source: SourceMetadata, want: metaTime},
+		{name: "metadata invalid falls back to API", metadata: "bad", apiTime: apiTime, source: SourceMetadata, want: apiTime},
+		{name: "API preferred", metadata: meta, apiTime: apiTime, source: SourceAPI, want: apiTime},
+		{name: "API missing falls back to metadata", metadata: meta, source: SourceAPI, want: metaTime},
+		{name: "both missing", source: SourceAPI, want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := resolveReceivedAt(tt.metadata, tt.apiTime, tt.source); !got.Equal(tt.want) {
+				t.Errorf("resolveReceivedAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestInbox_Accessors(t *testing.T) {
 	t.Parallel()
 	now := time.Now()
@@ -921,6 +1301,837 @@ func TestNewInboxFromResult(t *testing.T) {
 	}
 }
 
+// newPlainRawEmail builds a plain (unencrypted) API email with the given
+// metadata and optional parsed content, as returned by the list endpoint.
+func newPlainRawEmail(t *testing.T, id string, metadata, parsed map[string]interface{}) *api.RawEmail {
+	t.Helper()
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatalf("marshal metadata: %v", err)
+	}
+	raw := &api.RawEmail{
+		ID:         id,
+		ReceivedAt: time.Now().UTC().Truncate(time.Second),
+		Metadata:   crypto.ToBase64URL(metadataJSON),
+	}
+	if parsed != nil {
+		parsedJSON, err := json.Marshal(parsed)
+		if err != nil {
+			t.Fatalf("marshal parsed: %v", err)
+		}
+		raw.Parsed = crypto.ToBase64URL(parsedJSON)
+	}
+	return raw
+}
+
+// newTestInboxWithServer returns a plain inbox backed by an httptest server
+// running the given handler.
+func newTestInboxWithServer(t *testing.T, handler http.HandlerFunc) *Inbox {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-key", api.WithBaseURL(server.URL), api.WithRetries(0))
+	if err != nil {
+		t.Fatalf("api.New() error = %v", err)
+	}
+	return &Inbox{
+		emailAddress: "test@example.com",
+		inboxHash:    "hash123",
+		client:       &Client{apiClient: apiClient, subs: newSubscriptionManager()},
+	}
+}
+
+func TestInbox_ExpiredInboxErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		status       int
+		message      string
+		wantNotFound bool
+	}{
+		{"410 Gone", http.StatusGone, "Inbox is gone", false},
+		{"404 reporting expiry", http.StatusNotFound, "Inbox has expired", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]string{"error": tt.message})
+			})
+
+			_, err := inbox.GetEmails(context.Background())
+			if !errors.Is(err, ErrInboxExpired) {
+				t.Errorf("GetEmails() error = %v, want ErrInboxExpired", err)
+			}
+			if got := errors.Is(err, ErrInboxNotFound); got != tt.wantNotFound {
+				t.Errorf("errors.Is(err, ErrInboxNotFound) = %v, want %v", got, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestInbox_GetEmails_ServerFilter_Plain(t *testing.T) {
+	t.Parallel()
+	var gotSubject, gotFrom string
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotSubject = r.URL.Query().Get("subject")
+		gotFrom = r.URL.Query().Get("from")
+		w.Header().Set("Content-Type", "application/json")
+		// Simulate a gateway that ignores the filter and returns everything
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1", map[string]interface{}{"from": "shop@example.com", "subject": "Order shipped"}, nil),
+			newPlainRawEmail(t, "e2", map[string]interface{}{"from": "news@example.com", "subject": "Weekly digest"}, nil),
+		})
+	})
+
+	emails, err := inbox.GetEmails(context.Background(), WithServerFilter(ServerFilter{Subject: "order", From: "shop@"}))
+	if err != nil {
+		t.Fatalf("GetEmails() error = %v", err)
+	}
+
+	if gotSubject != "order" || gotFrom != "shop@" {
+		t.Errorf("query subject=%q from=%q, want subject=%q from=%q", gotSubject, gotFrom, "order", "shop@")
+	}
+	if len(emails) != 1 || emails[0].ID != "e1" {
+		t.Fatalf("GetEmails() returned %d emails, want only e1", len(emails))
+	}
+}
+
+func TestInbox_GetEmails_ServerFilter_EncryptedFiltersLocally(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{"from": "a@example.com", "subject": "Welcome"})
+	payload, serverPk := createTestEncryptedPayload(t, metadata, kp)
+
+	var rawQuery string
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{{ID: "e1", EncryptedMetadata: payload}})
+	})
+	inbox.encrypted = true
+	inbox.keypair = kp
+	inbox.serverSigPks = [][]byte{serverPk}
+
+	emails, err := inbox.GetEmails(context.Background(), WithServerFilter(ServerFilter{Subject: "invoice"}))
+	if err != nil {
+		t.Fatalf("GetEmails() error = %v", err)
+	}
+	if rawQuery != "includeContent=true" {
+		t.Errorf("query = %q, want only includeContent for encrypted inbox", rawQuery)
+	}
+	if len(emails) != 0 {
+		t.Errorf("GetEmails() returned %d emails, want 0", len(emails))
+	}
+
+	emails, err = inbox.GetEmails(context.Background(), WithServerFilter(ServerFilter{Subject: "WELCOME"}))
+	if err != nil {
+		t.Fatalf("GetEmails() error = %v", err)
+	}
+	if len(emails) != 1 {
+		t.Errorf("GetEmails() returned %d emails, want 1", len(emails))
+	}
+}
+
+func TestInbox_GetEmails_ReadState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		serverSide bool
+		fetch      func(*Inbox) ([]*Email, error)
+		wantQuery  string
+		wantID     string
+	}{
+		{"unread server-side", true, func(i *Inbox) ([]*Email, error) { return i.GetUnreadEmails(context.Background()) }, "false", "e1"},
+		{"unread client-side", false, func(i *Inbox) ([]*Email, error) { return i.GetUnreadEmails(context.Background()) }, "false", "e1"},
+		{"read server-side", true, func(i *Inbox) ([]*Email, error) { return i.GetEmails(context.Background(), WithReadOnly()) }, "true", "e2"},
+		{"read client-side", false, func(i *Inbox) ([]*Email, error) { return i.GetEmails(context.Background(), WithReadOnly()) }, "true", "e2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var gotQuery string
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query().Get("read")
+				unread := newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "New"}, nil)
+				read := newPlainRawEmail(t, "e2", map[string]interface{}{"subject": "Seen"}, nil)
+				read.IsRead = true
+				emails := []*api.RawEmail{unread, read}
+				if tt.serverSide {
+					emails = slices.DeleteFunc(emails, func(e *api.RawEmail) bool {
+						return strconv.FormatBool(e.IsRead) != gotQuery
+					})
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(emails)
+			})
+
+			emails, err := tt.fetch(inbox)
+			if err != nil {
+				t.Fatalf("fetch error = %v", err)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("read query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if len(emails) != 1 || emails[0].ID != tt.wantID {
+				t.Fatalf("got %d emails, want only %s", len(emails), tt.wantID)
+			}
+		})
+	}
+}
+
+func TestInbox_MarkAllAsRead(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		status    map[string]int // mark-read status per email ID
+		wantCount int
+		wantErrs  []string
+	}{
+		{
+			name:      "all marked",
+			wantCount: 3,
+		},
+		{
+			name:      "concurrently deleted email is skipped",
+			status:    map[string]int{"e3": http.StatusNotFound},
+			wantCount: 2,
+		},
+		{
+			name:      "failures are aggregated",
+			status:    map[string]int{"e1": http.StatusInternalServerError, "e3": http.StatusNotFound, "e4": http.StatusForbidden},
+			wantCount: 0,
+			wantErrs:  []string{"e1", "e4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var marked sync.Map
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPatch {
+					id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/inboxes/test@example.com/emails/"), "/read")
+					if status, ok := tt.status[id]; ok {
+						w.WriteHeader(status)
+						return
+					}
+					marked.Store(id, true)
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				emails := []*api.RawEmail{{ID: "e1"}, {ID: "e2", IsRead: true}, {ID: "e3"}, {ID: "e4"}}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(emails)
+			})
+
+			count, err := inbox.MarkAllAsRead(context.Background())
+			if count != tt.wantCount {
+				t.Errorf("count = %d, want %d", count, tt.wantCount)
+			}
+			if _, ok := marked.Load("e2"); ok {
+				t.Error("already-read email e2 was marked again")
+			}
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("MarkAllAsRead() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("MarkAllAsRead() error = nil, want aggregated error")
+			}
+			for _, id := range tt.wantErrs {
+				if !strings.Contains(err.Error(), "mark email "+id) {
+					t.Errorf("error %q does not mention %s", err, id)
+				}
+			}
+			if errors.Is(err, ErrEmailNotFound) {
+				t.Errorf("error %q includes the not-found email", err)
+			}
+		})
+	}
+}
+
+func TestInbox_MarkAllAsRead_StopsOnCancel(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var marks atomic.Int32
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			marks.Add(1)
+			cancel() // The caller gives up after the first email.
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{{ID: "e1"}, {ID: "e2"}, {ID: "e3"}})
+	})
+
+	count, err := inbox.MarkAllAsRead(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("MarkAllAsRead() error = %v, want context.Canceled", err)
+	}
+	if got := marks.Load(); got != 1 || count > 1 {
+		t.Errorf("mark requests = %d, count = %d; want 1 request and no more emails", got, count)
+	}
+}
+
+func TestInbox_DeleteAllEmails(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	stored := map[string]bool{"e1": true, "e2": true, "e3": true}
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodDelete {
+			id := strings.TrimPrefix(r.URL.Path, "/api/inboxes/test@example.com/emails/")
+			if !stored[id] {
+				http.NotFound(w, r)
+				return
+			}
+			delete(stored, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/sync") {
+			state := &syncState{seenEmails: make(map[string]struct{})}
+			for id := range stored {
+				state.seenEmails[id] = struct{}{}
+			}
+			json.NewEncoder(w).Encode(api.SyncStatus{EmailsHash: state.computeEmailsHash(), EmailCount: len(stored)})
+			return
+		}
+		emails := []*api.RawEmail{}
+		for id := range stored {
+			emails = append(emails, &api.RawEmail{ID: id})
+		}
+		// e4 was deleted by someone else after the listing.
+		emails = append(emails, &api.RawEmail{ID: "e4"})
+		json.NewEncoder(w).Encode(emails)
+	})
+	seen := map[string]struct{}{"e1": {}, "e2": {}, "e3": {}}
+	inbox.client.syncStates = map[string]*syncState{inbox.inboxHash: {seenEmails: seen}}
+
+	count, err := inbox.DeleteAllEmails(context.Background())
+	if err != nil {
+		t.Fatalf("DeleteAllEmails() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("count = %d, want 3", count)
+	}
+	mu.Lock()
+	remaining := len(stored)
+	mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("server still has %d emails", remaining)
+	}
+
+	status, err := inbox.GetSyncStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetSyncStatus() error = %v", err)
+	}
+	if local := inbox.client.syncStates[inbox.inboxHash].computeEmailsHash(); local != status.EmailsHash {
+		t.Errorf("local sync hash %q does not match server hash %q after delete", local, status.EmailsHash)
+	}
+}
+
 // Note: Full inbox tests require a real API connection
 // These tests verify the data structures and validation
 // Integration tests are in the integration/ directory
+
+// countingWriter counts the bytes written to it and discards them.
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func TestInbox_DownloadAttachment(t *testing.T) {
+	t.Parallel()
+	content := bytes.Repeat([]byte("0123456789abcdef"), 256<<10/16)
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newPlainRawEmail(t, "e1",
+			map[string]interface{}{"subject": "Backup"},
+			map[string]interface{}{
+				"attachments": []map[string]interface{}{{
+					"filename":    "backup.tar",
+					"contentType": "application/x-tar",
+					"size":        len(content),
+					"content":     base64.StdEncoding.EncodeToString(content),
+				}},
+			}))
+	})
+
+	var cw countingWriter
+	if err := inbox.DownloadAttachment(context.Background(), "e1", "backup.tar", &cw); err != nil {
+		t.Fatalf("DownloadAttachment() error = %v", err)
+	}
+	if cw.n != int64(len(content)) {
+		t.Errorf("wrote %d bytes, want %d", cw.n, len(content))
+	}
+
+	var buf bytes.Buffer
+	if err := inbox.DownloadAttachment(context.Background(), "e1", "backup.tar", &buf); err != nil {
+		t.Fatalf("DownloadAttachment() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Error("downloaded content does not match the attachment")
+	}
+
+	err := inbox.DownloadAttachment(context.Background(), "e1", "missing.tar", &cw)
+	if !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("DownloadAttachment() error = %v, want ErrAttachmentNotFound", err)
+	}
+}
+
+func TestInbox_GetAttachmentPreview(t *testing.T) {
+	t.Parallel()
+	content := []byte("%PDF-1.7 rest of a large document")
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newPlainRawEmail(t, "e1",
+			map[string]interface{}{"from": "a@example.com", "subject": "Report"},
+			map[string]interface{}{
+				"text": "see attached",
+				"attachments": []map[string]interface{}{{
+					"filename":    "report.bin",
+					"contentType": "application/octet-stream",
+					"size":        len(content),
+					"content":     base64.StdEncoding.EncodeToString(content),
+				}},
+			}))
+	})
+
+	tests := []struct {
+		name     string
+		filename string
+		n        int
+		want     string
+		wantErr  error
+	}{
+		{name: "prefix", filename: "report.bin", n: 8, want: "%PDF-1.7"},
+		{name: "n larger than content", filename: "report.bin", n: 1 << 20, want: string(content)},
+		{name: "non-positive n returns all", filename: "report.bin", n: 0, want: string(content)},
+		{name: "missing attachment", filename: "other.bin", n: 8, wantErr: ErrAttachmentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := inbox.GetAttachmentPreview(context.Background(), "e1", tt.filename, tt.n)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetAttachmentPreview() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAttachmentPreview() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("GetAttachmentPreview() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInbox_GetEmailByMessageID(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1",
+				map[string]interface{}{"from": "a@example.com", "subject": "No ID"},
+				map[string]interface{}{"text": "one"}),
+			newPlainRawEmail(t, "e2",
+				map[string]interface{}{"from": "a@example.com", "subject": "Welcome"},
+				map[string]interface{}{"text": "two", "headers": map[string]interface{}{"message-id": "<Abc.123@Mail.Example.com>"}}),
+		})
+	})
+
+	tests := []struct {
+		name      string
+		messageID string
+		wantID    string
+	}{
+		{name: "exact", messageID: "<Abc.123@Mail.Example.com>", wantID: "e2"},
+		{name: "without brackets", messageID: "Abc.123@Mail.Example.com", wantID: "e2"},
+		{name: "different case", messageID: "<abc.123@mail.example.com>", wantID: "e2"},
+		{name: "missing", messageID: "<other@mail.example.com>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			email, err := inbox.GetEmailByMessageID(context.Background(), tt.messageID)
+			if tt.wantID == "" {
+				if !errors.Is(err, ErrEmailNotFound) {
+					t.Fatalf("GetEmailByMessageID() error = %v, want ErrEmailNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetEmailByMessageID() error = %v", err)
+			}
+			if email.ID != tt.wantID {
+				t.Errorf("GetEmailByMessageID() ID = %q, want %q", email.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestExtremeEmail(t *testing.T) {
+	t.Parallel()
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	newYork := time.FixedZone("EDT", -4*60*60)
+	emails := []*Email{
+		{ID: "middle", ReceivedAt: base},
+		// Later instant, but an earlier wall-clock reading than base.
+		{ID: "newest", ReceivedAt: base.Add(time.Hour).In(newYork)},
+		{ID: "oldest", ReceivedAt: base.Add(-time.Hour)},
+		{ID: "oldest-tie", ReceivedAt: base.Add(-time.Hour).In(newYork)},
+	}
+
+	oldest, err := extremeEmail(emails, false)
+	if err != nil || oldest.ID != "oldest" {
+		t.Errorf("extremeEmail(oldest) = %v, %v; want oldest", oldest, err)
+	}
+	newest, err := extremeEmail(emails, true)
+	if err != nil || newest.ID != "newest" {
+		t.Errorf("extremeEmail(newest) = %v, %v; want newest", newest, err)
+	}
+	for _, pick := range []bool{false, true} {
+		if _, err := extremeEmail(nil, pick); !errors.Is(err, ErrEmailNotFound) {
+			t.Errorf("extremeEmail(nil, %v) error = %v, want ErrEmailNotFound", pick, err)
+		}
+	}
+}
+
+func TestInbox_GetEmail_MaxBodyBytes(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newPlainRawEmail(t, "e1",
+			map[string]interface{}{"subject": "Big"},
+			map[string]interface{}{"text": "0123456789", "html": "<p>short</p>"}))
+	})
+
+	email, err := inbox.GetEmail(context.Background(), "e1", WithMaxBodyBytes(4))
+	if err != nil {
+		t.Fatalf("GetEmail() error = %v", err)
+	}
+	if email.Text != "0123" || email.HTML != "<p>s" || !email.BodyTruncated {
+		t.Errorf("got Text=%q HTML=%q BodyTruncated=%v, want \"0123\" \"<p>s\" true", email.Text, email.HTML, email.BodyTruncated)
+	}
+
+	full, err := inbox.GetEmail(context.Background(), "e1")
+	if err != nil {
+		t.Fatalf("GetEmail() error = %v", err)
+	}
+	if full.Text != "0123456789" || full.BodyTruncated {
+		t.Errorf("got Text=%q BodyTruncated=%v, want full body", full.Text, full.BodyTruncated)
+	}
+}
+
+func TestConvertDecryptedEmail_ListUnsubscribe(t *testing.T) {
+	t.Parallel()
+	inbox := &Inbox{}
+
+	email := inbox.convertDecryptedEmail(&crypto.DecryptedEmail{
+		ID: "test-id",
+		Headers: map[string]string{
+			"list-unsubscribe":      "<mailto:u@example.com>, <https://example.com/u>",
+			"list-unsubscribe-post": "List-Unsubscribe=One-Click",
+		},
+	})
+	want := []string{"mailto:u@example.com", "https://example.com/u"}
+	if !reflect.DeepEqual(email.ListUnsubscribe, want) {
+		t.Errorf("ListUnsubscribe = %q, want %q", email.ListUnsubscribe, want)
+	}
+	if email.ListUnsubscribePost != "List-Unsubscribe=One-Click" {
+		t.Errorf("ListUnsubscribePost = %q", email.ListUnsubscribePost)
+	}
+
+	plain := inbox.convertDecryptedEmail(&crypto.DecryptedEmail{ID: "no-headers"})
+	if plain.ListUnsubscribe != nil || plain.ListUnsubscribePost != "" {
+		t.Errorf("got ListUnsubscribe=%q Post=%q, want empty", plain.ListUnsubscribe, plain.ListUnsubscribePost)
+	}
+}
+
+func TestInbox_EqualAndKey(t *testing.T) {
+	t.Parallel()
+	a := &Inbox{emailAddress: "a@example.com", inboxHash: "hash-a"}
+	aImported := &Inbox{emailAddress: "a@example.com", inboxHash: "hash-a"}
+	b := &Inbox{emailAddress: "b@example.com", inboxHash: "hash-b"}
+	var nilInbox *Inbox
+
+	tests := []struct {
+		name string
+		x, y *Inbox
+		want bool
+	}{
+		{name: "same pointer", x: a, y: a, want: true},
+		{name: "same hash", x: a, y: aImported, want: true},
+		{name: "different hash", x: a, y: b, want: false},
+		{name: "nil other", x: a, y: nil, want: false},
+		{name: "nil receiver", x: nilInbox, y: a, want: false},
+		{name: "both nil", x: nilInbox, y: nil, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.x.Equal(tt.y); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	groups := map[string]int{}
+	for _, in := range []*Inbox{a, aImported, b} {
+		groups[in.Key()]++
+	}
+	if groups["hash-a"] != 2 || groups["hash-b"] != 1 {
+		t.Errorf("groups by Key() = %v, want hash-a:2 hash-b:1", groups)
+	}
+}
+
+func TestInbox_WithFields(t *testing.T) {
+	t.Parallel()
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{"from": "a@example.com", "subject": "Welcome"})
+	metaPayload, serverPk := createTestEncryptedPayload(t, metadata, kp)
+	// Parsed content that fails verification, proving it is never decrypted.
+	badParsed := *metaPayload
+	badParsed.Sig = crypto.ToBase64URL(make([]byte, 16))
+
+	var rawQuery string
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		raw := &api.RawEmail{ID: "e1", EncryptedMetadata: metaPayload, EncryptedParsed: &badParsed}
+		if strings.HasSuffix(r.URL.Path, "/emails") {
+			rawQuery = r.URL.RawQuery
+			if rawQuery == "" {
+				raw.EncryptedParsed = nil // Metadata-only listing.
+			}
+			json.NewEncoder(w).Encode([]*api.RawEmail{raw})
+			return
+		}
+		json.NewEncoder(w).Encode(raw)
+	})
+	inbox.encrypted = true
+	inbox.keypair = kp
+	inbox.serverSigPks = [][]byte{serverPk}
+
+	email, err := inbox.GetEmail(context.Background(), "e1", WithFields(FieldSubject))
+	if err != nil {
+		t.Fatalf("GetEmail() error = %v", err)
+	}
+	if email.Subject != "Welcome" || email.From != "" || email.ID != "e1" {
+		t.Errorf("got Subject=%q From=%q ID=%q, want only Subject and ID", email.Subject, email.From, email.ID)
+	}
+
+	emails, err := inbox.GetEmails(context.Background(), WithFields(FieldSubject|FieldFrom))
+	if err != nil {
+		t.Fatalf("GetEmails() error = %v", err)
+	}
+	if rawQuery != "" {
+		t.Errorf("query = %q, want content not requested", rawQuery)
+	}
+	if len(emails) != 1 || emails[0].From != "a@example.com" {
+		t.Errorf("GetEmails() = %+v, want one email with From set", emails)
+	}
+
+	if _, err := inbox.GetEmail(context.Background(), "e1", WithFields(FieldBody)); err == nil {
+		t.Error("GetEmail(FieldBody) error = nil, want parsed content to be verified")
+	}
+}
+
+func TestNewDetachedInbox(t *testing.T) {
+	t.Parallel()
+	data := &ExportedInbox{
+		Version:      ExportVersion,
+		EmailAddress: "detached@example.com",
+		InboxHash:    "hash-detached",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	inbox, err := NewDetachedInbox(data)
+	if err != nil {
+		t.Fatalf("NewDetachedInbox() error = %v", err)
+	}
+	if !inbox.Detached() {
+		t.Error("Detached() = false, want true")
+	}
+	if inbox.EmailAddress() != data.EmailAddress || inbox.IsExpired() {
+		t.Errorf("offline accessors: EmailAddress=%q IsExpired=%v", inbox.EmailAddress(), inbox.IsExpired())
+	}
+	if exported := inbox.Export(); exported.InboxHash != data.InboxHash {
+		t.Errorf("Export().InboxHash = %q, want %q", exported.InboxHash, data.InboxHash)
+	}
+
+	ctx := context.Background()
+	networkCalls := map[string]func() error{
+		"GetEmails":       func() error { _, err := inbox.GetEmails(ctx); return err },
+		"GetEmail":        func() error { _, err := inbox.GetEmail(ctx, "e1"); return err },
+		"GetSyncStatus":   func() error { _, err := inbox.GetSyncStatus(ctx); return err },
+		"Delete":          func() error { return inbox.Delete(ctx) },
+		"MarkEmailAsRead": func() error { return inbox.MarkEmailAsRead(ctx, "e1") },
+		"WaitForEmail":    func() error { _, err := inbox.WaitForEmail(ctx, WithWaitTimeout(time.Second)); return err },
+		"WaitUntilEmpty":  func() error { _, err := inbox.WaitUntilEmpty(ctx, time.Second); return err },
+		"GetChaosConfig":  func() error { _, err := inbox.GetChaosConfig(ctx); return err },
+		"ListWebhooks":    func() error { _, err := inbox.ListWebhooks(ctx); return err },
+	}
+	for name, call := range networkCalls {
+		if err := call(); !errors.Is(err, ErrDetachedInbox) {
+			t.Errorf("%s() error = %v, want ErrDetachedInbox", name, err)
+		}
+	}
+	if ch := inbox.Watch(ctx); ch == nil {
+		t.Error("Watch() returned nil channel")
+	}
+
+	if _, err := NewDetachedInbox(nil); err == nil {
+		t.Error("NewDetachedInbox(nil) error = nil, want error")
+	}
+}
+
+func TestInbox_Attach(t *testing.T) {
+	t.Parallel()
+	newClient := func() *Client {
+		return &Client{
+			inboxes:       make(map[string]*Inbox),
+			inboxesByHash: make(map[string]*Inbox),
+			syncStates:    make(map[string]*syncState),
+			strategy:      delivery.NewPollingStrategy(delivery.Config{}),
+			subs:          newSubscriptionManager(),
+		}
+	}
+	detached := func() *Inbox {
+		return &Inbox{emailAddress: "d@example.com", inboxHash: "hash-d", expiresAt: time.Now().Add(time.Hour)}
+	}
+
+	c := newClient()
+	inbox := detached()
+	if err := inbox.Attach(c); err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	if inbox.Detached() {
+		t.Error("Detached() = true after Attach")
+	}
+	if got, ok := c.GetInbox("d@example.com"); !ok || got != inbox {
+		t.Error("attached inbox is not registered with the client")
+	}
+	if err := inbox.Attach(newClient()); err == nil {
+		t.Error("Attach() on attached inbox error = nil, want error")
+	}
+	if err := detached().Attach(c); !errors.Is(err, ErrInboxAlreadyExists) {
+		t.Errorf("Attach() duplicate error = %v, want ErrInboxAlreadyExists", err)
+	}
+	if err := detached().Attach(nil); err == nil {
+		t.Error("Attach(nil) error = nil, want error")
+	}
+
+	closed := newClient()
+	closed.closed = true
+	other := detached()
+	if err := other.Attach(closed); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Attach() closed client error = %v, want ErrClientClosed", err)
+	}
+	if !other.Detached() {
+		t.Error("failed Attach left the inbox attached")
+	}
+}
+
+func TestInbox_Attach_ConcurrentVerify(t *testing.T) {
+	t.Parallel()
+	keypair, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+	serverPk, serverPriv, err := crypto.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	payload, err := crypto.Encrypt([]byte("x"), keypair.PublicKey, serverPriv, []byte("hash-d"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	inbox := &Inbox{
+		emailAddress: "d@example.com",
+		inboxHash:    "hash-d",
+		expiresAt:    time.Now().Add(time.Hour),
+		serverSigPks: [][]byte{serverPk},
+		keypair:      keypair,
+		encrypted:    true,
+	}
+	c := &Client{
+		inboxes:       make(map[string]*Inbox),
+		inboxesByHash: make(map[string]*Inbox),
+		syncStates:    make(map[string]*syncState),
+		strategy:      delivery.NewPollingStrategy(delivery.Config{}),
+		subs:          newSubscriptionManager(),
+	}
+
+	// Run with -race: Attach re-pins the server keys that verification reads.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				if err := inbox.VerifyEmailSignature(payload); err != nil {
+					t.Errorf("VerifyEmailSignature() error = %v", err)
+				}
+				inbox.Export()
+			}
+		}()
+	}
+	if err := inbox.Attach(c); err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	wg.Wait()
+}
+
+func TestInbox_Attach_Concurrent(t *testing.T) {
+	t.Parallel()
+	inbox := &Inbox{emailAddress: "d@example.com", inboxHash: "hash-d", expiresAt: time.Now().Add(time.Hour)}
+
+	// Run with -race: each Attach checks and sets the inbox's client.
+	var wg sync.WaitGroup
+	var attached atomic.Int32
+	for range 8 {
+		c := &Client{
+			inboxes:       make(map[string]*Inbox),
+			inboxesByHash: make(map[string]*Inbox),
+			syncStates:    make(map[string]*syncState),
+			strategy:      delivery.NewPollingStrategy(delivery.Config{}),
+			subs:          newSubscriptionManager(),
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if inbox.Attach(c) == nil {
+				attached.Add(1)
+			}
+			inbox.Detached()
+		}()
+	}
+	wg.Wait()
+	if got := attached.Load(); got != 1 {
+		t.Errorf("successful Attach calls = %d, want 1", got)
+	}
+}
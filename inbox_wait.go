@@ -2,7 +2,16 @@ package vaultsandbox
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/tracing"
 )
 
 // waitForEmails is a helper that handles the common wait pattern:
@@ -10,18 +19,43 @@ import (
 // 2. Check existing emails
 // 3. Watch for new emails until done returns true or context expires
 func (i *Inbox) waitForEmails(ctx context.Context, cfg *waitConfig, process func(*Email) (done bool)) error {
-	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	ctx, cancel := clock.WithTimeout(ctx, i.client.clock(), cfg.timeout)
 	defer cancel()
 
 	emails := i.Watch(ctx)
 
+	// accept reports whether e completed the wait, first waiting for its
+	// body if WithWaitForParsed is set. Only emails whose metadata matches
+	// are waited for, so unrelated unparsed emails cannot use up the timeout.
+	// With WithAutoMarkRead, emails the server reports as read are skipped.
+	accept := func(e *Email) (bool, error) {
+		if cfg.autoMarkRead && e.IsRead {
+			return false, nil
+		}
+		if cfg.waitForParsed && !e.BodyReady {
+			if !cfg.matchesMetadata(e) {
+				return false, nil
+			}
+			ready, err := i.awaitBodyReady(ctx, e.ID)
+			if err != nil {
+				return false, err
+			}
+			e = ready
+		}
+		ok, err := cfg.evaluate(e)
+		if err != nil {
+			return false, err
+		}
+		return ok && process(e), nil
+	}
+
 	existing, err := i.GetEmails(ctx)
 	if err != nil {
 		return err
 	}
 	for _, e := range existing {
-		if cfg.Matches(e) && process(e) {
-			return nil
+		if done, err := accept(e); done || err != nil {
+			return err
 		}
 	}
 
@@ -30,9 +64,46 @@ func (i *Inbox) waitForEmails(ctx context.Context, cfg *waitConfig, process func
 		case <-ctx.Done():
 			return ctx.Err()
 		case email := <-emails:
-			if email != nil && cfg.Matches(email) && process(email) {
-				return nil
+			if email == nil {
+				continue
+			}
+			if done, err := accept(email); done || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// awaitBodyReady re-fetches an email with the client's polling intervals
+// until the server reports its body as processed.
+func (i *Inbox) awaitBodyReady(ctx context.Context, emailID string) (*Email, error) {
+	pc := i.client.pollingConfig
+	if pc.InitialInterval <= 0 {
+		pc = resolvePollingConfig(&clientConfig{})
+	}
+	interval := pc.InitialInterval
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-i.client.clock().After(interval):
+		}
+
+		email, err := i.GetEmail(ctx, emailID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
 			}
+			return nil, fmt.Errorf("wait for body of email %s: %w", emailID, err)
+		}
+		if email.BodyReady {
+			return email, nil
+		}
+
+		interval = time.Duration(float64(interval) * pc.BackoffMultiplier)
+		if interval > pc.MaxBackoff {
+			interval = pc.MaxBackoff
 		}
 	}
 }
@@ -41,6 +112,9 @@ func (i *Inbox) waitForEmails(ctx context.Context, cfg *waitConfig, process func
 // The channel is not closed when the context is cancelled; use a select
 // on ctx.Done() to detect cancellation.
 //
+// Filter options such as [WithSubject] or [WithRecipientPattern] restrict
+// which emails are delivered; [WithWaitTimeout] is ignored.
+//
 // Example:
 //
 //	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -55,13 +129,24 @@ func (i *Inbox) waitForEmails(ctx context.Context, cfg *waitConfig, process func
 //	        fmt.Printf("New email: %s\n", email.Subject)
 //	    }
 //	}
-func (i *Inbox) Watch(ctx context.Context) <-chan *Email {
+func (i *Inbox) Watch(ctx context.Context, opts ...WaitOption) <-chan *Email {
+	cfg := &waitConfig{clock: i.client.clock()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	ch := make(chan *Email, 16)
+	if i.client == nil {
+		return ch // Detached inboxes never receive live emails.
+	}
 
 	// Subscribe with callback that sends to channel.
 	// We spawn a goroutine for each send to guarantee delivery without
 	// blocking the event source. Given low volume, overhead is negligible.
 	unsubscribe := i.client.subs.subscribe(i.inboxHash, func(email *Email) {
+		if email != nil && !cfg.Matches(email) {
+			return
+		}
 		go func(e *Email) { ch <- e }(email)
 	})
 
@@ -79,47 +164,254 @@ func (i *Inbox) Watch(ctx context.Context) <-chan *Email {
 // WatchFunc calls fn for each email as they arrive until the context is cancelled.
 // This is a convenience wrapper around Watch for simpler use cases.
 //
+// A panic in fn is recovered, counted in [ClientStats.CallbackPanics], and
+// reported to the [WithOnSyncError] callback as a [*CallbackPanicError];
+// subsequent emails continue to be delivered. Filter options are applied
+// as in [Inbox.Watch].
+//
 // Example:
 //
 //	inbox.WatchFunc(ctx, func(email *vaultsandbox.Email) {
-//	    fmt.Printf("New email: %s\n", email.Subject)
-//	})
-func (i *Inbox) WatchFunc(ctx context.Context, fn func(*Email)) {
-	emails := i.Watch(ctx)
+//	    fmt.Printf("New order: %s\n", email.EnvelopeTo)
+//	}, vaultsandbox.WithRecipientPattern(regexp.MustCompile(`^order-.*@`)))
+func (i *Inbox) WatchFunc(ctx context.Context, fn func(*Email), opts ...WaitOption) {
+	emails := i.Watch(ctx, opts...)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case email := <-emails:
 			if email != nil {
-				fn(email)
+				i.client.invokeCallback(func() { fn(email) })
 			}
 		}
 	}
 }
 
+// WatchChan is like [Inbox.Watch], but emails arrive in the order they were
+// delivered and both returned channels are closed when the watch ends, so
+// the emails can be consumed with a plain range loop. Filter options are
+// applied as in Watch.
+//
+// The watch ends when ctx is done, when the inbox is deleted through this
+// client, or when the client is closed. Closing the client sends
+// [ErrClientClosed] on the error channel and a detached inbox sends
+// [ErrDetachedInbox] at once; the error channel receives at most one error
+// and is closed after the email channel. If the inbox is deleted, emails
+// already delivered are still sent before the channels close. Pending
+// emails are dropped once ctx is done, so cancelling ctx never leaks the
+// goroutine behind the channels.
+//
+// Example:
+//
+//	emails, errs := inbox.WatchChan(ctx)
+//	for email := range emails {
+//	    fmt.Println(email.Subject)
+//	}
+//	if err := <-errs; err != nil {
+//	    log.Fatal(err)
+//	}
+func (i *Inbox) WatchChan(ctx context.Context, opts ...WaitOption) (<-chan *Email, <-chan error) {
+	cfg := &waitConfig{clock: i.client.clock()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	emails := make(chan *Email)
+	errs := make(chan error, 1)
+	if i.client == nil {
+		errs <- ErrDetachedInbox
+		close(emails)
+		close(errs)
+		return emails, errs
+	}
+
+	q := newWatchQueue[*Email]()
+	unsubscribe := i.client.subs.subscribeWithEnd(i.inboxHash, func(email *Email) {
+		if email != nil && cfg.Matches(email) {
+			q.push(email)
+		}
+	}, q.end)
+	go q.run(ctx, emails, errs, unsubscribe)
+
+	return emails, errs
+}
+
+// watchQueue hands values from subscription callbacks to a consumer channel.
+// Callbacks must not block the event source, so values are queued and sent
+// in order by the single goroutine running run.
+type watchQueue[T any] struct {
+	mu      sync.Mutex
+	queue   []T
+	endErr  error
+	wake    chan struct{}
+	ended   chan struct{}
+	endOnce sync.Once
+}
+
+func newWatchQueue[T any]() *watchQueue[T] {
+	return &watchQueue[T]{
+		wake:  make(chan struct{}, 1),
+		ended: make(chan struct{}),
+	}
+}
+
+// push queues v for sending. It never blocks.
+func (q *watchQueue[T]) push(v T) {
+	q.mu.Lock()
+	q.queue = append(q.queue, v)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// end stops the queue once the values pushed so far are sent; a non-nil
+// err is then reported on the error channel. Only the first call counts.
+func (q *watchQueue[T]) end(err error) {
+	q.endOnce.Do(func() {
+		q.mu.Lock()
+		q.endErr = err
+		q.mu.Unlock()
+		close(q.ended)
+	})
+}
+
+func (q *watchQueue[T]) next() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var zero T
+	if len(q.queue) == 0 {
+		return zero, false
+	}
+	v := q.queue[0]
+	q.queue[0] = zero
+	q.queue = q.queue[1:]
+	return v, true
+}
+
+// run sends queued values to out until the queue ends or ctx is done, then
+// calls cleanup and closes out and errs. errs must have a buffer of one.
+func (q *watchQueue[T]) run(ctx context.Context, out chan<- T, errs chan<- error, cleanup func()) {
+	defer close(errs)
+	defer close(out)
+	defer cleanup()
+
+	for {
+		if v, ok := q.next(); ok {
+			select {
+			case out <- v:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-q.wake:
+		case <-q.ended:
+			// Flush what was pushed before the end, then report why.
+			for v, ok := q.next(); ok; v, ok = q.next() {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+			q.mu.Lock()
+			err := q.endErr
+			q.mu.Unlock()
+			if err != nil {
+				errs <- err
+			}
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// EmailsReader returns a reader that yields the inbox's matching emails as
+// NDJSON: one [Email.MarshalJSON] object per line, written as each email
+// arrives. Filter options are applied as in [Inbox.Watch]. The output can be
+// piped straight into a subprocess or an HTTP request body.
+//
+// Read blocks until the next email arrives and returns io.EOF once ctx is
+// done. Closing the reader stops the underlying watch; the caller must close
+// it when finished.
+func (i *Inbox) EmailsReader(ctx context.Context, opts ...WaitOption) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	emails := i.Watch(ctx, opts...)
+	pr, pw := io.Pipe()
+
+	go func() {
+		enc := json.NewEncoder(pw)
+		for {
+			select {
+			case <-ctx.Done():
+				pw.Close()
+				return
+			case email := <-emails:
+				if email == nil {
+					continue
+				}
+				if err := enc.Encode(email); err != nil {
+					pw.CloseWithError(err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return &emailsReader{PipeReader: pr, cancel: cancel}
+}
+
+// emailsReader stops the watch behind [Inbox.EmailsReader] on Close.
+type emailsReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (r *emailsReader) Close() error {
+	r.cancel()
+	return r.PipeReader.Close()
+}
+
 // WaitForEmail waits for an email matching the given criteria.
 // It uses the client's callback infrastructure to receive instant notifications
 // when SSE is active, or receives events when the polling handler fires.
-func (i *Inbox) WaitForEmail(ctx context.Context, opts ...WaitOption) (*Email, error) {
+func (i *Inbox) WaitForEmail(ctx context.Context, opts ...WaitOption) (_ *Email, err error) {
+	ctx, span := i.client.startSpan(ctx, "vaultsandbox.WaitForEmail", "vaultsandbox.inbox.hash", i.inboxHash)
+	defer func() { tracing.End(span, err) }()
+
 	cfg := &waitConfig{
 		timeout: defaultWaitTimeout,
+		clock:   i.client.clock(),
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
 	var result *Email
-	err := i.waitForEmails(ctx, cfg, func(e *Email) bool {
+	err = i.waitForEmails(ctx, cfg, func(e *Email) bool {
 		result = e
 		return true
 	})
+	if err == nil && cfg.autoMarkRead {
+		i.markReadBestEffort(ctx, result)
+	}
 	return result, err
 }
 
 // WaitForEmailCount waits until at least count matching emails are found.
 // It uses the client's callback infrastructure to receive instant notifications
 // when SSE is active, or receives events when the polling handler fires.
+//
+// count is a minimum: the wait succeeds as soon as count distinct matching
+// emails have been seen, whether they were already in the inbox or arrived
+// later, and more emails arriving afterwards do not affect the result. With
+// [WithStopPredicate] the wait may complete earlier with fewer emails.
 func (i *Inbox) WaitForEmailCount(ctx context.Context, count int, opts ...WaitOption) ([]*Email, error) {
 	if count < 0 {
 		return nil, fmt.Errorf("count must be non-negative, got %d", count)
@@ -130,24 +422,179 @@ func (i *Inbox) WaitForEmailCount(ctx context.Context, count int, opts ...WaitOp
 
 	cfg := &waitConfig{
 		timeout: defaultWaitTimeout,
+		clock:   i.client.clock(),
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	seen := make(map[string]struct{})
-	var results []*Email
+	acc := newEmailAccumulator(count, cfg.stopPredicate)
+	if err := i.waitForEmails(ctx, cfg, acc.add); err != nil {
+		return nil, err
+	}
+	if cfg.autoMarkRead {
+		for _, e := range acc.emails {
+			i.markReadBestEffort(ctx, e)
+		}
+	}
+	return acc.emails, nil
+}
 
+// WaitForEmails collects every matching email seen until the wait timeout
+// (see [WithWaitTimeout]) elapses, then returns them in the order they were
+// seen. Emails already in the inbox are included, and each email is returned
+// once even if it is reported by both the existing listing and a live event.
+// If nothing matched, it returns an empty slice and no error.
+//
+// Unlike [Inbox.WaitForEmail] and [Inbox.WaitForEmailCount], the timeout is
+// the expected outcome rather than a failure. If ctx is done before the
+// timeout elapses, the emails collected so far are returned with ctx's error.
+//
+// Example — assert that exactly one reset email is sent within 10 seconds:
+//
+//	emails, err := inbox.WaitForEmails(ctx,
+//	    vaultsandbox.WithSubject("Reset your password"),
+//	    vaultsandbox.WithWaitTimeout(10*time.Second))
+func (i *Inbox) WaitForEmails(ctx context.Context, opts ...WaitOption) ([]*Email, error) {
+	cfg := &waitConfig{
+		timeout: defaultWaitTimeout,
+		clock:   i.client.clock(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	acc := newEmailAccumulator(0, nil)
 	err := i.waitForEmails(ctx, cfg, func(e *Email) bool {
-		if _, ok := seen[e.ID]; ok {
-			return false //coverage:ignore
-		}
-		seen[e.ID] = struct{}{}
-		results = append(results, e)
-		return len(results) >= count
+		acc.add(e)
+		return false
 	})
+	if ctx.Err() != nil {
+		return acc.result(), ctx.Err()
+	}
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+	if cfg.autoMarkRead {
+		for _, e := range acc.emails {
+			i.markReadBestEffort(ctx, e)
+		}
+	}
+	return acc.result(), nil
+}
+
+// emailAccumulator collects distinct emails for WaitForEmailCount and
+// WaitForEmails until count is reached or stop reports true. A count of zero
+// means no limit.
+type emailAccumulator struct {
+	count  int
+	stop   func([]*Email) bool
+	seen   map[string]struct{}
+	emails []*Email
+}
+
+func newEmailAccumulator(count int, stop func([]*Email) bool) *emailAccumulator {
+	return &emailAccumulator{count: count, stop: stop, seen: make(map[string]struct{})}
+}
+
+// add records e and reports whether the wait is complete. Emails already
+// seen are ignored.
+func (a *emailAccumulator) add(e *Email) bool {
+	if _, ok := a.seen[e.ID]; ok {
+		return false
+	}
+	a.seen[e.ID] = struct{}{}
+	a.emails = append(a.emails, e)
+	if a.count > 0 && len(a.emails) >= a.count {
+		return true
+	}
+	return a.stop != nil && a.stop(slices.Clip(a.emails))
+}
+
+// result returns the collected emails, or an empty slice if there are none.
+func (a *emailAccumulator) result() []*Email {
+	if a.emails == nil {
+		return []*Email{}
+	}
+	return a.emails
+}
+
+// WaitForEmailWithRaw waits for an email like [Inbox.WaitForEmail] and also
+// returns its raw RFC 5322 source. The raw source is fetched by the matched
+// email's ID immediately after the match, so both results always describe the
+// same email. If the email is deleted before its source can be fetched, the
+// matched email is returned along with the error.
+func (i *Inbox) WaitForEmailWithRaw(ctx context.Context, opts ...WaitOption) (*Email, string, error) {
+	email, err := i.WaitForEmail(ctx, opts...)
 	if err != nil {
+		return nil, "", err
+	}
+	raw, err := i.GetRawEmail(ctx, email.ID)
+	if err != nil {
+		return email, "", fmt.Errorf("fetch raw source of email %s: %w", email.ID, err)
+	}
+	return email, raw, nil
+}
+
+// markReadBestEffort marks an email as read for WithAutoMarkRead. Failures
+// are reported via onSyncError rather than returned.
+func (i *Inbox) markReadBestEffort(ctx context.Context, e *Email) {
+	if e.IsRead {
+		return
+	}
+	if err := i.MarkEmailAsRead(ctx, e.ID); err != nil {
+		if i.client.onSyncError != nil {
+			i.client.onSyncError(fmt.Errorf("auto-mark email %s as read: %w", e.ID, err))
+		}
+		return
+	}
+	e.IsRead = true
+}
+
+// WaitUntilEmpty polls the inbox sync status until it reports no emails,
+// which is useful after deleting emails in test teardown when deletion is
+// eventually consistent. It returns immediately if the inbox is already empty.
+//
+// Polling uses the client's polling intervals and backoff (see
+// [WithPollingConfig]). If the inbox is not empty when timeout elapses or ctx
+// is cancelled, the last observed status is returned with the context error.
+func (i *Inbox) WaitUntilEmpty(ctx context.Context, timeout time.Duration) (*SyncStatus, error) {
+	if err := i.checkAttached(); err != nil {
 		return nil, err
 	}
-	return results[:count], nil
+
+	ctx, cancel := clock.WithTimeout(ctx, i.client.clock(), timeout)
+	defer cancel()
+
+	pc := i.client.pollingConfig
+	if pc.InitialInterval <= 0 {
+		pc = resolvePollingConfig(&clientConfig{})
+	}
+	interval := pc.InitialInterval
+
+	var last *SyncStatus
+	for {
+		status, err := i.GetSyncStatus(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			return last, err
+		}
+		last = status
+		if status.EmailCount == 0 {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-i.client.clock().After(interval):
+		}
+
+		interval = time.Duration(float64(interval) * pc.BackoffMultiplier)
+		if interval > pc.MaxBackoff {
+			interval = pc.MaxBackoff
+		}
+	}
 }
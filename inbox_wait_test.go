@@ -1,11 +1,23 @@
 package vaultsandbox
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
 	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/delivery"
 )
 
 func TestInbox_Watch_ReturnsChannel(t *testing.T) {
@@ -156,6 +168,120 @@ func TestInbox_Watch_CancelRemovesWatcher(t *testing.T) {
 	}
 }
 
+func TestInbox_WatchChan_DeliversInOrder(t *testing.T) {
+	t.Parallel()
+	client := &Client{subs: newSubscriptionManager()}
+	inbox := &Inbox{inboxHash: "test-hash", client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	emails, errs := inbox.WatchChan(ctx)
+
+	// Both events are delivered before the consumer reads either.
+	client.subs.notify("test-hash", &Email{ID: "email-1"})
+	client.subs.notify("test-hash", &Email{ID: "email-2"})
+
+	for _, want := range []string{"email-1", "email-2"} {
+		select {
+		case email := <-emails:
+			if email.ID != want {
+				t.Fatalf("email.ID = %q, want %q", email.ID, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("did not receive %s", want)
+		}
+	}
+
+	cancel()
+	for range emails {
+		t.Error("received email after cancel")
+	}
+	if err, ok := <-errs; ok {
+		t.Errorf("errs received %v after cancel, want it closed", err)
+	}
+}
+
+func TestInbox_WatchChan_Filters(t *testing.T) {
+	t.Parallel()
+	client := &Client{subs: newSubscriptionManager()}
+	inbox := &Inbox{inboxHash: "test-hash", client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	emails, _ := inbox.WatchChan(ctx, WithSubject("keep"))
+
+	client.subs.notify("test-hash", &Email{ID: "skip", Subject: "other"})
+	client.subs.notify("test-hash", &Email{ID: "keep", Subject: "keep"})
+
+	select {
+	case email := <-emails:
+		if email.ID != "keep" {
+			t.Errorf("email.ID = %q, want keep", email.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive the matching email")
+	}
+}
+
+func TestInbox_WatchChan_ClosesWhenInboxDeleted(t *testing.T) {
+	t.Parallel()
+	client := &Client{
+		subs:          newSubscriptionManager(),
+		strategy:      delivery.NewPollingStrategy(delivery.Config{}),
+		inboxes:       make(map[string]*Inbox),
+		inboxesByHash: make(map[string]*Inbox),
+		syncStates:    make(map[string]*syncState),
+	}
+	inbox := &Inbox{emailAddress: "test@example.com", inboxHash: "test-hash", client: client}
+	client.inboxes[inbox.emailAddress] = inbox
+	client.inboxesByHash[inbox.inboxHash] = inbox
+
+	emails, errs := inbox.WatchChan(context.Background())
+	client.subs.notify("test-hash", &Email{ID: "before-delete"})
+	client.untrackInbox(inbox.emailAddress)
+
+	var got []string
+	for email := range emails {
+		got = append(got, email.ID)
+	}
+	if len(got) != 1 || got[0] != "before-delete" {
+		t.Errorf("emails = %v, want [before-delete]", got)
+	}
+	if err, ok := <-errs; ok {
+		t.Errorf("errs received %v, want it closed without an error", err)
+	}
+}
+
+func TestInbox_WatchChan_ClientClosed(t *testing.T) {
+	t.Parallel()
+	client := &Client{
+		subs:          newSubscriptionManager(),
+		inboxes:       make(map[string]*Inbox),
+		inboxesByHash: make(map[string]*Inbox),
+	}
+	inbox := &Inbox{inboxHash: "test-hash", client: client}
+
+	emails, errs := inbox.WatchChan(context.Background())
+	client.Close()
+
+	for range emails {
+	}
+	if err := <-errs; !errors.Is(err, ErrClientClosed) {
+		t.Errorf("errs received %v, want ErrClientClosed", err)
+	}
+}
+
+func TestInbox_WatchChan_Detached(t *testing.T) {
+	t.Parallel()
+	emails, errs := (&Inbox{inboxHash: "test-hash"}).WatchChan(context.Background())
+	if _, ok := <-emails; ok {
+		t.Error("emails channel should be closed")
+	}
+	if err := <-errs; !errors.Is(err, ErrDetachedInbox) {
+		t.Errorf("errs received %v, want ErrDetachedInbox", err)
+	}
+}
+
 func TestClient_WatchInboxes_ReturnsChannel(t *testing.T) {
 	t.Parallel()
 	client := &Client{
@@ -258,6 +384,166 @@ func TestClient_WatchInboxes_UnsubscribesOnContextCancel(t *testing.T) {
 	}
 }
 
+// fakeStrategy is a delivery strategy whose events are injected by the test.
+type fakeStrategy struct {
+	mu      sync.Mutex
+	handler delivery.EventHandler
+}
+
+func (f *fakeStrategy) Start(_ context.Context, _ []delivery.InboxInfo, handler delivery.EventHandler) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handler = handler
+	return nil
+}
+
+func (f *fakeStrategy) Stop() error                       { return nil }
+func (f *fakeStrategy) AddInbox(delivery.InboxInfo) error { return nil }
+func (f *fakeStrategy) RemoveInbox(string) error          { return nil }
+func (f *fakeStrategy) Name() string                      { return "fake" }
+func (f *fakeStrategy) OnReconnect(func(context.Context)) {}
+
+// deliver reports a new email to the strategy's handler.
+func (f *fakeStrategy) deliver(t *testing.T, inboxHash, emailID string) {
+	t.Helper()
+	f.mu.Lock()
+	handler := f.handler
+	f.mu.Unlock()
+	if err := handler(context.Background(), &api.SSEEvent{InboxID: inboxHash, EmailID: emailID}); err != nil {
+		t.Fatalf("handler(%s, %s) error = %v", inboxHash, emailID, err)
+	}
+}
+
+// newFakeStrategyClient returns a client with two registered plain inboxes
+// whose events come from the returned fake strategy. The server answers
+// every email fetch with an email whose ID is the requested one.
+func newFakeStrategyClient(t *testing.T) (*Client, *fakeStrategy, *Inbox, *Inbox) {
+	t.Helper()
+	inbox1 := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		id := path.Base(r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newPlainRawEmail(t, id, map[string]interface{}{"subject": id}, nil))
+	})
+	c := inbox1.client
+	inbox2 := &Inbox{emailAddress: "other@example.com", inboxHash: "hash456", client: c}
+
+	strategy := &fakeStrategy{}
+	c.strategy = strategy
+	c.inboxes = map[string]*Inbox{inbox1.emailAddress: inbox1, inbox2.emailAddress: inbox2}
+	c.inboxesByHash = map[string]*Inbox{inbox1.inboxHash: inbox1, inbox2.inboxHash: inbox2}
+	c.syncStates = map[string]*syncState{
+		inbox1.inboxHash: {seenEmails: map[string]struct{}{}},
+		inbox2.inboxHash: {seenEmails: map[string]struct{}{}},
+	}
+	if err := strategy.Start(context.Background(), nil, c.handleSSEEvent); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return c, strategy, inbox1, inbox2
+}
+
+func TestClient_WatchInboxesChan_EventsCarryInbox(t *testing.T) {
+	t.Parallel()
+	c, strategy, inbox1, inbox2 := newFakeStrategyClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errs := c.WatchInboxesChan(ctx, inbox1, inbox2)
+
+	strategy.deliver(t, inbox1.inboxHash, "e1")
+	strategy.deliver(t, inbox2.inboxHash, "e2")
+	strategy.deliver(t, inbox1.inboxHash, "e3")
+
+	want := []struct {
+		inbox *Inbox
+		id    string
+	}{{inbox1, "e1"}, {inbox2, "e2"}, {inbox1, "e3"}}
+	for _, w := range want {
+		select {
+		case event := <-events:
+			if event.Inbox != w.inbox || event.Email.ID != w.id {
+				t.Errorf("event = (%s, %s), want (%s, %s)",
+					event.Inbox.EmailAddress(), event.Email.ID, w.inbox.EmailAddress(), w.id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("did not receive %s", w.id)
+		}
+	}
+
+	cancel()
+	for range events {
+		t.Error("received event after cancel")
+	}
+	if err, ok := <-errs; ok {
+		t.Errorf("errs received %v after cancel, want it closed", err)
+	}
+}
+
+func TestClient_WatchInboxesChan_EndsWhenAllInboxesDeleted(t *testing.T) {
+	t.Parallel()
+	c, strategy, inbox1, inbox2 := newFakeStrategyClient(t)
+
+	events, errs := c.WatchInboxesChan(context.Background(), inbox1, inbox2)
+
+	// Deleting one inbox keeps the watch on the other.
+	c.untrackInbox(inbox1.emailAddress)
+	strategy.deliver(t, inbox2.inboxHash, "e1")
+	select {
+	case event := <-events:
+		if event.Inbox != inbox2 || event.Email.ID != "e1" {
+			t.Errorf("event = (%s, %s), want (%s, e1)", event.Inbox.EmailAddress(), event.Email.ID, inbox2.EmailAddress())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive e1")
+	}
+
+	c.untrackInbox(inbox2.emailAddress)
+	for event := range events {
+		t.Errorf("unexpected event %s", event.Email.ID)
+	}
+	if err, ok := <-errs; ok {
+		t.Errorf("errs received %v, want it closed without an error", err)
+	}
+}
+
+func TestClient_WatchInboxesChan_ClientClosed(t *testing.T) {
+	t.Parallel()
+	c, _, inbox1, inbox2 := newFakeStrategyClient(t)
+
+	events, errs := c.WatchInboxesChan(context.Background(), inbox1, inbox2)
+	c.Close()
+
+	for range events {
+	}
+	if err := <-errs; !errors.Is(err, ErrClientClosed) {
+		t.Errorf("errs received %v, want ErrClientClosed", err)
+	}
+}
+
+func TestClient_WatchInboxesChan_NoInboxesOrDetached(t *testing.T) {
+	t.Parallel()
+	client := &Client{subs: newSubscriptionManager()}
+
+	tests := []struct {
+		name    string
+		inboxes []*Inbox
+		wantErr error
+	}{
+		{"no inboxes", nil, nil},
+		{"detached inbox", []*Inbox{{inboxHash: "hash-1", client: client}, {inboxHash: "hash-2"}}, ErrDetachedInbox},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, errs := client.WatchInboxesChan(context.Background(), tt.inboxes...)
+			if _, ok := <-events; ok {
+				t.Error("events channel should be closed")
+			}
+			if err := <-errs; !errors.Is(err, tt.wantErr) {
+				t.Errorf("errs received %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestSubscriptionManager_Subscribe(t *testing.T) {
 	t.Parallel()
 	m := newSubscriptionManager()
@@ -465,6 +751,199 @@ func TestWaitForEmailCount_DeduplicatesEmails(t *testing.T) {
 	}
 }
 
+func TestEmailAccumulator(t *testing.T) {
+	t.Parallel()
+	stopAtConfirm := func(got []*Email) bool { return got[len(got)-1].Subject == "confirm" }
+
+	tests := []struct {
+		name     string
+		count    int
+		stop     func([]*Email) bool
+		incoming []*Email
+		wantIDs  []string
+		wantDone bool
+	}{
+		{
+			name:     "count reached",
+			count:    2,
+			incoming: []*Email{{ID: "1"}, {ID: "2"}},
+			wantIDs:  []string{"1", "2"},
+			wantDone: true,
+		},
+		{
+			name:     "duplicates ignored",
+			count:    2,
+			incoming: []*Email{{ID: "1"}, {ID: "1"}},
+			wantIDs:  []string{"1"},
+		},
+		{
+			name:     "stop predicate completes early",
+			count:    3,
+			stop:     stopAtConfirm,
+			incoming: []*Email{{ID: "1"}, {ID: "2", Subject: "confirm"}},
+			wantIDs:  []string{"1", "2"},
+			wantDone: true,
+		},
+		{
+			name:     "stop predicate false keeps waiting",
+			count:    3,
+			stop:     stopAtConfirm,
+			incoming: []*Email{{ID: "1"}, {ID: "2"}},
+			wantIDs:  []string{"1", "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			acc := newEmailAccumulator(tt.count, tt.stop)
+			done := false
+			for _, e := range tt.incoming {
+				if done {
+					t.Fatal("add called after the wait completed")
+				}
+				done = acc.add(e)
+			}
+			if done != tt.wantDone {
+				t.Errorf("done = %v, want %v", done, tt.wantDone)
+			}
+			var ids []string
+			for _, e := range acc.emails {
+				ids = append(ids, e.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("emails = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestWaitForEmailCount_StopPredicate(t *testing.T) {
+	t.Parallel()
+	listed := make(chan struct{})
+	var once sync.Once
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Order received"}, nil),
+		})
+		once.Do(func() { close(listed) })
+	})
+
+	// Deliver live emails once the existing ones have been listed; the
+	// wait subscribes before listing, so none of these can be missed. The
+	// confirmation is sent only after the duplicate has been accumulated,
+	// since Watch does not preserve delivery order.
+	accumulated := make(chan int, 5)
+	go func() {
+		<-listed
+		inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e2", Subject: "Order packed"})
+		inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e2", Subject: "Order packed"})
+		for n := range accumulated {
+			if n == 2 {
+				break
+			}
+		}
+		inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e3", Subject: "Order confirmed"})
+	}()
+
+	var calls atomic.Int32
+	emails, err := inbox.WaitForEmailCount(context.Background(), 5,
+		WithWaitTimeout(5*time.Second),
+		WithStopPredicate(func(got []*Email) bool {
+			calls.Add(1)
+			accumulated <- len(got)
+			return got[len(got)-1].Subject == "Order confirmed"
+		}))
+	if err != nil {
+		t.Fatalf("WaitForEmailCount() error = %v", err)
+	}
+	if len(emails) != 3 {
+		t.Fatalf("len(emails) = %d, want 3", len(emails))
+	}
+	if emails[0].ID != "e1" || emails[2].ID != "e3" {
+		t.Errorf("emails = [%s ... %s], want [e1 ... e3]", emails[0].ID, emails[2].ID)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("stop predicate calls = %d, want 3 (once per distinct email)", got)
+	}
+}
+
+func TestWaitForEmails(t *testing.T) {
+	t.Parallel()
+	listed := make(chan struct{})
+	var once sync.Once
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Alert: disk"}, nil),
+			newPlainRawEmail(t, "e2", map[string]interface{}{"subject": "Newsletter"}, nil),
+		})
+		once.Do(func() { close(listed) })
+	})
+
+	go func() {
+		<-listed
+		// e1 is redelivered live, as a sync after reconnect would do.
+		inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e1", Subject: "Alert: disk"})
+		inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e3", Subject: "Alert: cpu"})
+		inbox.client.subs.notify(inbox.inboxHash, &Email{ID: "e4", Subject: "Newsletter"})
+	}()
+
+	emails, err := inbox.WaitForEmails(context.Background(),
+		WithSubjectRegex(regexp.MustCompile(`^Alert:`)),
+		WithWaitTimeout(300*time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitForEmails() error = %v", err)
+	}
+	var ids []string
+	for _, e := range emails {
+		ids = append(ids, e.ID)
+	}
+	if strings.Join(ids, ",") != "e1,e3" {
+		t.Errorf("emails = %v, want [e1 e3]", ids)
+	}
+}
+
+func TestWaitForEmails_NoneMatch(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Newsletter"}, nil),
+		})
+	})
+
+	emails, err := inbox.WaitForEmails(context.Background(),
+		WithSubject("Alert"), WithWaitTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("WaitForEmails() error = %v", err)
+	}
+	if emails == nil || len(emails) != 0 {
+		t.Errorf("emails = %v, want empty non-nil slice", emails)
+	}
+}
+
+func TestWaitForEmails_ContextCanceled(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*api.RawEmail{
+			newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Alert"}, nil),
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	emails, err := inbox.WaitForEmails(ctx, WithWaitTimeout(time.Minute))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForEmails() error = %v, want context.DeadlineExceeded", err)
+	}
+	if len(emails) != 1 || emails[0].ID != "e1" {
+		t.Errorf("emails = %v, want the email collected before cancellation", emails)
+	}
+}
+
 func TestInboxEvent_Fields(t *testing.T) {
 	t.Parallel()
 	inbox := &Inbox{emailAddress: "test@example.com"}
@@ -679,6 +1158,66 @@ func TestWaitConfig_MatchesFromRegex(t *testing.T) {
 	}
 }
 
+func TestWaitConfig_MatchesRecipientPattern(t *testing.T) {
+	t.Parallel()
+	cfg := &waitConfig{
+		recipientRegex: regexp.MustCompile(`^order-.*@example\.com$`),
+	}
+
+	tests := []struct {
+		name  string
+		email *Email
+		want  bool
+	}{
+		{"envelope match", &Email{EnvelopeTo: "order-42@example.com", To: []string{"list@example.com"}}, true},
+		{"to match", &Email{To: []string{"cc@example.com", "order-7@example.com"}}, true},
+		{"no match", &Email{EnvelopeTo: "invoice-1@example.com", To: []string{"invoice-1@example.com"}}, false},
+		{"no recipients", &Email{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := cfg.Matches(tt.email); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInbox_Watch_RecipientPattern(t *testing.T) {
+	t.Parallel()
+	client := &Client{
+		subs: newSubscriptionManager(),
+	}
+	inbox := &Inbox{
+		inboxHash: "test-hash",
+		client:    client,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := inbox.Watch(ctx, WithRecipientPattern(regexp.MustCompile(`^order-`)))
+
+	client.subs.notify("test-hash", &Email{ID: "skip", EnvelopeTo: "invoice-1@example.com"})
+	client.subs.notify("test-hash", &Email{ID: "keep", EnvelopeTo: "order-1@example.com"})
+
+	select {
+	case email := <-ch:
+		if email.ID != "keep" {
+			t.Errorf("email.ID = %q, want %q", email.ID, "keep")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("did not receive email")
+	}
+
+	select {
+	case email := <-ch:
+		t.Errorf("unexpected email %q", email.ID)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
 func TestWaitConfig_MultipleFilters(t *testing.T) {
 	t.Parallel()
 	cfg := &waitConfig{
@@ -700,3 +1239,583 @@ func TestWaitConfig_MultipleFilters(t *testing.T) {
 		t.Error("config should not match email with only from matching")
 	}
 }
+
+// waitForSubscribers blocks until the inbox has at least n subscribers.
+func waitForSubscribers(t *testing.T, m *subscriptionManager, inboxHash string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.RLock()
+		count := len(m.subs[inboxHash])
+		m.mu.RUnlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d subscribers on %s", n, inboxHash)
+}
+
+func TestInbox_EmailsReader(t *testing.T) {
+	t.Parallel()
+	client := &Client{subs: newSubscriptionManager()}
+	inbox := &Inbox{inboxHash: "test-hash", client: client}
+
+	r := inbox.EmailsReader(context.Background(), WithSubject("match"))
+	waitForSubscribers(t, client.subs, "test-hash", 1)
+
+	client.subs.notify("test-hash", &Email{ID: "skip", Subject: "other"})
+	client.subs.notify("test-hash", &Email{ID: "email-1", Subject: "match"})
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		t.Fatalf("Scan() = false, err = %v", scanner.Err())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(scanner.Bytes(), &got); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	if got["id"] != "email-1" || got["subject"] != "match" {
+		t.Errorf("line = %s, want email-1", scanner.Text())
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		client.subs.mu.RLock()
+		count := len(client.subs.subs["test-hash"])
+		client.subs.mu.RUnlock()
+		if count == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watch still subscribed after Close")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInbox_EmailsReader_EOFOnContextDone(t *testing.T) {
+	t.Parallel()
+	client := &Client{subs: newSubscriptionManager()}
+	inbox := &Inbox{inboxHash: "test-hash", client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := inbox.EmailsReader(ctx)
+	defer r.Close()
+	waitForSubscribers(t, client.subs, "test-hash", 1)
+
+	client.subs.notify("test-hash", &Email{ID: "email-1"})
+	client.subs.notify("test-hash", &Email{ID: "email-2"})
+
+	br := bufio.NewReader(r)
+	for range 2 {
+		if _, err := br.ReadString('\n'); err != nil {
+			t.Fatalf("ReadString() error = %v", err)
+		}
+	}
+	cancel()
+
+	rest, err := io.ReadAll(br)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v, want EOF", err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("unexpected trailing data %q", rest)
+	}
+}
+
+func TestInbox_WatchFunc_RecoversFromPanic(t *testing.T) {
+	t.Parallel()
+	errCh := make(chan error, 1)
+	logger := &captureLogger{}
+	client := &Client{
+		subs:        newSubscriptionManager(),
+		onSyncError: func(err error) { errCh <- err },
+		logger:      logger,
+	}
+	inbox := &Inbox{inboxHash: "test-hash", client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 2)
+	done := make(chan struct{})
+	go func() {
+		inbox.WatchFunc(ctx, func(email *Email) {
+			if email.ID == "bad" {
+				panic("boom")
+			}
+			received <- email.ID
+		})
+		close(done)
+	}()
+
+	waitForSubscribers(t, client.subs, "test-hash", 1)
+	client.subs.notify("test-hash", &Email{ID: "bad"})
+
+	select {
+	case err := <-errCh:
+		var panicErr *CallbackPanicError
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("onSyncError got %T, want *CallbackPanicError", err)
+		}
+		if panicErr.Value != "boom" {
+			t.Errorf("Value = %v, want boom", panicErr.Value)
+		}
+		if len(panicErr.Stack) == 0 {
+			t.Error("Stack is empty")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("panic was not reported")
+	}
+	if r, ok := logger.find("callback panicked", map[string]any{"panic": "boom"}); !ok || r.level != "error" {
+		t.Errorf("panic logged as %+v, %v; want an error record", r, ok)
+	} else if stack, _ := r.fields["stack"].(string); stack == "" {
+		t.Error("logged panic has no stack")
+	}
+
+	// Subsequent emails are still delivered
+	client.subs.notify("test-hash", &Email{ID: "good"})
+	select {
+	case id := <-received:
+		if id != "good" {
+			t.Errorf("received %q, want good", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch stopped delivering after panic")
+	}
+
+	if got := client.Stats().CallbackPanics; got != 1 {
+		t.Errorf("Stats().CallbackPanics = %d, want 1", got)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestClient_WatchInboxesFunc_RecoversFromPanic(t *testing.T) {
+	t.Parallel()
+	client := &Client{subs: newSubscriptionManager()}
+	inbox := &Inbox{inboxHash: "hash-a", client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan string, 2)
+	done := make(chan struct{})
+	go func() {
+		client.WatchInboxesFunc(ctx, func(event *InboxEvent) {
+			if event.Email.ID == "bad" {
+				panic(errors.New("boom"))
+			}
+			received <- event.Email.ID
+		}, inbox)
+		close(done)
+	}()
+
+	waitForSubscribers(t, client.subs, "hash-a", 1)
+	client.subs.notify("hash-a", &Email{ID: "bad"})
+	for client.Stats().CallbackPanics == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	client.subs.notify("hash-a", &Email{ID: "good"})
+
+	select {
+	case id := <-received:
+		if id != "good" {
+			t.Errorf("received %q, want good", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch stopped delivering after panic")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestSubscriptionManager_NotifyRecoversFromPanic(t *testing.T) {
+	t.Parallel()
+	m := newSubscriptionManager()
+	var panics int
+	m.onPanic = func(value any, stack []byte) { panics++ }
+
+	var called bool
+	m.subscribe("hash", func(*Email) { panic("boom") })
+	m.subscribe("hash", func(*Email) { called = true })
+
+	m.notify("hash", &Email{ID: "e1"})
+
+	if panics != 1 {
+		t.Errorf("onPanic called %d times, want 1", panics)
+	}
+	if !called {
+		t.Error("second subscriber was not notified after first panicked")
+	}
+}
+
+func TestInbox_WaitUntilEmpty(t *testing.T) {
+	t.Parallel()
+	fastPolling := PollingConfig{
+		InitialInterval:   5 * time.Millisecond,
+		MaxBackoff:        20 * time.Millisecond,
+		BackoffMultiplier: 2,
+	}
+
+	t.Run("already empty", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"emailCount": 0, "emailsHash": "empty"})
+		})
+		inbox.client.pollingConfig = fastPolling
+
+		status, err := inbox.WaitUntilEmpty(context.Background(), time.Second)
+		if err != nil {
+			t.Fatalf("WaitUntilEmpty() error = %v", err)
+		}
+		if status.EmailCount != 0 {
+			t.Errorf("EmailCount = %d, want 0", status.EmailCount)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("sync calls = %d, want 1", calls.Load())
+		}
+	})
+
+	t.Run("becomes empty", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+			count := 0
+			if calls.Add(1) < 3 {
+				count = 2
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"emailCount": count, "emailsHash": "h"})
+		})
+		inbox.client.pollingConfig = fastPolling
+
+		status, err := inbox.WaitUntilEmpty(context.Background(), time.Second)
+		if err != nil {
+			t.Fatalf("WaitUntilEmpty() error = %v", err)
+		}
+		if status.EmailCount != 0 {
+			t.Errorf("EmailCount = %d, want 0", status.EmailCount)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("sync calls = %d, want 3", calls.Load())
+		}
+	})
+
+	t.Run("timeout returns last status", func(t *testing.T) {
+		t.Parallel()
+		inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"emailCount": 1, "emailsHash": "h"})
+		})
+		inbox.client.pollingConfig = fastPolling
+
+		status, err := inbox.WaitUntilEmpty(context.Background(), 50*time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("WaitUntilEmpty() error = %v, want context.DeadlineExceeded", err)
+		}
+		if status == nil || status.EmailCount != 1 {
+			t.Errorf("status = %+v, want EmailCount 1", status)
+		}
+	})
+}
+
+func TestWaitForEmail_AutoMarkRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		markStatus  int
+		wantRead    bool
+		wantSyncErr bool
+	}{
+		{name: "success", markStatus: http.StatusNoContent, wantRead: true},
+		{name: "failure is best-effort", markStatus: http.StatusInternalServerError, wantSyncErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var markCalls atomic.Int32
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPatch {
+					markCalls.Add(1)
+					w.WriteHeader(tt.markStatus)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode([]*api.RawEmail{
+					newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Welcome"}, nil),
+				})
+			})
+			var syncErrs atomic.Int32
+			inbox.client.onSyncError = func(error) { syncErrs.Add(1) }
+
+			email, err := inbox.WaitForEmail(context.Background(),
+				WithSubject("Welcome"), WithAutoMarkRead(), WithWaitTimeout(time.Second))
+			if err != nil {
+				t.Fatalf("WaitForEmail() error = %v", err)
+			}
+			if got := markCalls.Load(); got != 1 {
+				t.Errorf("mark-read calls = %d, want 1", got)
+			}
+			if email.IsRead != tt.wantRead {
+				t.Errorf("IsRead = %v, want %v", email.IsRead, tt.wantRead)
+			}
+			if got := syncErrs.Load() > 0; got != tt.wantSyncErr {
+				t.Errorf("sync error reported = %v, want %v", got, tt.wantSyncErr)
+			}
+		})
+	}
+}
+
+func TestWaitForEmail_AutoMarkRead_SkipsReadEmails(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	read := map[string]bool{"e0": true}
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPatch {
+			read[path.Base(path.Dir(r.URL.Path))] = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// e0 was read elsewhere; the others are read once marked.
+		w.Header().Set("Content-Type", "application/json")
+		var emails []*api.RawEmail
+		for _, id := range []string{"e0", "e1", "e2"} {
+			raw := newPlainRawEmail(t, id, map[string]interface{}{"subject": "Code"}, nil)
+			raw.IsRead = read[id]
+			emails = append(emails, raw)
+		}
+		json.NewEncoder(w).Encode(emails)
+	})
+
+	for _, want := range []string{"e1", "e2"} {
+		email, err := inbox.WaitForEmail(context.Background(),
+			WithSubject("Code"), WithAutoMarkRead(), WithWaitTimeout(time.Second))
+		if err != nil {
+			t.Fatalf("WaitForEmail() error = %v", err)
+		}
+		if email.ID != want {
+			t.Errorf("WaitForEmail() = %s, want %s", email.ID, want)
+		}
+	}
+
+	// Without WithAutoMarkRead the read emails still match.
+	email, err := inbox.WaitForEmail(context.Background(), WithSubject("Code"), WithWaitTimeout(time.Second))
+	if err != nil || email.ID != "e0" {
+		t.Errorf("WaitForEmail() without auto-mark = %v, %v; want e0", email, err)
+	}
+}
+
+func TestWaitForEmail_WaitForParsed(t *testing.T) {
+	t.Parallel()
+	var fetches atomic.Int32
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		metadata := map[string]interface{}{"subject": "Welcome"}
+		if strings.HasSuffix(r.URL.Path, "/emails") {
+			raw := newPlainRawEmail(t, "e1", metadata, map[string]interface{}{"text": ""})
+			notReady := false
+			raw.ParsedReady = &notReady
+			json.NewEncoder(w).Encode([]*api.RawEmail{raw})
+			return
+		}
+		// The body becomes ready on the second single-email fetch.
+		raw := newPlainRawEmail(t, "e1", metadata, map[string]interface{}{"text": "Hello"})
+		ready := fetches.Add(1) >= 2
+		raw.ParsedReady = &ready
+		json.NewEncoder(w).Encode(raw)
+	})
+	inbox.client.pollingConfig = PollingConfig{
+		InitialInterval:   time.Millisecond,
+		MaxBackoff:        5 * time.Millisecond,
+		BackoffMultiplier: 2,
+	}
+
+	email, err := inbox.WaitForEmail(context.Background(),
+		WithSubject("Welcome"), WithWaitForParsed(), WithWaitTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("WaitForEmail() error = %v", err)
+	}
+	if !email.BodyReady || email.Text != "Hello" {
+		t.Errorf("email BodyReady = %v, Text = %q; want ready body %q", email.BodyReady, email.Text, "Hello")
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("single-email fetches = %d, want 2", got)
+	}
+}
+
+func TestWaitForEmail_WaitForParsed_SkipsUnmatchedUnready(t *testing.T) {
+	t.Parallel()
+	// The unready email does not match the filter, so its body must not be
+	// waited for: it never becomes ready here and would exhaust the timeout.
+	var fetches atomic.Int32
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/emails") {
+			other := newPlainRawEmail(t, "e0", map[string]interface{}{"subject": "Other"}, map[string]interface{}{"text": ""})
+			notReady := false
+			other.ParsedReady = &notReady
+			welcome := newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Welcome"}, map[string]interface{}{"text": "Hello"})
+			ready := true
+			welcome.ParsedReady = &ready
+			json.NewEncoder(w).Encode([]*api.RawEmail{other, welcome})
+			return
+		}
+		fetches.Add(1)
+		http.NotFound(w, r)
+	})
+
+	email, err := inbox.WaitForEmail(context.Background(),
+		WithSubject("Welcome"), WithWaitForParsed(), WithWaitTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("WaitForEmail() error = %v", err)
+	}
+	if email.ID != "e1" {
+		t.Errorf("email ID = %q, want e1", email.ID)
+	}
+	if got := fetches.Load(); got != 0 {
+		t.Errorf("single-email fetches = %d, want 0", got)
+	}
+}
+
+func TestWaitForEmail_WaitForParsed_FetchError(t *testing.T) {
+	t.Parallel()
+	inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/emails") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		raw := newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Welcome"}, nil)
+		json.NewEncoder(w).Encode([]*api.RawEmail{raw})
+	})
+	inbox.client.pollingConfig = PollingConfig{InitialInterval: time.Millisecond, MaxBackoff: time.Millisecond, BackoffMultiplier: 1}
+
+	_, err := inbox.WaitForEmail(context.Background(), WithWaitForParsed(), WithWaitTimeout(time.Second))
+	if !errors.Is(err, ErrEmailNotFound) {
+		t.Errorf("WaitForEmail() error = %v, want ErrEmailNotFound", err)
+	}
+}
+
+func TestWaitForEmail_PredicateErr(t *testing.T) {
+	t.Parallel()
+	errMalformed := errors.New("malformed email")
+	tests := []struct {
+		name      string
+		predicate func(*Email) (bool, error)
+		wantID    string
+		wantErr   error
+	}{
+		{
+			name:      "match",
+			predicate: func(e *Email) (bool, error) { return e.Subject == "Second", nil },
+			wantID:    "e2",
+		},
+		{
+			name:      "no match",
+			predicate: func(*Email) (bool, error) { return false, nil },
+			wantErr:   context.DeadlineExceeded,
+		},
+		{
+			name: "error aborts",
+			predicate: func(e *Email) (bool, error) {
+				if e.Subject == "First" {
+					return false, errMalformed
+				}
+				return true, nil
+			},
+			wantErr: errMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode([]*api.RawEmail{
+					newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "First"}, nil),
+					newPlainRawEmail(t, "e2", map[string]interface{}{"subject": "Second"}, nil),
+				})
+			})
+
+			start := time.Now()
+			email, err := inbox.WaitForEmail(context.Background(),
+				WithPredicateErr(tt.predicate), WithWaitTimeout(100*time.Millisecond))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WaitForEmail() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == errMalformed && time.Since(start) >= 100*time.Millisecond {
+				t.Error("WaitForEmail() waited for the timeout instead of aborting")
+			}
+			if tt.wantID != "" && email.ID != tt.wantID {
+				t.Errorf("email.ID = %q, want %q", email.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestWaitForEmailWithRaw(t *testing.T) {
+	t.Parallel()
+	const rawSource = "Subject: Welcome\r\n\r\nHello"
+
+	tests := []struct {
+		name       string
+		rawStatus  int
+		wantErr    bool
+		wantEmail  bool
+		wantRawSrc string
+	}{
+		{name: "success", rawStatus: http.StatusOK, wantEmail: true, wantRawSrc: rawSource},
+		{name: "deleted before raw fetch", rawStatus: http.StatusNotFound, wantErr: true, wantEmail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var rawPath string
+			inbox := newTestInboxWithServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if strings.HasSuffix(r.URL.Path, "/raw") {
+					rawPath = r.URL.Path
+					w.WriteHeader(tt.rawStatus)
+					json.NewEncoder(w).Encode(map[string]string{
+						"id":  "e1",
+						"raw": base64.StdEncoding.EncodeToString([]byte(rawSource)),
+					})
+					return
+				}
+				json.NewEncoder(w).Encode([]*api.RawEmail{
+					newPlainRawEmail(t, "e1", map[string]interface{}{"subject": "Welcome"}, nil),
+				})
+			})
+
+			email, raw, err := inbox.WaitForEmailWithRaw(context.Background(),
+				WithSubject("Welcome"), WithWaitTimeout(time.Second))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WaitForEmailWithRaw() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrEmailNotFound) {
+				t.Errorf("error = %v, want ErrEmailNotFound", err)
+			}
+			if (email != nil) != tt.wantEmail || (email != nil && email.ID != "e1") {
+				t.Errorf("email = %+v, want e1", email)
+			}
+			if raw != tt.wantRawSrc {
+				t.Errorf("raw = %q, want %q", raw, tt.wantRawSrc)
+			}
+			if !strings.Contains(rawPath, "/emails/e1/raw") {
+				t.Errorf("raw fetched from %q, want matched email e1", rawPath)
+			}
+		})
+	}
+}
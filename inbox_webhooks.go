@@ -5,6 +5,9 @@ import "context"
 // CreateWebhook creates a new webhook for this inbox.
 // Inbox webhooks only receive notifications for emails sent to this specific inbox.
 func (i *Inbox) CreateWebhook(ctx context.Context, url string, opts ...WebhookCreateOption) (*Webhook, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -20,6 +23,9 @@ func (i *Inbox) CreateWebhook(ctx context.Context, url string, opts ...WebhookCr
 
 // ListWebhooks returns all webhooks configured for this inbox.
 func (i *Inbox) ListWebhooks(ctx context.Context) (*WebhookListResponse, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -34,6 +40,9 @@ func (i *Inbox) ListWebhooks(ctx context.Context) (*WebhookListResponse, error)
 
 // GetWebhook returns a specific webhook by ID.
 func (i *Inbox) GetWebhook(ctx context.Context, webhookID string) (*Webhook, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -48,6 +57,9 @@ func (i *Inbox) GetWebhook(ctx context.Context, webhookID string) (*Webhook, err
 
 // UpdateWebhook updates a webhook for this inbox.
 func (i *Inbox) UpdateWebhook(ctx context.Context, webhookID string, opts ...WebhookUpdateOption) (*Webhook, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -63,6 +75,9 @@ func (i *Inbox) UpdateWebhook(ctx context.Context, webhookID string, opts ...Web
 
 // DeleteWebhook deletes a webhook from this inbox.
 func (i *Inbox) DeleteWebhook(ctx context.Context, webhookID string) error {
+	if err := i.checkAttached(); err != nil {
+		return err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return err
 	}
@@ -72,6 +87,9 @@ func (i *Inbox) DeleteWebhook(ctx context.Context, webhookID string) error {
 
 // TestWebhook sends a test request to a webhook.
 func (i *Inbox) TestWebhook(ctx context.Context, webhookID string) (*TestWebhookResponse, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -87,6 +105,9 @@ func (i *Inbox) TestWebhook(ctx context.Context, webhookID string) (*TestWebhook
 // RotateWebhookSecret rotates the signing secret for a webhook.
 // The previous secret remains valid for a grace period to allow for seamless rotation.
 func (i *Inbox) RotateWebhookSecret(ctx context.Context, webhookID string) (*RotateSecretResponse, error) {
+	if err := i.checkAttached(); err != nil {
+		return nil, err
+	}
 	if err := i.client.checkClosed(); err != nil {
 		return nil, err
 	}
@@ -1026,14 +1026,7 @@ func TestIntegration_EmailAddressFormat(t *testing.T) {
 
 	// Domain should be in allowed domains
 	serverInfo := client.ServerInfo()
-	found := false
-	for _, domain := range serverInfo.AllowedDomains {
-		if parts[1] == domain {
-			found = true
-			break
-		}
-	}
-	if !found {
+	if !serverInfo.IsDomainAllowed(parts[1]) {
 		t.Errorf("EmailAddress domain %q not in AllowedDomains %v", parts[1], serverInfo.AllowedDomains)
 	}
 }
@@ -1301,6 +1294,39 @@ func TestIntegration_WatchInboxes_ClosedClient(t *testing.T) {
 	cleanupClient.DeleteInbox(ctx, emailAddr)
 }
 
+// TestIntegration_ListInboxes tests that inboxes created by another client
+// are listed by the server.
+func TestIntegration_ListInboxes(t *testing.T) {
+	creator := newClient(t)
+	ctx := context.Background()
+
+	inbox, err := creator.CreateInbox(ctx, vaultsandbox.WithTTL(5*time.Minute))
+	if err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+	defer creator.DeleteInbox(ctx, inbox.EmailAddress())
+
+	lister := newClient(t)
+	summaries, err := lister.ListInboxes(ctx)
+	if err != nil {
+		t.Fatalf("ListInboxes() error = %v", err)
+	}
+
+	for _, s := range summaries {
+		if s.EmailAddress != inbox.EmailAddress() {
+			continue
+		}
+		if s.InboxHash != inbox.InboxHash() {
+			t.Errorf("InboxHash = %s, want %s", s.InboxHash, inbox.InboxHash())
+		}
+		if s.ExpiresAt.IsZero() {
+			t.Error("ExpiresAt is zero")
+		}
+		return
+	}
+	t.Errorf("ListInboxes() did not include %s", inbox.EmailAddress())
+}
+
 // TestIntegration_DeleteAllInboxes tests deleting all inboxes at once.
 func TestIntegration_DeleteAllInboxes(t *testing.T) {
 	t.Skip("Skipping: DeleteAllInboxes can interfere with other tests and services")
@@ -2378,3 +2404,33 @@ func TestIntegration_SyncInboxNewEmails_Automated(t *testing.T) {
 		}
 	}
 }
+
+func TestIntegration_ExtendTTL(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	inbox, err := client.CreateInbox(ctx, vaultsandbox.WithTTL(5*time.Minute))
+	if err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+	defer inbox.Delete(ctx)
+
+	before := inbox.ExpiresAt()
+	if err := inbox.ExtendTTL(ctx, 30*time.Minute); err != nil {
+		t.Fatalf("ExtendTTL() error = %v", err)
+	}
+	if !inbox.ExpiresAt().After(before) {
+		t.Errorf("ExpiresAt() = %v, want after %v", inbox.ExpiresAt(), before)
+	}
+
+	if err := inbox.ExtendTTL(ctx, 30*time.Second); err == nil {
+		t.Error("ExtendTTL() below minimum should fail")
+	}
+
+	if err := inbox.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := inbox.ExtendTTL(ctx, 30*time.Minute); !errors.Is(err, vaultsandbox.ErrInboxNotFound) {
+		t.Errorf("ExtendTTL() after delete error = %v, want ErrInboxNotFound", err)
+	}
+}
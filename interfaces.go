@@ -0,0 +1,54 @@
+package vaultsandbox
+
+import (
+	"context"
+	"time"
+)
+
+// ClientAPI is the core method set of [Client]: managing inboxes and the
+// client lifecycle. It is parameterized by the inbox type its methods
+// return, because Go has no covariant return types: *Client implements
+// ClientAPI[*Inbox], and the in-memory fake in the vaultsandboxtest package
+// implements ClientAPI[*vaultsandboxtest.Inbox]. Code that should run
+// against either takes a type parameter constrained by [InboxAPI]:
+//
+//	func setup[I vaultsandbox.InboxAPI](ctx context.Context, c vaultsandbox.ClientAPI[I]) (I, error) {
+//		return c.CreateInbox(ctx)
+//	}
+//
+// Methods may be added as the SDK grows; a mock that embeds the interface
+// keeps compiling.
+type ClientAPI[I InboxAPI] interface {
+	CreateInbox(ctx context.Context, opts ...InboxOption) (I, error)
+	ImportInbox(ctx context.Context, data *ExportedInbox) (I, error)
+	DeleteInbox(ctx context.Context, emailAddress string) error
+	DeleteAllInboxes(ctx context.Context) (int, error)
+	GetInbox(emailAddress string) (I, bool)
+	Inboxes() []I
+	ServerInfo() *ServerInfo
+	CheckKey(ctx context.Context) error
+	Close() error
+}
+
+// InboxAPI is the core method set of [Inbox]: reading, waiting for and
+// deleting emails. The in-memory fake in the vaultsandboxtest package
+// implements it, so code written against InboxAPI can be unit tested without
+// a server.
+type InboxAPI interface {
+	EmailAddress() string
+	InboxHash() string
+	ExpiresAt() time.Time
+	IsExpired() bool
+	GetEmails(ctx context.Context, opts ...FetchOption) ([]*Email, error)
+	GetEmail(ctx context.Context, emailID string, opts ...FetchOption) (*Email, error)
+	MarkEmailAsRead(ctx context.Context, emailID string) error
+	DeleteEmail(ctx context.Context, emailID string) error
+	Delete(ctx context.Context) error
+	WaitForEmail(ctx context.Context, opts ...WaitOption) (*Email, error)
+	WaitForEmailCount(ctx context.Context, count int, opts ...WaitOption) ([]*Email, error)
+}
+
+var (
+	_ ClientAPI[*Inbox] = (*Client)(nil)
+	_ InboxAPI          = (*Inbox)(nil)
+)
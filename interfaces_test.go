@@ -0,0 +1,25 @@
+package vaultsandbox
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConcreteTypesImplementInterfaces(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		concrete any
+		iface    reflect.Type
+	}{
+		{"Client", (*Client)(nil), reflect.TypeFor[ClientAPI[*Inbox]]()},
+		{"Inbox", (*Inbox)(nil), reflect.TypeFor[InboxAPI]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.TypeOf(tt.concrete).Implements(tt.iface) {
+				t.Errorf("*%s does not implement %s", tt.name, tt.iface)
+			}
+		})
+	}
+}
@@ -6,10 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/apierrors"
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/logging"
+	"github.com/vaultsandbox/client-go/internal/tracing"
+	"github.com/vaultsandbox/client-go/internal/version"
 )
 
 const (
@@ -34,10 +40,44 @@ type Client struct {
 	maxRetries int
 	// retryDelay is the base delay between retry attempts (doubles with each attempt).
 	retryDelay time.Duration
+	// retryJitter is the fraction by which each retry delay is randomly
+	// lengthened or shortened (0 = deterministic).
+	retryJitter float64
+	// randFloat returns a random number in [0, 1) for jitter.
+	randFloat func() float64
+	// maxElapsed bounds the total time of a request and its retries (0 = unbounded).
+	maxElapsed time.Duration
 	// retryOn contains HTTP status codes that trigger automatic retry.
 	retryOn []int
+	// retryDecider, if set, decides retries instead of retryOn.
+	retryDecider RetryDecider
+	// onRetry, if set, is called before each retry delay.
+	onRetry RetryHook
+	// logger receives request lifecycle records at debug level.
+	logger logging.Logger
+	// tracer starts a span for every request attempt.
+	tracer tracing.Tracer
+	// userAgent is sent as the User-Agent header on every request.
+	userAgent string
+	// clock times retry delays and rate-limit resets.
+	clock clock.Clock
+	// rateLimit and rateBurst configure limiter (rateLimit 0 = unlimited).
+	rateLimit float64
+	rateBurst int
+	// limiter, if set, paces every outbound request.
+	limiter *rateLimiter
 }
 
+// RetryHook observes a retry before its delay. attempt is the upcoming
+// retry number starting at 1, statusCode the status of the failed attempt
+// (0 for network errors), and err the error that caused the retry.
+type RetryHook func(attempt int, statusCode int, delay time.Duration, err error)
+
+// RetryDecider reports whether a request attempt should be retried. It is
+// called after every failed attempt with either the error response, whose
+// body holds at most its first 64 KiB, or the network error.
+type RetryDecider func(resp *http.Response, err error) bool
+
 // New creates a new API client using the functional options pattern.
 // The apiKey is required for authentication. Use [Option] functions like
 // [WithBaseURL], [WithTimeout], and [WithRetries] to customize behavior.
@@ -56,7 +96,12 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 		},
 		maxRetries: DefaultMaxRetries,
 		retryDelay: DefaultRetryDelay,
+		randFloat:  rand.Float64,
 		retryOn:    DefaultRetryOn,
+		logger:     logging.Nop{},
+		tracer:     tracing.Nop{},
+		userAgent:  version.UserAgent,
+		clock:      clock.Real{},
 	}
 
 	for _, opt := range opts {
@@ -66,6 +111,9 @@ func New(apiKey string, opts ...Option) (*Client, error) {
 	if c.baseURL == "" {
 		return nil, fmt.Errorf("base URL is required")
 	}
+	if c.rateLimit > 0 {
+		c.limiter = newRateLimiter(c.rateLimit, c.rateBurst, c.clock)
+	}
 
 	return c, nil
 }
@@ -101,6 +149,22 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithRetryJitter randomizes each retry delay by up to ±fraction of its
+// value. The caller validates that fraction is in [0, 1).
+func WithRetryJitter(fraction float64) Option {
+	return func(c *Client) {
+		c.retryJitter = fraction
+	}
+}
+
+// WithMaxElapsedTime bounds the total time of a request and its retries.
+// Zero means no bound.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxElapsed = d
+	}
+}
+
 // WithRetryOn sets the HTTP status codes that trigger a retry.
 func WithRetryOn(statusCodes []int) Option {
 	return func(c *Client) {
@@ -108,6 +172,68 @@ func WithRetryOn(statusCodes []int) Option {
 	}
 }
 
+// WithRetryDecider sets a function that decides retries in place of the
+// retryOn status codes.
+func WithRetryDecider(decider RetryDecider) Option {
+	return func(c *Client) {
+		c.retryDecider = decider
+	}
+}
+
+// WithOnRetry sets a hook called before each retry delay.
+func WithOnRetry(hook RetryHook) Option {
+	return func(c *Client) {
+		c.onRetry = hook
+	}
+}
+
+// WithLogger sets the logger that receives a debug record for every request
+// attempt and retry. A nil logger discards them.
+func WithLogger(logger logging.Logger) Option {
+	return func(c *Client) {
+		c.logger = logging.OrNop(logger)
+	}
+}
+
+// WithTracer sets the tracer that receives a span for every request attempt,
+// retries included. If the tracer implements [tracing.Injector], the span
+// context is also propagated in the request headers. A nil tracer disables
+// tracing.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracing.OrNop(tracer)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request. An empty
+// string keeps the default.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		if userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithClock sets the clock used for retry delays and rate-limit reset times.
+// A nil clock keeps the real one.
+func WithClock(c clock.Clock) Option {
+	return func(cl *Client) {
+		cl.clock = clock.OrReal(c)
+	}
+}
+
+// WithRateLimit paces outbound requests, retries and event-stream
+// connections included, to rps per second with bursts of up to burst
+// requests. A request waiting for its turn ends early if its context does.
+// A non-positive rps means no limit.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimit = rps
+		c.rateBurst = burst
+	}
+}
+
 // SetHTTPClient sets a custom HTTP client.
 func (c *Client) SetHTTPClient(client *http.Client) {
 	c.httpClient = client
@@ -132,7 +258,8 @@ func (c *Client) HTTPClient() *http.Client {
 //   - body: Request body to JSON-encode, or nil for no body.
 //   - result: Pointer to unmarshal the JSON response into, or nil to discard.
 //
-// The request includes X-API-Key, Content-Type, and Accept headers automatically.
+// The request includes X-API-Key, Content-Type, Accept, and User-Agent headers
+// automatically.
 // Retries are attempted with exponential backoff for status codes in retryOn.
 func (c *Client) Do(ctx context.Context, method, path string, body any, result any) error {
 	var bodyReader io.Reader
@@ -151,16 +278,50 @@ func (c *Client) Do(ctx context.Context, method, path string, body any, result a
 // It handles network errors, retryable status codes, error response parsing,
 // and successful response decoding. The body must be an io.Seeker if retries
 // are needed, as it will be reset between attempts.
+//
+// With maxElapsed set, no retry is started whose delay would overrun the
+// budget, and an attempt still in flight when it runs out is cancelled; the
+// last error is then returned wrapped in [apierrors.ErrMaxElapsedTime].
 func (c *Client) doWithRetry(ctx context.Context, method, path string, body io.Reader, result any) error {
 	var lastErr error
+	var lastStatus int
+
+	parent := ctx
+	var deadline time.Time
+	if c.maxElapsed > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, c.clock, c.maxElapsed)
+		defer cancel()
+		deadline = c.clock.Now().Add(c.maxElapsed)
+	}
+	// budgetExceeded wraps err once the budget, rather than the caller's
+	// context, has ended the operation.
+	budgetExceeded := func(err error) error {
+		return fmt.Errorf("%w after %v: %w", apierrors.ErrMaxElapsedTime, c.maxElapsed, err)
+	}
 
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		if attempt > 0 {
-			delay := c.retryDelay * time.Duration(1<<(attempt-1)) // Exponential backoff
+			delay := c.retryDelayFor(attempt)
+			if !deadline.IsZero() && c.clock.Now().Add(delay).After(deadline) {
+				c.logger.Debug("retry budget exhausted",
+					"method", method, "path", path, "attempt", attempt+1,
+					"status", lastStatus, "max_elapsed", c.maxElapsed, "error", lastErr)
+				return budgetExceeded(lastErr)
+			}
+			c.logger.Debug("retrying request",
+				"method", method, "path", path, "attempt", attempt+1,
+				"status", lastStatus, "delay", delay, "error", lastErr)
+			if c.onRetry != nil {
+				c.onRetry(attempt, lastStatus, delay, lastErr)
+			}
 			select {
 			case <-ctx.Done():
+				if parent.Err() == nil {
+					return budgetExceeded(lastErr)
+				}
 				return ctx.Err()
-			case <-time.After(delay):
+			case <-c.clock.After(delay):
 			}
 
 			// Reset body reader if needed
@@ -171,6 +332,13 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body io.R
 			}
 		}
 
+		if err := c.waitForToken(ctx); err != nil {
+			if parent.Err() == nil {
+				return budgetExceeded(err)
+			}
+			return err
+		}
+
 		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
 		if err != nil {
 			return fmt.Errorf("create request: %w", err)
@@ -179,23 +347,40 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body io.R
 		req.Header.Set("X-API-Key", c.apiKey)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
 
-		resp, err := c.httpClient.Do(req)
+		start := time.Now()
+		resp, err := c.send(req, path, attempt)
 		if err != nil {
+			c.logger.Debug("request failed",
+				"method", method, "path", path, "attempt", attempt+1,
+				"duration", time.Since(start), "error", err)
 			lastErr = &apierrors.NetworkError{Err: err}
+			lastStatus = 0
+			if ctx.Err() != nil && parent.Err() == nil {
+				return budgetExceeded(lastErr)
+			}
+			if c.retryDecider != nil && !c.retryDecider(nil, err) {
+				return lastErr
+			}
 			continue
 		}
 
-		// Check for retryable status codes
-		if c.isRetryable(resp.StatusCode) && attempt < c.maxRetries {
+		c.logger.Debug("request completed",
+			"method", method, "path", path, "attempt", attempt+1,
+			"status", resp.StatusCode, "duration", time.Since(start))
+
+		// Check for retryable responses
+		if c.shouldRetry(resp) && attempt < c.maxRetries {
 			lastErr = &apierrors.APIError{StatusCode: resp.StatusCode}
+			lastStatus = resp.StatusCode
 			resp.Body.Close()
 			continue
 		}
 
 		// Handle error responses
 		if resp.StatusCode >= 400 {
-			err := parseErrorResponse(resp)
+			err := c.parseErrorResponse(resp)
 			resp.Body.Close()
 			return err
 		}
@@ -221,6 +406,77 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body io.R
 	return lastErr
 }
 
+// waitForToken blocks until the rate limiter, if any, admits a request.
+func (c *Client) waitForToken(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.wait(ctx)
+}
+
+// send performs a single request attempt inside its own span. path is the
+// API path of req, recorded without the base URL.
+func (c *Client) send(req *http.Request, path string, attempt int) (*http.Response, error) {
+	ctx, span := c.tracer.Start(req.Context(), req.Method,
+		"http.request.method", req.Method,
+		"url.path", path,
+		"http.request.resend_count", attempt)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	if injector, ok := c.tracer.(tracing.Injector); ok {
+		injector.Inject(ctx, req.Header)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= 400 {
+		span.RecordError(&apierrors.APIError{StatusCode: resp.StatusCode})
+	}
+	return resp, nil
+}
+
+// maxDeciderBodyBytes caps how much of an error response body is buffered
+// for retryDecider.
+const maxDeciderBodyBytes = 64 << 10
+
+// shouldRetry reports whether resp should be retried, asking retryDecider if
+// set and falling back to retryOn. Successful responses are never retried.
+// For error responses the first maxDeciderBodyBytes of the body are buffered
+// so the decider can read them without consuming them for the caller.
+func (c *Client) shouldRetry(resp *http.Response) bool {
+	if c.retryDecider == nil {
+		return c.isRetryable(resp.StatusCode)
+	}
+	if resp.StatusCode < 400 {
+		return false
+	}
+	rest := resp.Body
+	body, readErr := io.ReadAll(io.LimitReader(rest, maxDeciderBodyBytes))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	retry := c.retryDecider(resp, readErr)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), rest), rest}
+	return retry
+}
+
+// retryDelayFor returns the delay before the given retry (starting at 1):
+// retryDelay doubled for each earlier retry, then jittered by up to
+// ±retryJitter.
+func (c *Client) retryDelayFor(attempt int) time.Duration {
+	delay := c.retryDelay * time.Duration(1<<(attempt-1)) // Exponential backoff
+	if c.retryJitter > 0 {
+		delay = time.Duration(float64(delay) * (1 + c.retryJitter*(2*c.randFloat()-1)))
+	}
+	return delay
+}
+
 // isRetryable checks if a status code should trigger a retry based on retryOn.
 func (c *Client) isRetryable(statusCode int) bool {
 	for _, code := range c.retryOn {
@@ -234,7 +490,19 @@ func (c *Client) isRetryable(statusCode int) bool {
 // parseErrorResponse extracts error information from an HTTP error response.
 // It attempts to parse a JSON error body with "error", "message", and "request_id"
 // fields. If parsing fails, the raw body is used as the error message.
-func parseErrorResponse(resp *http.Response) error {
+// A 429 response is returned as an [apierrors.RateLimitError] carrying the
+// back-off hints from its headers.
+func (c *Client) parseErrorResponse(resp *http.Response) error {
+	apiErr := parseAPIError(resp)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, resetAt := parseRateLimitHeaders(resp.Header, c.clock.Now())
+		return &apierrors.RateLimitError{APIError: apiErr, RetryAfter: retryAfter, ResetAt: resetAt}
+	}
+	return apiErr
+}
+
+// parseAPIError builds the *apierrors.APIError for an error response.
+func parseAPIError(resp *http.Response) *apierrors.APIError {
 	body, _ := io.ReadAll(resp.Body)
 
 	var errResp struct {
@@ -263,3 +531,36 @@ func parseErrorResponse(resp *http.Response) error {
 		Message:    string(body),
 	}
 }
+
+// parseRateLimitHeaders reads the back-off hints of a 429 response relative
+// to now. Retry-After may be delay-seconds or an HTTP date. RateLimit-Reset
+// is delay-seconds as in the IETF draft; X-RateLimit-Reset is a Unix
+// timestamp. When only one of the two values is present the other is
+// derived from it. Unparseable headers are ignored.
+func parseRateLimitHeaders(h http.Header, now time.Time) (retryAfter time.Duration, resetAt time.Time) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		} else if t, err := http.ParseTime(v); err == nil {
+			retryAfter = max(t.Sub(now), 0)
+		}
+	}
+
+	if v := h.Get("RateLimit-Reset"); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+			resetAt = now.Add(time.Duration(secs) * time.Second)
+		}
+	} else if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if unix, err := strconv.ParseInt(v, 10, 64); err == nil && unix > 0 {
+			resetAt = time.Unix(unix, 0)
+		}
+	}
+
+	switch {
+	case resetAt.IsZero() && retryAfter > 0:
+		resetAt = now.Add(retryAfter)
+	case retryAfter == 0 && !resetAt.IsZero():
+		retryAfter = max(resetAt.Sub(now), 0)
+	}
+	return retryAfter, resetAt
+}
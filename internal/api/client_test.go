@@ -3,15 +3,21 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/apierrors"
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/version"
 )
 
 func TestNew_RequiresAPIKey(t *testing.T) {
@@ -122,6 +128,39 @@ func TestClient_Do_Success(t *testing.T) {
 	}
 }
 
+func TestClient_Do_UserAgent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, version.UserAgent},
+		{"override", []Option{WithUserAgent("my-tests/1.0")}, "my-tests/1.0"},
+		{"empty keeps default", []Option{WithUserAgent("")}, version.UserAgent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("User-Agent")
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(server.Close)
+
+			client, _ := New("test-key", append([]Option{WithBaseURL(server.URL)}, tt.opts...)...)
+			if err := client.Do(context.Background(), "GET", "/test", nil, nil); err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_Do_WithBody(t *testing.T) {
 	t.Parallel()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -199,6 +238,241 @@ func TestClient_Do_Retry(t *testing.T) {
 	}
 }
 
+func TestClient_Do_RetryDelayUsesClock(t *testing.T) {
+	t.Parallel()
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+	}))
+	defer server.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client, _ := New("test-key", WithBaseURL(server.URL), WithRetries(3), WithClock(fake))
+	client.retryDelay = time.Hour
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Do(context.Background(), "GET", "/test", nil, nil)
+	}()
+
+	// Each retry waits on the fake clock; advancing it releases the retry
+	// without sleeping for an hour.
+	for _, delay := range []time.Duration{time.Hour, 2 * time.Hour} {
+		fake.BlockUntil(1)
+		fake.Advance(delay)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestClient_Do_MaxElapsedTimeStopsRetries(t *testing.T) {
+	t.Parallel()
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client, _ := New("test-key",
+		WithBaseURL(server.URL),
+		WithRetries(5),
+		WithClock(fake),
+		WithMaxElapsedTime(90*time.Minute),
+	)
+	client.retryDelay = time.Hour
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Do(context.Background(), "GET", "/test", nil, nil)
+	}()
+
+	// The first retry (1h) fits the 90m budget; the second (2h) would not.
+	fake.BlockUntil(2) // budget timer and first retry delay
+	fake.Advance(time.Hour)
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do() did not stop when the budget ran out")
+	}
+	if !errors.Is(err, apierrors.ErrMaxElapsedTime) {
+		t.Errorf("Do() error = %v, want ErrMaxElapsedTime", err)
+	}
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Do() error = %v, want it to wrap the last 503", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2 (retries remained but the budget did not)", got)
+	}
+}
+
+func TestClient_Do_MaxElapsedTimeCancelsAttempt(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key",
+		WithBaseURL(server.URL),
+		WithRetries(3),
+		WithMaxElapsedTime(50*time.Millisecond),
+	)
+
+	start := time.Now()
+	err := client.Do(context.Background(), "GET", "/test", nil, nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Do() took %v, want about 50ms", elapsed)
+	}
+	if !errors.Is(err, apierrors.ErrMaxElapsedTime) {
+		t.Errorf("Do() error = %v, want ErrMaxElapsedTime", err)
+	}
+	var netErr *apierrors.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("Do() error = %v, want it to wrap a NetworkError", err)
+	}
+}
+
+func TestClient_Do_MaxElapsedTimeParentCancel(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL), WithMaxElapsedTime(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := client.Do(ctx, "GET", "/test", nil, nil)
+	if errors.Is(err, apierrors.ErrMaxElapsedTime) {
+		t.Errorf("Do() error = %v; the caller's deadline should not be reported as the budget", err)
+	}
+}
+
+func TestClient_RetryDelayFor(t *testing.T) {
+	t.Parallel()
+	client, _ := New("test-key", WithBaseURL("https://example.com"))
+	client.retryDelay = time.Second
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		if got := client.retryDelayFor(attempt); got != want {
+			t.Errorf("retryDelayFor(%d) without jitter = %v, want %v", attempt, got, want)
+		}
+	}
+
+	const jitter = 0.25
+	client, _ = New("test-key", WithBaseURL("https://example.com"), WithRetryJitter(jitter))
+	client.retryDelay = time.Second
+	client.randFloat = rand.New(rand.NewPCG(1, 2)).Float64
+
+	var varied bool
+	for attempt := 1; attempt <= 3; attempt++ {
+		base := time.Second * time.Duration(1<<(attempt-1))
+		lo := time.Duration(float64(base) * (1 - jitter))
+		hi := time.Duration(float64(base) * (1 + jitter))
+		for range 50 {
+			got := client.retryDelayFor(attempt)
+			if got < lo || got > hi {
+				t.Fatalf("retryDelayFor(%d) = %v, want within [%v, %v]", attempt, got, lo, hi)
+			}
+			if got != base {
+				varied = true
+			}
+		}
+	}
+	if !varied {
+		t.Error("jittered delays never differed from the base delay")
+	}
+}
+
+func TestClient_Do_RateLimited(t *testing.T) {
+	t.Parallel()
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "42")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":"too many requests"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, _ := New("test-key", WithBaseURL(server.URL), WithRetries(2))
+	client.retryDelay = time.Millisecond
+
+	before := time.Now()
+	err := client.Do(context.Background(), "GET", "/test", nil, nil)
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if !errors.Is(err, apierrors.ErrRateLimited) {
+		t.Fatalf("Do() error = %v, want ErrRateLimited", err)
+	}
+	var rlErr *apierrors.RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("Do() error = %T, want *RateLimitError", err)
+	}
+	if rlErr.RetryAfter != 42*time.Second {
+		t.Errorf("RetryAfter = %v, want 42s", rlErr.RetryAfter)
+	}
+	if rlErr.ResetAt.Before(before.Add(42*time.Second)) || rlErr.ResetAt.After(time.Now().Add(42*time.Second)) {
+		t.Errorf("ResetAt = %v, want about 42s from now", rlErr.ResetAt)
+	}
+	if rlErr.Message != "too many requests" {
+		t.Errorf("Message = %q, want %q", rlErr.Message, "too many requests")
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		retryAfter time.Duration
+		resetAt    time.Time
+	}{
+		{"none", nil, 0, time.Time{}},
+		{"retry-after seconds", map[string]string{"Retry-After": "10"}, 10 * time.Second, now.Add(10 * time.Second)},
+		{"retry-after date", map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)}, time.Minute, now.Add(time.Minute)},
+		{"retry-after in the past", map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0, time.Time{}},
+		{"ratelimit-reset delta", map[string]string{"RateLimit-Reset": "20"}, 20 * time.Second, now.Add(20 * time.Second)},
+		{"x-ratelimit-reset unix", map[string]string{"X-RateLimit-Reset": fmt.Sprint(now.Add(5 * time.Second).Unix())}, 5 * time.Second, now.Add(5 * time.Second)},
+		{"both headers", map[string]string{"Retry-After": "3", "RateLimit-Reset": "60"}, 3 * time.Second, now.Add(60 * time.Second)},
+		{"garbage", map[string]string{"Retry-After": "soon", "X-RateLimit-Reset": "later"}, 0, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			retryAfter, resetAt := parseRateLimitHeaders(h, now)
+			if retryAfter != tt.retryAfter {
+				t.Errorf("retryAfter = %v, want %v", retryAfter, tt.retryAfter)
+			}
+			if !resetAt.Equal(tt.resetAt) {
+				t.Errorf("resetAt = %v, want %v", resetAt, tt.resetAt)
+			}
+		})
+	}
+}
+
 func TestClient_Do_NoRetryOn4xx(t *testing.T) {
 	t.Parallel()
 	var attempts int32
@@ -225,6 +499,268 @@ func TestClient_Do_NoRetryOn4xx(t *testing.T) {
 	}
 }
 
+func TestClient_Do_OnRetry(t *testing.T) {
+	t.Parallel()
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+	}))
+	defer server.Close()
+
+	type retry struct {
+		attempt int
+		status  int
+		delay   time.Duration
+	}
+	var mu sync.Mutex
+	var retries []retry
+	client, _ := New("test-key",
+		WithBaseURL(server.URL),
+		WithRetries(5),
+		WithOnRetry(func(attempt, statusCode int, delay time.Duration, err error) {
+			if err == nil {
+				t.Error("OnRetry called with nil error")
+			}
+			mu.Lock()
+			retries = append(retries, retry{attempt, statusCode, delay})
+			mu.Unlock()
+		}),
+	)
+	client.retryDelay = time.Millisecond
+
+	if err := client.Do(context.Background(), "GET", "/test", nil, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	want := []retry{
+		{1, http.StatusServiceUnavailable, time.Millisecond},
+		{2, http.StatusServiceUnavailable, 2 * time.Millisecond},
+		{3, http.StatusServiceUnavailable, 4 * time.Millisecond},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(retries) != len(want) {
+		t.Fatalf("OnRetry calls = %d, want %d", len(retries), len(want))
+	}
+	for i := range want {
+		if retries[i] != want[i] {
+			t.Errorf("retry %d = %+v, want %+v", i, retries[i], want[i])
+		}
+	}
+}
+
+// lineLogger is a logging.Logger that formats each record as
+// "level msg key=value ...".
+type lineLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *lineLogger) log(level, msg string, kv []any) {
+	line := level + " " + msg
+	for i := 0; i+1 < len(kv); i += 2 {
+		line += fmt.Sprintf(" %v=%v", kv[i], kv[i+1])
+	}
+	l.mu.Lock()
+	l.lines = append(l.lines, line)
+	l.mu.Unlock()
+}
+
+func (l *lineLogger) Debug(msg string, kv ...any) { l.log("debug", msg, kv) }
+func (l *lineLogger) Info(msg string, kv ...any)  { l.log("info", msg, kv) }
+func (l *lineLogger) Warn(msg string, kv ...any)  { l.log("warn", msg, kv) }
+func (l *lineLogger) Error(msg string, kv ...any) { l.log("error", msg, kv) }
+
+func TestClient_Do_Logger(t *testing.T) {
+	t.Parallel()
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+	}))
+	defer server.Close()
+
+	logger := &lineLogger{}
+	client, _ := New("test-key", WithBaseURL(server.URL), WithLogger(logger))
+	client.retryDelay = time.Millisecond
+
+	if err := client.Do(context.Background(), "GET", "/test", nil, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	wantPrefixes := []string{
+		"debug request completed method=GET path=/test attempt=1 status=503 duration=",
+		"debug retrying request method=GET path=/test attempt=2 status=503 delay=1ms error=",
+		"debug request completed method=GET path=/test attempt=2 status=200 duration=",
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if len(logger.lines) != len(wantPrefixes) {
+		t.Fatalf("logged %d lines, want %d: %q", len(logger.lines), len(wantPrefixes), logger.lines)
+	}
+	for i, want := range wantPrefixes {
+		if !strings.HasPrefix(logger.lines[i], want) {
+			t.Errorf("line %d = %q, want prefix %q", i, logger.lines[i], want)
+		}
+	}
+	for _, line := range logger.lines {
+		if strings.Contains(line, "test-key") {
+			t.Errorf("log line leaks the API key: %q", line)
+		}
+	}
+}
+
+func TestWithLogger_Nil(t *testing.T) {
+	t.Parallel()
+	client, _ := New("test-key", WithBaseURL("http://localhost"), WithLogger(nil))
+	if client.logger == nil {
+		t.Fatal("WithLogger(nil) left a nil logger")
+	}
+}
+
+func TestClient_Do_RetryDecider(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		decider      RetryDecider
+		wantAttempts int32
+		wantStatus   int
+		wantMessage  string
+	}{
+		{
+			name:   "forces retry on 400",
+			status: http.StatusBadRequest,
+			body:   `{"error":"try_again"}`,
+			decider: func(resp *http.Response, err error) bool {
+				b, _ := io.ReadAll(resp.Body)
+				return strings.Contains(string(b), "try_again")
+			},
+			wantAttempts: 3,
+			wantStatus:   http.StatusBadRequest,
+			wantMessage:  "try_again",
+		},
+		{
+			name:         "suppresses retry on 500",
+			status:       http.StatusInternalServerError,
+			body:         `{"error":"permanent"}`,
+			decider:      func(resp *http.Response, err error) bool { return false },
+			wantAttempts: 1,
+			wantStatus:   http.StatusInternalServerError,
+			wantMessage:  "permanent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			client, _ := New("test-key", WithBaseURL(server.URL), WithRetries(2), WithRetryDecider(tt.decider))
+			client.retryDelay = time.Millisecond
+
+			err := client.Do(context.Background(), "GET", "/test", nil, nil)
+			var apiErr *apierrors.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Fatalf("Do() error = %v, want APIError %d", err, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			// The decider reading the body must not hide it from the error.
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("error message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RetryDecider_BuffersOnlyErrorBodies(t *testing.T) {
+	t.Parallel()
+	var status atomic.Int32
+	large := strings.Repeat("x", 2*maxDeciderBodyBytes)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		if status.Load() == http.StatusOK {
+			w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		w.Write([]byte(large))
+	}))
+	t.Cleanup(server.Close)
+
+	var calls atomic.Int32
+	var seen atomic.Int64
+	client, _ := New("test-key", WithBaseURL(server.URL), WithRetries(0),
+		WithRetryDecider(func(resp *http.Response, err error) bool {
+			calls.Add(1)
+			b, _ := io.ReadAll(resp.Body)
+			seen.Store(int64(len(b)))
+			return false
+		}))
+
+	status.Store(http.StatusOK)
+	var result map[string]bool
+	if err := client.Do(context.Background(), "GET", "/test", nil, &result); err != nil || !result["ok"] {
+		t.Fatalf("Do() = %v, %v; want ok", result, err)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("decider calls for a success = %d, want 0", got)
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	err := client.Do(context.Background(), "GET", "/test", nil, nil)
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Do() error = %v, want APIError", err)
+	}
+	if got := seen.Load(); got != maxDeciderBodyBytes {
+		t.Errorf("decider read %d bytes, want %d", got, maxDeciderBodyBytes)
+	}
+	if len(apiErr.Message) != len(large) {
+		t.Errorf("error message has %d bytes, want the full %d", len(apiErr.Message), len(large))
+	}
+}
+
+func TestClient_Do_RetryDecider_NetworkError(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	client, _ := New("test-key",
+		WithBaseURL("http://127.0.0.1:1"),
+		WithRetries(3),
+		WithRetryDecider(func(resp *http.Response, err error) bool {
+			calls.Add(1)
+			return false
+		}),
+	)
+	client.retryDelay = time.Millisecond
+
+	err := client.Do(context.Background(), "GET", "/test", nil, nil)
+	var netErr *apierrors.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("Do() error = %v, want NetworkError", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("decider calls = %d, want 1", got)
+	}
+}
+
 func TestClient_Do_ContextCancellation(t *testing.T) {
 	t.Parallel()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -438,12 +974,7 @@ func TestWithHTTPClient(t *testing.T) {
 
 // Helper function to check if error is APIError
 func isAPIError(err error, target **apierrors.APIError) bool {
-	apiErr, ok := err.(*apierrors.APIError)
-	if ok {
-		*target = apiErr
-		return true
-	}
-	return false
+	return errors.As(err, target)
 }
 
 // ExampleNew demonstrates creating an API client with functional options.
@@ -462,6 +993,64 @@ func ExampleNew() {
 	// Output: Client created for: https://api.vaultsandbox.com
 }
 
+func TestPing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+	}{
+		{name: "healthy", status: http.StatusOK},
+		{name: "unavailable", status: http.StatusServiceUnavailable, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				if r.URL.Path != "/health" {
+					t.Errorf("path = %s, want /health", r.URL.Path)
+				}
+				if key := r.Header.Get("X-API-Key"); key != "" {
+					t.Errorf("X-API-Key = %q, want none", key)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+
+			client, _ := New("test-key", WithBaseURL(server.URL), WithRetries(3))
+			err := client.Ping(context.Background())
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("Ping() error = %v", err)
+				}
+			} else {
+				var apiErr *apierrors.APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+					t.Fatalf("Ping() error = %v, want APIError %d", err, tt.wantStatus)
+				}
+			}
+			if got := attempts.Load(); got != 1 {
+				t.Errorf("attempts = %d, want 1 (Ping is not retried)", got)
+			}
+		})
+	}
+}
+
+func TestPing_NetworkError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL))
+	err := client.Ping(context.Background())
+	var netErr *apierrors.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("Ping() error = %v, want NetworkError", err)
+	}
+}
+
 func TestCheckKey_Success(t *testing.T) {
 	t.Parallel()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -583,6 +1172,54 @@ func TestDeleteAllInboxes_Error(t *testing.T) {
 	}
 }
 
+func TestListInboxes_Success(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/inboxes" {
+			t.Errorf("path = %s, want /api/inboxes", r.URL.Path)
+		}
+		if r.Method != "GET" {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"inboxes":[{"emailAddress":"a@test.com","inboxHash":"h1","expiresAt":"2030-01-02T03:04:05Z","encrypted":true}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL))
+	inboxes, err := client.ListInboxes(context.Background())
+	if err != nil {
+		t.Fatalf("ListInboxes() error = %v", err)
+	}
+	if len(inboxes) != 1 {
+		t.Fatalf("len(inboxes) = %d, want 1", len(inboxes))
+	}
+	want := InboxListItem{
+		EmailAddress: "a@test.com",
+		InboxHash:    "h1",
+		ExpiresAt:    time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		Encrypted:    true,
+	}
+	if got := inboxes[0]; got.EmailAddress != want.EmailAddress || got.InboxHash != want.InboxHash ||
+		!got.ExpiresAt.Equal(want.ExpiresAt) || got.Encrypted != want.Encrypted {
+		t.Errorf("inboxes[0] = %+v, want %+v", got, want)
+	}
+}
+
+func TestListInboxes_Error(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "internal error"})
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL), WithRetries(0))
+	if _, err := client.ListInboxes(context.Background()); err == nil {
+		t.Fatal("ListInboxes() should return error for 500 response")
+	}
+}
+
 func TestDeleteInboxByEmail_Success(t *testing.T) {
 	t.Parallel()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +29,29 @@ func (c *Client) CheckKey(ctx context.Context) error {
 	return nil
 }
 
+// Ping checks that the gateway is reachable with a single GET /health
+// request. It sends no API key, so it does not count against the key's
+// quota, and it is never retried. A transport failure is returned as a
+// [apierrors.NetworkError] and a non-2xx status as an API error.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.send(req, "/health", 0)
+	if err != nil {
+		return &apierrors.NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.parseErrorResponse(resp)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
 // GetServerInfo retrieves the server configuration including supported
 // algorithms, TTL limits, and allowed email domains.
 func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
@@ -44,6 +69,22 @@ func (c *Client) DeleteInboxByEmail(ctx context.Context, emailAddress string) er
 	return apierrors.WithResourceType(c.Do(ctx, "DELETE", path, nil, nil), apierrors.ResourceInbox)
 }
 
+// ExtendInboxTTL resets an inbox's time-to-live to ttl from now and returns
+// the new expiry.
+func (c *Client) ExtendInboxTTL(ctx context.Context, emailAddress string, ttl time.Duration) (time.Time, error) {
+	path := fmt.Sprintf("/api/inboxes/%s/extend", url.PathEscape(emailAddress))
+	req := struct {
+		TTL int `json:"ttl"`
+	}{TTL: int(ttl.Seconds())}
+	var result struct {
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := c.Do(ctx, http.MethodPost, path, req, &result); err != nil {
+		return time.Time{}, apierrors.WithResourceType(err, apierrors.ResourceInbox)
+	}
+	return result.ExpiresAt, nil
+}
+
 // DeleteAllInboxes deletes all inboxes associated with the API key.
 // Returns the number of inboxes deleted.
 func (c *Client) DeleteAllInboxes(ctx context.Context) (int, error) {
@@ -56,6 +97,15 @@ func (c *Client) DeleteAllInboxes(ctx context.Context) (int, error) {
 	return result.Deleted, nil
 }
 
+// ListInboxes returns all inboxes associated with the API key.
+func (c *Client) ListInboxes(ctx context.Context) ([]InboxListItem, error) {
+	var result ListInboxesResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/inboxes", nil, &result); err != nil {
+		return nil, apierrors.WithResourceType(err, apierrors.ResourceInbox)
+	}
+	return result.Inboxes, nil
+}
+
 // GetInboxSync returns the sync status for an inbox, including the email
 // count and a hash that changes when emails are added or removed.
 func (c *Client) GetInboxSync(ctx context.Context, emailAddress string) (*SyncStatus, error) {
@@ -73,8 +123,15 @@ func (c *Client) GetInboxSync(ctx context.Context, emailAddress string) (*SyncSt
 //
 // This method uses a dedicated HTTP client without a timeout to support
 // long-lived SSE connections. Use the context for cancellation control.
-func (c *Client) OpenEventStream(ctx context.Context, inboxHashes []string) (*http.Response, error) {
+// A non-2xx response is closed and returned as an *APIError.
+//
+// A non-empty lastEventID is sent in the Last-Event-ID header so the server
+// can replay events after that one.
+func (c *Client) OpenEventStream(ctx context.Context, inboxHashes []string, lastEventID string) (*http.Response, error) {
 	path := fmt.Sprintf("/api/events?inboxes=%s", url.QueryEscape(strings.Join(inboxHashes, ",")))
+	if err := c.waitForToken(ctx); err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
 	if err != nil {
@@ -84,13 +141,25 @@ func (c *Client) OpenEventStream(ctx context.Context, inboxHashes []string) (*ht
 	req.Header.Set("X-API-Key", c.apiKey)
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", c.userAgent)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
 
 	// Clone transport from existing client, but disable timeout for SSE
 	sseClient := &http.Client{
 		Transport: c.httpClient.Transport,
 		Timeout:   0,
 	}
-	return sseClient.Do(req)
+	resp, err := sseClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, c.parseErrorResponse(resp)
+	}
+	return resp, nil
 }
 
 // CreateInboxParams contains parameters for creating an inbox.
@@ -192,14 +261,54 @@ type GetEmailsResponse struct {
 	Emails []*RawEmail
 }
 
+// ListEmailsParams contains optional query parameters for listing emails.
+type ListEmailsParams struct {
+	// IncludeContent requests full email content instead of metadata only.
+	IncludeContent bool
+	// Subject asks the server to return only emails whose subject matches.
+	// Only honored for plain inboxes; encrypted metadata is opaque to the server.
+	Subject string
+	// From asks the server to return only emails whose sender matches.
+	// Only honored for plain inboxes; encrypted metadata is opaque to the server.
+	From string
+	// Read, if set, asks the server to return only read (true) or unread
+	// (false) emails. The read state is never encrypted, so this works for
+	// all inboxes.
+	Read *bool
+}
+
 // GetEmails returns all emails in an inbox.
 // If includeContent is true, the server returns full email content.
 func (c *Client) GetEmails(ctx context.Context, emailAddress string, includeContent bool) (*GetEmailsResponse, error) {
+	return c.ListEmails(ctx, emailAddress, &ListEmailsParams{IncludeContent: includeContent})
+}
+
+// ListEmails returns the emails in an inbox, passing the given parameters
+// as query string filters. Servers that do not support a filter ignore it,
+// so callers should still filter the result locally.
+func (c *Client) ListEmails(ctx context.Context, emailAddress string, params *ListEmailsParams) (*GetEmailsResponse, error) {
 	var resp []*RawEmail
 	path := fmt.Sprintf("/api/inboxes/%s/emails", url.PathEscape(emailAddress))
-	if includeContent {
-		path += "?includeContent=true"
+
+	query := url.Values{}
+	if params != nil {
+		if params.IncludeContent {
+			query.Set("includeContent", "true")
+		}
+		if params.Subject != "" {
+			query.Set("subject", params.Subject)
+		}
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.Read != nil {
+			query.Set("read", strconv.FormatBool(*params.Read))
+		}
 	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
 	if err := c.Do(ctx, http.MethodGet, path, nil, &resp); err != nil {
 		// This endpoint can fail due to inbox not found
 		return nil, apierrors.WithResourceType(err, apierrors.ResourceInbox)
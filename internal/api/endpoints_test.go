@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/vaultsandbox/client-go/internal/apierrors"
 	"github.com/vaultsandbox/client-go/internal/crypto"
 )
 
@@ -97,7 +98,7 @@ func TestOpenEventStream_Success(t *testing.T) {
 	defer server.Close()
 
 	client, _ := New("test-key", WithBaseURL(server.URL))
-	resp, err := client.OpenEventStream(context.Background(), []string{"hash1", "hash2"})
+	resp, err := client.OpenEventStream(context.Background(), []string{"hash1", "hash2"}, "")
 	if err != nil {
 		t.Fatalf("OpenEventStream() error = %v", err)
 	}
@@ -108,11 +109,47 @@ func TestOpenEventStream_Success(t *testing.T) {
 	}
 }
 
+func TestOpenEventStream_LastEventID(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Last-Event-ID"); got != "evt-42" {
+			t.Errorf("Last-Event-ID = %q, want evt-42", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL))
+	resp, err := client.OpenEventStream(context.Background(), []string{"hash1"}, "evt-42")
+	if err != nil {
+		t.Fatalf("OpenEventStream() error = %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestOpenEventStream_StatusError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL))
+	resp, err := client.OpenEventStream(context.Background(), []string{"hash1"}, "")
+	if resp != nil {
+		t.Error("OpenEventStream() returned a response for a 401")
+	}
+	if !errors.Is(err, apierrors.ErrUnauthorized) {
+		t.Errorf("OpenEventStream() error = %v, want ErrUnauthorized", err)
+	}
+}
+
 func TestOpenEventStream_Error(t *testing.T) {
 	t.Parallel()
 	// Use invalid URL to trigger error
 	client, _ := New("test-key", WithBaseURL("http://invalid.invalid.invalid:99999"))
-	_, err := client.OpenEventStream(context.Background(), []string{"hash1"})
+	_, err := client.OpenEventStream(context.Background(), []string{"hash1"}, "")
 	if err == nil {
 		t.Fatal("OpenEventStream() should return error for invalid URL")
 	}
@@ -123,7 +160,7 @@ func TestOpenEventStream_RequestCreationError(t *testing.T) {
 	// Use a URL with invalid characters that will cause NewRequestWithContext to fail
 	// A URL containing a space character without encoding will cause url.Parse to fail
 	client, _ := New("test-key", WithBaseURL("http://example .com"))
-	_, err := client.OpenEventStream(context.Background(), []string{"hash1"})
+	_, err := client.OpenEventStream(context.Background(), []string{"hash1"}, "")
 	if err == nil {
 		t.Fatal("OpenEventStream() should return error for malformed URL")
 	}
@@ -303,6 +340,57 @@ func TestGetEmails_WithIncludeContent(t *testing.T) {
 	}
 }
 
+func TestListEmails_WithFilters(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("includeContent") != "true" {
+			t.Errorf("includeContent query = %s, want true", q.Get("includeContent"))
+		}
+		if q.Get("subject") != "Order #1" {
+			t.Errorf("subject query = %q, want %q", q.Get("subject"), "Order #1")
+		}
+		if q.Get("from") != "shop@example.com" {
+			t.Errorf("from query = %q, want %q", q.Get("from"), "shop@example.com")
+		}
+		if q.Get("read") != "false" {
+			t.Errorf("read query = %q, want %q", q.Get("read"), "false")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*RawEmail{})
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL))
+	_, err := client.ListEmails(context.Background(), "test@example.com", &ListEmailsParams{
+		IncludeContent: true,
+		Subject:        "Order #1",
+		From:           "shop@example.com",
+		Read:           new(bool),
+	})
+	if err != nil {
+		t.Fatalf("ListEmails() error = %v", err)
+	}
+}
+
+func TestListEmails_NoParams(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("query = %q, want empty", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*RawEmail{})
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL))
+	if _, err := client.ListEmails(context.Background(), "test@example.com", nil); err != nil {
+		t.Fatalf("ListEmails() error = %v", err)
+	}
+}
+
 func TestGetEmails_Error(t *testing.T) {
 	t.Parallel()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/clock"
+)
+
+// rateLimiter is a token bucket that paces outbound requests. It holds up to
+// burst tokens and refills at rps tokens per second; each request takes one.
+type rateLimiter struct {
+	rps   float64
+	burst float64
+	clock clock.Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter that starts with a full bucket. A burst
+// below 1 is raised to 1.
+func newRateLimiter(rps float64, burst int, c clock.Clock) *rateLimiter {
+	b := float64(max(burst, 1))
+	return &rateLimiter{rps: rps, burst: b, clock: clock.OrReal(c), tokens: b}
+}
+
+// wait takes a token, blocking until one is available. If ctx ends first,
+// the token is given back and ctx.Err() is returned.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.clock.Now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rps)
+	}
+	l.last = now
+	l.tokens--
+	// A negative balance is the wait, in tokens, for this caller's turn.
+	delay := time.Duration(-l.tokens / l.rps * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-l.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/clock"
+)
+
+func TestClient_Do_RateLimit(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// A burst of 2 goes out at once; the other 4 are paced 50ms apart.
+	client, _ := New("test-key", WithBaseURL(server.URL), WithRateLimit(20, 2))
+	const n = 6
+	minElapsed := (n - 2) * time.Second / 20
+
+	start := time.Now()
+	for range n {
+		if err := client.Do(context.Background(), "DELETE", "/test", nil, nil); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < minElapsed {
+		t.Errorf("%d requests took %v, want at least %v", n, elapsed, minElapsed)
+	}
+	if got := requests.Load(); got != n {
+		t.Errorf("server saw %d requests, want %d", got, n)
+	}
+}
+
+func TestClient_Do_RateLimitContextCancel(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, _ := New("test-key", WithBaseURL(server.URL), WithRateLimit(0.001, 1))
+	if err := client.Do(context.Background(), "GET", "/test", nil, nil); err != nil {
+		t.Fatalf("first Do() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.Do(ctx, "GET", "/test", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Do() took %v, want it to end with the context", elapsed)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestRateLimiter_CanceledWaitReturnsToken(t *testing.T) {
+	t.Parallel()
+	fake := clock.NewFake(time.Unix(0, 0))
+	l := newRateLimiter(1, 1, fake)
+
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wait() error = %v, want context.Canceled", err)
+	}
+
+	// One second refills one token; without the refund it would be owed to
+	// the canceled wait.
+	fake.Advance(time.Second)
+	done := make(chan error, 1)
+	go func() { done <- l.wait(context.Background()) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("wait() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("wait() blocked after the bucket refilled")
+	}
+}
@@ -57,6 +57,15 @@ type ServerInfo struct {
 	SpamAnalysisEnabled bool `json:"spamAnalysisEnabled"`
 	// ChaosEnabled indicates whether chaos engineering features are enabled on the server.
 	ChaosEnabled bool `json:"chaosEnabled"`
+	// SSEEnabled indicates whether the server supports the SSE event stream.
+	// Nil if the server does not advertise it.
+	SSEEnabled *bool `json:"sseEnabled,omitempty"`
+	// MaxAttachmentSize is the largest attachment the server accepts, in
+	// bytes. Zero if the server does not advertise a limit.
+	MaxAttachmentSize int64 `json:"maxAttachmentSize,omitempty"`
+	// SupportedAlgorithms lists the algorithms the server can use, beyond
+	// the single suite in Algs. Empty if the server does not advertise them.
+	SupportedAlgorithms []string `json:"supportedAlgorithms,omitempty"`
 }
 
 // SyncStatus represents the /api/inboxes/{email}/sync response used to check
@@ -68,6 +77,21 @@ type SyncStatus struct {
 	EmailsHash string `json:"emailsHash"`
 }
 
+// InboxListItem is one entry of the GET /api/inboxes response. It carries
+// no key material; secret keys exist only on the client that created the
+// inbox.
+type InboxListItem struct {
+	EmailAddress string    `json:"emailAddress"`
+	InboxHash    string    `json:"inboxHash"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	Encrypted    bool      `json:"encrypted"`
+}
+
+// ListInboxesResponse represents the GET /api/inboxes response.
+type ListInboxesResponse struct {
+	Inboxes []InboxListItem `json:"inboxes"`
+}
+
 // RawEmail represents an email from the API, either encrypted or plain.
 // Use IsEncrypted() to determine the format:
 //   - Encrypted: EncryptedMetadata and EncryptedParsed are set
@@ -95,6 +119,24 @@ type RawEmail struct {
 	// Parsed contains the Base64-encoded JSON email body and attachments.
 	// Only present when fetching full email details.
 	Parsed string `json:"parsed,omitempty"`
+
+	// ParsedEncoding is the content encoding applied to the parsed JSON
+	// before Base64 encoding (plain) or encryption (encrypted), for example
+	// "gzip". Empty means the JSON is not compressed. It is not signed, so
+	// for encrypted emails it is only a hint.
+	ParsedEncoding string `json:"parsedEncoding,omitempty"`
+
+	// ParsedReady reports whether the server finished processing the body.
+	// False means the parsed content may still be empty or incomplete. Nil
+	// when the server does not report readiness.
+	ParsedReady *bool `json:"parsedReady,omitempty"`
+}
+
+// BodyReady reports whether the email carries its fully processed body:
+// parsed content is present and the server did not flag it as unfinished.
+func (r *RawEmail) BodyReady() bool {
+	hasParsed := r.EncryptedParsed != nil || r.Parsed != ""
+	return hasParsed && (r.ParsedReady == nil || *r.ParsedReady)
 }
 
 // IsEncrypted returns true if the email is in encrypted format.
@@ -121,6 +163,9 @@ func (r *RawEmailSource) IsEncrypted() bool {
 // SSEEvent represents a server-sent event payload for real-time email notifications.
 // Use IsEncrypted() to determine the format.
 type SSEEvent struct {
+	// ID is the event ID from the SSE "id:" line, or empty if the server
+	// did not send one. It is not part of the JSON payload.
+	ID string `json:"-"`
 	// InboxID is the inbox that received the email.
 	InboxID string `json:"inboxId"`
 	// EmailID is the unique identifier of the new email.
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxWireLogBody is the maximum number of body bytes written per message.
+// Longer bodies are truncated with a marker noting the full size.
+const maxWireLogBody = 4096
+
+// redactedValue replaces sensitive values in wire logs.
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders are request headers whose values are never logged.
+var sensitiveHeaders = []string{"X-API-Key", "Authorization"}
+
+// sensitiveFieldPattern matches JSON string fields carrying key material.
+var sensitiveFieldPattern = regexp.MustCompile(`("(?:secretKey|serverSigPk|secret)"\s*:\s*)"[^"]*"`)
+
+// EnableWireLog makes the client write every HTTP request and response to w,
+// including method, URL, status, headers, and bodies. API keys and key
+// material are redacted and large bodies are truncated. Event-stream response
+// bodies are not captured, since they never end.
+//
+// The client's current *http.Client is copied rather than modified, so call
+// this after SetHTTPClient.
+func (c *Client) EnableWireLog(w io.Writer) {
+	if w == nil {
+		return
+	}
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	hc := *c.httpClient
+	hc.Transport = &wireLogTransport{next: next, w: w}
+	c.httpClient = &hc
+}
+
+// wireLogTransport is an http.RoundTripper that logs traffic to a writer.
+type wireLogTransport struct {
+	next http.RoundTripper
+	w    io.Writer
+	mu   sync.Mutex // Serializes writes so concurrent requests don't interleave.
+}
+
+// RoundTrip logs the request, forwards it, and logs the response.
+func (t *wireLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--> %s %s\n", req.Method, req.URL)
+	writeHeaders(&b, req.Header)
+	writeBody(&b, reqBody)
+
+	if err != nil {
+		fmt.Fprintf(&b, "<-- %s %s error after %v: %v\n\n", req.Method, req.URL, elapsed, err)
+		t.write(b.String())
+		return resp, err
+	}
+
+	fmt.Fprintf(&b, "<-- %s %s %s (%v)\n", resp.Status, req.Method, req.URL, elapsed)
+	writeHeaders(&b, resp.Header)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") && resp.Body != nil {
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		writeBody(&b, respBody)
+		if readErr != nil {
+			fmt.Fprintf(&b, "[body read error: %v]\n", readErr)
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(respBody), errReader{readErr}))
+		}
+	}
+	b.WriteString("\n")
+	t.write(b.String())
+
+	return resp, nil
+}
+
+func (t *wireLogTransport) write(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.w, s)
+}
+
+// writeHeaders writes headers in canonical form with sensitive values redacted.
+func writeHeaders(b *strings.Builder, h http.Header) {
+	redacted := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	redacted.Write(b)
+}
+
+// writeBody writes a redacted, truncated copy of body.
+func writeBody(b *strings.Builder, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	redacted := redactWireBody(body)
+	if len(redacted) > maxWireLogBody {
+		fmt.Fprintf(b, "%s... [truncated, %d bytes total]\n", redacted[:maxWireLogBody], len(body))
+		return
+	}
+	b.Write(redacted)
+	if redacted[len(redacted)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+}
+
+// redactWireBody replaces the values of key-material JSON fields
+// (secretKey, serverSigPk, secret) with a placeholder.
+func redactWireBody(body []byte) []byte {
+	return sensitiveFieldPattern.ReplaceAll(body, []byte(`$1"`+redactedValue+`"`))
+}
+
+// errReader returns a fixed error, preserving read failures for callers.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
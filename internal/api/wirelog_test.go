@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEnableWireLog_RedactsAndLogs(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{
+			"emailAddress": "test@example.com",
+			"serverSigPk":  "c2VydmVyLXNpZ25pbmcta2V5",
+		})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c, err := New("super-secret-api-key", WithBaseURL(server.URL), WithRetries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.EnableWireLog(&buf)
+
+	var result map[string]string
+	err = c.Do(context.Background(), http.MethodPost, "/api/inboxes", map[string]string{
+		"clientKpk": "public",
+		"secretKey": "c2VjcmV0LWtleS1tYXRlcmlhbA",
+	}, &result)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	// The response must still be decoded after being logged.
+	if result["emailAddress"] != "test@example.com" {
+		t.Errorf("emailAddress = %q, want test@example.com", result["emailAddress"])
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"--> POST " + server.URL + "/api/inboxes",
+		"<-- 201 Created POST",
+		"X-Api-Key: [REDACTED]",
+		`"secretKey":"[REDACTED]"`,
+		`"serverSigPk":"[REDACTED]"`,
+		`"clientKpk":"public"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("wire log missing %q\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"super-secret-api-key", "c2VjcmV0LWtleS1tYXRlcmlhbA", "c2VydmVyLXNpZ25pbmcta2V5"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("wire log leaked %q\n%s", secret, out)
+		}
+	}
+}
+
+func TestEnableWireLog_TruncatesLargeBodies(t *testing.T) {
+	t.Parallel()
+	large := strings.Repeat("x", maxWireLogBody*2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"data": large})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c, _ := New("key", WithBaseURL(server.URL))
+	c.EnableWireLog(&buf)
+
+	var result map[string]string
+	if err := c.Do(context.Background(), http.MethodGet, "/api/big", nil, &result); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if result["data"] != large {
+		t.Error("response body was altered by wire logging")
+	}
+	if !strings.Contains(buf.String(), "[truncated,") {
+		t.Error("large body was not truncated")
+	}
+	if buf.Len() > maxWireLogBody*2 {
+		t.Errorf("wire log length = %d, want < %d", buf.Len(), maxWireLogBody*2)
+	}
+}
+
+func TestEnableWireLog_DoesNotModifyHTTPClient(t *testing.T) {
+	t.Parallel()
+	custom := &http.Client{}
+	c, _ := New("key", WithBaseURL("http://example.com"), WithHTTPClient(custom))
+	c.EnableWireLog(&bytes.Buffer{})
+
+	if custom.Transport != nil {
+		t.Error("caller's http.Client was modified")
+	}
+	if c.HTTPClient() == custom {
+		t.Error("wire log should wrap a copy of the http.Client")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Sentinel errors for errors.Is() checks
@@ -21,6 +22,10 @@ var (
 	// ErrInboxNotFound is returned when an inbox is not found.
 	ErrInboxNotFound = errors.New("inbox not found")
 
+	// ErrInboxExpired is returned when an inbox has expired and been removed
+	// by the server, as opposed to never having existed.
+	ErrInboxExpired = errors.New("inbox expired")
+
 	// ErrEmailNotFound is returned when an email is not found.
 	ErrEmailNotFound = errors.New("email not found")
 
@@ -44,6 +49,10 @@ var (
 
 	// ErrChaosDisabled is returned when chaos is disabled globally on the server.
 	ErrChaosDisabled = errors.New("chaos is disabled on this server")
+
+	// ErrMaxElapsedTime is returned when a request and its retries exceed
+	// the total time budget.
+	ErrMaxElapsedTime = errors.New("max elapsed time exceeded")
 )
 
 // ResourceType indicates which type of resource an error relates to.
@@ -93,6 +102,10 @@ func (e *APIError) Is(target error) bool {
 		}
 		return false
 	case 404:
+		// Some servers answer 404 for a reaped inbox; the message says so.
+		if target == ErrInboxExpired {
+			return e.ResourceType != ResourceWebhook && strings.Contains(strings.ToLower(e.Message), "expired")
+		}
 		switch e.ResourceType {
 		case ResourceInbox:
 			return target == ErrInboxNotFound
@@ -105,6 +118,8 @@ func (e *APIError) Is(target error) bool {
 		}
 	case 409:
 		return target == ErrInboxAlreadyExists
+	case 410:
+		return target == ErrInboxExpired
 	case 429:
 		return target == ErrRateLimited
 	}
@@ -119,16 +134,57 @@ func WithResourceType(err error, rt ResourceType) error {
 	}
 	var apiErr *APIError
 	if errors.As(err, &apiErr) {
-		return &APIError{
+		typed := &APIError{
 			StatusCode:   apiErr.StatusCode,
 			Message:      apiErr.Message,
 			RequestID:    apiErr.RequestID,
 			ResourceType: rt,
 		}
+		var rlErr *RateLimitError
+		if errors.As(err, &rlErr) {
+			return &RateLimitError{APIError: typed, RetryAfter: rlErr.RetryAfter, ResetAt: rlErr.ResetAt}
+		}
+		return typed
 	}
 	return err
 }
 
+// RateLimitError is returned when the server keeps answering 429 Too Many
+// Requests after all retries are exhausted. It carries the server's
+// back-off hints so callers can wait before trying again.
+// errors.Is(err, ErrRateLimited) reports true, and errors.As exposes the
+// underlying *APIError.
+type RateLimitError struct {
+	*APIError
+
+	// RetryAfter is how long the server asked the client to wait, from the
+	// Retry-After header or derived from the reset time. Zero if the server
+	// sent no hint.
+	RetryAfter time.Duration
+
+	// ResetAt is when the rate limit window resets, from the RateLimit-Reset
+	// or X-RateLimit-Reset header or derived from RetryAfter. Zero if the
+	// server sent no hint.
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %v)", e.APIError.Error(), e.RetryAfter)
+	}
+	return e.APIError.Error()
+}
+
+// Is implements errors.Is; a RateLimitError always matches ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Unwrap returns the underlying *APIError.
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
 // NetworkError represents a network-level failure.
 type NetworkError struct {
 	Err error
@@ -148,13 +204,21 @@ func (e *NetworkError) Unwrap() error {
 type SignatureVerificationError struct {
 	Message       string
 	IsKeyMismatch bool
+	// Part names the email payload that failed verification: "metadata",
+	// "parsed", or "raw". It is empty when the payload was verified on its
+	// own, as by Inbox.VerifyEmailSignature.
+	Part string
 }
 
 func (e *SignatureVerificationError) Error() string {
+	kind := "signature verification failed"
 	if e.IsKeyMismatch {
-		return fmt.Sprintf("server key mismatch: %s", e.Message)
+		kind = "server key mismatch"
+	}
+	if e.Part != "" {
+		return fmt.Sprintf("%s in %s payload: %s", kind, e.Part, e.Message)
 	}
-	return fmt.Sprintf("signature verification failed: %s", e.Message)
+	return fmt.Sprintf("%s: %s", kind, e.Message)
 }
 
 // Is implements errors.Is for sentinel error matching.
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestAPIError_Error(t *testing.T) {
@@ -71,6 +72,36 @@ func TestAPIError_Is(t *testing.T) {
 			target:   ErrInboxNotFound,
 			expected: true,
 		},
+		{
+			name:     "410 matches ErrInboxExpired",
+			err:      &APIError{StatusCode: 410, ResourceType: ResourceInbox},
+			target:   ErrInboxExpired,
+			expected: true,
+		},
+		{
+			name:     "410 does not match ErrInboxNotFound",
+			err:      &APIError{StatusCode: 410, ResourceType: ResourceInbox},
+			target:   ErrInboxNotFound,
+			expected: false,
+		},
+		{
+			name:     "404 with expired message matches ErrInboxExpired",
+			err:      &APIError{StatusCode: 404, Message: "Inbox has expired", ResourceType: ResourceInbox},
+			target:   ErrInboxExpired,
+			expected: true,
+		},
+		{
+			name:     "404 with expired message still matches ErrInboxNotFound",
+			err:      &APIError{StatusCode: 404, Message: "Inbox has expired", ResourceType: ResourceInbox},
+			target:   ErrInboxNotFound,
+			expected: true,
+		},
+		{
+			name:     "404 without expired message does not match ErrInboxExpired",
+			err:      &APIError{StatusCode: 404, Message: "Inbox not found", ResourceType: ResourceInbox},
+			target:   ErrInboxExpired,
+			expected: false,
+		},
 		{
 			name:     "404 with inbox resource does not match ErrEmailNotFound",
 			err:      &APIError{StatusCode: 404, ResourceType: ResourceInbox},
@@ -203,6 +234,40 @@ func TestWithResourceType(t *testing.T) {
 	}
 }
 
+func TestRateLimitError(t *testing.T) {
+	t.Parallel()
+	resetAt := time.Unix(1700000000, 0)
+	err := &RateLimitError{
+		APIError:   &APIError{StatusCode: 429, Message: "slow down"},
+		RetryAfter: 30 * time.Second,
+		ResetAt:    resetAt,
+	}
+
+	if got, want := err.Error(), "API error 429: slow down (retry after 30s)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Error("errors.Is(err, ErrRateLimited) = false, want true")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 {
+		t.Errorf("errors.As(*APIError) = %v, want status 429", apiErr)
+	}
+
+	// Attaching a resource type must keep the back-off hints.
+	typed := WithResourceType(fmt.Errorf("wrapped: %w", err), ResourceInbox)
+	var rlErr *RateLimitError
+	if !errors.As(typed, &rlErr) {
+		t.Fatalf("WithResourceType dropped RateLimitError: %T", typed)
+	}
+	if rlErr.RetryAfter != 30*time.Second || !rlErr.ResetAt.Equal(resetAt) {
+		t.Errorf("RetryAfter, ResetAt = %v, %v; want 30s, %v", rlErr.RetryAfter, rlErr.ResetAt, resetAt)
+	}
+	if rlErr.ResourceType != ResourceInbox {
+		t.Errorf("ResourceType = %q, want %q", rlErr.ResourceType, ResourceInbox)
+	}
+}
+
 func TestNetworkError_Error(t *testing.T) {
 	t.Parallel()
 	underlying := fmt.Errorf("connection refused")
@@ -246,6 +311,16 @@ func TestSignatureVerificationError_Error(t *testing.T) {
 			err:      &SignatureVerificationError{Message: "unexpected server key", IsKeyMismatch: true},
 			expected: "server key mismatch: unexpected server key",
 		},
+		{
+			name:     "with part",
+			err:      &SignatureVerificationError{Message: "invalid signature", Part: "parsed"},
+			expected: "signature verification failed in parsed payload: invalid signature",
+		},
+		{
+			name:     "key mismatch with part",
+			err:      &SignatureVerificationError{Message: "unexpected server key", IsKeyMismatch: true, Part: "raw"},
+			expected: "server key mismatch in raw payload: unexpected server key",
+		},
 	}
 
 	for _, tt := range tests {
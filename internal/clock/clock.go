@@ -0,0 +1,75 @@
+// Package clock abstracts the passage of time so that expiry, timeout, and
+// backoff logic can be tested without sleeping.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and schedules wake-ups.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the Clock backed by the time package.
+type Real struct{}
+
+// Now returns time.Now().
+func (Real) Now() time.Time { return time.Now() }
+
+// After returns time.After(d).
+func (Real) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// OrReal returns c, or Real if c is nil.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
+
+// WithTimeout is like context.WithTimeout, but the timeout elapses on c.
+// For the real clock it is exactly context.WithTimeout. For other clocks the
+// returned context reports context.DeadlineExceeded once c has advanced by
+// d; contexts derived from it see context.Canceled instead.
+func WithTimeout(parent context.Context, c Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	c = OrReal(c)
+	if _, ok := c.(Real); ok {
+		return context.WithTimeout(parent, d)
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	tc := &timerCtx{Context: ctx, deadline: c.Now().Add(d)}
+	expired := c.After(d)
+	go func() {
+		select {
+		case <-expired:
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+		}
+	}()
+	return tc, func() { cancel(context.Canceled) }
+}
+
+// timerCtx is a context cancelled by a non-real clock's timer.
+type timerCtx struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c *timerCtx) Deadline() (time.Time, bool) {
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+func (c *timerCtx) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFake_AfterAndAdvance(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+
+	ch := f.After(time.Minute)
+	f.Advance(59 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("After fired before its time")
+	default:
+	}
+
+	f.Advance(time.Second)
+	select {
+	case got := <-ch:
+		if want := epoch.Add(time.Minute); !got.Equal(want) {
+			t.Errorf("After sent %v, want %v", got, want)
+		}
+	default:
+		t.Fatal("After did not fire")
+	}
+
+	if got := f.Now(); !got.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("Now() = %v, want %v", got, epoch.Add(time.Minute))
+	}
+}
+
+func TestFake_AfterNonPositiveFiresImmediately(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+	select {
+	case <-f.After(0):
+	default:
+		t.Fatal("After(0) did not fire immediately")
+	}
+}
+
+func TestWithTimeout_Fake(t *testing.T) {
+	t.Parallel()
+	f := NewFake(epoch)
+	ctx, cancel := WithTimeout(context.Background(), f, time.Hour)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("Deadline() = %v, %v; want %v, true", deadline, ok, epoch.Add(time.Hour))
+	}
+
+	f.BlockUntil(1)
+	f.Advance(time.Hour)
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want DeadlineExceeded", ctx.Err())
+	}
+}
+
+func TestWithTimeout_FakeCancel(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := WithTimeout(context.Background(), NewFake(epoch), time.Hour)
+	cancel()
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want Canceled", ctx.Err())
+	}
+}
+
+func TestWithTimeout_Real(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := WithTimeout(context.Background(), nil, time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want DeadlineExceeded", ctx.Err())
+	}
+}
@@ -2,6 +2,9 @@ package crypto
 
 import (
 	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
 )
 
 // Base64 encoding functions for cryptographic data.
@@ -71,3 +74,46 @@ func ToBase64(data []byte) string {
 func FromBase64(s string) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(s)
 }
+
+// DecodeBase64Limited decodes base64 data, refusing to produce more than
+// maxSize bytes. The encoding (standard or URL-safe, padded or not) is
+// detected from the input.
+//
+// The decoded size is first estimated from the encoded length so that
+// oversized input is rejected before any allocation. Otherwise the data is
+// decoded incrementally and decoding aborts as soon as maxSize is exceeded.
+// A maxSize of zero or less disables the limit.
+func DecodeBase64Limited(s string, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		return DecodeBase64(s)
+	}
+
+	var enc *base64.Encoding
+	urlSafe := strings.ContainsAny(s, "-_")
+	padded := strings.HasSuffix(s, "=")
+	switch {
+	case urlSafe && padded:
+		enc = base64.URLEncoding
+	case urlSafe:
+		enc = base64.RawURLEncoding
+	case padded:
+		enc = base64.StdEncoding
+	default:
+		enc = base64.RawStdEncoding
+	}
+
+	// DecodedLen over-estimates padded input by at most two bytes.
+	if enc.DecodedLen(len(s))-2 > maxSize {
+		return nil, fmt.Errorf("%w: encoded length %d exceeds limit of %d bytes", ErrAttachmentTooLarge, len(s), maxSize)
+	}
+
+	dec := base64.NewDecoder(enc, strings.NewReader(s))
+	data, err := io.ReadAll(io.LimitReader(dec, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("%w: exceeds limit of %d bytes", ErrAttachmentTooLarge, maxSize)
+	}
+	return data, nil
+}
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -335,3 +336,45 @@ func Example_base64Encoding() {
 	// Both decode to: Hello, World!
 	// Decoded match: true
 }
+
+func TestDecodeBase64Limited(t *testing.T) {
+	t.Parallel()
+	data := []byte{0xfb, 0xf0, 0x01, 0x02, 0x03, 0x04, 0x05}
+	tests := []struct {
+		name    string
+		encoded string
+		maxSize int
+		wantErr bool
+	}{
+		{"standard padded within limit", ToBase64(data), 7, false},
+		{"url raw within limit", ToBase64URL(data), 7, false},
+		{"no limit", ToBase64(data), 0, false},
+		{"exceeds limit by one", ToBase64(data), 6, true},
+		{"far exceeds limit", ToBase64(make([]byte, 4096)), 16, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeBase64Limited(tt.encoded, tt.maxSize)
+			if tt.wantErr {
+				if !errors.Is(err, ErrAttachmentTooLarge) {
+					t.Fatalf("DecodeBase64Limited() error = %v, want ErrAttachmentTooLarge", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeBase64Limited() error = %v", err)
+			}
+			if !bytes.Equal(decoded, data) {
+				t.Errorf("DecodeBase64Limited() = %v, want %v", decoded, data)
+			}
+		})
+	}
+}
+
+func TestDecodeBase64Limited_InvalidInput(t *testing.T) {
+	t.Parallel()
+	if _, err := DecodeBase64Limited("!!!not-base64!!!", 100); err == nil {
+		t.Error("DecodeBase64Limited() should fail for invalid input")
+	}
+}
@@ -29,6 +29,9 @@ const (
 	// PublicKeyOffset is the byte offset where the public key is embedded
 	// within an ML-KEM-768 secret key.
 	PublicKeyOffset = 1152
+	// PublicKeyHashOffset is the byte offset of the SHA3-256 hash of the
+	// embedded public key within an ML-KEM-768 secret key.
+	PublicKeyHashOffset = PublicKeyOffset + MLKEMPublicKeySize
 )
 
 // AlgsCiphersuite is the canonical string representation of the algorithm suite.
@@ -1,12 +1,14 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
 	"time"
 
 	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
@@ -22,6 +24,10 @@ type DecryptedMetadata struct {
 	// To is the primary recipient. Note: only one recipient is included in
 	// metadata; use DecryptedParsed.Headers for full recipient list.
 	To string `json:"to"`
+	// EnvelopeTo is the SMTP envelope recipient (RCPT TO), if reported by
+	// the server. For catch-all inboxes this is the sub-address the email
+	// was actually delivered to.
+	EnvelopeTo string `json:"envelopeTo,omitempty"`
 	// Subject is the email subject line.
 	Subject string `json:"subject"`
 	// ReceivedAt is the timestamp when the email was received (ISO 8601 format).
@@ -37,6 +43,9 @@ type DecryptedParsed struct {
 	HTML string `json:"html"`
 	// Headers contains the full email headers as key-value pairs.
 	Headers map[string]interface{} `json:"headers"`
+	// HeaderOrder lists the Headers keys in the order the server sent them,
+	// which follows the order the headers first occur in the message.
+	HeaderOrder []string `json:"-"`
 	// Attachments contains the email attachments with their content.
 	Attachments []DecryptedAttachment `json:"attachments"`
 	// Links contains URLs extracted from the email body.
@@ -56,6 +65,8 @@ type DecryptedEmail struct {
 	From string
 	// To contains all recipient email addresses.
 	To []string
+	// EnvelopeTo is the SMTP envelope recipient.
+	EnvelopeTo string
 	// Subject is the email subject line.
 	Subject string
 	// Text is the plain text body.
@@ -66,6 +77,11 @@ type DecryptedEmail struct {
 	ReceivedAt time.Time
 	// Headers contains email headers as string key-value pairs.
 	Headers map[string]string
+	// MultiHeaders contains headers that occurred more than once, with
+	// every value in order.
+	MultiHeaders map[string][]string
+	// HeaderOrder lists the Headers and MultiHeaders keys in message order.
+	HeaderOrder []string
 	// Attachments contains the email attachments.
 	Attachments []DecryptedAttachment
 	// Links contains URLs extracted from the email body.
@@ -136,6 +152,106 @@ func (b *Base64Bytes) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ParseDecryptedParsed unmarshals decrypted parsed content JSON.
+//
+// Attachment content is decoded with [DecodeBase64Limited] so that a crafted
+// payload cannot expand past maxAttachmentSize bytes per attachment. An
+// attachment whose declared size already exceeds the limit is rejected
+// without decoding its content. A maxAttachmentSize of zero or less disables
+// the limit.
+func ParseDecryptedParsed(data []byte, maxAttachmentSize int) (*DecryptedParsed, error) {
+	if maxAttachmentSize <= 0 {
+		var aux struct {
+			DecryptedParsed
+			Headers json.RawMessage `json:"headers"`
+		}
+		if err := json.Unmarshal(data, &aux); err != nil {
+			return nil, err
+		}
+		parsed := aux.DecryptedParsed
+		if err := parsed.setHeaders(aux.Headers); err != nil {
+			return nil, err
+		}
+		return &parsed, nil
+	}
+
+	// Defer attachment decoding by shadowing Content with the raw JSON value.
+	type rawAttachment struct {
+		DecryptedAttachment
+		Content json.RawMessage `json:"content,omitempty"`
+	}
+	var aux struct {
+		DecryptedParsed
+		Headers     json.RawMessage `json:"headers"`
+		Attachments []rawAttachment `json:"attachments"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return nil, err
+	}
+
+	parsed := aux.DecryptedParsed
+	if err := parsed.setHeaders(aux.Headers); err != nil {
+		return nil, err
+	}
+	parsed.Attachments = nil
+	if aux.Attachments != nil {
+		parsed.Attachments = make([]DecryptedAttachment, len(aux.Attachments))
+	}
+	for i, a := range aux.Attachments {
+		att := a.DecryptedAttachment
+		if att.Size > maxAttachmentSize {
+			return nil, fmt.Errorf("%w: %q declares %d bytes, limit is %d", ErrAttachmentTooLarge, att.Filename, att.Size, maxAttachmentSize)
+		}
+
+		var encoded string
+		if len(a.Content) > 0 && string(a.Content) != "null" {
+			if err := json.Unmarshal(a.Content, &encoded); err != nil {
+				return nil, fmt.Errorf("attachment %q content: %w", att.Filename, err)
+			}
+		}
+		if encoded != "" {
+			content, err := DecodeBase64Limited(encoded, maxAttachmentSize)
+			if err != nil {
+				return nil, fmt.Errorf("attachment %q: %w", att.Filename, err)
+			}
+			att.Content = content
+		}
+		parsed.Attachments[i] = att
+	}
+
+	return &parsed, nil
+}
+
+// setHeaders decodes the raw headers object into Headers and records its key
+// order in HeaderOrder. A missing or null object leaves both nil.
+func (p *DecryptedParsed) setHeaders(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &p.Headers); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+		if key := tok.(string); !slices.Contains(p.HeaderOrder, key) {
+			p.HeaderOrder = append(p.HeaderOrder, key)
+		}
+	}
+	return nil
+}
+
 // Decrypt decrypts an encrypted payload using the provided keypair.
 //
 // The decryption process:
@@ -6,6 +6,8 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
@@ -469,3 +471,83 @@ func BenchmarkDeriveKey(b *testing.B) {
 		_, _ = DeriveKey(secret, salt, info, 32)
 	}
 }
+
+func TestParseDecryptedParsed_WithinLimit(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"text":"hi","attachments":[{"filename":"a.txt","size":5,"content":"` + ToBase64([]byte("hello")) + `"}]}`)
+
+	parsed, err := ParseDecryptedParsed(data, 5)
+	if err != nil {
+		t.Fatalf("ParseDecryptedParsed() error = %v", err)
+	}
+	if parsed.Text != "hi" {
+		t.Errorf("Text = %q, want %q", parsed.Text, "hi")
+	}
+	if len(parsed.Attachments) != 1 || string(parsed.Attachments[0].Content) != "hello" {
+		t.Fatalf("Attachments = %+v, want one attachment with content hello", parsed.Attachments)
+	}
+	if parsed.Attachments[0].Filename != "a.txt" || parsed.Attachments[0].Size != 5 {
+		t.Errorf("attachment metadata not preserved: %+v", parsed.Attachments[0])
+	}
+}
+
+func TestParseDecryptedParsed_DeclaredSizeTooLarge(t *testing.T) {
+	t.Parallel()
+	// The declared size is checked before the content is decoded at all,
+	// so even undecodable content is never touched.
+	data := []byte(`{"attachments":[{"filename":"huge.bin","size":1099511627776,"content":"!!!"}]}`)
+
+	_, err := ParseDecryptedParsed(data, 1024)
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("ParseDecryptedParsed() error = %v, want ErrAttachmentTooLarge", err)
+	}
+}
+
+func TestParseDecryptedParsed_ContentTooLarge(t *testing.T) {
+	t.Parallel()
+	// Size is under-declared; the encoded length alone exceeds the limit.
+	content := ToBase64(make([]byte, 64*1024))
+	data := []byte(`{"attachments":[{"filename":"lie.bin","size":10,"content":"` + content + `"}]}`)
+
+	_, err := ParseDecryptedParsed(data, 1024)
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("ParseDecryptedParsed() error = %v, want ErrAttachmentTooLarge", err)
+	}
+}
+
+func TestParseDecryptedParsed_NoLimit(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"attachments":[{"filename":"a.txt","size":5,"content":"` + ToBase64([]byte("hello")) + `"}]}`)
+
+	parsed, err := ParseDecryptedParsed(data, 0)
+	if err != nil {
+		t.Fatalf("ParseDecryptedParsed() error = %v", err)
+	}
+	if string(parsed.Attachments[0].Content) != "hello" {
+		t.Errorf("Content = %q, want hello", parsed.Attachments[0].Content)
+	}
+}
+
+func TestParseDecryptedParsed_HeaderOrder(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"headers":{"X-Tag":"one","Received":["a","b"],"x-tag":"two","Subject":"hi"}}`)
+	want := []string{"X-Tag", "Received", "x-tag", "Subject"}
+
+	for _, limit := range []int{0, 1024} {
+		parsed, err := ParseDecryptedParsed(data, limit)
+		if err != nil {
+			t.Fatalf("ParseDecryptedParsed(limit %d) error = %v", limit, err)
+		}
+		if !slices.Equal(parsed.HeaderOrder, want) {
+			t.Errorf("limit %d: HeaderOrder = %v, want %v", limit, parsed.HeaderOrder, want)
+		}
+		if parsed.Headers["x-tag"] != "two" || len(parsed.Headers) != 4 {
+			t.Errorf("limit %d: Headers = %v", limit, parsed.Headers)
+		}
+	}
+
+	parsed, err := ParseDecryptedParsed([]byte(`{"headers":null}`), 0)
+	if err != nil || parsed.Headers != nil || parsed.HeaderOrder != nil {
+		t.Errorf("null headers: parsed = %+v, err = %v; want no headers", parsed, err)
+	}
+}
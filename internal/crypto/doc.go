@@ -35,7 +35,7 @@
 // Always use [VerifySignature] before [Decrypt], passing the server's public key
 // that was pinned at inbox creation time:
 //
-//	if err := crypto.VerifySignature(payload, inbox.serverSigPk); err != nil {
+//	if err := crypto.VerifySignature(payload, pinnedServerPk); err != nil {
 //	    return nil, fmt.Errorf("signature verification failed: %w", err)
 //	}
 //	plaintext, err := crypto.Decrypt(payload, keypair)
@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
+	"github.com/cloudflare/circl/sign/mldsa/mldsa65"
+)
+
+// GenerateSigningKey generates an ML-DSA-65 key pair of the kind the server
+// signs payloads with, returned in packed form.
+func GenerateSigningKey() (publicKey, privateKey []byte, err error) {
+	pub, priv, err := mldsa65.GenerateKey(randReader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate signing key: %w", err)
+	}
+	publicKey, err = pub.MarshalBinary()
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal signing public key: %w", err) //coverage:ignore
+	}
+	privateKey, err = priv.MarshalBinary()
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal signing private key: %w", err) //coverage:ignore
+	}
+	return publicKey, privateKey, nil
+}
+
+// Encrypt produces a signed payload for the holder of the ML-KEM-768 secret
+// key matching recipientPubKey, the way the server encrypts emails:
+//  1. ML-KEM-768 encapsulation to the recipient's public key
+//  2. HKDF-SHA-512 key derivation as in [Decrypt]
+//  3. AES-256-GCM encryption with a random nonce and aad
+//  4. ML-DSA-65 signature over the transcript with serverPriv
+//
+// serverPriv is a packed ML-DSA-65 private key as returned by
+// [GenerateSigningKey]. The result is accepted by [VerifySignature] with the
+// matching public key and by [Decrypt] with the recipient's keypair.
+func Encrypt(plaintext, recipientPubKey, serverPriv, aad []byte) (*EncryptedPayload, error) {
+	var recipient mlkem768.PublicKey
+	if err := recipient.Unpack(recipientPubKey); err != nil {
+		return nil, fmt.Errorf("unmarshal recipient public key: %w", err)
+	}
+
+	var signer mldsa65.PrivateKey
+	if err := signer.UnmarshalBinary(serverPriv); err != nil {
+		return nil, fmt.Errorf("unmarshal server private key: %w", err)
+	}
+	serverSigPk, err := signer.Public().(*mldsa65.PublicKey).MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("marshal server public key: %w", err) //coverage:ignore
+	}
+
+	// 1. KEM Encapsulation
+	ctKem := make([]byte, MLKEMCiphertextSize)
+	sharedSecret := make([]byte, MLKEMSharedKeySize)
+	recipient.EncapsulateTo(ctKem, sharedSecret, nil)
+
+	// 2. Key Derivation (HKDF-SHA-512)
+	aesKey := deriveKey(sharedSecret, aad, ctKem)
+
+	// 3. AES-256-GCM Encryption
+	nonce := make([]byte, AESNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err) //coverage:ignore
+	}
+	block, err := newCipher(aesKey)
+	if err != nil {
+		return nil, err //coverage:ignore
+	}
+	aesGCM, err := newGCM(block)
+	if err != nil {
+		return nil, err //coverage:ignore
+	}
+	ciphertext := aesGCM.Seal(nil, nonce, plaintext, aad)
+
+	// 4. Signature over the transcript
+	payload := &EncryptedPayload{
+		V:           ProtocolVersion,
+		Algs:        DefaultSuite,
+		CtKem:       ToBase64URL(ctKem),
+		Nonce:       ToBase64URL(nonce),
+		AAD:         ToBase64URL(aad),
+		Ciphertext:  ToBase64URL(ciphertext),
+		ServerSigPk: ToBase64URL(serverSigPk),
+	}
+	transcript := buildTranscript(payload.V, payload.Algs, ctKem, nonce, aad, ciphertext, serverSigPk)
+	sig := make([]byte, MLDSASignatureSize)
+	if err := mldsa65.SignTo(&signer, transcript, nil, false, sig); err != nil {
+		return nil, fmt.Errorf("sign payload: %w", err) //coverage:ignore
+	}
+	payload.Sig = ToBase64URL(sig)
+
+	return payload, nil
+}
@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	t.Parallel()
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverPk, serverPriv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		plaintext []byte
+		aad       []byte
+	}{
+		{"json", []byte(`{"subject":"Hello"}`), []byte("aad")},
+		{"empty plaintext", []byte{}, []byte("aad")},
+		{"no aad", []byte("no additional data"), nil},
+		{"large plaintext", bytes.Repeat([]byte("0123456789abcdef"), 64<<10), []byte("inbox-hash")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			payload, err := Encrypt(tt.plaintext, kp.PublicKey, serverPriv, tt.aad)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+
+			if err := ValidatePayload(payload); err != nil {
+				t.Errorf("ValidatePayload() error = %v", err)
+			}
+			if err := VerifySignature(payload, serverPk); err != nil {
+				t.Fatalf("VerifySignature() error = %v", err)
+			}
+			got, err := Decrypt(payload, kp)
+			if err != nil {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.plaintext) {
+				t.Errorf("Decrypt() returned %d bytes, want %d", len(got), len(tt.plaintext))
+			}
+		})
+	}
+}
+
+func TestEncrypt_FreshEncapsulation(t *testing.T) {
+	t.Parallel()
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, serverPriv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := Encrypt([]byte("same"), kp.PublicKey, serverPriv, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Encrypt([]byte("same"), kp.PublicKey, serverPriv, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.CtKem == second.CtKem || first.Nonce == second.Nonce {
+		t.Error("two encryptions share a KEM ciphertext or nonce")
+	}
+}
+
+func TestEncrypt_InvalidKeys(t *testing.T) {
+	t.Parallel()
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, serverPriv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Encrypt([]byte("x"), []byte("short"), serverPriv, nil); err == nil {
+		t.Error("Encrypt() with invalid recipient key: expected error")
+	}
+	if _, err := Encrypt([]byte("x"), kp.PublicKey, []byte("short"), nil); err == nil {
+		t.Error("Encrypt() with invalid server key: expected error")
+	}
+}
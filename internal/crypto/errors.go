@@ -9,6 +9,10 @@ var (
 	// ErrInvalidPublicKeySize is returned when the public key size is invalid.
 	ErrInvalidPublicKeySize = errors.New("invalid public key size")
 
+	// ErrPublicKeyMismatch is returned when the public key embedded in a
+	// secret key does not match the hash of it stored alongside.
+	ErrPublicKeyMismatch = errors.New("embedded public key does not match its hash")
+
 	// ErrInvalidCiphertextSize is returned when the ciphertext size is invalid.
 	ErrInvalidCiphertextSize = errors.New("invalid ciphertext size")
 
@@ -38,4 +42,16 @@ var (
 
 	// ErrInvalidSize is returned when a decoded field has an incorrect size.
 	ErrInvalidSize = errors.New("invalid size")
+
+	// ErrUnexpectedSuite is returned when a payload's algorithm suite differs
+	// from the suite an inbox requires.
+	ErrUnexpectedSuite = errors.New("unexpected algorithm suite")
+
+	// ErrUnexpectedAAD is returned when a payload's associated data differs
+	// from the associated data an inbox expects.
+	ErrUnexpectedAAD = errors.New("unexpected associated data")
+
+	// ErrAttachmentTooLarge is returned when attachment content exceeds
+	// the configured maximum size.
+	ErrAttachmentTooLarge = errors.New("attachment too large")
 )
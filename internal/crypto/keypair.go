@@ -1,6 +1,9 @@
 package crypto
 
 import (
+	"crypto/sha3"
+	"crypto/subtle"
+	"fmt"
 	"io"
 
 	"github.com/cloudflare/circl/kem/mlkem/mlkem768"
@@ -54,6 +57,16 @@ func KeypairFromSecretKey(secretKey []byte) (*Keypair, error) {
 	}, nil
 }
 
+// KeypairFromSecretKeyB64 reconstructs a keypair from a base64url-encoded
+// secret key, the form in which inbox exports store it.
+func KeypairFromSecretKeyB64(b64 string) (*Keypair, error) {
+	secretKey, err := FromBase64URL(b64)
+	if err != nil {
+		return nil, fmt.Errorf("decode secret key: %w", err)
+	}
+	return KeypairFromSecretKey(secretKey)
+}
+
 // NewKeypairFromBytes creates a keypair from raw bytes.
 func NewKeypairFromBytes(privateKeyBytes, publicKeyBytes []byte) (*Keypair, error) {
 	if len(privateKeyBytes) != MLKEMSecretKeySize {
@@ -129,6 +142,22 @@ func DerivePublicKeyFromSecret(secretKey []byte) ([]byte, error) {
 	return publicKey, nil
 }
 
+// CheckSecretKey checks that the public key embedded in an ML-KEM-768 secret
+// key matches the SHA3-256 hash of it that the secret key also stores, which
+// detects truncated, corrupted, or spliced keys. It returns
+// ErrInvalidSecretKeySize or ErrPublicKeyMismatch.
+func CheckSecretKey(secretKey []byte) error {
+	publicKey, err := DerivePublicKeyFromSecret(secretKey)
+	if err != nil {
+		return err
+	}
+	hash := sha3.Sum256(publicKey)
+	if subtle.ConstantTimeCompare(hash[:], secretKey[PublicKeyHashOffset:PublicKeyHashOffset+len(hash)]) != 1 {
+		return ErrPublicKeyMismatch
+	}
+	return nil
+}
+
 // Decapsulate decapsulates a shared secret from the encapsulated key.
 func (k *Keypair) Decapsulate(encapsulatedKey []byte) ([]byte, error) {
 	if len(encapsulatedKey) != MLKEMCiphertextSize {
@@ -111,6 +111,32 @@ func TestKeypairFromSecretKey(t *testing.T) {
 	}
 }
 
+func TestKeypairFromSecretKeyB64(t *testing.T) {
+	t.Parallel()
+	original, err := GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+
+	reconstructed, err := KeypairFromSecretKeyB64(ToBase64URL(original.SecretKey))
+	if err != nil {
+		t.Fatalf("KeypairFromSecretKeyB64() error = %v", err)
+	}
+	if !bytes.Equal(original.PublicKey, reconstructed.PublicKey) {
+		t.Error("Reconstructed public key does not match original")
+	}
+	if original.PublicKeyB64 != reconstructed.PublicKeyB64 {
+		t.Errorf("PublicKeyB64 mismatch: got %s, want %s", reconstructed.PublicKeyB64, original.PublicKeyB64)
+	}
+
+	if _, err := KeypairFromSecretKeyB64("!!!not-base64!!!"); err == nil {
+		t.Error("KeypairFromSecretKeyB64() with invalid base64: expected error")
+	}
+	if _, err := KeypairFromSecretKeyB64(ToBase64URL([]byte("short"))); !errors.Is(err, ErrInvalidSecretKeySize) {
+		t.Errorf("KeypairFromSecretKeyB64() with short key error = %v, want ErrInvalidSecretKeySize", err)
+	}
+}
+
 func TestKeypairFromSecretKey_InvalidSize(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -190,6 +216,46 @@ func TestNewKeypairFromBytes_InvalidPrivateKeyBytes(t *testing.T) {
 }
 
 
+func TestCheckSecretKey(t *testing.T) {
+	t.Parallel()
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+	other, err := GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+
+	corrupt := func(offset int) []byte {
+		key := bytes.Clone(kp.SecretKey)
+		key[offset] ^= 0xff
+		return key
+	}
+	spliced := bytes.Clone(kp.SecretKey)
+	copy(spliced[PublicKeyOffset:], other.PublicKey)
+
+	tests := []struct {
+		name    string
+		key     []byte
+		wantErr error
+	}{
+		{"valid", kp.SecretKey, nil},
+		{"corrupt public key", corrupt(PublicKeyOffset + 10), ErrPublicKeyMismatch},
+		{"corrupt public key hash", corrupt(PublicKeyHashOffset), ErrPublicKeyMismatch},
+		{"spliced public key", spliced, ErrPublicKeyMismatch},
+		{"truncated", kp.SecretKey[:MLKEMSecretKeySize-1], ErrInvalidSecretKeySize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := CheckSecretKey(tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckSecretKey() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestKeypair_Decapsulate(t *testing.T) {
 	t.Parallel()
 	kp, err := GenerateKeypair()
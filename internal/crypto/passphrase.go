@@ -0,0 +1,23 @@
+package crypto
+
+import "golang.org/x/crypto/argon2"
+
+// Default Argon2id parameters for passphrase-derived keys, following the
+// second recommended option of RFC 9106 (64 MiB, 3 passes).
+const (
+	// Argon2Time is the number of passes over the memory.
+	Argon2Time = 3
+	// Argon2Memory is the memory cost in KiB.
+	Argon2Memory = 64 * 1024
+	// Argon2Threads is the degree of parallelism.
+	Argon2Threads = 4
+	// Argon2SaltSize is the size of a random salt in bytes.
+	Argon2SaltSize = 16
+)
+
+// DeriveKeyFromPassphrase derives an AES-256 key from passphrase and salt
+// using Argon2id with the given cost parameters. The same inputs always
+// produce the same key.
+func DeriveKeyFromPassphrase(passphrase string, salt []byte, time, memory uint32, threads uint8) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, time, memory, threads, AESKeySize)
+}
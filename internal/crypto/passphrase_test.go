@@ -0,0 +1,25 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveKeyFromPassphrase(t *testing.T) {
+	t.Parallel()
+	salt := []byte("0123456789abcdef")
+
+	key := DeriveKeyFromPassphrase("correct horse", salt, 1, 1024, 1)
+	if len(key) != AESKeySize {
+		t.Fatalf("len(key) = %d, want %d", len(key), AESKeySize)
+	}
+	if again := DeriveKeyFromPassphrase("correct horse", salt, 1, 1024, 1); !bytes.Equal(key, again) {
+		t.Error("same passphrase and salt produced different keys")
+	}
+	if other := DeriveKeyFromPassphrase("battery staple", salt, 1, 1024, 1); bytes.Equal(key, other) {
+		t.Error("different passphrases produced the same key")
+	}
+	if other := DeriveKeyFromPassphrase("correct horse", []byte("fedcba9876543210"), 1, 1024, 1); bytes.Equal(key, other) {
+		t.Error("different salts produced the same key")
+	}
+}
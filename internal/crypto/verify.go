@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/subtle"
 	"fmt"
 
@@ -50,6 +51,41 @@ type AlgorithmSuite struct {
 	KDF string `json:"kdf"`
 }
 
+// DefaultSuite is the algorithm suite defined by VaultSandbox spec Section 3.
+var DefaultSuite = AlgorithmSuite{
+	KEM:  ExpectedKEM,
+	Sig:  ExpectedSig,
+	AEAD: ExpectedAEAD,
+	KDF:  ExpectedKDF,
+}
+
+// String returns the canonical colon-separated form of the suite.
+func (s AlgorithmSuite) String() string {
+	return s.KEM + ":" + s.Sig + ":" + s.AEAD + ":" + s.KDF
+}
+
+// RequireSuite returns ErrUnexpectedSuite unless the payload's algorithm
+// suite exactly matches required.
+func RequireSuite(payload *EncryptedPayload, required AlgorithmSuite) error {
+	if payload.Algs != required {
+		return fmt.Errorf("%w: got %s, required %s", ErrUnexpectedSuite, payload.Algs, required)
+	}
+	return nil
+}
+
+// RequireAAD returns ErrUnexpectedAAD unless the payload's associated data
+// equals expected.
+func RequireAAD(payload *EncryptedPayload, expected []byte) error {
+	aad, err := FromBase64URL(payload.AAD)
+	if err != nil {
+		return fmt.Errorf("%w: decode aad: %v", ErrInvalidPayload, err)
+	}
+	if !bytes.Equal(aad, expected) {
+		return fmt.Errorf("%w: got %q, expected %q", ErrUnexpectedAAD, aad, expected)
+	}
+	return nil
+}
+
 // ValidatePayload validates the encrypted payload structure per VaultSandbox spec Section 8.
 // This performs steps 2-4 of the decryption process:
 //   - Validate version == 1
@@ -121,6 +157,13 @@ func ValidatePayload(payload *EncryptedPayload) error {
 //
 // Per spec Section 11.3, constant-time comparison is used for server key verification.
 func VerifySignature(payload *EncryptedPayload, pinnedServerPk []byte) error {
+	return VerifySignatureAny(payload, [][]byte{pinnedServerPk})
+}
+
+// VerifySignatureAny is like VerifySignature but accepts a set of pinned
+// server keys, as during a server key rotation. The payload's embedded key
+// must match one of them exactly, or ErrServerKeyMismatch is returned.
+func VerifySignatureAny(payload *EncryptedPayload, pinnedServerPks [][]byte) error {
 	// First validate the payload structure
 	if err := ValidatePayload(payload); err != nil {
 		return err
@@ -140,11 +183,17 @@ func VerifySignature(payload *EncryptedPayload, pinnedServerPk []byte) error {
 	serverSigPk, _ := FromBase64URL(payload.ServerSigPk)
 	sig, _ := FromBase64URL(payload.Sig)
 
-	// Step 5: Verify the payload's server key matches the pinned key from inbox creation.
+	// Step 5: Verify the payload's server key matches a pinned key from inbox creation.
 	// Per spec Section 11.3: MUST use constant-time comparison.
 	// This is critical: without this check, an attacker could inject payloads
 	// signed with their own key and bypass authenticity verification.
-	if len(serverSigPk) != len(pinnedServerPk) || subtle.ConstantTimeCompare(serverSigPk, pinnedServerPk) != 1 {
+	pinned := false
+	for _, pk := range pinnedServerPks {
+		if len(serverSigPk) == len(pk) && subtle.ConstantTimeCompare(serverSigPk, pk) == 1 {
+			pinned = true
+		}
+	}
+	if !pinned {
 		return ErrServerKeyMismatch
 	}
 
@@ -320,6 +320,60 @@ func TestVerifySignature_ServerKeyMismatch(t *testing.T) {
 	}
 }
 
+func TestVerifySignatureAny(t *testing.T) {
+	t.Parallel()
+	kp, err := GenerateKeypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldPk, oldPriv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPk, newPriv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, unknownPriv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinned := [][]byte{oldPk, newPk}
+	tests := []struct {
+		name    string
+		signer  []byte
+		wantErr error
+	}{
+		{"first key", oldPriv, nil},
+		{"second key", newPriv, nil},
+		{"unknown key", unknownPriv, ErrServerKeyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			payload, err := Encrypt([]byte("hello"), kp.PublicKey, tt.signer, []byte("aad"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifySignatureAny(payload, pinned); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifySignatureAny() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("no pinned keys", func(t *testing.T) {
+		t.Parallel()
+		payload, err := Encrypt([]byte("hello"), kp.PublicKey, oldPriv, []byte("aad"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifySignatureAny(payload, nil); !errors.Is(err, ErrServerKeyMismatch) {
+			t.Errorf("VerifySignatureAny() error = %v, want ErrServerKeyMismatch", err)
+		}
+	})
+}
+
 func TestVerifySignature_InvalidSignature(t *testing.T) {
 	t.Parallel()
 	// Generate a valid public key but provide invalid signature
@@ -841,3 +895,44 @@ func BenchmarkVerify(b *testing.B) {
 		_ = Verify(pubBytes, message, sig)
 	}
 }
+
+func TestAlgorithmSuite_String(t *testing.T) {
+	t.Parallel()
+	if got := DefaultSuite.String(); got != AlgsCiphersuite {
+		t.Errorf("DefaultSuite.String() = %q, want %q", got, AlgsCiphersuite)
+	}
+}
+
+func TestRequireAAD(t *testing.T) {
+	t.Parallel()
+	payload := &EncryptedPayload{AAD: ToBase64URL([]byte("inbox-hash"))}
+
+	if err := RequireAAD(payload, []byte("inbox-hash")); err != nil {
+		t.Errorf("RequireAAD() with matching AAD error = %v", err)
+	}
+	if err := RequireAAD(payload, []byte("other")); !errors.Is(err, ErrUnexpectedAAD) {
+		t.Errorf("RequireAAD() error = %v, want ErrUnexpectedAAD", err)
+	}
+	if err := RequireAAD(&EncryptedPayload{}, nil); err != nil {
+		t.Errorf("RequireAAD() with empty AAD error = %v", err)
+	}
+	if err := RequireAAD(&EncryptedPayload{AAD: "!!"}, nil); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("RequireAAD() with malformed AAD error = %v, want ErrInvalidPayload", err)
+	}
+}
+
+func TestRequireSuite(t *testing.T) {
+	t.Parallel()
+	payload := &EncryptedPayload{Algs: DefaultSuite}
+
+	if err := RequireSuite(payload, DefaultSuite); err != nil {
+		t.Errorf("RequireSuite() with matching suite error = %v", err)
+	}
+
+	pinned := DefaultSuite
+	pinned.KDF = "HKDF-SHA-256"
+	err := RequireSuite(payload, pinned)
+	if !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("RequireSuite() error = %v, want ErrUnexpectedSuite", err)
+	}
+}
@@ -4,7 +4,7 @@
 //
 // # Delivery Strategies
 //
-// The package implements two delivery strategies:
+// The package implements three delivery strategies:
 //
 //   - [SSEStrategy]: Uses Server-Sent Events for real-time push notifications.
 //     Lowest latency, recommended for most use cases.
@@ -13,6 +13,10 @@
 //     backoff to reduce API calls when no new emails arrive. Use when SSE is not
 //     available or for edge cases requiring explicit polling.
 //
+//   - [WebhookStrategy]: Registers a webhook per inbox and receives
+//     notifications on an http.Handler the caller mounts. Use when an HTTP
+//     receiver is already running and holding a connection is undesirable.
+//
 // # Usage
 //
 // All strategies implement the [Strategy] interface for event-driven delivery:
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/logging"
 )
 
 // PollingStrategy implements email delivery via periodic API polling.
@@ -35,6 +37,12 @@ type PollingStrategy struct {
 	maxBackoff        time.Duration
 	backoffMultiplier float64
 	jitterFactor      float64
+	concurrency       int
+	minInterval       time.Duration // Lower bound on waits (0 = none).
+	maxInterval       time.Duration // Upper bound on waits (0 = none).
+
+	logger logging.Logger // Config.Logger.
+	clock  clock.Clock    // Config.Clock.
 }
 
 // polledInbox tracks the state of a single inbox being polled.
@@ -52,12 +60,18 @@ func NewPollingStrategy(cfg Config) *PollingStrategy {
 	initialInterval := cfg.PollingInitialInterval
 	if initialInterval == 0 {
 		initialInterval = DefaultPollingInitialInterval
+		if cfg.PollMinInterval > 0 {
+			initialInterval = cfg.PollMinInterval
+		}
 	}
 
 	maxBackoff := cfg.PollingMaxBackoff
 	if maxBackoff == 0 {
 		maxBackoff = DefaultPollingMaxBackoff
 	}
+	if cfg.PollMaxInterval > 0 {
+		maxBackoff = min(maxBackoff, cfg.PollMaxInterval)
+	}
 
 	backoffMultiplier := cfg.PollingBackoffMultiplier
 	if backoffMultiplier == 0 {
@@ -69,19 +83,42 @@ func NewPollingStrategy(cfg Config) *PollingStrategy {
 		jitterFactor = DefaultPollingJitterFactor
 	}
 
+	concurrency := cfg.PollingConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultPollingConcurrency
+	}
+
 	// Create local random source to avoid contention on global rand
 	seed := uint64(time.Now().UnixNano())
 	rng := rand.New(rand.NewPCG(seed, seed^0xDEADBEEF))
 
-	return &PollingStrategy{
+	p := &PollingStrategy{
 		apiClient:         cfg.APIClient,
 		inboxes:           make(map[string]*polledInbox),
 		rng:               rng,
-		initialInterval:   initialInterval,
 		maxBackoff:        maxBackoff,
 		backoffMultiplier: backoffMultiplier,
 		jitterFactor:      jitterFactor,
+		concurrency:       concurrency,
+		minInterval:       cfg.PollMinInterval,
+		maxInterval:       cfg.PollMaxInterval,
+		logger:            logging.OrNop(cfg.Logger),
+		clock:             clock.OrReal(cfg.Clock),
 	}
+	p.initialInterval = p.clampInterval(initialInterval)
+	return p
+}
+
+// clampInterval limits d to the configured PollMinInterval and
+// PollMaxInterval bounds.
+func (p *PollingStrategy) clampInterval(d time.Duration) time.Duration {
+	if p.maxInterval > 0 && d > p.maxInterval {
+		d = p.maxInterval
+	}
+	if p.minInterval > 0 && d < p.minInterval {
+		d = p.minInterval
+	}
+	return d
 }
 
 // Name returns the strategy name for logging and debugging.
@@ -109,8 +146,10 @@ func (p *PollingStrategy) Start(ctx context.Context, inboxes []InboxInfo, handle
 		}
 	}
 	p.started = true
+	count := len(p.inboxes)
 	p.mu.Unlock()
 
+	p.logger.Info("polling started", "inboxes", count)
 	ctx, p.cancel = context.WithCancel(ctx)
 	go p.pollLoop(ctx)
 	return nil
@@ -174,7 +213,7 @@ func (p *PollingStrategy) pollLoop(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(minWait):
+		case <-p.clock.After(minWait):
 		}
 	}
 }
@@ -194,9 +233,7 @@ func (p *PollingStrategy) pollAll(ctx context.Context) time.Duration {
 		return p.initialInterval
 	}
 
-	for _, inbox := range inboxList {
-		p.pollInbox(ctx, inbox)
-	}
+	p.pollInboxes(ctx, inboxList)
 
 	// Return minimum wait duration with jitter
 	var minWait time.Duration
@@ -209,6 +246,31 @@ func (p *PollingStrategy) pollAll(ctx context.Context) time.Duration {
 	return minWait
 }
 
+// pollInboxes polls each inbox once, running at most p.concurrency polls at
+// a time. Each inbox is owned by a single goroutine for the duration of the
+// cycle, so per-inbox state needs no extra locking.
+func (p *PollingStrategy) pollInboxes(ctx context.Context, inboxList []*polledInbox) {
+	if p.concurrency <= 1 || len(inboxList) == 1 {
+		for _, inbox := range inboxList {
+			p.pollInbox(ctx, inbox)
+		}
+		return
+	}
+
+	sem := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for _, inbox := range inboxList {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(inbox *polledInbox) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.pollInbox(ctx, inbox)
+		}(inbox)
+	}
+	wg.Wait()
+}
+
 // pollInbox polls a single inbox for new emails. It first checks the sync
 // status to detect changes, then fetches emails only if changes are detected.
 // This minimizes API calls when no new emails have arrived.
@@ -221,6 +283,7 @@ func (p *PollingStrategy) pollInbox(ctx context.Context, inbox *polledInbox) {
 	// Check sync status first
 	sync, err := p.apiClient.GetInboxSync(ctx, inbox.emailAddress)
 	if err != nil {
+		p.logger.Warn("poll sync failed", "inbox", inbox.emailAddress, "error", err)
 		p.mu.RLock()
 		onError := p.onError
 		p.mu.RUnlock()
@@ -247,6 +310,7 @@ func (p *PollingStrategy) pollInbox(ctx context.Context, inbox *polledInbox) {
 
 	resp, err := p.apiClient.GetEmails(ctx, inbox.emailAddress, true)
 	if err != nil {
+		p.logger.Warn("poll fetch failed", "inbox", inbox.emailAddress, "error", err)
 		p.mu.RLock()
 		onError := p.onError
 		p.mu.RUnlock()
@@ -298,13 +362,14 @@ func (p *PollingStrategy) pollInbox(ctx context.Context, inbox *polledInbox) {
 
 // getWaitDuration calculates the wait duration for an inbox, adding random
 // jitter to the base interval to prevent synchronized polling across clients.
+// The result stays within the configured interval bounds.
 func (p *PollingStrategy) getWaitDuration(inbox *polledInbox) time.Duration {
 	// Add jitter to prevent thundering herd
 	// Use local random source with mutex protection (rand.Rand is not thread-safe)
 	p.rngMu.Lock()
 	jitter := time.Duration(p.rng.Float64() * p.jitterFactor * float64(inbox.interval))
 	p.rngMu.Unlock()
-	return inbox.interval + jitter
+	return p.clampInterval(inbox.interval + jitter)
 }
 
 // OnReconnect is a no-op for polling strategy since polling doesn't have
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
@@ -392,6 +393,61 @@ func TestPollingStrategy_DefaultConfig(t *testing.T) {
 	if p.jitterFactor != DefaultPollingJitterFactor {
 		t.Errorf("jitterFactor = %v, want %v", p.jitterFactor, DefaultPollingJitterFactor)
 	}
+	if p.concurrency != DefaultPollingConcurrency {
+		t.Errorf("concurrency = %v, want %v", p.concurrency, DefaultPollingConcurrency)
+	}
+}
+
+func TestPollingStrategy_pollAll_Concurrency(t *testing.T) {
+	t.Parallel()
+	const limit = 3
+	var inFlight, maxInFlight, calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"emailCount": 0,
+			"emailsHash": "unchanged",
+		})
+	}))
+	defer server.Close()
+
+	apiClient, _ := api.New("test-key", api.WithBaseURL(server.URL))
+	p := NewPollingStrategy(Config{
+		APIClient:          apiClient,
+		PollingConcurrency: limit,
+	})
+	for i := 0; i < 10; i++ {
+		hash := fmt.Sprintf("hash-%d", i)
+		p.inboxes[hash] = &polledInbox{
+			hash:         hash,
+			emailAddress: hash + "@example.com",
+			lastHash:     "unchanged",
+			seenEmails:   make(map[string]struct{}),
+			interval:     time.Second,
+		}
+	}
+
+	p.pollAll(context.Background())
+
+	if got := calls.Load(); got != 10 {
+		t.Errorf("sync calls = %d, want 10", got)
+	}
+	if got := maxInFlight.Load(); got > limit {
+		t.Errorf("max in-flight = %d, want <= %d", got, limit)
+	}
+	if got := maxInFlight.Load(); got < 2 {
+		t.Errorf("max in-flight = %d, want inboxes polled in parallel", got)
+	}
 }
 
 func TestPollingStrategy_pollInbox_NoChange(t *testing.T) {
@@ -607,6 +663,75 @@ func TestPollingStrategy_pollInbox_BackoffCapping(t *testing.T) {
 	}
 }
 
+func TestPollingStrategy_PollBounds(t *testing.T) {
+	t.Parallel()
+	syncHash := "samehash"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"emailCount": 0,
+			"emailsHash": syncHash,
+		})
+	}))
+	defer server.Close()
+
+	const minInterval, maxInterval = 200 * time.Millisecond, 1 * time.Second
+	apiClient, _ := api.New("test-key", api.WithBaseURL(server.URL))
+	p := NewPollingStrategy(Config{
+		APIClient:           apiClient,
+		PollingJitterFactor: 0.9,
+		PollMinInterval:     minInterval,
+		PollMaxInterval:     maxInterval,
+	})
+	if p.initialInterval != minInterval {
+		t.Errorf("initialInterval = %v, want PollMinInterval %v", p.initialInterval, minInterval)
+	}
+
+	inbox := &polledInbox{
+		hash:         "hash123",
+		emailAddress: "test@example.com",
+		seenEmails:   make(map[string]struct{}),
+		lastHash:     syncHash,
+		interval:     p.initialInterval,
+	}
+
+	// Each empty poll backs off; with 90% jitter the raw wait would
+	// overshoot the maximum well before the backoff reaches it.
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 20; j++ {
+			if wait := p.getWaitDuration(inbox); wait < minInterval || wait > maxInterval {
+				t.Fatalf("poll %d: wait %v outside [%v, %v]", i, wait, minInterval, maxInterval)
+			}
+		}
+		p.pollInbox(context.Background(), inbox)
+	}
+	if inbox.interval != maxInterval {
+		t.Errorf("interval after backoff = %v, want %v", inbox.interval, maxInterval)
+	}
+}
+
+func TestPollingStrategy_PollBounds_ClampInitial(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  Config
+		want time.Duration
+	}{
+		{"initial below min", Config{PollingInitialInterval: 50 * time.Millisecond, PollMinInterval: time.Second, PollMaxInterval: 5 * time.Second}, time.Second},
+		{"initial above max", Config{PollingInitialInterval: 10 * time.Second, PollMinInterval: time.Second, PollMaxInterval: 5 * time.Second}, 5 * time.Second},
+		{"default initial above max", Config{PollMaxInterval: 500 * time.Millisecond}, 500 * time.Millisecond},
+		{"no bounds", Config{}, DefaultPollingInitialInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := NewPollingStrategy(tt.cfg).initialInterval; got != tt.want {
+				t.Errorf("initialInterval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPollingStrategy_pollInbox_OnErrorNil(t *testing.T) {
 	t.Parallel()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/logging"
 )
 
 // SSE reconnection constants control the behavior when the SSE connection
@@ -29,6 +31,15 @@ const (
 	SSEBackoffMultiplier = 2
 )
 
+// ErrStreamClosed is reported to Config.OnReconnect when the server ends the
+// event stream without an error.
+var ErrStreamClosed = errors.New("SSE stream closed by server")
+
+// ErrHeartbeatTimeout is reported to Config.OnReconnect when the stream
+// goes silent for longer than Config.SSEHeartbeatTimeout and the strategy
+// drops it to reconnect.
+var ErrHeartbeatTimeout = errors.New("SSE heartbeat timeout")
+
 // SSEStrategy implements email delivery via Server-Sent Events (SSE).
 // SSE provides real-time push notifications with lower latency than polling.
 //
@@ -43,15 +54,21 @@ const (
 //
 //	data: {"inbox_id":"...","email_id":"...","encrypted_metadata":"..."}
 //
-// Lines starting with ":" are comments (used for keep-alive) and are ignored.
-// Empty lines delimit events.
+// Lines starting with ":" are comments (used for keep-alive) and carry no
+// event. Any line, comment or not, counts as a heartbeat when
+// Config.SSEHeartbeatTimeout is set: a stream that stays silent longer than
+// that is assumed dead (for example, dropped by an idle-killing proxy) and is
+// reconnected.
+// Empty lines delimit events. An "id:" line tags the event; on reconnect the
+// last received ID is sent in the Last-Event-ID header so the server can
+// replay events missed while disconnected.
 type SSEStrategy struct {
 	apiClient     *api.Client          // API client for establishing connections.
 	inboxHashes   map[string]struct{}  // Set of inbox hashes to monitor.
 	handler       EventHandler         // Callback for new email events.
 	cancel        context.CancelFunc   // Cancels the connection goroutine.
 	connCancel    context.CancelFunc   // Cancels the current connection (for reconnection).
-	mu            sync.RWMutex         // Protects inboxHashes, handler, connCancel, onReconnect, onError.
+	mu            sync.RWMutex         // Protects inboxHashes, handler, connCancel, onReconnect, onError, lastEventID.
 	reconnectWait time.Duration        // Base interval for reconnection backoff.
 	attempts      atomic.Int32         // Consecutive failed connection attempts.
 	started       bool                 // Whether the strategy is active.
@@ -61,6 +78,12 @@ type SSEStrategy struct {
 	inboxAdded    chan struct{}        // Signaled when an inbox is added (0→1 case).
 	onReconnect   func(ctx context.Context) // Called after each successful connection.
 	onError       func(error)          // Callback for event processing errors.
+	lastEventID   string               // ID of the last fully received event, sent as Last-Event-ID.
+	reconnectHook func(attempt int, err error) // Config.OnReconnect.
+	connectedHook func()                       // Config.OnConnected.
+	heartbeat     time.Duration                // Config.SSEHeartbeatTimeout.
+	logger        logging.Logger               // Config.Logger.
+	clock         clock.Clock                  // Config.Clock.
 }
 
 // NewSSEStrategy creates a new SSE strategy with the given configuration.
@@ -72,6 +95,11 @@ func NewSSEStrategy(cfg Config) *SSEStrategy {
 		reconnectWait: SSEReconnectInterval,
 		connected:     make(chan struct{}),
 		inboxAdded:    make(chan struct{}, 1),
+		reconnectHook: cfg.OnReconnect,
+		connectedHook: cfg.OnConnected,
+		heartbeat:     cfg.SSEHeartbeatTimeout,
+		logger:        logging.OrNop(cfg.Logger),
+		clock:         clock.OrReal(cfg.Clock),
 	}
 }
 
@@ -139,6 +167,7 @@ func (s *SSEStrategy) Start(ctx context.Context, inboxes []InboxInfo, handler Ev
 	s.connectedOnce = sync.Once{}
 	s.attempts.Store(0)
 	s.lastError = nil
+	s.lastEventID = ""
 	s.inboxHashes = make(map[string]struct{})
 
 	for _, inbox := range inboxes {
@@ -167,6 +196,25 @@ func (s *SSEStrategy) Stop() error {
 	return nil
 }
 
+// ResumeFrom sets the event ID sent in the Last-Event-ID header, typically
+// the last event handled before a restart, so the server replays the events
+// after it. An open connection is reopened with the ID. It has no effect once
+// an event has been received, as the stream then resumes from that one.
+func (s *SSEStrategy) ResumeFrom(id string) {
+	s.mu.Lock()
+	if s.lastEventID != "" || id == "" {
+		s.mu.Unlock()
+		return
+	}
+	s.lastEventID = id
+	connCancel := s.connCancel
+	s.mu.Unlock()
+
+	if connCancel != nil {
+		connCancel()
+	}
+}
+
 // AddInbox adds an inbox to be monitored. If the strategy is running,
 // this triggers an immediate reconnection with the updated inbox list.
 func (s *SSEStrategy) AddInbox(inbox InboxInfo) error {
@@ -245,6 +293,22 @@ func (s *SSEStrategy) connectLoop(ctx context.Context) {
 		err := s.connect(ctx)
 		if err == nil {
 			// Clean disconnect - reconnect immediately
+			if ctx.Err() == nil {
+				s.logger.Info("sse stream closed by server, reconnecting")
+				if s.reconnectHook != nil {
+					s.reconnectHook(int(s.attempts.Load())+1, ErrStreamClosed)
+				}
+			}
+			continue
+		}
+
+		// A silent stream is dropped by the heartbeat watchdog; like a clean
+		// disconnect, reconnect immediately.
+		if errors.Is(err, ErrHeartbeatTimeout) && ctx.Err() == nil {
+			s.logger.Warn("sse heartbeat timeout, reconnecting", "timeout", s.heartbeat)
+			if s.reconnectHook != nil {
+				s.reconnectHook(int(s.attempts.Load())+1, err)
+			}
 			continue
 		}
 
@@ -258,6 +322,7 @@ func (s *SSEStrategy) connectLoop(ctx context.Context) {
 		// Check if this was a context.Canceled error from AddInbox/RemoveInbox
 		// triggering a reconnection - in that case, reconnect immediately without backoff
 		if errors.Is(err, context.Canceled) {
+			s.logger.Debug("sse reconnecting after inbox change")
 			s.attempts.Store(0)
 			continue
 		}
@@ -266,14 +331,20 @@ func (s *SSEStrategy) connectLoop(ctx context.Context) {
 		attempts := s.attempts.Add(1)
 		if attempts >= SSEMaxReconnectAttempts {
 			// Max attempts reached, give up
+			s.logger.Error("sse reconnect attempts exhausted", "attempt", int(attempts), "error", err)
 			return
 		}
 
+		if s.reconnectHook != nil {
+			s.reconnectHook(int(attempts), err)
+		}
+
 		wait := s.reconnectWait * time.Duration(1<<(attempts-1))
+		s.logger.Warn("sse connection failed", "attempt", int(attempts), "delay", wait, "error", err)
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(wait):
+		case <-s.clock.After(wait):
 		}
 	}
 }
@@ -293,6 +364,7 @@ func (s *SSEStrategy) connect(ctx context.Context) error {
 	for h := range s.inboxHashes {
 		hashes = append(hashes, h)
 	}
+	lastEventID := s.lastEventID
 	s.mu.Unlock()
 
 	// Clean up connCancel when we exit
@@ -317,7 +389,7 @@ func (s *SSEStrategy) connect(ctx context.Context) error {
 		return err
 	}
 
-	resp, err := s.apiClient.OpenEventStream(connCtx, hashes)
+	resp, err := s.apiClient.OpenEventStream(connCtx, hashes, lastEventID)
 	if err != nil {
 		s.mu.Lock()
 		s.lastError = err
@@ -333,6 +405,10 @@ func (s *SSEStrategy) connect(ctx context.Context) error {
 	s.connectedOnce.Do(func() {
 		close(s.connected)
 	})
+	s.logger.Info("sse connected", "inboxes", len(hashes))
+	if s.connectedHook != nil {
+		s.connectedHook()
+	}
 
 	// Call reconnect handler to sync emails that may have arrived
 	// during the reconnection window. Run async to not block the event loop.
@@ -346,11 +422,53 @@ func (s *SSEStrategy) connect(ctx context.Context) error {
 	scanner := bufio.NewScanner(resp.Body)
 	// Allow lines up to 1MB (default is 64KB)
 	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	// Every line read is a sign of life for the heartbeat watchdog, which
+	// cancels the connection if none arrives within the timeout.
+	var activity chan struct{}
+	var stalled atomic.Bool
+	if s.heartbeat > 0 {
+		activity = make(chan struct{}, 1)
+		go s.watchHeartbeat(connCtx, connCancel, activity, &stalled)
+	}
+
+	// ID from the "id:" line of the event being read. It becomes the last
+	// event ID once the event's terminating empty line arrives, after its
+	// data has been handled, so a drop mid-event replays that event.
+	var eventID string
+	var hasEventID bool
 	for scanner.Scan() {
 		line := scanner.Text()
+		if activity != nil {
+			select {
+			case activity <- struct{}{}:
+			default:
+			}
+		}
+
+		if line == "" {
+			if hasEventID {
+				s.mu.Lock()
+				s.lastEventID = eventID
+				s.mu.Unlock()
+			}
+			eventID, hasEventID = "", false
+			continue
+		}
+
+		// Skip comments
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, ":") {
+		// Parse SSE id line. IDs containing NUL are ignored, as the SSE
+		// specification requires.
+		if line == "id" || strings.HasPrefix(line, "id:") {
+			id := strings.TrimPrefix(strings.TrimPrefix(line, "id"), ":")
+			id = strings.TrimPrefix(id, " ")
+			if !strings.ContainsRune(id, 0) {
+				eventID, hasEventID = id, true
+			}
 			continue
 		}
 
@@ -362,6 +480,7 @@ func (s *SSEStrategy) connect(ctx context.Context) error {
 			if err := json.Unmarshal([]byte(data), &event); err != nil {
 				continue // Skip malformed events
 			}
+			event.ID = eventID
 
 			s.mu.RLock()
 			handler := s.handler
@@ -378,6 +497,25 @@ func (s *SSEStrategy) connect(ctx context.Context) error {
 		}
 	}
 
+	if stalled.Load() {
+		return ErrHeartbeatTimeout
+	}
 	return scanner.Err()
 }
 
+// watchHeartbeat cancels the connection and sets stalled if no signal arrives
+// on activity within the heartbeat timeout. It returns when ctx is done.
+func (s *SSEStrategy) watchHeartbeat(ctx context.Context, cancel context.CancelFunc, activity <-chan struct{}, stalled *atomic.Bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-activity:
+		case <-s.clock.After(s.heartbeat):
+			stalled.Store(true)
+			cancel()
+			return
+		}
+	}
+}
+
@@ -2,14 +2,17 @@ package delivery
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
 )
 
 func TestNewSSEStrategy(t *testing.T) {
@@ -545,3 +548,280 @@ func TestSSEStrategy_MalformedSSEEvent(t *testing.T) {
 	cancel()
 	<-serverDone
 }
+
+func TestSSEStrategy_ReconnectHooks_StreamClosed(t *testing.T) {
+	t.Parallel()
+	// The server ends every stream right after it is opened, so the
+	// strategy keeps reconnecting.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, ": hello\n\n")
+		w.(http.Flusher).Flush()
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-api-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create api client: %v", err)
+	}
+
+	var mu sync.Mutex
+	var connects int
+	var drops []error
+	enough := make(chan struct{})
+	var once sync.Once
+	s := NewSSEStrategy(Config{
+		APIClient: apiClient,
+		OnConnected: func() {
+			mu.Lock()
+			connects++
+			mu.Unlock()
+		},
+		OnReconnect: func(attempt int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if attempt != 1 {
+				t.Errorf("OnReconnect attempt = %d, want 1 after a successful connection", attempt)
+			}
+			drops = append(drops, err)
+			if len(drops) == 3 {
+				once.Do(func() { close(enough) })
+			}
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx, []InboxInfo{{Hash: "hash1"}}, func(context.Context, *api.SSEEvent) error { return nil }); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	select {
+	case <-enough:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for reconnects")
+	}
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if connects < 3 {
+		t.Errorf("OnConnected called %d times, want >= 3", connects)
+	}
+	for _, err := range drops {
+		if !errors.Is(err, ErrStreamClosed) {
+			t.Errorf("OnReconnect err = %v, want ErrStreamClosed", err)
+		}
+	}
+}
+
+func TestSSEStrategy_ReconnectHooks_ConnectFailures(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-api-key", api.WithBaseURL(server.URL), api.WithRetries(0))
+	if err != nil {
+		t.Fatalf("failed to create api client: %v", err)
+	}
+
+	attempts := make(chan int, SSEMaxReconnectAttempts)
+	s := NewSSEStrategy(Config{
+		APIClient:   apiClient,
+		OnConnected: func() { t.Error("OnConnected called for a failed connection") },
+		OnReconnect: func(attempt int, err error) {
+			if err == nil {
+				t.Error("OnReconnect err = nil, want the connection error")
+			}
+			attempts <- attempt
+		},
+	})
+	s.reconnectWait = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx, []InboxInfo{{Hash: "hash1"}}, func(context.Context, *api.SSEEvent) error { return nil }); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	for want := 1; want <= 3; want++ {
+		select {
+		case got := <-attempts:
+			if got != want {
+				t.Errorf("OnReconnect attempt = %d, want %d", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for reconnect attempt %d", want)
+		}
+	}
+	s.Stop()
+}
+
+func TestSSEStrategy_LastEventIDOnReconnect(t *testing.T) {
+	t.Parallel()
+	// The first stream delivers two events and then drops; the reconnect
+	// must carry the ID of the last one.
+	headers := make(chan string, 2)
+	var conns atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		if conns.Add(1) == 1 {
+			fmt.Fprintf(w, "id: evt-1\ndata: {\"inboxId\":\"hash1\",\"emailId\":\"email1\"}\n\n")
+			fmt.Fprintf(w, "id: evt-2\ndata: {\"inboxId\":\"hash1\",\"emailId\":\"email2\"}\n\n")
+			w.(http.Flusher).Flush()
+			return
+		}
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-api-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create api client: %v", err)
+	}
+
+	events := make(chan *api.SSEEvent, 2)
+	s := NewSSEStrategy(Config{APIClient: apiClient})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx, []InboxInfo{{Hash: "hash1"}}, func(_ context.Context, event *api.SSEEvent) error {
+		events <- event
+		return nil
+	}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	for _, wantHeader := range []string{"", "evt-2"} {
+		select {
+		case got := <-headers:
+			if got != wantHeader {
+				t.Errorf("Last-Event-ID = %q, want %q", got, wantHeader)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for connection with Last-Event-ID %q", wantHeader)
+		}
+	}
+	for _, wantID := range []string{"evt-1", "evt-2"} {
+		event := <-events
+		if event.ID != wantID {
+			t.Errorf("event.ID = %q, want %q", event.ID, wantID)
+		}
+	}
+	s.Stop()
+}
+
+func TestSSEStrategy_HeartbeatTimeout(t *testing.T) {
+	t.Parallel()
+	// Every stream is opened and then stalls without a single line, as a
+	// connection silently dropped by a proxy would.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-api-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create api client: %v", err)
+	}
+
+	fc := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	connects := make(chan struct{}, 2)
+	drops := make(chan error, 2)
+	s := NewSSEStrategy(Config{
+		APIClient:           apiClient,
+		SSEHeartbeatTimeout: 30 * time.Second,
+		Clock:               fc,
+		OnConnected:         func() { connects <- struct{}{} },
+		OnReconnect:         func(_ int, err error) { drops <- err },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx, []InboxInfo{{Hash: "hash1"}}, func(context.Context, *api.SSEEvent) error { return nil }); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	select {
+	case <-connects:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for first connection")
+	}
+
+	fc.BlockUntil(1)
+	fc.Advance(29 * time.Second)
+	select {
+	case err := <-drops:
+		t.Fatalf("stream dropped before the heartbeat timeout: %v", err)
+	default:
+	}
+
+	fc.Advance(time.Second)
+	select {
+	case err := <-drops:
+		if !errors.Is(err, ErrHeartbeatTimeout) {
+			t.Errorf("OnReconnect err = %v, want ErrHeartbeatTimeout", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for heartbeat reconnect")
+	}
+	select {
+	case <-connects:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for reconnection")
+	}
+	s.Stop()
+}
+
+func TestSSEStrategy_ResumeFrom(t *testing.T) {
+	t.Parallel()
+	headers := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-api-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create api client: %v", err)
+	}
+
+	s := NewSSEStrategy(Config{APIClient: apiClient})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx, []InboxInfo{{Hash: "hash1"}}, func(context.Context, *api.SSEEvent) error { return nil }); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	<-s.Connected()
+
+	// The open stream is reopened with the stored ID.
+	s.ResumeFrom("evt-41")
+	for _, want := range []string{"", "evt-41"} {
+		select {
+		case got := <-headers:
+			if got != want {
+				t.Errorf("Last-Event-ID = %q, want %q", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for connection with Last-Event-ID %q", want)
+		}
+	}
+
+	// A later call does not override the ID already in use.
+	s.ResumeFrom("evt-7")
+	s.mu.RLock()
+	got := s.lastEventID
+	s.mu.RUnlock()
+	if got != "evt-41" {
+		t.Errorf("lastEventID = %q, want evt-41", got)
+	}
+	s.Stop()
+}
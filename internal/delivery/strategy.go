@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
+	"github.com/vaultsandbox/client-go/internal/logging"
 )
 
 // InboxInfo contains the information needed to monitor an inbox for new emails.
@@ -25,7 +27,7 @@ type InboxInfo struct {
 type EventHandler func(ctx context.Context, event *api.SSEEvent) error
 
 // Strategy defines the interface for email delivery mechanisms.
-// Implementations: [SSEStrategy], [PollingStrategy].
+// Implementations: [SSEStrategy], [PollingStrategy], [WebhookStrategy].
 //
 // The typical lifecycle is:
 //  1. Create a strategy with NewXxxStrategy(cfg)
@@ -88,6 +90,58 @@ type Config struct {
 	// poll intervals (as a fraction of the interval).
 	// If zero, defaults to DefaultPollingJitterFactor.
 	PollingJitterFactor float64
+
+	// PollingConcurrency is the maximum number of inboxes polled in
+	// parallel during each poll cycle.
+	// If zero, defaults to DefaultPollingConcurrency.
+	PollingConcurrency int
+
+	// PollMinInterval is the shortest wait between polls of an inbox,
+	// jitter included. It is also the starting interval when
+	// PollingInitialInterval is zero. If zero, there is no lower bound.
+	PollMinInterval time.Duration
+
+	// PollMaxInterval is the longest wait between polls of an inbox,
+	// jitter included. It caps PollingMaxBackoff. If zero, there is no
+	// upper bound beyond PollingMaxBackoff plus jitter.
+	PollMaxInterval time.Duration
+
+	// WebhookURL is the public URL at which the webhook strategy's Handler
+	// is mounted. The strategy registers it with the server for each inbox.
+	// Required by the webhook strategy; ignored by the others.
+	WebhookURL string
+
+	// ServerSigPks are the server's accepted ML-DSA-65 public keys, used by
+	// the webhook strategy to verify the signature of encrypted events. More
+	// than one key is accepted during a server key rotation.
+	ServerSigPks [][]byte
+
+	// OnReconnect is called by the SSE strategy each time the connection
+	// drops and a reconnect is scheduled. attempt is the number of
+	// consecutive reconnect attempts, starting at 1, and err is why the
+	// connection dropped (ErrStreamClosed if the server ended the stream).
+	// It is not called for reconnects caused by adding or removing inboxes,
+	// nor when the strategy gives up. Ignored by the polling strategy.
+	OnReconnect func(attempt int, err error)
+
+	// OnConnected is called by the SSE strategy each time a stream is
+	// established, including the first connection. Ignored by the polling
+	// strategy.
+	OnConnected func()
+
+	// SSEHeartbeatTimeout is how long the SSE strategy tolerates a stream
+	// with no lines, heartbeat comments included, before dropping it and
+	// reconnecting. Zero disables the check. Ignored by the other
+	// strategies.
+	SSEHeartbeatTimeout time.Duration
+
+	// Logger receives connection transitions and delivery errors.
+	// If nil, nothing is logged.
+	Logger logging.Logger
+
+	// Clock times poll intervals and reconnect delays. If nil, the real
+	// clock is used.
+	Clock clock.Clock
 }
 
 // Default polling configuration values.
@@ -96,5 +150,6 @@ const (
 	DefaultPollingMaxBackoff        = 30 * time.Second
 	DefaultPollingBackoffMultiplier = 1.5
 	DefaultPollingJitterFactor      = 0.3
+	DefaultPollingConcurrency       = 1
 )
 
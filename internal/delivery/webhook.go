@@ -0,0 +1,354 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/apierrors"
+	"github.com/vaultsandbox/client-go/internal/crypto"
+	"github.com/vaultsandbox/client-go/internal/logging"
+)
+
+// WebhookEventEmailReceived is the webhook event type the strategy
+// subscribes to and dispatches.
+const WebhookEventEmailReceived = "email.received"
+
+// webhookRequestTimeout bounds each webhook registration or removal request.
+const webhookRequestTimeout = 10 * time.Second
+
+// maxWebhookBodySize caps the size of a webhook callback body.
+const maxWebhookBodySize = 1 << 20
+
+// WebhookStrategy implements email delivery via server-pushed webhooks.
+// For each monitored inbox it registers an inbox webhook pointing at
+// Config.WebhookURL; the server then POSTs a notification there for every
+// received email. The user mounts [WebhookStrategy.Handler] at that URL.
+//
+// The handler expects a JSON body of the form:
+//
+//	{"type":"email.received","data":{"inboxId":"...","emailId":"...","encryptedMetadata":{...}}}
+//
+// where data has the shape of [api.SSEEvent]. Other event types are
+// acknowledged and ignored. The signature of encrypted metadata is verified
+// against Config.ServerSigPks before the event is dispatched; plain events
+// carry no signature and are dispatched as is, since the handler only uses
+// them as a hint to fetch the email from the API.
+//
+// Registration happens in the background so that AddInbox does not block.
+// Once an inbox's webhook is registered the OnReconnect callback runs, which
+// catches emails that arrived before the webhook existed.
+type WebhookStrategy struct {
+	apiClient    *api.Client
+	url          string
+	serverSigPks [][]byte
+	logger       logging.Logger
+
+	mu          sync.RWMutex
+	ctx         context.Context           // Strategy lifetime, from Start.
+	cancel      context.CancelFunc        // Cancels ctx.
+	handler     EventHandler              // Callback for new email events.
+	inboxes     map[string]*webhookInbox  // Monitored inboxes by hash.
+	started     bool                      // Whether the strategy is active.
+	onReconnect func(ctx context.Context) // Called after each registration.
+	onError     func(error)               // Callback for registration and event errors.
+	wg          sync.WaitGroup            // In-flight registrations.
+}
+
+// webhookInbox tracks the webhook registered for one inbox.
+type webhookInbox struct {
+	emailAddress string
+	webhookID    string // Empty until registration succeeds.
+	removed      bool   // Set by RemoveInbox; a late registration is undone.
+}
+
+// NewWebhookStrategy creates a new webhook strategy with the given
+// configuration. The strategy is created in a stopped state; call Start to
+// begin registering webhooks.
+func NewWebhookStrategy(cfg Config) *WebhookStrategy {
+	return &WebhookStrategy{
+		apiClient:    cfg.APIClient,
+		url:          cfg.WebhookURL,
+		serverSigPks: cfg.ServerSigPks,
+		logger:       logging.OrNop(cfg.Logger),
+		inboxes:      make(map[string]*webhookInbox),
+	}
+}
+
+// Name returns the strategy name for logging and debugging.
+func (w *WebhookStrategy) Name() string {
+	return "webhook"
+}
+
+// Start registers a webhook for each of the given inboxes and begins
+// accepting callbacks on Handler. Registration is asynchronous; failures are
+// reported to the OnError callback.
+func (w *WebhookStrategy) Start(ctx context.Context, inboxes []InboxInfo, handler EventHandler) error {
+	if w.url == "" {
+		return fmt.Errorf("webhook strategy: webhook URL is empty")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.handler = handler
+	w.started = true
+
+	for _, inbox := range inboxes {
+		if _, exists := w.inboxes[inbox.Hash]; !exists {
+			w.inboxes[inbox.Hash] = &webhookInbox{emailAddress: inbox.EmailAddress}
+		}
+	}
+	// Register every inbox, including those added before Start.
+	for _, tracked := range w.inboxes {
+		w.wg.Add(1)
+		go w.register(w.ctx, tracked)
+	}
+	return nil
+}
+
+// Stop stops dispatching callbacks and deletes the webhooks registered by
+// the strategy. Stop is idempotent and safe to call multiple times.
+func (w *WebhookStrategy) Stop() error {
+	w.mu.Lock()
+	if !w.started {
+		w.mu.Unlock()
+		return nil
+	}
+	w.started = false
+	w.cancel()
+	w.mu.Unlock()
+
+	// Let in-flight registrations finish so their webhooks are removed too.
+	w.wg.Wait()
+
+	w.mu.Lock()
+	inboxes := w.inboxes
+	w.inboxes = make(map[string]*webhookInbox)
+	w.mu.Unlock()
+
+	var errs []error
+	for _, inbox := range inboxes {
+		if err := w.deleteWebhook(inbox); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddInbox registers a webhook for the inbox in the background. Adding an
+// inbox that is already monitored is a no-op.
+func (w *WebhookStrategy) AddInbox(inbox InboxInfo) error {
+	w.mu.Lock()
+	if _, exists := w.inboxes[inbox.Hash]; exists {
+		w.mu.Unlock()
+		return nil
+	}
+	tracked := &webhookInbox{emailAddress: inbox.EmailAddress}
+	w.inboxes[inbox.Hash] = tracked
+	started := w.started
+	ctx := w.ctx
+	if started {
+		w.wg.Add(1)
+	}
+	w.mu.Unlock()
+
+	if started {
+		go w.register(ctx, tracked)
+	}
+	return nil
+}
+
+// RemoveInbox stops monitoring the inbox and deletes its webhook in the
+// background.
+func (w *WebhookStrategy) RemoveInbox(inboxHash string) error {
+	w.mu.Lock()
+	tracked, exists := w.inboxes[inboxHash]
+	delete(w.inboxes, inboxHash)
+	if exists {
+		tracked.removed = true
+	}
+	w.mu.Unlock()
+
+	if exists {
+		go func() {
+			if err := w.deleteWebhook(tracked); err != nil {
+				w.reportError(err)
+			}
+		}()
+	}
+	return nil
+}
+
+// OnReconnect sets a callback that is invoked after each webhook is
+// registered, so emails that arrived before registration can be synced.
+func (w *WebhookStrategy) OnReconnect(fn func(ctx context.Context)) {
+	w.mu.Lock()
+	w.onReconnect = fn
+	w.mu.Unlock()
+}
+
+// OnError sets a callback that is invoked when registering a webhook,
+// verifying a callback, or handling an event fails.
+func (w *WebhookStrategy) OnError(fn func(error)) {
+	w.mu.Lock()
+	w.onError = fn
+	w.mu.Unlock()
+}
+
+// register creates the webhook for an inbox and runs the OnReconnect callback.
+func (w *WebhookStrategy) register(ctx context.Context, inbox *webhookInbox) {
+	defer w.wg.Done()
+	if w.apiClient == nil {
+		w.reportError(fmt.Errorf("webhook strategy: API client is nil"))
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+	webhook, err := w.apiClient.CreateInboxWebhook(reqCtx, inbox.emailAddress, &api.CreateWebhookRequest{
+		URL:         w.url,
+		Events:      []string{WebhookEventEmailReceived},
+		Description: "vaultsandbox client delivery",
+	})
+	if err != nil {
+		w.logger.Warn("webhook registration failed", "inbox", inbox.emailAddress, "error", err)
+		w.reportError(fmt.Errorf("register webhook for %s: %w", inbox.emailAddress, err))
+		return
+	}
+	w.logger.Info("webhook registered", "inbox", inbox.emailAddress, "webhook_id", webhook.ID)
+
+	w.mu.Lock()
+	inbox.webhookID = webhook.ID
+	removed := inbox.removed
+	onReconnect := w.onReconnect
+	w.mu.Unlock()
+
+	// RemoveInbox ran while the webhook was being created.
+	if removed {
+		if err := w.deleteWebhook(inbox); err != nil {
+			w.reportError(err)
+		}
+		return
+	}
+	// Once stopping, skip the sync: Stop waits for this goroutine.
+	if onReconnect != nil && ctx.Err() == nil {
+		onReconnect(ctx)
+	}
+}
+
+// deleteWebhook removes the inbox's webhook, if one was registered. A
+// webhook or inbox that no longer exists, including an expired inbox, is not
+// an error.
+func (w *WebhookStrategy) deleteWebhook(inbox *webhookInbox) error {
+	w.mu.RLock()
+	id := inbox.webhookID
+	w.mu.RUnlock()
+	if id == "" || w.apiClient == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookRequestTimeout)
+	defer cancel()
+	err := w.apiClient.DeleteInboxWebhook(ctx, inbox.emailAddress, id)
+	if err != nil && !errors.Is(err, apierrors.ErrWebhookNotFound) &&
+		!errors.Is(err, apierrors.ErrInboxNotFound) && !errors.Is(err, apierrors.ErrInboxExpired) {
+		return fmt.Errorf("delete webhook %s for %s: %w", id, inbox.emailAddress, err)
+	}
+	return nil
+}
+
+// reportError passes err to the OnError callback, if set.
+func (w *WebhookStrategy) reportError(err error) {
+	w.mu.RLock()
+	onError := w.onError
+	w.mu.RUnlock()
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// webhookPayload is the body of a webhook callback.
+type webhookPayload struct {
+	Type string       `json:"type"`
+	Data api.SSEEvent `json:"data"`
+}
+
+// Handler returns the http.Handler that receives webhook callbacks. Mount it
+// at the path of Config.WebhookURL. It responds 204 once an event has been
+// handled, 400 for a malformed body, 401 if the signature does not verify,
+// and 503 while the strategy is stopped. The event is handled before the
+// response is written, so the server's retry policy covers slow handlers.
+func (w *WebhookStrategy) Handler() http.Handler {
+	return http.HandlerFunc(w.serveHTTP)
+}
+
+func (w *WebhookStrategy) serveHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.mu.RLock()
+	started, ctx, handler := w.started, w.ctx, w.handler
+	w.mu.RUnlock()
+	if !started {
+		http.Error(rw, "webhook delivery is stopped", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(rw, "read body", http.StatusBadRequest)
+		return
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(rw, "malformed webhook payload", http.StatusBadRequest)
+		return
+	}
+	if payload.Type != WebhookEventEmailReceived {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event := &payload.Data
+	if event.InboxID == "" || event.EmailID == "" {
+		http.Error(rw, "webhook payload is missing inboxId or emailId", http.StatusBadRequest)
+		return
+	}
+	if err := w.verify(event); err != nil {
+		w.logger.Warn("webhook event rejected", "email_id", event.EmailID, "error", err)
+		w.reportError(err)
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if handler != nil {
+		if err := handler(ctx, event); err != nil {
+			w.reportError(err)
+		}
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the server signature on an encrypted event.
+func (w *WebhookStrategy) verify(event *api.SSEEvent) error {
+	if !event.IsEncrypted() {
+		return nil
+	}
+	if len(w.serverSigPks) == 0 {
+		return fmt.Errorf("webhook event for email %s: no server signing key to verify it", event.EmailID)
+	}
+	if err := crypto.VerifySignatureAny(event.EncryptedMetadata, w.serverSigPks); err != nil {
+		return fmt.Errorf("webhook event for email %s: %w", event.EmailID, err)
+	}
+	return nil
+}
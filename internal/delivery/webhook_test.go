@@ -0,0 +1,250 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/crypto"
+)
+
+// webhookTestServer mocks the inbox webhook endpoints and records the
+// webhooks that are currently registered.
+type webhookTestServer struct {
+	mu         sync.Mutex
+	registered map[string]string // webhook ID -> URL
+}
+
+func newWebhookTestServer(t *testing.T) (*webhookTestServer, *api.Client) {
+	t.Helper()
+	ws := &webhookTestServer{registered: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/inboxes/{email}/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		var req api.CreateWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id := "wh_" + r.PathValue("email")
+		ws.mu.Lock()
+		ws.registered[id] = req.URL
+		ws.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.WebhookDTO{ID: id, URL: req.URL, Events: req.Events, Scope: "inbox"})
+	})
+	mux.HandleFunc("DELETE /api/inboxes/{email}/webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ws.mu.Lock()
+		delete(ws.registered, r.PathValue("id"))
+		ws.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	apiClient, err := api.New("test-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("api.New() error = %v", err)
+	}
+	return ws, apiClient
+}
+
+func (ws *webhookTestServer) count() int {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return len(ws.registered)
+}
+
+// signedWebhookEvent returns an encrypted email.received payload signed with
+// a fresh server key, along with that key's public half.
+func signedWebhookEvent(t *testing.T) (body []byte, serverSigPk []byte) {
+	t.Helper()
+	serverSigPk, serverPriv, err := crypto.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	kp, err := crypto.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("GenerateKeypair() error = %v", err)
+	}
+	metadata, err := crypto.Encrypt([]byte(`{"subject":"hi"}`), kp.PublicKey, serverPriv, nil)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	body, err = json.Marshal(webhookPayload{
+		Type: WebhookEventEmailReceived,
+		Data: api.SSEEvent{InboxID: "hash1", EmailID: "email1", EncryptedMetadata: metadata},
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	return body, serverSigPk
+}
+
+func TestWebhookStrategy_Name(t *testing.T) {
+	t.Parallel()
+	w := NewWebhookStrategy(Config{})
+	if w.Name() != "webhook" {
+		t.Errorf("Name() = %s, want webhook", w.Name())
+	}
+}
+
+func TestWebhookStrategy_Start_EmptyURL(t *testing.T) {
+	t.Parallel()
+	w := NewWebhookStrategy(Config{})
+	if err := w.Start(context.Background(), nil, nil); err == nil {
+		t.Error("Start() error = nil, want error for empty webhook URL")
+	}
+}
+
+func TestWebhookStrategy_RegistersAndDispatchesSignedEvent(t *testing.T) {
+	t.Parallel()
+	ws, apiClient := newWebhookTestServer(t)
+	body, serverSigPk := signedWebhookEvent(t)
+
+	w := NewWebhookStrategy(Config{
+		APIClient:    apiClient,
+		WebhookURL:   "https://hooks.example.com/vaultsandbox",
+		ServerSigPks: [][]byte{serverSigPk},
+	})
+	synced := make(chan struct{}, 1)
+	w.OnReconnect(func(ctx context.Context) { synced <- struct{}{} })
+
+	events := make(chan *api.SSEEvent, 1)
+	err := w.Start(context.Background(), []InboxInfo{{Hash: "hash1", EmailAddress: "a@example.com"}},
+		func(ctx context.Context, event *api.SSEEvent) error {
+			events <- event
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	select {
+	case <-synced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnReconnect was not called after registration")
+	}
+	if got := ws.count(); got != 1 {
+		t.Fatalf("registered webhooks = %d, want 1", got)
+	}
+
+	rec := httptest.NewRecorder()
+	w.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/vaultsandbox", bytes.NewReader(body)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	select {
+	case event := <-events:
+		if event.InboxID != "hash1" || event.EmailID != "email1" {
+			t.Errorf("event = %+v, want inbox hash1, email email1", event)
+		}
+	default:
+		t.Fatal("handler was not called for a signed event")
+	}
+
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if got := ws.count(); got != 0 {
+		t.Errorf("registered webhooks after Stop = %d, want 0", got)
+	}
+}
+
+func TestWebhookStrategy_Handler_RejectsBadRequests(t *testing.T) {
+	t.Parallel()
+	body, serverSigPk := signedWebhookEvent(t)
+	otherSigPk, _, err := crypto.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		sigPks [][]byte
+		method string
+		body   string
+		want   int
+	}{
+		{"wrong server key", [][]byte{otherSigPk}, http.MethodPost, string(body), http.StatusUnauthorized},
+		{"no server key", nil, http.MethodPost, string(body), http.StatusUnauthorized},
+		{"malformed body", [][]byte{serverSigPk}, http.MethodPost, "{", http.StatusBadRequest},
+		{"missing ids", [][]byte{serverSigPk}, http.MethodPost, `{"type":"email.received","data":{}}`, http.StatusBadRequest},
+		{"other event type", [][]byte{serverSigPk}, http.MethodPost, `{"type":"webhook.test"}`, http.StatusNoContent},
+		{"wrong method", [][]byte{serverSigPk}, http.MethodGet, "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			w := NewWebhookStrategy(Config{WebhookURL: "https://hooks.example.com", ServerSigPks: tt.sigPks})
+			called := false
+			if err := w.Start(context.Background(), nil, func(ctx context.Context, event *api.SSEEvent) error {
+				called = true
+				return nil
+			}); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer w.Stop()
+
+			rec := httptest.NewRecorder()
+			w.Handler().ServeHTTP(rec, httptest.NewRequest(tt.method, "/", bytes.NewReader([]byte(tt.body))))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if called {
+				t.Error("handler was called for a rejected or ignored event")
+			}
+		})
+	}
+}
+
+func TestWebhookStrategy_Handler_Stopped(t *testing.T) {
+	t.Parallel()
+	body, serverSigPk := signedWebhookEvent(t)
+	w := NewWebhookStrategy(Config{WebhookURL: "https://hooks.example.com", ServerSigPks: [][]byte{serverSigPk}})
+
+	rec := httptest.NewRecorder()
+	w.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestWebhookStrategy_RemoveInbox_DeletesWebhook(t *testing.T) {
+	t.Parallel()
+	ws, apiClient := newWebhookTestServer(t)
+
+	w := NewWebhookStrategy(Config{APIClient: apiClient, WebhookURL: "https://hooks.example.com"})
+	synced := make(chan struct{}, 1)
+	w.OnReconnect(func(ctx context.Context) { synced <- struct{}{} })
+	if err := w.Start(context.Background(), nil, nil); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop()
+
+	if err := w.AddInbox(InboxInfo{Hash: "hash1", EmailAddress: "a@example.com"}); err != nil {
+		t.Fatalf("AddInbox() error = %v", err)
+	}
+	select {
+	case <-synced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not registered")
+	}
+
+	if err := w.RemoveInbox("hash1"); err != nil {
+		t.Fatalf("RemoveInbox() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for ws.count() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("webhook was not deleted after RemoveInbox")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,27 @@
+// Package logging defines the structured logger used inside the SDK.
+package logging
+
+// Logger receives structured log records. Each method takes a message
+// followed by alternating keys and values, as [log/slog.Logger] does.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// Nop is a Logger that discards every record.
+type Nop struct{}
+
+func (Nop) Debug(string, ...any) {}
+func (Nop) Info(string, ...any)  {}
+func (Nop) Warn(string, ...any)  {}
+func (Nop) Error(string, ...any) {}
+
+// OrNop returns l, or Nop if l is nil.
+func OrNop(l Logger) Logger {
+	if l == nil {
+		return Nop{}
+	}
+	return l
+}
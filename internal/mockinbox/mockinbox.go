@@ -0,0 +1,120 @@
+// Package mockinbox creates the key material of encrypted inboxes offline and
+// builds the encrypted email responses they accept. It backs
+// vaultsandboxtest.NewMockInbox and the vaultsandbox package's own tests,
+// which cannot import vaultsandboxtest.
+package mockinbox
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/crypto"
+)
+
+// TTL is the lifetime of a mock inbox.
+const TTL = 24 * time.Hour
+
+// Inbox is the key material of an encrypted inbox created offline, with the
+// mock server signing key its emails are signed with.
+type Inbox struct {
+	EmailAddress string
+	Keypair      *crypto.Keypair
+	InboxHash    string    // Base64url SHA-256 of the public key
+	ServerSigPk  []byte    // Mock server ML-DSA-65 public key
+	SigningKey   []byte    // Packed ML-DSA-65 private key matching ServerSigPk
+	CreatedAt    time.Time // Also the export time
+	ExpiresAt    time.Time // CreatedAt plus TTL
+}
+
+// New generates a real ML-KEM-768 keypair and a mock ML-DSA-65 server
+// signing key for an inbox at emailAddress. The address is not validated;
+// that is left to the inbox import.
+func New(emailAddress string) (*Inbox, error) {
+	keypair, err := crypto.GenerateKeypair()
+	if err != nil {
+		return nil, fmt.Errorf("generate keypair: %w", err)
+	}
+	serverSigPk, serverPriv, err := crypto.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(keypair.PublicKey)
+	now := time.Now().UTC()
+	return &Inbox{
+		EmailAddress: emailAddress,
+		Keypair:      keypair,
+		InboxHash:    crypto.ToBase64URL(hash[:]),
+		ServerSigPk:  serverSigPk,
+		SigningKey:   serverPriv,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(TTL),
+	}, nil
+}
+
+// ExportJSON returns the inbox's export in the format of the VaultSandbox
+// specification Section 9, ready to decode into a vaultsandbox.ExportedInbox,
+// which this package cannot import.
+func (m *Inbox) ExportJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version      int       `json:"version"`
+		EmailAddress string    `json:"emailAddress"`
+		ExpiresAt    time.Time `json:"expiresAt"`
+		InboxHash    string    `json:"inboxHash"`
+		ServerSigPk  string    `json:"serverSigPk"`
+		SecretKey    string    `json:"secretKey"`
+		ExportedAt   time.Time `json:"exportedAt"`
+		Encrypted    bool      `json:"encrypted"`
+	}{
+		Version:      1,
+		EmailAddress: m.EmailAddress,
+		ExpiresAt:    m.ExpiresAt,
+		InboxHash:    m.InboxHash,
+		ServerSigPk:  crypto.ToBase64URL(m.ServerSigPk),
+		SecretKey:    crypto.ToBase64URL(m.Keypair.SecretKey),
+		ExportedAt:   m.CreatedAt,
+		Encrypted:    true,
+	})
+}
+
+// EncryptedPayload encrypts plaintext for the inbox with the inbox hash as
+// AAD and signs it with the mock server key.
+func (m *Inbox) EncryptedPayload(plaintext []byte) (*crypto.EncryptedPayload, error) {
+	return crypto.Encrypt(plaintext, m.Keypair.PublicKey, m.SigningKey, []byte(m.InboxHash))
+}
+
+// EmailJSON returns the JSON object the server sends for an email in the
+// inbox, with metadata and parsed (if non-nil) encrypted by
+// [Inbox.EncryptedPayload].
+func (m *Inbox) EmailJSON(emailID string, metadata, parsed any) (json.RawMessage, error) {
+	raw := &api.RawEmail{
+		ID:         emailID,
+		InboxID:    m.InboxHash,
+		ReceivedAt: time.Now().UTC(),
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata: %w", err)
+	}
+	raw.EncryptedMetadata, err = m.EncryptedPayload(metadataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed != nil {
+		parsedJSON, err := json.Marshal(parsed)
+		if err != nil {
+			return nil, fmt.Errorf("marshal parsed content: %w", err)
+		}
+		raw.EncryptedParsed, err = m.EncryptedPayload(parsedJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(raw)
+}
@@ -0,0 +1,57 @@
+// Package tracing defines the span hooks used inside the SDK. It has no
+// dependency on a tracing library; adapters such as the otel module
+// implement Tracer on top of one.
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts spans. Attributes are given as alternating keys and values.
+type Tracer interface {
+	Start(ctx context.Context, name string, keysAndValues ...any) (context.Context, Span)
+}
+
+// Span is an operation started by a Tracer.
+type Span interface {
+	SetAttributes(keysAndValues ...any)
+	RecordError(err error)
+	End()
+}
+
+// Injector is implemented by tracers that propagate the span context in
+// outgoing HTTP request headers.
+type Injector interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Nop is a Tracer whose spans do nothing.
+type Nop struct{}
+
+// Start returns ctx unchanged and a span that does nothing.
+func (Nop) Start(ctx context.Context, _ string, _ ...any) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...any) {}
+func (nopSpan) RecordError(error)    {}
+func (nopSpan) End()                 {}
+
+// OrNop returns t, or Nop if t is nil.
+func OrNop(t Tracer) Tracer {
+	if t == nil {
+		return Nop{}
+	}
+	return t
+}
+
+// End records err on span, if non-nil, and ends the span.
+func End(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
@@ -0,0 +1,9 @@
+// Package version holds the SDK version shared by the public package and
+// the internal HTTP client.
+package version
+
+// Version is the current SDK version.
+const Version = "0.9.2"
+
+// UserAgent is the default User-Agent sent with every API request.
+const UserAgent = "vaultsandbox-go/" + Version
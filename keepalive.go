@@ -0,0 +1,42 @@
+package vaultsandbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// keepAlivePingTimeout bounds a single keepalive request.
+const keepAlivePingTimeout = 10 * time.Second
+
+// startKeepAlive launches the pinger configured with [WithKeepAlivePing]. It
+// stops when ctx is cancelled and closes c.keepAliveDone on exit.
+func (c *Client) startKeepAlive(ctx context.Context, interval time.Duration) {
+	c.keepAliveDone = make(chan struct{})
+	go func() {
+		defer close(c.keepAliveDone)
+
+		clk := c.clock()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-clk.After(interval):
+				c.keepAlivePing(ctx)
+			}
+		}
+	}()
+}
+
+// keepAlivePing issues one unauthenticated health check, reporting failures
+// unless the client is shutting down.
+func (c *Client) keepAlivePing(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, keepAlivePingTimeout)
+	defer cancel()
+
+	if err := c.apiClient.Ping(pingCtx); err != nil && ctx.Err() == nil {
+		if c.onSyncError != nil {
+			c.onSyncError(fmt.Errorf("keepalive ping: %w", err))
+		}
+	}
+}
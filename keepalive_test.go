@@ -0,0 +1,180 @@
+package vaultsandbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/clock"
+)
+
+// newKeepAliveServer serves check-key, server-info and health, counting
+// health checks. Health checks fail while failing is set.
+func newKeepAliveServer(t *testing.T, pings *atomic.Int32, failing *atomic.Bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/health":
+			pings.Add(1)
+			if r.Header.Get("X-API-Key") != "" {
+				t.Error("keepalive ping sent the API key")
+			}
+			if failing.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"status":"ok"}`))
+		case "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+		case "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestKeepAlivePing(t *testing.T) {
+	t.Parallel()
+	var pings atomic.Int32
+	var failing atomic.Bool
+	server := newKeepAliveServer(t, &pings, &failing)
+
+	client, err := New("test-api-key",
+		WithBaseURL(server.URL),
+		WithDeliveryStrategy(StrategyPolling),
+		WithKeepAlivePing(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for pings.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := pings.Load(); got < 3 {
+		t.Fatalf("pings = %d, want at least 3", got)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	stopped := pings.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := pings.Load(); got != stopped {
+		t.Errorf("pings after Close = %d, want %d", got, stopped)
+	}
+}
+
+func TestKeepAlivePing_FakeClock(t *testing.T) {
+	t.Parallel()
+	var pings atomic.Int32
+	var failing atomic.Bool
+	server := newKeepAliveServer(t, &pings, &failing)
+
+	// Only the pinger runs on the fake clock, so BlockUntil(1) waits for it.
+	apiClient, err := api.New("test-api-key", api.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("api.New() error = %v", err)
+	}
+	fake := clock.NewFake(testEpoch)
+	client := &Client{apiClient: apiClient, clk: fake}
+	ctx, cancel := context.WithCancel(context.Background())
+	client.startKeepAlive(ctx, time.Minute)
+	defer func() {
+		cancel()
+		<-client.keepAliveDone
+	}()
+
+	for want := int32(1); want <= 2; want++ {
+		fake.BlockUntil(1)
+		if got := pings.Load(); got != want-1 {
+			t.Fatalf("pings before interval %d elapsed = %d, want %d", want, got, want-1)
+		}
+		fake.Advance(time.Minute)
+		deadline := time.Now().Add(2 * time.Second)
+		for pings.Load() < want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := pings.Load(); got != want {
+			t.Fatalf("pings after interval %d = %d, want %d", want, got, want)
+		}
+	}
+}
+
+func TestKeepAlivePing_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+	var pings atomic.Int32
+	var failing atomic.Bool
+	server := newKeepAliveServer(t, &pings, &failing)
+
+	client, err := New("test-api-key", WithBaseURL(server.URL), WithDeliveryStrategy(StrategyPolling))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.keepAliveDone != nil {
+		t.Error("keepalive pinger started without WithKeepAlivePing")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if got := pings.Load(); got != 0 {
+		t.Errorf("pings = %d, want 0", got)
+	}
+}
+
+func TestKeepAlivePing_ReportsFailures(t *testing.T) {
+	t.Parallel()
+	var pings atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	server := newKeepAliveServer(t, &pings, &failing)
+
+	errCh := make(chan error, 10)
+	client, err := New("test-api-key",
+		WithBaseURL(server.URL),
+		WithDeliveryStrategy(StrategyPolling),
+		WithKeepAlivePing(10*time.Millisecond),
+		WithOnSyncError(func(err error) {
+			select {
+			case errCh <- err:
+			default:
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Fatal("reported nil error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ping failure was not reported")
+	}
+}
+
+func TestWithKeepAlivePing(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithKeepAlivePing(30 * time.Second)(cfg)
+	if cfg.keepAliveInterval != 30*time.Second {
+		t.Errorf("keepAliveInterval = %v, want 30s", cfg.keepAliveInterval)
+	}
+}
@@ -0,0 +1,52 @@
+package vaultsandbox
+
+import (
+	"log/slog"
+
+	"github.com/vaultsandbox/client-go/internal/logging"
+)
+
+// Logger receives structured log records from the client. Each method takes
+// a message followed by alternating keys and values, the calling convention
+// of [log/slog.Logger]. Implementations must be safe for concurrent use.
+//
+// The client logs each HTTP attempt (method, path, status, duration) and
+// each retry at debug level, and delivery connection transitions (connects,
+// disconnects, webhook registrations) at info level and above. Request
+// headers and bodies, including the API key, are never logged.
+//
+// Set one with [WithLogger] or [WithSlog]; by default nothing is logged.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// *slog.Logger is used as a Logger directly by WithSlog.
+var _ Logger = (*slog.Logger)(nil)
+
+// WithSlog sends the client's logs to logger. Records carry attributes such
+// as method, path, status, attempt, and duration for HTTP requests, and inbox
+// and email_id for delivered emails; levels map to the slog levels of the
+// same name. A nil logger disables logging.
+//
+// It is the [log/slog] counterpart of [WithLogger], and unlike passing a
+// *slog.Logger to WithLogger it treats a nil pointer as "no logger".
+func WithSlog(logger *slog.Logger) Option {
+	return func(c *clientConfig) {
+		if logger == nil {
+			c.logger = nil
+			return
+		}
+		c.logger = logger
+	}
+}
+
+// log returns the client's logger, or a no-op logger if none is set.
+func (c *Client) log() Logger {
+	if c.logger == nil {
+		return logging.Nop{}
+	}
+	return c.logger
+}
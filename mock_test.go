@@ -0,0 +1,31 @@
+package vaultsandbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vaultsandbox/client-go/internal/mockinbox"
+)
+
+// newMockInbox creates an encrypted, detached inbox offline, as
+// vaultsandboxtest.NewMockInbox does, returning it together with the mock
+// key material that builds the emails it accepts.
+func newMockInbox(emailAddress string) (*Inbox, *mockinbox.Inbox, error) {
+	mock, err := mockinbox.New(emailAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := mock.ExportJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	var exported ExportedInbox
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, nil, fmt.Errorf("decode mock export: %w", err)
+	}
+	inbox, err := newInboxFromExport(&exported, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inbox, mock, nil
+}
@@ -0,0 +1,168 @@
+package vaultsandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errMonitorStopped is returned by InboxMonitor methods after the monitor
+// has stopped.
+var errMonitorStopped = errors.New("inbox monitor stopped")
+
+// InboxMonitor watches a changing set of inboxes, delivering their emails on
+// one channel in arrival order. Create one with [Client.MonitorInboxes].
+// All methods are safe for concurrent use.
+type InboxMonitor struct {
+	client *Client
+	queue  *watchQueue[*InboxEvent]
+	events chan *InboxEvent
+	errs   chan error
+
+	mu      sync.Mutex
+	watched map[string]*monitoredInbox // by email address
+	stopped bool
+}
+
+// monitoredInbox is an InboxMonitor's subscription to one inbox.
+type monitoredInbox struct {
+	unsubscribe func()
+}
+
+// MonitorInboxes starts monitoring the given inboxes and returns the running
+// monitor. Unlike [Client.WatchInboxesChan], inboxes can be added and
+// removed while the monitor runs, so a long-lived monitor can follow inboxes
+// as they are created and deleted.
+//
+// The monitor stops when ctx is done, or when the client is closed while it
+// monitors at least one inbox, which sends [ErrClientClosed] on the error
+// channel. A monitored inbox that is deleted through this client is removed
+// from the monitor, which keeps running even when it has no inboxes left. It returns an error if any of
+// the inboxes cannot be added (see [InboxMonitor.Add]).
+//
+// Example:
+//
+//	monitor, err := client.MonitorInboxes(ctx, inbox1)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	go func() {
+//	    inbox2, _ := client.CreateInbox(ctx)
+//	    monitor.Add(inbox2)
+//	}()
+//	for event := range monitor.Events() {
+//	    fmt.Printf("Email in %s: %s\n", event.Inbox.EmailAddress(), event.Email.Subject)
+//	}
+func (c *Client) MonitorInboxes(ctx context.Context, inboxes ...*Inbox) (*InboxMonitor, error) {
+	m := &InboxMonitor{
+		client:  c,
+		queue:   newWatchQueue[*InboxEvent](),
+		events:  make(chan *InboxEvent),
+		errs:    make(chan error, 1),
+		watched: make(map[string]*monitoredInbox),
+	}
+	for _, inbox := range inboxes {
+		if err := m.Add(inbox); err != nil {
+			m.stop()
+			return nil, err
+		}
+	}
+	go m.queue.run(ctx, m.events, m.errs, m.stop)
+	return m, nil
+}
+
+// Events returns the channel of monitored emails. It is closed when the
+// monitor stops.
+func (m *InboxMonitor) Events() <-chan *InboxEvent {
+	return m.events
+}
+
+// Errors returns a channel that receives the error that stopped the
+// monitor, if any. It is closed after the Events channel.
+func (m *InboxMonitor) Errors() <-chan error {
+	return m.errs
+}
+
+// Add starts monitoring inbox. The inbox must be managed by the monitor's
+// client and not already monitored. Emails that arrived before Add are not
+// delivered.
+func (m *InboxMonitor) Add(inbox *Inbox) error {
+	if inbox == nil {
+		return fmt.Errorf("inbox cannot be nil")
+	}
+	if inbox.client == nil {
+		return ErrDetachedInbox
+	}
+	if inbox.client != m.client {
+		return fmt.Errorf("inbox %s belongs to a different client", inbox.emailAddress)
+	}
+
+	// Lock order is client, then monitor, then subscriptions, matching
+	// the inbox deletion path that ends the subscription.
+	m.client.mu.RLock()
+	defer m.client.mu.RUnlock()
+	if m.client.closed {
+		return ErrClientClosed
+	}
+	if m.client.inboxes[inbox.emailAddress] != inbox {
+		return fmt.Errorf("inbox %s: %w", inbox.emailAddress, ErrInboxNotFound)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return errMonitorStopped
+	}
+	if _, exists := m.watched[inbox.emailAddress]; exists {
+		return fmt.Errorf("inbox %s is already monitored", inbox.emailAddress)
+	}
+
+	entry := &monitoredInbox{}
+	entry.unsubscribe = m.client.subs.subscribeWithEnd(inbox.inboxHash, func(email *Email) {
+		if email != nil {
+			m.queue.push(&InboxEvent{Inbox: inbox, Email: email})
+		}
+	}, func(err error) {
+		if err != nil {
+			m.queue.end(err)
+			return
+		}
+		// The inbox was deleted, which already removed the subscription.
+		m.mu.Lock()
+		if m.watched[inbox.emailAddress] == entry {
+			delete(m.watched, inbox.emailAddress)
+		}
+		m.mu.Unlock()
+	})
+	m.watched[inbox.emailAddress] = entry
+	return nil
+}
+
+// Remove stops monitoring the inbox with the given address. Emails from it
+// that are already queued are still delivered.
+func (m *InboxMonitor) Remove(emailAddress string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return errMonitorStopped
+	}
+	entry, exists := m.watched[emailAddress]
+	if !exists {
+		return fmt.Errorf("inbox %s is not monitored", emailAddress)
+	}
+	entry.unsubscribe()
+	delete(m.watched, emailAddress)
+	return nil
+}
+
+// stop unsubscribes from every monitored inbox and rejects further changes.
+func (m *InboxMonitor) stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopped = true
+	for _, entry := range m.watched {
+		entry.unsubscribe()
+	}
+	clear(m.watched)
+}
@@ -0,0 +1,175 @@
+package vaultsandbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiveEvent waits for the next monitor event and checks its inbox and ID.
+func receiveEvent(t *testing.T, events <-chan *InboxEvent, inbox *Inbox, emailID string) {
+	t.Helper()
+	select {
+	case event := <-events:
+		if event.Inbox != inbox || event.Email.ID != emailID {
+			t.Errorf("event = (%s, %s), want (%s, %s)",
+				event.Inbox.EmailAddress(), event.Email.ID, inbox.EmailAddress(), emailID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("did not receive %s", emailID)
+	}
+}
+
+func TestInboxMonitor_AddAndRemove(t *testing.T) {
+	t.Parallel()
+	c, strategy, inbox1, inbox2 := newFakeStrategyClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor, err := c.MonitorInboxes(ctx, inbox1)
+	if err != nil {
+		t.Fatalf("MonitorInboxes() error = %v", err)
+	}
+
+	// inbox2 is not monitored yet.
+	strategy.deliver(t, inbox2.inboxHash, "ignored")
+	strategy.deliver(t, inbox1.inboxHash, "e1")
+	receiveEvent(t, monitor.Events(), inbox1, "e1")
+
+	if err := monitor.Add(inbox2); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	strategy.deliver(t, inbox2.inboxHash, "e2")
+	strategy.deliver(t, inbox1.inboxHash, "e3")
+	receiveEvent(t, monitor.Events(), inbox2, "e2")
+	receiveEvent(t, monitor.Events(), inbox1, "e3")
+
+	if err := monitor.Remove(inbox1.EmailAddress()); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	strategy.deliver(t, inbox1.inboxHash, "removed")
+	strategy.deliver(t, inbox2.inboxHash, "e4")
+	receiveEvent(t, monitor.Events(), inbox2, "e4")
+
+	cancel()
+	for event := range monitor.Events() {
+		t.Errorf("unexpected event %s", event.Email.ID)
+	}
+	if err, ok := <-monitor.Errors(); ok {
+		t.Errorf("Errors() received %v after cancel, want it closed", err)
+	}
+	if err := monitor.Add(inbox1); !errors.Is(err, errMonitorStopped) {
+		t.Errorf("Add() after stop error = %v, want errMonitorStopped", err)
+	}
+}
+
+func TestInboxMonitor_AddErrors(t *testing.T) {
+	t.Parallel()
+	c, _, inbox1, _ := newFakeStrategyClient(t)
+	other := &Client{subs: newSubscriptionManager()}
+
+	monitor, err := c.MonitorInboxes(context.Background(), inbox1)
+	if err != nil {
+		t.Fatalf("MonitorInboxes() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		inbox   *Inbox
+		wantErr error
+	}{
+		{"nil inbox", nil, nil},
+		{"detached", &Inbox{emailAddress: "detached@example.com"}, ErrDetachedInbox},
+		{"other client", &Inbox{emailAddress: "x@example.com", client: other}, nil},
+		{"untracked", &Inbox{emailAddress: "gone@example.com", client: c}, ErrInboxNotFound},
+		{"already monitored", inbox1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := monitor.Add(tt.inbox)
+			if err == nil {
+				t.Fatal("Add() error = nil, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := monitor.Remove("unknown@example.com"); err == nil {
+		t.Error("Remove() of an unmonitored inbox error = nil, want error")
+	}
+}
+
+func TestInboxMonitor_DeletedInboxIsRemoved(t *testing.T) {
+	t.Parallel()
+	c, strategy, inbox1, inbox2 := newFakeStrategyClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor, err := c.MonitorInboxes(ctx, inbox1, inbox2)
+	if err != nil {
+		t.Fatalf("MonitorInboxes() error = %v", err)
+	}
+
+	c.untrackInbox(inbox1.emailAddress)
+	if err := monitor.Remove(inbox1.EmailAddress()); err == nil {
+		t.Error("Remove() of a deleted inbox error = nil, want error")
+	}
+
+	// The monitor keeps running for the remaining inbox.
+	strategy.deliver(t, inbox2.inboxHash, "e1")
+	receiveEvent(t, monitor.Events(), inbox2, "e1")
+}
+
+func TestInboxMonitor_ClientClosed(t *testing.T) {
+	t.Parallel()
+	c, _, inbox1, _ := newFakeStrategyClient(t)
+
+	monitor, err := c.MonitorInboxes(context.Background(), inbox1)
+	if err != nil {
+		t.Fatalf("MonitorInboxes() error = %v", err)
+	}
+	c.Close()
+
+	for range monitor.Events() {
+	}
+	if err := <-monitor.Errors(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Errors() received %v, want ErrClientClosed", err)
+	}
+	if _, err := c.MonitorInboxes(context.Background(), inbox1); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("MonitorInboxes() on closed client error = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestInboxMonitor_ConcurrentAddRemove(t *testing.T) {
+	t.Parallel()
+	c, strategy, inbox1, inbox2 := newFakeStrategyClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor, err := c.MonitorInboxes(ctx)
+	if err != nil {
+		t.Fatalf("MonitorInboxes() error = %v", err)
+	}
+	go func() {
+		for range monitor.Events() {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, inbox := range []*Inbox{inbox1, inbox2} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				monitor.Add(inbox)
+				strategy.deliver(t, inbox.inboxHash, "e")
+				monitor.Remove(inbox.EmailAddress())
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,9 +1,16 @@
 package vaultsandbox
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/vaultsandbox/client-go/authresults"
+	"github.com/vaultsandbox/client-go/internal/clock"
 )
 
 // DeliveryStrategy specifies how the client receives new emails.
@@ -14,6 +21,10 @@ const (
 	StrategySSE DeliveryStrategy = "sse"
 	// StrategyPolling uses periodic API calls with exponential backoff.
 	StrategyPolling DeliveryStrategy = "polling"
+	// StrategyWebhook registers a webhook per inbox and receives
+	// notifications on [Client.WebhookHandler]. Select it with
+	// [WithWebhookDelivery].
+	StrategyWebhook DeliveryStrategy = "webhook"
 )
 
 const (
@@ -29,15 +40,77 @@ type clientConfig struct {
 	timeout          time.Duration
 	retries          int
 	retryOn          []int
+	retryJitter      float64
+	maxElapsedTime   time.Duration
+	rateLimit        float64
+	rateBurst        int
+	retryDecider     func(resp *http.Response, err error) bool
+	onRetry          func(attempt int, statusCode int, delay time.Duration, err error)
 
 	// Polling configuration
 	pollingInitialInterval   time.Duration
 	pollingMaxBackoff        time.Duration
 	pollingBackoffMultiplier float64
 	pollingJitterFactor      float64
+	monitorConcurrency       int
+
+	// Polling interval bounds; validated in New when pollingBoundsSet
+	pollingBoundsSet   bool
+	pollingMinInterval time.Duration
+	pollingMaxInterval time.Duration
 
 	// Error callback for background sync failures
 	onSyncError func(error)
+
+	// Maximum decoded size of a single attachment (0 = unlimited)
+	maxAttachmentSize int
+
+	// Tolerate server-info failures in New
+	serverInfoOptional bool
+
+	// Verify attachment SHA-256 checksums during decryption
+	verifyAttachmentChecksums bool
+
+	// Extra accepted server signing keys (base64url), for key rotation
+	additionalServerKeys []string
+
+	// Maximum allowed future skew of signed timestamps (0 = disabled)
+	clockSkewTolerance time.Duration
+	strictClockSkew    bool
+
+	// Destination for HTTP request/response dumps (nil = disabled)
+	wireLog io.Writer
+
+	// Structured logger for request and delivery lifecycle (nil = disabled)
+	logger Logger
+
+	// Span hooks for operations and HTTP attempts (nil = disabled)
+	tracer Tracer
+
+	// User-Agent header for API requests ("" = DefaultUserAgent)
+	userAgent string
+
+	// Client-wide limit on concurrent decryptions (0 = GOMAXPROCS)
+	maxConcurrentDecrypts int
+
+	// Which timestamp wins for Email.ReceivedAt
+	receivedAtSource ReceivedAtSource
+
+	// Interval between idle keepalive pings (0 = disabled)
+	keepAliveInterval time.Duration
+
+	// Public URL of the webhook handler, for StrategyWebhook
+	webhookURL string
+
+	// SSE connection lifecycle hooks
+	sseReconnectHook func(attempt int, err error)
+	sseConnectedHook func()
+
+	// Longest silence tolerated on the SSE stream (0 = no limit)
+	sseHeartbeatTimeout time.Duration
+
+	// Time source for expiry, timeouts, and backoff (nil = real clock)
+	clock clock.Clock
 }
 
 // EncryptionMode specifies the desired encryption mode for an inbox.
@@ -54,21 +127,61 @@ const (
 
 // inboxConfig holds configuration for inbox creation.
 type inboxConfig struct {
-	ttl          time.Duration
-	emailAddress string
-	emailAuth    *bool
-	encryption   EncryptionMode
-	spamAnalysis *bool
+	ttl            time.Duration
+	emailAddress   string
+	emailAuth      *bool
+	encryption     EncryptionMode
+	spamAnalysis   *bool
+	requireSuite   *AlgorithmSuite
+	aadFunc        AADFunc
+	collisionRetry int
 }
 
 // waitConfig holds configuration for waiting on emails.
 type waitConfig struct {
-	subject      string
-	subjectRegex *regexp.Regexp
-	from         string
-	fromRegex    *regexp.Regexp
-	predicate    func(*Email) bool
-	timeout      time.Duration
+	subject        string
+	subjectRegex   *regexp.Regexp
+	from           string
+	fromRegex      *regexp.Regexp
+	to             string
+	toRegex        *regexp.Regexp
+	recipientRegex *regexp.Regexp
+	bodyContains   string
+	bodyRegex      *regexp.Regexp
+	headers        []headerMatch
+	predicate      func(*Email) bool
+	predicateErr   func(*Email) (bool, error)
+	stopPredicate  func([]*Email) bool
+	minReceivedAt  time.Time
+	clock          clock.Clock // Time source for WithSince; nil means the real clock
+	timeout        time.Duration
+	autoMarkRead   bool
+	waitForParsed  bool
+	authResults    []authResultMatch
+	authPassing    *bool
+}
+
+// authResultMatch is a single WithAuthResult criterion.
+type authResultMatch struct {
+	check  string
+	result string
+}
+
+// headerMatch is a single WithHeader or WithHeaderRegex criterion; regex is
+// nil for an exact match on value.
+type headerMatch struct {
+	name  string
+	value string
+	regex *regexp.Regexp
+}
+
+// fetchConfig holds configuration for fetching emails.
+type fetchConfig struct {
+	serverFilter   *ServerFilter
+	readState      *bool
+	maxBodyBytes   int
+	fields         Field
+	decryptWorkers int
 }
 
 // Option configures the client.
@@ -80,6 +193,9 @@ type InboxOption func(*inboxConfig)
 // WaitOption configures email waiting.
 type WaitOption func(*waitConfig)
 
+// FetchOption configures email retrieval.
+type FetchOption func(*fetchConfig)
+
 // WithBaseURL sets the API base URL.
 func WithBaseURL(url string) Option {
 	return func(c *clientConfig) {
@@ -87,27 +203,115 @@ func WithBaseURL(url string) Option {
 	}
 }
 
-// WithHTTPClient sets a custom HTTP client.
+// WithHTTPClient sets a custom HTTP client, for example to route traffic
+// through a proxy, trust a private CA, or limit connections. Its Transport is
+// used for every request, including the SSE event stream, which runs without
+// the client's Timeout. The caller owns the client: its Timeout is kept as
+// is, so [WithTimeout] no longer bounds individual HTTP requests (it still
+// bounds the initial checks in [New]).
 func WithHTTPClient(client *http.Client) Option {
 	return func(c *clientConfig) {
 		c.httpClient = client
 	}
 }
 
-// WithDeliveryStrategy sets the delivery strategy.
+// WithDeliveryStrategy sets the delivery strategy. Without it the client
+// uses [StrategySSE], or [StrategyPolling] if [ServerInfo].SSEEnabled is
+// false; an explicitly chosen strategy is used as is.
 func WithDeliveryStrategy(strategy DeliveryStrategy) Option {
 	return func(c *clientConfig) {
 		c.deliveryStrategy = strategy
 	}
 }
 
-// WithTimeout sets the default timeout.
+// WithWebhookDelivery selects [StrategyWebhook]: instead of holding an SSE
+// connection or polling, the client registers an inbox webhook for every
+// inbox it manages, pointing at url, and the server pushes a notification
+// there for each new email. Mount [Client.WebhookHandler] so that it serves
+// url; it must be reachable from the VaultSandbox server. The webhooks are
+// deleted again by [Client.Close].
+//
+// Signatures of encrypted notifications are verified with the server key from
+// server info before the email is fetched. Registration runs in the
+// background and failures are reported to the [WithOnSyncError] callback.
+func WithWebhookDelivery(url string) Option {
+	return func(c *clientConfig) {
+		c.deliveryStrategy = StrategyWebhook
+		c.webhookURL = url
+	}
+}
+
+// WithSSEReconnectHook sets a function called each time the SSE event stream
+// drops and a reconnect is scheduled. attempt counts consecutive reconnect
+// attempts starting at 1; err is why the stream dropped. A hook that fires
+// repeatedly means delivery is flapping, which explains missing emails better
+// than a wait timeout does. Reconnects caused by adding or removing inboxes
+// are not reported. The hook runs on the SSE connection goroutine and must
+// not block. Ignored with [StrategyPolling].
+func WithSSEReconnectHook(fn func(attempt int, err error)) Option {
+	return func(c *clientConfig) {
+		c.sseReconnectHook = fn
+	}
+}
+
+// WithSSEConnectedHook sets a function called each time an SSE event stream
+// is established, including the first connection and every reconnect. Like
+// [WithSSEReconnectHook] it runs on the SSE connection goroutine and must not
+// block. Ignored with [StrategyPolling].
+func WithSSEConnectedHook(fn func()) Option {
+	return func(c *clientConfig) {
+		c.sseConnectedHook = fn
+	}
+}
+
+// WithSSEHeartbeatTimeout makes the SSE strategy drop and reopen the event
+// stream when nothing, not even a keep-alive comment, arrives on it for d.
+// Some proxies silently kill idle connections before the server's heartbeat;
+// without a timeout such a stream looks open but delivers nothing. Pick d
+// comfortably above the server's heartbeat interval. Zero, the default,
+// disables the check. Drops are reported to [WithSSEReconnectHook]. Ignored
+// with [StrategyPolling].
+func WithSSEHeartbeatTimeout(d time.Duration) Option {
+	return func(c *clientConfig) {
+		c.sseHeartbeatTimeout = d
+	}
+}
+
+// WithTimeout sets the default timeout. It bounds each HTTP request and the
+// initial API key and server-info checks in [New]. With [WithHTTPClient], the
+// custom client's own Timeout applies to requests instead.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *clientConfig) {
 		c.timeout = timeout
 	}
 }
 
+// WithRetryDecider sets a function that decides whether an API call is
+// retried, taking precedence over [WithRetryOn]. It is called after each
+// failed attempt with the error response (status 400 or above), whose body it
+// may read up to its first 64 KiB, or with the network error and a nil
+// response; successful responses are never retried. Use it to retry on a body-level "try again" code
+// or to skip retrying a 5xx that carries a permanent error. The number of
+// attempts is still bounded by [WithRetries].
+func WithRetryDecider(decider func(resp *http.Response, err error) bool) Option {
+	return func(c *clientConfig) {
+		c.retryDecider = decider
+	}
+}
+
+// WithOnRetry sets a callback invoked before each retry of an API call,
+// after a failed attempt and before the backoff delay. attempt is the retry
+// number starting at 1, statusCode the HTTP status of the failed attempt (0
+// for network errors), delay the backoff about to be slept, and err the
+// failure. Use it to log or count retries in flaky environments. The
+// callback runs on the request's goroutine and may be called concurrently
+// from parallel requests, so it must be safe for concurrent use.
+func WithOnRetry(fn func(attempt int, statusCode int, delay time.Duration, err error)) Option {
+	return func(c *clientConfig) {
+		c.onRetry = fn
+	}
+}
+
 // WithRetries sets the number of retries for API calls.
 func WithRetries(count int) Option {
 	return func(c *clientConfig) {
@@ -115,6 +319,49 @@ func WithRetries(count int) Option {
 	}
 }
 
+// WithRetryJitter randomizes each API retry delay by up to ±fraction of its
+// value, so that many clients failing at once (for example parallel CI
+// workers) do not retry in lockstep. With a fraction of 0.2, a 2s delay
+// becomes anything from 1.6s to 2.4s. New returns an error unless fraction
+// is in [0, 1). Default: 0 (deterministic doubling).
+//
+// This only affects API retries. The polling delivery strategy has its own
+// jitter, set with PollingConfig.JitterFactor.
+func WithRetryJitter(fraction float64) Option {
+	return func(c *clientConfig) {
+		c.retryJitter = fraction
+	}
+}
+
+// WithMaxElapsedTime bounds the total time of each API call, retries and
+// retry delays included. [WithTimeout] limits each attempt; with several
+// retries a call can otherwise take many times longer. Once the budget is
+// spent, or a retry's delay would overrun it, the client stops retrying even
+// if attempts remain, cancels any attempt still in flight, and returns the
+// last attempt's error wrapped in [ErrMaxElapsedTime]. A deadline on the
+// caller's context still applies and is reported as the context's error.
+// Default: 0 (unbounded).
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(c *clientConfig) {
+		c.maxElapsedTime = d
+	}
+}
+
+// WithRateLimit limits the client to rps API requests per second, allowing
+// bursts of up to burst requests, so that loops such as DeleteAllInboxes
+// stay under the server's rate limit instead of running into HTTP 429s.
+// Every outbound request, retries and event-stream connections included,
+// takes a token from the bucket; a call waiting for one returns the
+// context's error if its context ends first. New returns an error unless rps
+// is finite and non-negative and, when rps is positive, burst is at least 1.
+// Default: 0 (unlimited).
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *clientConfig) {
+		c.rateLimit = rps
+		c.rateBurst = burst
+	}
+}
+
 // WithRetryOn sets the HTTP status codes that trigger a retry.
 // Default: [408, 429, 500, 502, 503, 504]
 func WithRetryOn(statusCodes []int) Option {
@@ -131,6 +378,187 @@ func WithOnSyncError(fn func(error)) Option {
 	}
 }
 
+// WithMaxAttachmentSize limits the decoded size of each email attachment.
+// Attachment content is base64-decoded incrementally during decryption and
+// decoding aborts once the limit is exceeded, so a crafted payload cannot
+// force a large allocation. Emails containing an oversized attachment fail
+// with [ErrAttachmentTooLarge]. A value of zero (the default) means no limit.
+func WithMaxAttachmentSize(bytes int) Option {
+	return func(c *clientConfig) {
+		c.maxAttachmentSize = bytes
+	}
+}
+
+// WithVerifyAttachmentChecksums makes decryption check every attachment
+// against its checksum (see [Attachment.VerifyChecksum]). An email with a
+// mismatching attachment fails to decrypt with a [*ChecksumError] instead of
+// being returned. Attachments without a checksum are not checked. Disabled by
+// default, in which case Checksum is passed through unverified.
+func WithVerifyAttachmentChecksums() Option {
+	return func(c *clientConfig) {
+		c.verifyAttachmentChecksums = true
+	}
+}
+
+// WithAdditionalServerKeys adds server signing keys (base64url-encoded
+// ML-DSA-65 public keys) that encrypted inboxes accept besides the key pinned
+// when they were created or imported. Use it during a server key rotation,
+// so emails signed with the new key verify without re-importing the inbox.
+// Payloads signed by any other key are still rejected with a
+// [*SignatureVerificationError]. The keys apply to the client's inboxes and
+// to webhook events; they are not written by [Inbox.Export]. [New] returns
+// an error if a key is not a valid ML-DSA-65 public key.
+func WithAdditionalServerKeys(keys []string) Option {
+	return func(c *clientConfig) {
+		c.additionalServerKeys = append(c.additionalServerKeys, keys...)
+	}
+}
+
+// WithServerInfoOptional lets [New] succeed when the server-info endpoint
+// fails, as long as the API key check passes. This is useful against minimal
+// gateways that do not implement server-info. The failure is reported to the
+// [WithOnSyncError] callback, [Client.ServerInfo] returns nil, and inbox TTLs
+// are no longer validated client-side against the server maximum.
+func WithServerInfoOptional() Option {
+	return func(c *clientConfig) {
+		c.serverInfoOptional = true
+	}
+}
+
+// WithClockSkewTolerance enables a check on the signed receivedAt timestamp
+// of encrypted emails. An email whose timestamp is more than d ahead of the
+// local clock produces a [*ClockSkewWarning], which is reported to the
+// [WithOnSyncError] callback while the email is still delivered. Combine with
+// [WithStrictClockSkew] to reject such emails instead. The check is disabled
+// by default.
+func WithClockSkewTolerance(d time.Duration) Option {
+	return func(c *clientConfig) {
+		c.clockSkewTolerance = d
+	}
+}
+
+// WithStrictClockSkew makes the [WithClockSkewTolerance] check fatal: emails
+// exceeding the tolerance fail to decrypt with an error matching [ErrClockSkew].
+func WithStrictClockSkew() Option {
+	return func(c *clientConfig) {
+		c.strictClockSkew = true
+	}
+}
+
+// WithWireLogging writes every HTTP request and response to w for debugging:
+// method, URL, status, headers, and bodies. The X-API-Key header and key
+// material fields (secretKey, serverSigPk, webhook secrets) are redacted, and
+// bodies longer than 4 KiB are truncated. Event-stream bodies are not logged.
+//
+// Encrypted email payloads are still logged in their encrypted form. Do not
+// enable this in production.
+func WithWireLogging(w io.Writer) Option {
+	return func(c *clientConfig) {
+		c.wireLog = w
+	}
+}
+
+// WithLogger sets the logger that receives request and delivery lifecycle
+// records; see [Logger] for what is logged. Unlike [WithWireLogging], it
+// never records headers or bodies, so it is suitable for production. A nil
+// logger disables logging, which is the default.
+func WithLogger(logger Logger) Option {
+	return func(c *clientConfig) {
+		c.logger = logger
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every API request,
+// replacing [DefaultUserAgent]. To identify your application while keeping
+// the SDK version visible to server operators, append to the default:
+//
+//	vaultsandbox.WithUserAgent(vaultsandbox.DefaultUserAgent + " my-tests/1.0")
+//
+// An empty string keeps the default.
+func WithUserAgent(userAgent string) Option {
+	return func(c *clientConfig) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithMonitorConcurrency sets how many inboxes the polling strategy checks in
+// parallel during each poll cycle. The default of 1 polls inboxes one after
+// another, which keeps at most one request in flight but makes a cycle over
+// hundreds of inboxes slow. Higher values shorten the cycle at the cost of up
+// to n concurrent HTTP connections and a burst of requests against the
+// server's rate limit.
+//
+// SSE delivery is unaffected: all watched inboxes are multiplexed over a
+// single event-stream connection regardless of how many are monitored.
+func WithMonitorConcurrency(n int) Option {
+	return func(c *clientConfig) {
+		c.monitorConcurrency = n
+	}
+}
+
+// WithMaxConcurrentDecrypts caps how many payloads the client verifies and
+// decrypts at once, across all inboxes and all callers: GetEmails, GetEmail,
+// watchers, and WaitForEmail share the same budget. This bounds the CPU spent
+// on ML-DSA and ML-KEM work when many fetches run in parallel. Callers that
+// fan out decryption themselves are further limited by this cap. Values <= 0
+// use the default of runtime.GOMAXPROCS(0). Plain inboxes are unaffected.
+func WithMaxConcurrentDecrypts(n int) Option {
+	return func(c *clientConfig) {
+		c.maxConcurrentDecrypts = n
+	}
+}
+
+// ReceivedAtSource selects which timestamp populates Email.ReceivedAt.
+type ReceivedAtSource int
+
+const (
+	// SourceMetadata prefers the receivedAt value from the signed email
+	// metadata, falling back to the API timestamp. This is the default.
+	SourceMetadata ReceivedAtSource = iota
+	// SourceAPI prefers the timestamp reported by the API, falling back to
+	// the metadata value.
+	SourceAPI
+)
+
+// WithReceivedAtSource sets which timestamp is authoritative for
+// Email.ReceivedAt and EmailMetadata.ReceivedAt. The two can differ by a few
+// seconds; tests asserting precise timing should pick the source they
+// measure against. Either way the other source is used when the preferred
+// one is missing or cannot be parsed.
+func WithReceivedAtSource(source ReceivedAtSource) Option {
+	return func(c *clientConfig) {
+		c.receivedAtSource = source
+	}
+}
+
+// WithKeepAlivePing sends an unauthenticated health check (the request made
+// by [Client.Ping]) every interval to keep a warm connection in the HTTP
+// client's pool, without counting against the API key's quota. Test suites
+// that idle between bursts otherwise pay a fresh TCP and TLS handshake on the
+// first request after each gap. Failed pings are reported to the
+// [WithOnSyncError] callback and do not stop the pinger, which runs until
+// [Client.Close]. Values <= 0 disable it, which is the default.
+func WithKeepAlivePing(interval time.Duration) Option {
+	return func(c *clientConfig) {
+		c.keepAliveInterval = interval
+	}
+}
+
+// WithPollingBounds keeps every wait between polls of an inbox, jitter
+// included, between min and max. Polling starts at min (unless
+// [PollingConfig.InitialInterval] is set) and backs off toward max while no
+// new emails arrive. Tight bounds suit fast CI loops; loose ones reduce API
+// calls in quota-limited environments. Both must be positive and min must
+// not exceed max, or [New] returns an error. Only the polling strategy and
+// polling helpers such as [WithWaitForParsed] use the bounds.
+func WithPollingBounds(min, max time.Duration) Option {
+	return func(c *clientConfig) {
+		c.pollingBoundsSet = true
+		c.pollingMinInterval = min
+		c.pollingMaxInterval = max
+	}
+}
+
 // PollingConfig holds all polling-related configuration options.
 // The defaults work well for most use cases. Only customize these if you have
 // specific requirements around polling frequency or backoff behavior.
@@ -152,6 +580,7 @@ type PollingConfig struct {
 	BackoffMultiplier float64
 
 	// JitterFactor adds randomness to prevent synchronized polling.
+	// API retries are jittered separately, see [WithRetryJitter].
 	// Default: 0.3 (30%)
 	JitterFactor float64
 }
@@ -198,6 +627,18 @@ func WithEmailAddress(email string) InboxOption {
 	}
 }
 
+// WithCollisionRetry makes CreateInbox retry up to n more times when the
+// server reports that the generated address is already taken, so each
+// attempt gets a fresh random local part (and a fresh keypair). Addresses
+// with an explicit local part set via [WithEmailAddress] are never retried.
+// Either way, a collision that is not resolved is returned as an
+// [*InboxCollisionError]. Retries stop early if ctx is done.
+func WithCollisionRetry(n int) InboxOption {
+	return func(c *inboxConfig) {
+		c.collisionRetry = n
+	}
+}
+
 // WithEmailAuth controls email authentication (SPF, DKIM, DMARC, PTR) for the inbox.
 // When enabled, incoming emails are validated and results are available in AuthResults.
 // When disabled, authentication checks are skipped and results have status "skipped".
@@ -235,6 +676,38 @@ func WithSpamAnalysis(enabled bool) InboxOption {
 	}
 }
 
+// WithRequireSuite pins the algorithm suite accepted for the inbox's
+// encrypted payloads. Any payload whose algorithms differ from suite in any
+// field is rejected with [ErrUnexpectedSuite] before signature verification.
+// Without this option the suite defined by the VaultSandbox specification
+// ([DefaultAlgorithmSuite]) is required. The pinned suite is kept in the
+// inbox's export and restored on import.
+//
+// Only [DefaultAlgorithmSuite] can be decrypted, so CreateInbox fails with
+// [ErrUnsupportedSuite] for any other suite, as do ImportInbox and
+// NewDetachedInbox for exported data that pins one.
+func WithRequireSuite(suite AlgorithmSuite) InboxOption {
+	return func(c *inboxConfig) {
+		c.requireSuite = &suite
+	}
+}
+
+// WithAAD sets how the inbox derives the associated data (AAD) its
+// encrypted email payloads must carry, for servers that bind more than the
+// protocol default into it. fn is called for each payload before its
+// signature is verified; a payload whose AAD differs from the result is
+// rejected with [ErrUnexpectedAAD]. Without this option the AAD is not
+// checked, as the signature already covers it.
+//
+// The hook applies to payloads of emails fetched from the server and to
+// [Inbox.DecryptEmailPayload]. It is not included in [Inbox.Export]; set it
+// on an imported inbox with [Inbox.SetAAD].
+func WithAAD(fn AADFunc) InboxOption {
+	return func(c *inboxConfig) {
+		c.aadFunc = fn
+	}
+}
+
 // WithSubject filters emails by exact subject match.
 func WithSubject(subject string) WaitOption {
 	return func(c *waitConfig) {
@@ -263,6 +736,68 @@ func WithFromRegex(pattern *regexp.Regexp) WaitOption {
 	}
 }
 
+// WithTo filters emails addressed to the given recipient. It matches if any
+// address in To equals to, compared case-insensitively.
+func WithTo(to string) WaitOption {
+	return func(c *waitConfig) {
+		c.to = to
+	}
+}
+
+// WithToRegex filters emails by recipient regex. It matches if any address
+// in To matches the pattern. Unlike [WithRecipientPattern], the envelope
+// recipient is not considered.
+func WithToRegex(pattern *regexp.Regexp) WaitOption {
+	return func(c *waitConfig) {
+		c.toRegex = pattern
+	}
+}
+
+// WithRecipientPattern filters emails by recipient regex. The pattern is
+// matched against the envelope recipient and every address in To, so a
+// catch-all inbox can be narrowed to sub-addresses such as order-.*@domain.
+func WithRecipientPattern(pattern *regexp.Regexp) WaitOption {
+	return func(c *waitConfig) {
+		c.recipientRegex = pattern
+	}
+}
+
+// WithBodyContains filters emails whose text or HTML body contains substr.
+// The comparison is case-sensitive; a match in either body satisfies it.
+func WithBodyContains(substr string) WaitOption {
+	return func(c *waitConfig) {
+		c.bodyContains = substr
+	}
+}
+
+// WithBodyRegex filters emails whose text or HTML body matches the pattern.
+// A match in either body satisfies it.
+func WithBodyRegex(pattern *regexp.Regexp) WaitOption {
+	return func(c *waitConfig) {
+		c.bodyRegex = pattern
+	}
+}
+
+// WithHeader filters emails that have a header named name with exactly the
+// given value, for example a correlation header such as X-Campaign-ID. The
+// name is matched case-insensitively; for a header that occurs more than
+// once, any occurrence may match. Repeat the option to require several
+// headers.
+func WithHeader(name, value string) WaitOption {
+	return func(c *waitConfig) {
+		c.headers = append(c.headers, headerMatch{name: name, value: value})
+	}
+}
+
+// WithHeaderRegex filters emails that have a header named name whose value
+// matches the pattern. Names and repeated headers are handled as by
+// [WithHeader].
+func WithHeaderRegex(name string, pattern *regexp.Regexp) WaitOption {
+	return func(c *waitConfig) {
+		c.headers = append(c.headers, headerMatch{name: name, regex: pattern})
+	}
+}
+
 // WithPredicate filters emails by custom predicate.
 func WithPredicate(fn func(*Email) bool) WaitOption {
 	return func(c *waitConfig) {
@@ -270,6 +805,120 @@ func WithPredicate(fn func(*Email) bool) WaitOption {
 	}
 }
 
+// WithPredicateErr filters emails by a custom predicate that can fail. A
+// non-nil error aborts [Inbox.WaitForEmail], [Inbox.WaitForEmailCount] and
+// [Inbox.WaitForEmails] at once, returning the error wrapped; use it for
+// conditions that should fail a test immediately, such as a malformed email.
+// fn is only called for emails that pass the other filters. Watch, WatchFunc
+// and WatchChan cannot abort, so for them an error counts as no match. It can
+// be combined with [WithPredicate].
+func WithPredicateErr(fn func(*Email) (bool, error)) WaitOption {
+	return func(c *waitConfig) {
+		c.predicateErr = fn
+	}
+}
+
+// WithStopPredicate lets WaitForEmailCount finish before count emails have
+// arrived. fn is called with the matching emails collected so far each time
+// a new one is added; once it returns true the wait completes immediately and
+// returns that set. fn must not modify the slice. Ignored by WaitForEmail,
+// Watch and WatchFunc.
+//
+// Example — expect three notifications, but stop at the confirmation:
+//
+//	emails, err := inbox.WaitForEmailCount(ctx, 3,
+//	    vaultsandbox.WithStopPredicate(func(got []*vaultsandbox.Email) bool {
+//	        return got[len(got)-1].Subject == "Order confirmed"
+//	    }))
+func WithStopPredicate(fn func([]*Email) bool) WaitOption {
+	return func(c *waitConfig) {
+		c.stopPredicate = fn
+	}
+}
+
+// WithMinReceivedAt filters for emails received at or after t, ignoring
+// older emails already in the inbox, as in a reused or imported inbox.
+// ReceivedAt is stamped by the server, so allow for clock skew between the
+// server and the local machine when choosing t.
+func WithMinReceivedAt(t time.Time) WaitOption {
+	return func(c *waitConfig) {
+		c.minReceivedAt = t
+	}
+}
+
+// WithSince filters for emails received at most d before the wait starts.
+// It is [WithMinReceivedAt] with a threshold of d before the moment the
+// option is applied, so an option value reused across waits is relative to
+// each wait. The moment is read from the client's clock. WithSince(0)
+// ignores every email received before the wait.
+func WithSince(d time.Duration) WaitOption {
+	return func(c *waitConfig) {
+		c.minReceivedAt = clock.OrReal(c.clock).Now().Add(-d)
+	}
+}
+
+// WithAuthResult filters emails by the result of one authentication check.
+// check is "spf", "dkim", "dmarc", or "reverseDns" and result is the raw
+// result such as "pass", "fail", or "softfail"; both are compared
+// case-insensitively. A DKIM criterion matches if any signature has the
+// result. Emails without authentication results never match.
+//
+// Authentication results are part of the parsed email content, so every
+// candidate is fully decrypted before it can be matched. Repeat the option to
+// require several results.
+func WithAuthResult(check, result string) WaitOption {
+	return func(c *waitConfig) {
+		c.authResults = append(c.authResults, authResultMatch{check: check, result: result})
+	}
+}
+
+// WithAuthFailing filters for emails that fail authentication, as decided by
+// [authresults.AuthResults.Validate]: at least one of SPF, DKIM, or DMARC
+// failed or is missing. Emails without any authentication results do not
+// match, since they were not evaluated. Useful for confirming that a filter
+// catches spoofed mail.
+func WithAuthFailing() WaitOption {
+	return func(c *waitConfig) {
+		passing := false
+		c.authPassing = &passing
+	}
+}
+
+// WithAuthPassing filters for emails whose SPF, DKIM, and DMARC checks all
+// pass, as decided by [authresults.AuthResults.Validate].
+func WithAuthPassing() WaitOption {
+	return func(c *waitConfig) {
+		passing := true
+		c.authPassing = &passing
+	}
+}
+
+// WithAutoMarkRead marks emails returned by WaitForEmail, WaitForEmailCount
+// and WaitForEmails as read before returning them. Marking is best-effort:
+// failures are reported to the [WithOnSyncError] callback and do not fail the
+// wait. Waits with WithAutoMarkRead only match emails the server reports as
+// unread, so each email is returned once, however it was marked read. Ignored
+// by Watch and WatchFunc.
+func WithAutoMarkRead() WaitOption {
+	return func(c *waitConfig) {
+		c.autoMarkRead = true
+	}
+}
+
+// WithWaitForParsed makes WaitForEmail and WaitForEmailCount return only
+// emails whose body the server has finished processing (see
+// [Email.BodyReady]). A candidate delivered before its body was parsed is
+// first checked against the metadata filters (subject, sender, recipients,
+// [WithSince]); if it matches, it is re-fetched with the client's polling
+// intervals until it is ready, and only then matched against the remaining
+// filters, so body-based filters never see an empty placeholder body.
+// Ignored by Watch and WatchFunc.
+func WithWaitForParsed() WaitOption {
+	return func(c *waitConfig) {
+		c.waitForParsed = true
+	}
+}
+
 // WithWaitTimeout sets the timeout for waiting.
 func WithWaitTimeout(timeout time.Duration) WaitOption {
 	return func(c *waitConfig) {
@@ -277,9 +926,208 @@ func WithWaitTimeout(timeout time.Duration) WaitOption {
 	}
 }
 
+// ServerFilter restricts which emails GetEmails returns.
+// Empty fields are ignored. Matching is a case-insensitive substring match.
+type ServerFilter struct {
+	// Subject matches emails whose subject contains this text.
+	Subject string
+	// From matches emails whose sender contains this text.
+	From string
+}
+
+// WithServerFilter filters the emails returned by GetEmails.
+//
+// For plain inboxes the filter is sent to the server as query parameters
+// (subject=, from=), so non-matching emails are never transferred. For
+// encrypted inboxes the server cannot read the metadata, so the full list
+// is fetched and decrypted and the filter is applied client-side.
+//
+// In both cases the result is filtered locally as well, so the returned
+// emails are the same whether or not the server supports filtering.
+func WithServerFilter(filter ServerFilter) FetchOption {
+	return func(c *fetchConfig) {
+		c.serverFilter = &filter
+	}
+}
+
+// WithUnreadOnly restricts GetEmails and GetEmailsCached to emails that have
+// not been marked as read. The read state is not encrypted, so the server is
+// asked to filter for every inbox; the result is also filtered locally, before
+// decryption, for servers that ignore the request.
+func WithUnreadOnly() FetchOption {
+	return func(c *fetchConfig) {
+		unread := false
+		c.readState = &unread
+	}
+}
+
+// WithReadOnly restricts GetEmails and GetEmailsCached to emails that have
+// been marked as read. It is filtered like [WithUnreadOnly].
+func WithReadOnly() FetchOption {
+	return func(c *fetchConfig) {
+		read := true
+		c.readState = &read
+	}
+}
+
+// WithMaxBodyBytes truncates each email's Text and HTML to at most n bytes
+// after decryption, setting [Email.BodyTruncated] when either was shortened.
+// Truncation never splits a multi-byte UTF-8 character, so a body may be cut
+// slightly shorter than n. Useful for listings and logs; fetch the email
+// again without this option for the full body. Values <= 0 disable
+// truncation.
+//
+// For gateways that send parsed content compressed, n also bounds how far
+// the decompressed content (bodies, headers and attachments together) may
+// expand; an email beyond it fails with [ErrDecompressedTooLarge] instead of
+// being truncated.
+func WithMaxBodyBytes(n int) FetchOption {
+	return func(c *fetchConfig) {
+		c.maxBodyBytes = n
+	}
+}
+
+// Field selects groups of [Email] fields for [WithFields]. Combine fields
+// with bitwise OR.
+type Field uint
+
+const (
+	// FieldSubject populates Subject.
+	FieldSubject Field = 1 << iota
+	// FieldFrom populates From.
+	FieldFrom
+	// FieldBody populates Text, HTML, Links, Headers, and the fields derived
+	// from headers such as ListUnsubscribe.
+	FieldBody
+	// FieldAttachments populates Attachments.
+	FieldAttachments
+	// FieldAuth populates AuthResults and SpamAnalysis.
+	FieldAuth
+
+	// FieldAll populates every field. It is the default.
+	FieldAll = FieldSubject | FieldFrom | FieldBody | FieldAttachments | FieldAuth
+
+	// fieldsParsed are the fields that require the parsed-content payload.
+	fieldsParsed = FieldBody | FieldAttachments | FieldAuth
+)
+
+// WithFields restricts the fields GetEmails and GetEmail populate. ID, To,
+// EnvelopeTo, ReceivedAt, and IsRead are always set; unrequested fields are
+// left at their zero value.
+//
+// Subject and From come from the small metadata payload. The body,
+// attachments, and auth results live in a separate, much larger parsed-content
+// payload; when none of FieldBody, FieldAttachments, or FieldAuth is requested
+// that payload is neither downloaded (for GetEmails) nor decrypted, which
+// roughly halves the crypto work per email for list-style consumers.
+//
+// [WithServerFilter] is evaluated before fields are dropped, so filtering on
+// Subject works without requesting FieldSubject.
+func WithFields(fields Field) FetchOption {
+	return func(c *fetchConfig) {
+		c.fields = fields
+	}
+}
+
+// WithDecryptWorkers sets how many emails GetEmails and GetEmailsCached
+// verify and decrypt in parallel. Values <= 0 use the default of
+// runtime.GOMAXPROCS(0); 1 decrypts sequentially. The returned slice keeps
+// the server's order regardless. Workers still share the client-wide budget
+// set by [WithMaxConcurrentDecrypts], so raising n beyond it has no effect.
+func WithDecryptWorkers(n int) FetchOption {
+	return func(c *fetchConfig) {
+		c.decryptWorkers = n
+	}
+}
+
+// resolvedFields returns the requested fields, defaulting to FieldAll.
+func (c *fetchConfig) resolvedFields() Field {
+	if c.fields == 0 {
+		return FieldAll
+	}
+	return c.fields
+}
+
+// matchesReadState reports whether an email with the given read state passes
+// WithReadOnly or WithUnreadOnly.
+func (c *fetchConfig) matchesReadState(isRead bool) bool {
+	return c.readState == nil || *c.readState == isRead
+}
+
+// matches checks if an email matches the filter.
+func (f *ServerFilter) matches(e *Email) bool {
+	if f.Subject != "" && !strings.Contains(strings.ToLower(e.Subject), strings.ToLower(f.Subject)) {
+		return false
+	}
+	if f.From != "" && !strings.Contains(strings.ToLower(e.From), strings.ToLower(f.From)) {
+		return false
+	}
+	return true
+}
 
-// Matches checks if an email matches the wait criteria.
+// Matches checks if an email matches the wait criteria. An error from the
+// WithPredicateErr predicate counts as no match; use evaluate to see it.
 func (w *waitConfig) Matches(e *Email) bool {
+	ok, err := w.evaluate(e)
+	return ok && err == nil
+}
+
+// evaluate checks if an email matches the wait criteria, returning the
+// wrapped error from the WithPredicateErr predicate if it fails.
+func (w *waitConfig) evaluate(e *Email) (bool, error) {
+	if !w.matchesFilters(e) {
+		return false, nil
+	}
+	if w.predicateErr == nil {
+		return true, nil
+	}
+	ok, err := w.predicateErr(e)
+	if err != nil {
+		return false, fmt.Errorf("wait predicate for email %s: %w", e.ID, err)
+	}
+	return ok, nil
+}
+
+// matchesFilters checks every criterion except the WithPredicateErr
+// predicate.
+func (w *waitConfig) matchesFilters(e *Email) bool {
+	if !w.matchesMetadata(e) {
+		return false
+	}
+	if w.bodyContains != "" && !strings.Contains(e.Text, w.bodyContains) && !strings.Contains(e.HTML, w.bodyContains) {
+		return false
+	}
+	if w.bodyRegex != nil && !w.bodyRegex.MatchString(e.Text) && !w.bodyRegex.MatchString(e.HTML) {
+		return false
+	}
+	for _, m := range w.headers {
+		if !matchesHeader(e, m) {
+			return false
+		}
+	}
+	for _, m := range w.authResults {
+		if !matchesAuthResult(e.AuthResults, m) {
+			return false
+		}
+	}
+	if w.authPassing != nil {
+		if e.AuthResults == nil || e.AuthResults.Validate().Passed != *w.authPassing {
+			return false
+		}
+	}
+	if w.predicate != nil && !w.predicate(e) {
+		return false
+	}
+	return true
+}
+
+// matchesMetadata checks the criteria that depend only on email metadata
+// (receipt time, subject, sender, and recipients), which is available before
+// the server has parsed the body.
+func (w *waitConfig) matchesMetadata(e *Email) bool {
+	if !w.minReceivedAt.IsZero() && e.ReceivedAt.Before(w.minReceivedAt) {
+		return false
+	}
 	if w.subject != "" && e.Subject != w.subject {
 		return false
 	}
@@ -292,8 +1140,98 @@ func (w *waitConfig) Matches(e *Email) bool {
 	if w.fromRegex != nil && !w.fromRegex.MatchString(e.From) {
 		return false
 	}
-	if w.predicate != nil && !w.predicate(e) {
+	if w.to != "" && !slices.ContainsFunc(e.To, func(to string) bool { return strings.EqualFold(to, w.to) }) {
+		return false
+	}
+	if w.toRegex != nil && !slices.ContainsFunc(e.To, w.toRegex.MatchString) {
+		return false
+	}
+	if w.recipientRegex != nil && !matchesRecipient(w.recipientRegex, e) {
 		return false
 	}
 	return true
 }
+
+// WaitCriteria is the resolved form of a set of [WaitOption] values. It lets
+// code that waits for emails itself, such as the fakes in the
+// vaultsandboxtest package, apply the options as [Inbox.WaitForEmail] does.
+type WaitCriteria struct {
+	cfg waitConfig
+}
+
+// NewWaitCriteria resolves opts, starting from the same defaults as
+// [Inbox.WaitForEmail].
+func NewWaitCriteria(opts ...WaitOption) *WaitCriteria {
+	c := &WaitCriteria{cfg: waitConfig{timeout: defaultWaitTimeout}}
+	for _, opt := range opts {
+		opt(&c.cfg)
+	}
+	return c
+}
+
+// Timeout returns the wait timeout set with [WithWaitTimeout], or the
+// default of 60 seconds.
+func (c *WaitCriteria) Timeout() time.Duration {
+	return c.cfg.timeout
+}
+
+// Matches reports whether e satisfies every filter option. An error from a
+// [WithPredicateErr] predicate counts as no match.
+func (c *WaitCriteria) Matches(e *Email) bool {
+	return c.cfg.Matches(e)
+}
+
+// Evaluate is like Matches, but returns the wrapped error from a
+// [WithPredicateErr] predicate, which should abort the wait.
+func (c *WaitCriteria) Evaluate(e *Email) (bool, error) {
+	return c.cfg.evaluate(e)
+}
+
+// matchesHeader reports whether any value of the named header in e
+// satisfies m.
+func matchesHeader(e *Email, m headerMatch) bool {
+	return slices.ContainsFunc(e.HeaderValues(m.name), func(v string) bool {
+		if m.regex != nil {
+			return m.regex.MatchString(v)
+		}
+		return v == m.value
+	})
+}
+
+// matchesAuthResult reports whether the named check in ar has the wanted
+// result.
+func matchesAuthResult(ar *authresults.AuthResults, m authResultMatch) bool {
+	if ar == nil {
+		return false
+	}
+	switch {
+	case strings.EqualFold(m.check, authresults.CheckSPF):
+		return ar.SPF != nil && strings.EqualFold(ar.SPF.Result, m.result)
+	case strings.EqualFold(m.check, authresults.CheckDKIM):
+		for _, d := range ar.DKIM {
+			if strings.EqualFold(d.Result, m.result) {
+				return true
+			}
+		}
+		return false
+	case strings.EqualFold(m.check, authresults.CheckDMARC):
+		return ar.DMARC != nil && strings.EqualFold(ar.DMARC.Result, m.result)
+	case strings.EqualFold(m.check, authresults.CheckReverseDNS):
+		return ar.ReverseDNS != nil && strings.EqualFold(ar.ReverseDNS.Result, m.result)
+	}
+	return false
+}
+
+// matchesRecipient reports whether pattern matches the envelope recipient
+// or any of the To addresses of e.
+func matchesRecipient(pattern *regexp.Regexp, e *Email) bool {
+	if e.EnvelopeTo != "" && pattern.MatchString(e.EnvelopeTo) {
+		return true
+	}
+	for _, to := range e.To {
+		if pattern.MatchString(to) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,20 @@
 package vaultsandbox
 
 import (
+	"bytes"
+	"errors"
+	"math"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/vaultsandbox/client-go/authresults"
+	"github.com/vaultsandbox/client-go/internal/crypto"
+	"github.com/vaultsandbox/client-go/internal/delivery"
 )
 
 func TestDeliveryStrategy_Constants(t *testing.T) {
@@ -84,6 +94,15 @@ func TestWithRetries(t *testing.T) {
 	}
 }
 
+func TestWithMaxElapsedTime(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithMaxElapsedTime(10 * time.Second)(cfg)
+	if cfg.maxElapsedTime != 10*time.Second {
+		t.Errorf("maxElapsedTime = %v, want 10s", cfg.maxElapsedTime)
+	}
+}
+
 func TestWithTTL(t *testing.T) {
 	t.Parallel()
 	cfg := &inboxConfig{}
@@ -140,6 +159,16 @@ func TestWithFromRegex(t *testing.T) {
 	}
 }
 
+func TestWithRecipientPattern(t *testing.T) {
+	t.Parallel()
+	cfg := &waitConfig{}
+	pattern := regexp.MustCompile(`^order-.*@example\.com$`)
+	WithRecipientPattern(pattern)(cfg)
+	if cfg.recipientRegex != pattern {
+		t.Error("recipientRegex was not set")
+	}
+}
+
 func TestWithPredicate(t *testing.T) {
 	t.Parallel()
 	cfg := &waitConfig{}
@@ -263,6 +292,158 @@ func TestWaitConfig_Matches(t *testing.T) {
 	}
 }
 
+func TestWaitCriteria(t *testing.T) {
+	t.Parallel()
+	if got := NewWaitCriteria().Timeout(); got != defaultWaitTimeout {
+		t.Errorf("default Timeout() = %v, want %v", got, defaultWaitTimeout)
+	}
+
+	c := NewWaitCriteria(WithSubject("Hello"), WithWaitTimeout(5*time.Second))
+	if got := c.Timeout(); got != 5*time.Second {
+		t.Errorf("Timeout() = %v, want 5s", got)
+	}
+	if !c.Matches(&Email{Subject: "Hello"}) {
+		t.Error("Matches() = false for matching subject")
+	}
+	if c.Matches(&Email{Subject: "Bye"}) {
+		t.Error("Matches() = true for other subject")
+	}
+}
+
+func TestWaitConfig_Evaluate_PredicateErr(t *testing.T) {
+	t.Parallel()
+	errBad := errors.New("bad email")
+	var calls int
+	cfg := waitConfig{
+		subject: "Hello",
+		predicateErr: func(e *Email) (bool, error) {
+			calls++
+			if e.From == "bad@example.com" {
+				return false, errBad
+			}
+			return e.From == "good@example.com", nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		email   *Email
+		want    bool
+		wantErr error
+	}{
+		{"match", &Email{Subject: "Hello", From: "good@example.com"}, true, nil},
+		{"no match", &Email{Subject: "Hello", From: "other@example.com"}, false, nil},
+		{"error", &Email{Subject: "Hello", From: "bad@example.com"}, false, errBad},
+	}
+	for _, tt := range tests {
+		got, err := cfg.evaluate(tt.email)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: evaluate() = %v, %v; want %v, %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+		if cfg.Matches(tt.email) != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, !tt.want, tt.want)
+		}
+	}
+
+	// The predicate is skipped for emails the other filters reject.
+	calls = 0
+	if ok, err := cfg.evaluate(&Email{Subject: "Other", From: "bad@example.com"}); ok || err != nil || calls != 0 {
+		t.Errorf("evaluate() = %v, %v with %d predicate calls, want false, nil, 0", ok, err, calls)
+	}
+}
+
+func TestWaitConfig_MatchesAuthResults(t *testing.T) {
+	t.Parallel()
+	passing := &authresults.AuthResults{
+		SPF:   &authresults.SPFResult{Result: "pass"},
+		DKIM:  []authresults.DKIMResult{{Result: "pass"}},
+		DMARC: &authresults.DMARCResult{Result: "pass"},
+	}
+	dkimFail := &authresults.AuthResults{
+		SPF:        &authresults.SPFResult{Result: "softfail"},
+		DKIM:       []authresults.DKIMResult{{Result: "pass"}, {Result: "fail"}},
+		DMARC:      &authresults.DMARCResult{Result: "fail"},
+		ReverseDNS: &authresults.ReverseDNSResult{Result: "pass"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []WaitOption
+		auth     *authresults.AuthResults
+		expected bool
+	}{
+		{"spf result match", []WaitOption{WithAuthResult("spf", "softfail")}, dkimFail, true},
+		{"spf result mismatch", []WaitOption{WithAuthResult("spf", "fail")}, dkimFail, false},
+		{"dkim any signature", []WaitOption{WithAuthResult("DKIM", "fail")}, dkimFail, true},
+		{"dkim no signature", []WaitOption{WithAuthResult("dkim", "fail")}, passing, false},
+		{"dmarc case-insensitive", []WaitOption{WithAuthResult("dmarc", "FAIL")}, dkimFail, true},
+		{"reverse dns", []WaitOption{WithAuthResult("reverseDns", "pass")}, dkimFail, true},
+		{"missing check", []WaitOption{WithAuthResult("reverseDns", "pass")}, passing, false},
+		{"unknown check", []WaitOption{WithAuthResult("arc", "pass")}, passing, false},
+		{"nil results", []WaitOption{WithAuthResult("spf", "pass")}, nil, false},
+		{"all results required", []WaitOption{WithAuthResult("spf", "softfail"), WithAuthResult("dmarc", "pass")}, dkimFail, false},
+		{"passing match", []WaitOption{WithAuthPassing()}, passing, true},
+		{"passing mismatch", []WaitOption{WithAuthPassing()}, dkimFail, false},
+		{"failing match", []WaitOption{WithAuthFailing()}, dkimFail, true},
+		{"failing mismatch", []WaitOption{WithAuthFailing()}, passing, false},
+		{"failing nil results", []WaitOption{WithAuthFailing()}, nil, false},
+		{"passing nil results", []WaitOption{WithAuthPassing()}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &waitConfig{}
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+			if got := cfg.Matches(&Email{AuthResults: tt.auth}); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWaitConfig_MatchesHeaders(t *testing.T) {
+	t.Parallel()
+	tagged := &Email{
+		Headers:      map[string]string{"X-Campaign-ID": "spring-2024", "Received": "from a"},
+		MultiHeaders: map[string][]string{"Received": {"from a", "from b"}},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []WaitOption
+		email    *Email
+		expected bool
+	}{
+		{"exact match", []WaitOption{WithHeader("X-Campaign-ID", "spring-2024")}, tagged, true},
+		{"name case-insensitive", []WaitOption{WithHeader("x-campaign-id", "spring-2024")}, tagged, true},
+		{"value case-sensitive", []WaitOption{WithHeader("X-Campaign-ID", "SPRING-2024")}, tagged, false},
+		{"value mismatch", []WaitOption{WithHeader("X-Campaign-ID", "autumn-2024")}, tagged, false},
+		{"header absent", []WaitOption{WithHeader("X-Trace-ID", "spring-2024")}, tagged, false},
+		{"no headers", []WaitOption{WithHeader("X-Campaign-ID", "spring-2024")}, &Email{}, false},
+		{"repeated header any value", []WaitOption{WithHeader("received", "from b")}, tagged, true},
+		{"regex match", []WaitOption{WithHeaderRegex("X-CAMPAIGN-ID", regexp.MustCompile(`^spring-\d+$`))}, tagged, true},
+		{"regex mismatch", []WaitOption{WithHeaderRegex("X-Campaign-ID", regexp.MustCompile(`^autumn`))}, tagged, false},
+		{"regex header absent", []WaitOption{WithHeaderRegex("X-Trace-ID", regexp.MustCompile(`.*`))}, tagged, false},
+		{"all headers required", []WaitOption{WithHeader("X-Campaign-ID", "spring-2024"), WithHeader("X-Trace-ID", "t1")}, tagged, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &waitConfig{}
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+			if got := cfg.Matches(tt.email); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestTTLConstants(t *testing.T) {
 	t.Parallel()
 	if MinTTL != 60*time.Second {
@@ -295,6 +476,214 @@ func TestWithOnSyncError(t *testing.T) {
 	}
 }
 
+func TestWithMaxAttachmentSize(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithMaxAttachmentSize(1024)(cfg)
+	if cfg.maxAttachmentSize != 1024 {
+		t.Errorf("maxAttachmentSize = %d, want 1024", cfg.maxAttachmentSize)
+	}
+}
+
+func TestWithClockSkewTolerance(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithClockSkewTolerance(5 * time.Minute)(cfg)
+	WithStrictClockSkew()(cfg)
+	if cfg.clockSkewTolerance != 5*time.Minute {
+		t.Errorf("clockSkewTolerance = %v, want 5m", cfg.clockSkewTolerance)
+	}
+	if !cfg.strictClockSkew {
+		t.Error("strictClockSkew was not set")
+	}
+}
+
+func TestWithWireLogging(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	var buf bytes.Buffer
+	WithWireLogging(&buf)(cfg)
+	if cfg.wireLog != &buf {
+		t.Error("wireLog was not set")
+	}
+}
+
+func TestWithMonitorConcurrency(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithMonitorConcurrency(8)(cfg)
+	if cfg.monitorConcurrency != 8 {
+		t.Errorf("monitorConcurrency = %d, want 8", cfg.monitorConcurrency)
+	}
+}
+
+func TestWithMaxConcurrentDecrypts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		n    int
+		want int
+	}{
+		{name: "explicit", n: 3, want: 3},
+		{name: "zero uses GOMAXPROCS", n: 0, want: runtime.GOMAXPROCS(0)},
+		{name: "negative uses GOMAXPROCS", n: -1, want: runtime.GOMAXPROCS(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &clientConfig{}
+			WithMaxConcurrentDecrypts(tt.n)(cfg)
+			if got := resolveMaxConcurrentDecrypts(cfg); got != tt.want {
+				t.Errorf("resolveMaxConcurrentDecrypts() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithReceivedAtSource(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	if cfg.receivedAtSource != SourceMetadata {
+		t.Errorf("default receivedAtSource = %v, want SourceMetadata", cfg.receivedAtSource)
+	}
+	WithReceivedAtSource(SourceAPI)(cfg)
+	if cfg.receivedAtSource != SourceAPI {
+		t.Errorf("receivedAtSource = %v, want SourceAPI", cfg.receivedAtSource)
+	}
+}
+
+func TestWithPollingBounds(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		min, max time.Duration
+		wantErr  bool
+	}{
+		{name: "valid", min: 100 * time.Millisecond, max: 5 * time.Second},
+		{name: "equal", min: time.Second, max: time.Second},
+		{name: "min exceeds max", min: 5 * time.Second, max: time.Second, wantErr: true},
+		{name: "zero min", min: 0, max: time.Second, wantErr: true},
+		{name: "negative max", min: time.Second, max: -time.Second, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &clientConfig{}
+			WithPollingBounds(tt.min, tt.max)(cfg)
+			if err := cfg.validatePollingBounds(); (err != nil) != tt.wantErr {
+				t.Errorf("validatePollingBounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := New("test-key", WithPollingBounds(5*time.Second, time.Second)); err == nil {
+		t.Error("New() with min > max: expected error")
+	}
+
+	cfg := &clientConfig{}
+	WithPollingBounds(100*time.Millisecond, 5*time.Second)(cfg)
+	pc := resolvePollingConfig(cfg)
+	if pc.InitialInterval != 100*time.Millisecond || pc.MaxBackoff != 5*time.Second {
+		t.Errorf("resolvePollingConfig() = %v/%v, want 100ms/5s", pc.InitialInterval, pc.MaxBackoff)
+	}
+}
+
+func TestWithRetryJitter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		fraction float64
+		wantErr  bool
+	}{
+		{name: "zero", fraction: 0},
+		{name: "valid", fraction: 0.25},
+		{name: "just below one", fraction: 0.999},
+		{name: "one", fraction: 1, wantErr: true},
+		{name: "negative", fraction: -0.1, wantErr: true},
+		{name: "NaN", fraction: math.NaN(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &clientConfig{}
+			WithRetryJitter(tt.fraction)(cfg)
+			if err := cfg.validateRetryJitter(); (err != nil) != tt.wantErr {
+				t.Errorf("validateRetryJitter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := New("test-key", WithRetryJitter(1.5)); err == nil {
+		t.Error("New() with jitter 1.5: expected error")
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		rps     float64
+		burst   int
+		wantErr bool
+	}{
+		{name: "unlimited", rps: 0, burst: 0},
+		{name: "valid", rps: 5, burst: 10},
+		{name: "fractional rps", rps: 0.5, burst: 1},
+		{name: "zero burst", rps: 5, burst: 0, wantErr: true},
+		{name: "negative rps", rps: -1, burst: 1, wantErr: true},
+		{name: "NaN", rps: math.NaN(), burst: 1, wantErr: true},
+		{name: "infinite", rps: math.Inf(1), burst: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &clientConfig{}
+			WithRateLimit(tt.rps, tt.burst)(cfg)
+			if err := cfg.validateRateLimit(); (err != nil) != tt.wantErr {
+				t.Errorf("validateRateLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := New("test-key", WithRateLimit(5, 0)); err == nil {
+		t.Error("New() with burst 0: expected error")
+	}
+}
+
+func TestWithAdditionalServerKeys(t *testing.T) {
+	t.Parallel()
+	validKey := crypto.ToBase64URL(make([]byte, crypto.MLDSAPublicKeySize))
+	tests := []struct {
+		name     string
+		keys     []string
+		wantKeys int
+		wantErr  bool
+	}{
+		{name: "none", keys: nil},
+		{name: "valid", keys: []string{validKey, validKey}, wantKeys: 2},
+		{name: "invalid encoding", keys: []string{validKey, "!!!"}, wantErr: true},
+		{name: "wrong size", keys: []string{crypto.ToBase64URL([]byte("short"))}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &clientConfig{}
+			WithAdditionalServerKeys(tt.keys)(cfg)
+			keys, err := cfg.decodeAdditionalServerKeys()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeAdditionalServerKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(keys) != tt.wantKeys {
+				t.Errorf("decodeAdditionalServerKeys() returned %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+
+	if _, err := New("test-key", WithAdditionalServerKeys([]string{"!!!"})); err == nil {
+		t.Error("New() with an invalid additional server key: expected error")
+	}
+}
+
 func TestWithPollingConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -369,6 +758,109 @@ func TestWithPollingConfig(t *testing.T) {
 	}
 }
 
+func TestWithRetryDecider(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithRetryDecider(func(*http.Response, error) bool { return true })(cfg)
+	if cfg.retryDecider == nil {
+		t.Fatal("retryDecider not set")
+	}
+	if !cfg.retryDecider(nil, nil) {
+		t.Error("retryDecider() = false, want the configured function")
+	}
+}
+
+func TestWithOnRetry(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	var called bool
+	WithOnRetry(func(int, int, time.Duration, error) { called = true })(cfg)
+	if cfg.onRetry == nil {
+		t.Fatal("onRetry not set")
+	}
+	cfg.onRetry(1, 503, time.Second, nil)
+	if !called {
+		t.Error("onRetry is not the configured function")
+	}
+}
+
+func TestWithSSEHooks(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	var reconnects, connects int
+	WithSSEReconnectHook(func(int, error) { reconnects++ })(cfg)
+	WithSSEConnectedHook(func() { connects++ })(cfg)
+	if cfg.sseReconnectHook == nil || cfg.sseConnectedHook == nil {
+		t.Fatal("SSE hooks not set")
+	}
+	cfg.sseReconnectHook(1, nil)
+	cfg.sseConnectedHook()
+	if reconnects != 1 || connects != 1 {
+		t.Errorf("hooks called %d/%d times, want 1/1", reconnects, connects)
+	}
+}
+
+func TestWithSSEHeartbeatTimeout(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{}
+	WithSSEHeartbeatTimeout(45 * time.Second)(cfg)
+	if cfg.sseHeartbeatTimeout != 45*time.Second {
+		t.Errorf("sseHeartbeatTimeout = %v, want 45s", cfg.sseHeartbeatTimeout)
+	}
+}
+
+func TestWithWebhookDelivery(t *testing.T) {
+	t.Parallel()
+	cfg := &clientConfig{deliveryStrategy: StrategySSE}
+	WithWebhookDelivery("https://hooks.example.com/vaultsandbox")(cfg)
+	if cfg.deliveryStrategy != StrategyWebhook {
+		t.Errorf("deliveryStrategy = %s, want %s", cfg.deliveryStrategy, StrategyWebhook)
+	}
+	if cfg.webhookURL != "https://hooks.example.com/vaultsandbox" {
+		t.Errorf("webhookURL = %q", cfg.webhookURL)
+	}
+	if _, ok := createDeliveryStrategy(cfg, nil, nil).(*delivery.WebhookStrategy); !ok {
+		t.Error("createDeliveryStrategy did not return a webhook strategy")
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	t.Parallel()
+	want := DefaultUserAgent + " my-tests/1.0"
+	var mu sync.Mutex
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents = append(agents, r.Header.Get("User-Agent"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/check-key":
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			w.Write([]byte(`{"allowedDomains":["test.com"],"maxTtl":3600,"defaultTtl":300}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New("test-key", WithBaseURL(server.URL), WithUserAgent(want))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(agents) == 0 {
+		t.Fatal("no requests were made")
+	}
+	for _, got := range agents {
+		if got != want {
+			t.Errorf("User-Agent = %q, want %q", got, want)
+		}
+	}
+}
+
 func TestWithRetryOn(t *testing.T) {
 	t.Parallel()
 	cfg := &clientConfig{}
@@ -0,0 +1,105 @@
+// Package vaultsandboxotel adapts an OpenTelemetry TracerProvider to the
+// VaultSandbox client's [vaultsandbox.Tracer] hooks. It lives in its own
+// module so that the client does not depend on OpenTelemetry.
+//
+//	client, err := vaultsandbox.New(apiKey,
+//	    vaultsandboxotel.WithTracerProvider(otel.GetTracerProvider()),
+//	)
+package vaultsandboxotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the spans.
+const ScopeName = "github.com/vaultsandbox/client-go"
+
+// WithTracerProvider returns a client option that records spans with tp and
+// propagates the span context in API request headers using the global
+// propagator. A nil tp uses the global TracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) vaultsandbox.Option {
+	return vaultsandbox.WithTracer(NewTracer(tp))
+}
+
+// NewTracer returns a [vaultsandbox.Tracer] backed by tp. A nil tp uses the
+// global TracerProvider.
+func NewTracer(tp trace.TracerProvider) vaultsandbox.Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &tracer{tracer: tp.Tracer(ScopeName)}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// Start starts an OpenTelemetry span. Spans for HTTP attempts are client
+// spans; the others are internal.
+func (t *tracer) Start(ctx context.Context, name string, keysAndValues ...any) (context.Context, vaultsandbox.Span) {
+	kind := trace.SpanKindInternal
+	switch name {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		kind = trace.SpanKindClient
+	}
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes(keysAndValues)...))
+	return ctx, span{s}
+}
+
+// Inject writes the span context of ctx into header.
+func (t *tracer) Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s span) SetAttributes(keysAndValues ...any) {
+	s.span.SetAttributes(attributes(keysAndValues)...)
+}
+
+func (s span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.span.End()
+}
+
+// attributes converts alternating keys and values to OpenTelemetry
+// attributes. Values of unsupported types are formatted with fmt.
+func attributes(keysAndValues []any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := attribute.Key(fmt.Sprint(keysAndValues[i]))
+		switch v := keysAndValues[i+1].(type) {
+		case string:
+			attrs = append(attrs, key.String(v))
+		case int:
+			attrs = append(attrs, key.Int(v))
+		case int64:
+			attrs = append(attrs, key.Int64(v))
+		case bool:
+			attrs = append(attrs, key.Bool(v))
+		case float64:
+			attrs = append(attrs, key.Float64(v))
+		case time.Duration:
+			attrs = append(attrs, key.Int64(v.Milliseconds()))
+		default:
+			attrs = append(attrs, key.String(fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
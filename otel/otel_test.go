@@ -0,0 +1,165 @@
+package vaultsandboxotel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func attr(s sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestWithTracerProvider_SpanPerAttempt(t *testing.T) {
+	t.Parallel()
+	var createCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]any{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodPost:
+			if createCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"emailAddress": "test@test.com",
+				"expiresAt":    time.Now().Add(time.Hour).Format(time.RFC3339),
+				"inboxHash":    "test-inbox-hash",
+				"encrypted":    false,
+			})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	client, err := vaultsandbox.New("test-api-key",
+		vaultsandbox.WithBaseURL(server.URL),
+		vaultsandbox.WithDeliveryStrategy(vaultsandbox.StrategyPolling),
+		WithTracerProvider(tp),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CreateInbox(context.Background()); err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+
+	var create sdktrace.ReadOnlySpan
+	var attempts []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		switch s.Name() {
+		case "vaultsandbox.CreateInbox":
+			create = s
+		case http.MethodPost:
+			if path, _ := attr(s, "url.path"); path.AsString() == "/api/inboxes" {
+				attempts = append(attempts, s)
+			}
+		}
+	}
+	if create == nil {
+		t.Fatal("no vaultsandbox.CreateInbox span")
+	}
+	if hash, _ := attr(create, "vaultsandbox.inbox.hash"); hash.AsString() != "test-inbox-hash" {
+		t.Errorf("inbox hash = %q, want test-inbox-hash", hash.AsString())
+	}
+
+	if len(attempts) != 2 {
+		t.Fatalf("POST /api/inboxes spans = %d, want 2 (one per attempt)", len(attempts))
+	}
+	for i, want := range []int64{http.StatusServiceUnavailable, http.StatusOK} {
+		s := attempts[i]
+		if s.SpanKind() != trace.SpanKindClient {
+			t.Errorf("attempt %d kind = %v, want client", i, s.SpanKind())
+		}
+		if s.Parent().SpanID() != create.SpanContext().SpanID() {
+			t.Errorf("attempt %d is not a child of the CreateInbox span", i)
+		}
+		if status, _ := attr(s, "http.response.status_code"); status.AsInt64() != want {
+			t.Errorf("attempt %d status = %d, want %d", i, status.AsInt64(), want)
+		}
+	}
+	if attempts[0].Status().Code != codes.Error {
+		t.Errorf("failed attempt status = %v, want Error", attempts[0].Status().Code)
+	}
+}
+
+func TestTracer_Inject(t *testing.T) {
+	// Not parallel: it replaces the global propagator.
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	tr := NewTracer(tp)
+
+	ctx, s := tr.Start(context.Background(), http.MethodGet)
+	defer s.End()
+	header := http.Header{}
+	tr.(interface {
+		Inject(context.Context, http.Header)
+	}).Inject(ctx, header)
+
+	traceID := trace.SpanContextFromContext(ctx).TraceID().String()
+	if got := header.Get("traceparent"); !strings.Contains(got, traceID) {
+		t.Errorf("traceparent = %q, want it to carry trace ID %s", got, traceID)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	t.Parallel()
+	got := attributes([]any{"s", "v", "i", 3, "b", true, "d", 1500 * time.Millisecond, "o", struct{}{}, "dangling"})
+	want := []attribute.KeyValue{
+		attribute.String("s", "v"),
+		attribute.Int("i", 3),
+		attribute.Bool("b", true),
+		attribute.Int64("d", 1500),
+		attribute.String("o", "{}"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("attributes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("attribute %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package vaultsandbox
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+// IsDomainAllowed reports whether inboxes may be created under domain. The
+// comparison is case-insensitive and ignores a trailing dot. An allowed entry
+// of the form "*.example.com" matches any subdomain of example.com (but not
+// example.com itself), and "*" matches every domain.
+func (s *ServerInfo) IsDomainAllowed(domain string) bool {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return false
+	}
+	for _, allowed := range s.AllowedDomains {
+		allowed = normalizeDomain(allowed)
+		switch {
+		case allowed == "*":
+			return true
+		case strings.HasPrefix(allowed, "*."):
+			if strings.HasSuffix(domain, allowed[1:]) {
+				return true
+			}
+		case domain == allowed:
+			return true
+		}
+	}
+	return false
+}
+
+// HasWildcardDomain reports whether any allowed domain is a wildcard entry
+// such as "*" or "*.example.com".
+func (s *ServerInfo) HasWildcardDomain() bool {
+	for _, allowed := range s.AllowedDomains {
+		if strings.HasPrefix(allowed, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// RandomDomain returns a randomly chosen allowed domain, for tests that need
+// an address under any valid domain. Wildcard entries are skipped since they
+// do not name a concrete domain. It returns "" if no concrete domain is
+// allowed.
+func (s *ServerInfo) RandomDomain() string {
+	var concrete []string
+	for _, allowed := range s.AllowedDomains {
+		if allowed != "" && !strings.HasPrefix(allowed, "*") {
+			concrete = append(concrete, allowed)
+		}
+	}
+	if len(concrete) == 0 {
+		return ""
+	}
+	return concrete[rand.IntN(len(concrete))]
+}
+
+// normalizeDomain lowercases a domain and strips a trailing dot.
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,65 @@
+package vaultsandbox
+
+import "testing"
+
+func TestServerInfo_IsDomainAllowed(t *testing.T) {
+	t.Parallel()
+	info := &ServerInfo{AllowedDomains: []string{"Example.com", "*.catchall.test"}}
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.COM", true},
+		{"example.com.", true},
+		{"other.com", false},
+		{"sub.example.com", false},
+		{"a.catchall.test", true},
+		{"a.b.catchall.test", true},
+		{"catchall.test", false},
+		{"evilcatchall.test", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := info.IsDomainAllowed(tt.domain); got != tt.want {
+			t.Errorf("IsDomainAllowed(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+
+	if !(&ServerInfo{AllowedDomains: []string{"*"}}).IsDomainAllowed("anything.io") {
+		t.Error(`IsDomainAllowed with "*" = false, want true`)
+	}
+}
+
+func TestServerInfo_HasWildcardDomain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		domains []string
+		want    bool
+	}{
+		{name: "none", domains: nil, want: false},
+		{name: "concrete only", domains: []string{"example.com"}, want: false},
+		{name: "subdomain wildcard", domains: []string{"example.com", "*.example.com"}, want: true},
+		{name: "catch-all", domains: []string{"*"}, want: true},
+	}
+	for _, tt := range tests {
+		if got := (&ServerInfo{AllowedDomains: tt.domains}).HasWildcardDomain(); got != tt.want {
+			t.Errorf("%s: HasWildcardDomain() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestServerInfo_RandomDomain(t *testing.T) {
+	t.Parallel()
+	info := &ServerInfo{AllowedDomains: []string{"a.com", "*.wild.com", "b.com"}}
+	for range 50 {
+		if got := info.RandomDomain(); got != "a.com" && got != "b.com" {
+			t.Fatalf("RandomDomain() = %q, want a concrete allowed domain", got)
+		}
+	}
+	if got := (&ServerInfo{AllowedDomains: []string{"*"}}).RandomDomain(); got != "" {
+		t.Errorf("RandomDomain() with only wildcards = %q, want empty", got)
+	}
+}
@@ -1,6 +1,7 @@
 package vaultsandbox
 
 import (
+	"runtime/debug"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -11,6 +12,7 @@ type subscription struct {
 	id        string
 	inboxHash string
 	callback  func(*Email)
+	onEnd     func(error) // optional; called once if the subscription is ended
 	active    atomic.Bool
 }
 
@@ -20,6 +22,10 @@ type subscriptionManager struct {
 	mu     sync.RWMutex
 	subs   map[string]map[string]*subscription // inboxHash -> subID -> subscription
 	nextID atomic.Uint64
+
+	// onPanic is called when a callback panics. The panic is recovered so
+	// that remaining subscribers are still notified.
+	onPanic func(value any, stack []byte)
 }
 
 // newSubscriptionManager creates a new subscription manager.
@@ -33,12 +39,21 @@ func newSubscriptionManager() *subscriptionManager {
 // The callback will be invoked synchronously when emails arrive.
 // Returns an unsubscribe function that must be called to clean up.
 func (m *subscriptionManager) subscribe(inboxHash string, callback func(*Email)) func() {
+	return m.subscribeWithEnd(inboxHash, callback, nil)
+}
+
+// subscribeWithEnd is like subscribe, but onEnd is called if the
+// subscription is ended by the manager rather than unsubscribed: with nil
+// when the inbox stops being tracked (see end), or with ErrClientClosed when
+// the client is closed (see clear). onEnd must not block.
+func (m *subscriptionManager) subscribeWithEnd(inboxHash string, callback func(*Email), onEnd func(error)) func() {
 	id := strconv.FormatUint(m.nextID.Add(1), 10)
 
 	sub := &subscription{
 		id:        id,
 		inboxHash: inboxHash,
 		callback:  callback,
+		onEnd:     onEnd,
 	}
 	sub.active.Store(true)
 
@@ -90,20 +105,58 @@ func (m *subscriptionManager) notify(inboxHash string, email *Email) {
 
 	for _, sub := range subs {
 		if sub.active.Load() {
-			sub.callback(email)
+			m.invoke(sub, email)
 		}
 	}
 }
 
-// clear removes all subscriptions. Called during Client.Close().
-func (m *subscriptionManager) clear() {
+// invoke calls a subscription's callback, recovering from panics so a
+// faulty subscriber cannot take down the delivery goroutine.
+func (m *subscriptionManager) invoke(sub *subscription, email *Email) {
+	defer func() {
+		if r := recover(); r != nil && m.onPanic != nil {
+			m.onPanic(r, debug.Stack())
+		}
+	}()
+	sub.callback(email)
+}
+
+// end removes all subscriptions for an inbox that is no longer tracked,
+// such as a deleted one, and calls their onEnd with err.
+func (m *subscriptionManager) end(inboxHash string, err error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	inboxSubs := m.subs[inboxHash]
+	delete(m.subs, inboxHash)
+	for _, sub := range inboxSubs {
+		sub.active.Store(false)
+	}
+	m.mu.Unlock()
 
-	for _, inboxSubs := range m.subs {
+	for _, sub := range inboxSubs {
+		if sub.onEnd != nil {
+			sub.onEnd(err)
+		}
+	}
+}
+
+// clear removes all subscriptions and calls their onEnd with
+// ErrClientClosed. Called during Client.Close().
+func (m *subscriptionManager) clear() {
+	m.mu.Lock()
+	old := m.subs
+	for _, inboxSubs := range old {
 		for _, sub := range inboxSubs {
 			sub.active.Store(false)
 		}
 	}
 	m.subs = make(map[string]map[string]*subscription)
+	m.mu.Unlock()
+
+	for _, inboxSubs := range old {
+		for _, sub := range inboxSubs {
+			if sub.onEnd != nil {
+				sub.onEnd(ErrClientClosed)
+			}
+		}
+	}
 }
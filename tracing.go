@@ -0,0 +1,46 @@
+package vaultsandbox
+
+import (
+	"context"
+
+	"github.com/vaultsandbox/client-go/internal/tracing"
+)
+
+// Tracer starts spans for client operations. Start receives the span name
+// and attributes as alternating keys and values, and returns a context
+// carrying the new span. A Tracer that also has a method
+// Inject(ctx context.Context, header http.Header) propagates the span
+// context in the headers of outgoing API requests.
+//
+// The client creates spans for [Client.CreateInbox], [Inbox.GetEmails],
+// [Inbox.WaitForEmail], and every HTTP attempt, retries included. Operation
+// spans carry the vaultsandbox.inbox.hash attribute; attempt spans carry
+// http.request.method, url.path, http.request.resend_count, and
+// http.response.status_code.
+//
+// The otel module (github.com/vaultsandbox/client-go/otel) provides a Tracer
+// backed by an OpenTelemetry TracerProvider, so this module does not depend
+// on OpenTelemetry.
+type Tracer = tracing.Tracer
+
+// Span is an operation started by a [Tracer]. Implementations must be safe
+// for concurrent use.
+type Span = tracing.Span
+
+// WithTracer sets the tracer that receives spans for client operations and
+// HTTP attempts; see [Tracer]. A nil tracer disables tracing, which is the
+// default.
+func WithTracer(tracer Tracer) Option {
+	return func(c *clientConfig) {
+		c.tracer = tracer
+	}
+}
+
+// startSpan starts a span named name with the client's tracer. It is safe to
+// call on a nil client, as detached inboxes have none.
+func (c *Client) startSpan(ctx context.Context, name string, keysAndValues ...any) (context.Context, Span) {
+	if c == nil {
+		return tracing.Nop{}.Start(ctx, name, keysAndValues...)
+	}
+	return tracing.OrNop(c.tracer).Start(ctx, name, keysAndValues...)
+}
@@ -0,0 +1,173 @@
+package vaultsandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// recordedSpan is a span captured by recordingTracer.
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+// recordingTracer is a Tracer that keeps every span it starts.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, kv ...any) (context.Context, Span) {
+	s := &recordedSpan{name: name, attrs: map[string]any{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		s.parent = parent.name
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	rs := &recordingSpan{tracer: t, span: s}
+	rs.SetAttributes(kv...)
+	return context.WithValue(ctx, spanKey{}, s), rs
+}
+
+func (t *recordingTracer) named(name string) []recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []recordedSpan
+	for _, s := range t.spans {
+		if s.name == name {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	span   *recordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(kv ...any) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	for i := 0; i+1 < len(kv); i += 2 {
+		s.span.attrs[kv[i].(string)] = kv[i+1]
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	s.span.err = err
+	s.tracer.mu.Unlock()
+}
+
+func (s *recordingSpan) End() {
+	s.tracer.mu.Lock()
+	s.span.ended = true
+	s.tracer.mu.Unlock()
+}
+
+func TestClient_WithTracer(t *testing.T) {
+	t.Parallel()
+	var createCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/api/check-key":
+			json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+
+		case r.URL.Path == "/api/server-info":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"allowedDomains": []string{"test.com"},
+				"maxTtl":         3600,
+				"defaultTtl":     300,
+			})
+
+		case r.URL.Path == "/api/inboxes" && r.Method == http.MethodPost:
+			if createCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			mockCreateInboxResponse(w)
+
+		case r.URL.Path == "/api/inboxes/test@test.com/emails":
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tracer := &recordingTracer{}
+	client, err := New("test-api-key", WithBaseURL(server.URL), WithDeliveryStrategy(StrategyPolling), WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer client.Close()
+
+	inbox, err := client.CreateInbox(context.Background())
+	if err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+
+	creates := tracer.named("vaultsandbox.CreateInbox")
+	if len(creates) != 1 {
+		t.Fatalf("CreateInbox spans = %d, want 1", len(creates))
+	}
+	if got := creates[0].attrs["vaultsandbox.inbox.hash"]; got != "test-inbox-hash" {
+		t.Errorf("CreateInbox inbox hash = %v, want test-inbox-hash", got)
+	}
+	if !creates[0].ended || creates[0].err != nil {
+		t.Errorf("CreateInbox span ended = %v, err = %v; want ended without error", creates[0].ended, creates[0].err)
+	}
+
+	var attempts []recordedSpan
+	for _, s := range tracer.named(http.MethodPost) {
+		if s.attrs["url.path"] == "/api/inboxes" {
+			attempts = append(attempts, s)
+		}
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("POST /api/inboxes attempt spans = %d, want 2", len(attempts))
+	}
+	for i, want := range []int{http.StatusServiceUnavailable, http.StatusOK} {
+		s := attempts[i]
+		if s.parent != "vaultsandbox.CreateInbox" {
+			t.Errorf("attempt %d parent = %q, want vaultsandbox.CreateInbox", i, s.parent)
+		}
+		if s.attrs["http.response.status_code"] != want {
+			t.Errorf("attempt %d status = %v, want %d", i, s.attrs["http.response.status_code"], want)
+		}
+		if s.attrs["http.request.resend_count"] != i {
+			t.Errorf("attempt %d resend count = %v, want %d", i, s.attrs["http.request.resend_count"], i)
+		}
+	}
+
+	if _, err := inbox.GetEmails(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("GetEmails() error = %v, want ErrUnauthorized", err)
+	}
+	gets := tracer.named("vaultsandbox.GetEmails")
+	if len(gets) != 1 {
+		t.Fatalf("GetEmails spans = %d, want 1", len(gets))
+	}
+	if !errors.Is(gets[0].err, ErrUnauthorized) {
+		t.Errorf("GetEmails span error = %v, want ErrUnauthorized", gets[0].err)
+	}
+	if got := gets[0].attrs["vaultsandbox.inbox.hash"]; got != "test-inbox-hash" {
+		t.Errorf("GetEmails inbox hash = %v, want test-inbox-hash", got)
+	}
+}
@@ -0,0 +1,309 @@
+// Package vaultsandboxtest provides in-memory fakes of the VaultSandbox
+// client and inbox for unit tests that should not need a gateway.
+//
+// A [Client] creates [Inbox] values that hold emails in memory. Tests
+// deliver emails with [Inbox.Deliver], exercise code that calls methods such
+// as [Inbox.WaitForEmail], and then assert on what happened, for example with
+// [Client.DeletedInboxes]. The fakes mirror the method signatures of
+// vaultsandbox.Client and vaultsandbox.Inbox and return the same sentinel
+// errors, so errors.Is checks behave as they do against a real server.
+//
+// For tests that keep the real client but replace the server with an
+// httptest mock, [NewMockInbox] creates a real encrypted inbox offline and
+// builds the encrypted email responses it accepts.
+//
+// Example:
+//
+//	client := vaultsandboxtest.NewClient()
+//	inbox, _ := client.CreateInbox(ctx)
+//	inbox.Deliver(&vaultsandbox.Email{Subject: "Welcome"})
+//	email, err := inbox.WaitForEmail(ctx, vaultsandbox.WithSubject("Welcome"))
+package vaultsandboxtest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+)
+
+// Defaults for fake inboxes.
+const (
+	DefaultDomain   = "vaultsandbox.test"
+	DefaultInboxTTL = time.Hour
+)
+
+// Option configures a fake [Client].
+type Option func(*Client)
+
+// WithDomain sets the domain of inbox addresses generated by
+// [Client.CreateInbox]. The default is [DefaultDomain].
+func WithDomain(domain string) Option {
+	return func(c *Client) {
+		c.domain = domain
+	}
+}
+
+// WithInboxTTL sets how long created inboxes live before
+// [Inbox.IsExpired] reports true. The default is [DefaultInboxTTL].
+func WithInboxTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ttl = ttl
+	}
+}
+
+// Client is an in-memory fake of vaultsandbox.Client that implements
+// [vaultsandbox.ClientAPI]. It is safe for concurrent use.
+type Client struct {
+	domain string
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	inboxes   map[string]*Inbox // keyed by email address
+	deleted   []string
+	nextInbox int
+	nextEmail int
+	injected  error
+	closed    bool
+}
+
+var _ vaultsandbox.ClientAPI[*Inbox] = (*Client)(nil)
+
+// NewClient returns an empty fake client.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		domain:  DefaultDomain,
+		ttl:     DefaultInboxTTL,
+		inboxes: make(map[string]*Inbox),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// InjectError makes the next call to a fake method that can fail return
+// err instead of doing its work. It is reset after one use.
+func (c *Client) InjectError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.injected = err
+}
+
+// begin checks whether a call may proceed. It must be called with c.mu held.
+func (c *Client) begin(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.closed {
+		return vaultsandbox.ErrClientClosed
+	}
+	if err := c.injected; err != nil {
+		c.injected = nil
+		return err
+	}
+	return nil
+}
+
+// CreateInbox creates an inbox with a generated address. The options are
+// accepted for signature compatibility but have no effect.
+func (c *Client) CreateInbox(ctx context.Context, opts ...vaultsandbox.InboxOption) (*Inbox, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.begin(ctx); err != nil {
+		return nil, err
+	}
+	c.nextInbox++
+	return c.addInbox(fmt.Sprintf("inbox-%d@%s", c.nextInbox, c.domain))
+}
+
+// AddInbox creates an inbox with the given address, for tests that need a
+// known address. It returns [vaultsandbox.ErrInboxAlreadyExists] if the
+// client already has an inbox with that address.
+func (c *Client) AddInbox(emailAddress string) (*Inbox, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, vaultsandbox.ErrClientClosed
+	}
+	return c.addInbox(emailAddress)
+}
+
+// addInbox registers a new inbox. It must be called with c.mu held.
+func (c *Client) addInbox(emailAddress string) (*Inbox, error) {
+	if _, exists := c.inboxes[emailAddress]; exists {
+		return nil, vaultsandbox.ErrInboxAlreadyExists
+	}
+	hash := sha256.Sum256([]byte(emailAddress))
+	inbox := &Inbox{
+		client:       c,
+		emailAddress: emailAddress,
+		inboxHash:    hex.EncodeToString(hash[:]),
+		expiresAt:    time.Now().Add(c.ttl),
+		changed:      make(chan struct{}),
+	}
+	c.inboxes[emailAddress] = inbox
+	return inbox, nil
+}
+
+// ImportInbox registers an inbox with the address, hash and expiry of data,
+// for code under test that imports exported inboxes. The secret key is not
+// used. It returns [vaultsandbox.ErrInboxAlreadyExists] if the client
+// already has an inbox with that address.
+func (c *Client) ImportInbox(ctx context.Context, data *vaultsandbox.ExportedInbox) (*Inbox, error) {
+	if data == nil {
+		return nil, fmt.Errorf("exported inbox data cannot be nil")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.begin(ctx); err != nil {
+		return nil, err
+	}
+	inbox, err := c.addInbox(data.EmailAddress)
+	if err != nil {
+		return nil, err
+	}
+	if data.InboxHash != "" {
+		inbox.inboxHash = data.InboxHash
+	}
+	if !data.ExpiresAt.IsZero() {
+		inbox.expiresAt = data.ExpiresAt
+	}
+	return inbox, nil
+}
+
+// GetInbox returns the inbox with the given address.
+func (c *Client) GetInbox(emailAddress string) (*Inbox, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inbox, ok := c.inboxes[emailAddress]
+	return inbox, ok
+}
+
+// Inboxes returns all inboxes, sorted by address.
+func (c *Client) Inboxes() []*Inbox {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inboxes := make([]*Inbox, 0, len(c.inboxes))
+	for _, inbox := range c.inboxes {
+		inboxes = append(inboxes, inbox)
+	}
+	sort.Slice(inboxes, func(i, j int) bool {
+		return inboxes[i].emailAddress < inboxes[j].emailAddress
+	})
+	return inboxes
+}
+
+// DeleteInbox deletes the inbox with the given address. It returns
+// [vaultsandbox.ErrInboxNotFound] if there is no such inbox.
+func (c *Client) DeleteInbox(ctx context.Context, emailAddress string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.begin(ctx); err != nil {
+		return err
+	}
+	inbox, ok := c.inboxes[emailAddress]
+	if !ok {
+		return fmt.Errorf("inbox %s: %w", emailAddress, vaultsandbox.ErrInboxNotFound)
+	}
+	c.deleteInbox(inbox)
+	return nil
+}
+
+// DeleteAllInboxes deletes every inbox and returns how many were deleted.
+func (c *Client) DeleteAllInboxes(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.begin(ctx); err != nil {
+		return 0, err
+	}
+	inboxes := make([]*Inbox, 0, len(c.inboxes))
+	for _, inbox := range c.inboxes {
+		inboxes = append(inboxes, inbox)
+	}
+	sort.Slice(inboxes, func(i, j int) bool {
+		return inboxes[i].emailAddress < inboxes[j].emailAddress
+	})
+	for _, inbox := range inboxes {
+		c.deleteInbox(inbox)
+	}
+	return len(inboxes), nil
+}
+
+// deleteInbox removes inbox and wakes its waiters. It must be called with
+// c.mu held.
+func (c *Client) deleteInbox(inbox *Inbox) {
+	delete(c.inboxes, inbox.emailAddress)
+	c.deleted = append(c.deleted, inbox.emailAddress)
+	inbox.markDeleted()
+}
+
+// DeletedInboxes returns the addresses of deleted inboxes, in deletion
+// order.
+func (c *Client) DeletedInboxes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.deleted...)
+}
+
+// ServerInfo returns server information describing the fake: its domain
+// is the only allowed one and its inbox TTL is both the default and the
+// maximum.
+func (c *Client) ServerInfo() *vaultsandbox.ServerInfo {
+	return &vaultsandbox.ServerInfo{
+		AllowedDomains: []string{c.domain},
+		MaxTTL:         c.ttl,
+		DefaultTTL:     c.ttl,
+	}
+}
+
+// CheckKey reports whether the fake would accept a call: it returns an
+// injected error, [vaultsandbox.ErrClientClosed] after Close, and nil
+// otherwise.
+func (c *Client) CheckKey(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.begin(ctx)
+}
+
+// Close closes the client. Later calls return
+// [vaultsandbox.ErrClientClosed], and waits in progress end with that error.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	for _, inbox := range c.inboxes {
+		inbox.wake()
+	}
+	return nil
+}
+
+// isClosed reports whether Close was called.
+func (c *Client) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// newEmailID returns a unique ID for a delivered email.
+func (c *Client) newEmailID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextEmail++
+	return fmt.Sprintf("email-%d", c.nextEmail)
+}
+
+// checkCall is begin for inbox methods, which do not hold c.mu.
+func (c *Client) checkCall(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.begin(ctx)
+}
@@ -0,0 +1,257 @@
+package vaultsandboxtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+)
+
+// Inbox is an in-memory fake of vaultsandbox.Inbox that implements
+// [vaultsandbox.InboxAPI]. It is safe for concurrent use.
+type Inbox struct {
+	client       *Client
+	emailAddress string
+	inboxHash    string
+	expiresAt    time.Time
+
+	mu      sync.Mutex
+	emails  []*vaultsandbox.Email
+	deleted bool
+	changed chan struct{} // closed and replaced whenever the inbox changes
+}
+
+var _ vaultsandbox.InboxAPI = (*Inbox)(nil)
+
+// EmailAddress returns the inbox address.
+func (i *Inbox) EmailAddress() string {
+	return i.emailAddress
+}
+
+// InboxHash returns the inbox hash, derived from the address.
+func (i *Inbox) InboxHash() string {
+	return i.inboxHash
+}
+
+// ExpiresAt returns when the inbox expires.
+func (i *Inbox) ExpiresAt() time.Time {
+	return i.expiresAt
+}
+
+// IsExpired reports whether the inbox has expired.
+func (i *Inbox) IsExpired() bool {
+	return time.Now().After(i.expiresAt)
+}
+
+// Deleted reports whether the inbox was deleted, either with [Inbox.Delete]
+// or through the client.
+func (i *Inbox) Deleted() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.deleted
+}
+
+// Deliver adds email to the inbox as if it had just arrived, waking any
+// waiters. An empty ID is replaced by a generated one, a zero ReceivedAt by
+// the current time, and an empty To by the inbox address. The inbox keeps
+// its own copy; a copy of the stored email is returned. Delivering to a
+// deleted inbox is ignored and returns nil.
+func (i *Inbox) Deliver(email *vaultsandbox.Email) *vaultsandbox.Email {
+	stored := *email
+	if stored.ID == "" {
+		stored.ID = i.client.newEmailID()
+	}
+	if stored.ReceivedAt.IsZero() {
+		stored.ReceivedAt = time.Now()
+	}
+	if len(stored.To) == 0 {
+		stored.To = []string{i.emailAddress}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.deleted {
+		return nil
+	}
+	i.emails = append(i.emails, &stored)
+	i.wakeLocked()
+	return copyEmail(&stored)
+}
+
+// GetEmails returns all emails in the inbox in delivery order. The options
+// are accepted for signature compatibility but have no effect.
+func (i *Inbox) GetEmails(ctx context.Context, opts ...vaultsandbox.FetchOption) ([]*vaultsandbox.Email, error) {
+	if err := i.check(ctx); err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	emails := make([]*vaultsandbox.Email, len(i.emails))
+	for n, e := range i.emails {
+		emails[n] = copyEmail(e)
+	}
+	return emails, nil
+}
+
+// GetEmail returns the email with the given ID. It returns
+// [vaultsandbox.ErrEmailNotFound] if there is no such email. The options are
+// accepted for signature compatibility but have no effect.
+func (i *Inbox) GetEmail(ctx context.Context, emailID string, opts ...vaultsandbox.FetchOption) (*vaultsandbox.Email, error) {
+	if err := i.check(ctx); err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if n := i.indexLocked(emailID); n >= 0 {
+		return copyEmail(i.emails[n]), nil
+	}
+	return nil, fmt.Errorf("email %s: %w", emailID, vaultsandbox.ErrEmailNotFound)
+}
+
+// MarkEmailAsRead marks the email with the given ID as read.
+func (i *Inbox) MarkEmailAsRead(ctx context.Context, emailID string) error {
+	if err := i.check(ctx); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	n := i.indexLocked(emailID)
+	if n < 0 {
+		return fmt.Errorf("email %s: %w", emailID, vaultsandbox.ErrEmailNotFound)
+	}
+	i.emails[n].IsRead = true
+	return nil
+}
+
+// DeleteEmail deletes the email with the given ID.
+func (i *Inbox) DeleteEmail(ctx context.Context, emailID string) error {
+	if err := i.check(ctx); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	n := i.indexLocked(emailID)
+	if n < 0 {
+		return fmt.Errorf("email %s: %w", emailID, vaultsandbox.ErrEmailNotFound)
+	}
+	i.emails = append(i.emails[:n], i.emails[n+1:]...)
+	return nil
+}
+
+// Delete deletes the inbox. The deletion is recorded by the client (see
+// [Client.DeletedInboxes]) and waits in progress end with
+// [vaultsandbox.ErrInboxNotFound].
+func (i *Inbox) Delete(ctx context.Context) error {
+	return i.client.DeleteInbox(ctx, i.emailAddress)
+}
+
+// WaitForEmail returns the first email, in delivery order, that matches the
+// filter options, waiting for one to be delivered if necessary. Like the real
+// method it honors [vaultsandbox.WithWaitTimeout] (60 seconds by default),
+// returns [context.DeadlineExceeded] when the timeout elapses, and aborts
+// with the error of a failing [vaultsandbox.WithPredicateErr] predicate.
+func (i *Inbox) WaitForEmail(ctx context.Context, opts ...vaultsandbox.WaitOption) (*vaultsandbox.Email, error) {
+	emails, err := i.WaitForEmailCount(ctx, 1, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return emails[0], nil
+}
+
+// WaitForEmailCount waits until at least count emails match the filter
+// options and returns the first count of them in delivery order. It times
+// out as [Inbox.WaitForEmail] does.
+func (i *Inbox) WaitForEmailCount(ctx context.Context, count int, opts ...vaultsandbox.WaitOption) ([]*vaultsandbox.Email, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must be non-negative, got %d", count)
+	}
+	criteria := vaultsandbox.NewWaitCriteria(opts...)
+	ctx, cancel := context.WithTimeout(ctx, criteria.Timeout())
+	defer cancel()
+
+	for {
+		if err := i.check(ctx); err != nil {
+			return nil, err
+		}
+		i.mu.Lock()
+		var matched []*vaultsandbox.Email
+		var err error
+		for _, e := range i.emails {
+			if len(matched) == count {
+				break
+			}
+			var ok bool
+			if ok, err = criteria.Evaluate(e); err != nil {
+				break
+			}
+			if ok {
+				matched = append(matched, copyEmail(e))
+			}
+		}
+		changed := i.changed
+		i.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == count {
+			return matched, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// check reports why the inbox cannot be used, if it cannot.
+func (i *Inbox) check(ctx context.Context) error {
+	if err := i.client.checkCall(ctx); err != nil {
+		return err
+	}
+	if i.Deleted() {
+		return fmt.Errorf("inbox %s: %w", i.emailAddress, vaultsandbox.ErrInboxNotFound)
+	}
+	return nil
+}
+
+// indexLocked returns the index of the email with the given ID, or -1. It
+// must be called with i.mu held.
+func (i *Inbox) indexLocked(emailID string) int {
+	for n, e := range i.emails {
+		if e.ID == emailID {
+			return n
+		}
+	}
+	return -1
+}
+
+// markDeleted marks the inbox deleted and wakes its waiters.
+func (i *Inbox) markDeleted() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.deleted = true
+	i.wakeLocked()
+}
+
+// wake wakes the inbox's waiters.
+func (i *Inbox) wake() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.wakeLocked()
+}
+
+func (i *Inbox) wakeLocked() {
+	close(i.changed)
+	i.changed = make(chan struct{})
+}
+
+// copyEmail returns a shallow copy of e, so callers cannot modify the
+// stored email.
+func copyEmail(e *vaultsandbox.Email) *vaultsandbox.Email {
+	c := *e
+	return &c
+}
@@ -0,0 +1,110 @@
+package vaultsandboxtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+	"github.com/vaultsandbox/client-go/internal/crypto"
+	"github.com/vaultsandbox/client-go/internal/mockinbox"
+)
+
+// MockInbox is a real encrypted [vaultsandbox.Inbox] created offline,
+// together with the mock server signing key its emails are signed with. It
+// is meant for tests that stand in for the server with an httptest mock and
+// want to exercise the real decryption path.
+type MockInbox struct {
+	// Inbox is the detached inbox. Bind it to a client with
+	// [vaultsandbox.Inbox.Attach].
+	Inbox *vaultsandbox.Inbox
+
+	// Export is the inbox's export. Serve it to
+	// [vaultsandbox.Client.ImportInbox] or pass it to
+	// [vaultsandbox.NewDetachedInbox].
+	Export *vaultsandbox.ExportedInbox
+
+	mock *mockinbox.Inbox // Key material, including the mock server key.
+}
+
+// NewMockInbox creates an encrypted inbox offline. It generates a real
+// ML-KEM-768 keypair and a mock ML-DSA-65 server signing key; use
+// [MockInbox.EncryptedPayload] and [MockInbox.EmailJSON] to build emails the
+// inbox decrypts and verifies like real ones.
+//
+// The inbox hash is derived from the public key and the inbox expires in 24
+// hours.
+func NewMockInbox(emailAddress string) (*MockInbox, error) {
+	mock, err := mockinbox.New(emailAddress)
+	if err != nil {
+		return nil, err
+	}
+	data, err := mock.ExportJSON()
+	if err != nil {
+		return nil, err
+	}
+	exported := &vaultsandbox.ExportedInbox{}
+	if err := json.Unmarshal(data, exported); err != nil {
+		return nil, fmt.Errorf("decode mock export: %w", err)
+	}
+
+	inbox, err := vaultsandbox.NewDetachedInbox(exported)
+	if err != nil {
+		return nil, err
+	}
+	return &MockInbox{Inbox: inbox, Export: exported, mock: mock}, nil
+}
+
+// EncryptedPayload encrypts plaintext for the inbox and signs it with the
+// mock server key, producing a payload the inbox accepts.
+func (m *MockInbox) EncryptedPayload(plaintext []byte) (*vaultsandbox.EncryptedPayload, error) {
+	return m.mock.EncryptedPayload(plaintext)
+}
+
+// EmailJSON returns the JSON object the server sends for an email in the
+// inbox, with metadata and parsed encrypted by [MockInbox.EncryptedPayload].
+// It can be written directly (or inside a JSON array, for email lists) by an
+// httptest handler.
+//
+// metadata is marshaled as the email metadata (from, to, subject,
+// receivedAt) and parsed as the parsed content (text, html, headers,
+// attachments, links, authResults); a nil parsed produces a metadata-only
+// email, as in list responses without content.
+func (m *MockInbox) EmailJSON(emailID string, metadata, parsed any) (json.RawMessage, error) {
+	return m.mock.EmailJSON(emailID, metadata, parsed)
+}
+
+// GenerateServerSigningKey generates an ML-DSA-65 key pair of the kind the
+// server signs payloads with, in packed form, for test harnesses that stand
+// in for the server. Pin the public key in an inbox export (base64url
+// encoded, as [vaultsandbox.ExportedInbox].ServerSigPk) or accept it with
+// [vaultsandbox.WithAdditionalServerKeys], and sign payloads with the private
+// key using [EncryptPayload].
+func GenerateServerSigningKey() (publicKey, privateKey []byte, err error) {
+	return crypto.GenerateSigningKey()
+}
+
+// EncryptPayload encrypts plaintext for inbox and signs it with
+// serverPrivateKey the way the server encrypts email content: ML-KEM-768
+// encapsulation to the inbox's public key, HKDF-SHA-512 key derivation,
+// AES-256-GCM with the inbox hash as additional data, and an ML-DSA-65
+// signature over the transcript. The inbox verifies and decrypts the payload
+// if it accepts the matching public key.
+//
+// serverPrivateKey is a packed ML-DSA-65 private key as returned by
+// [GenerateServerSigningKey]. It returns an error if inbox is nil or not
+// encrypted, or if serverPrivateKey is malformed.
+func EncryptPayload(inbox *vaultsandbox.Inbox, plaintext, serverPrivateKey []byte) (*vaultsandbox.EncryptedPayload, error) {
+	if inbox == nil {
+		return nil, errors.New("inbox is nil")
+	}
+	exported := inbox.Export()
+	if !inbox.Encrypted() || exported.SecretKey == "" {
+		return nil, fmt.Errorf("inbox %s is not encrypted", inbox.EmailAddress())
+	}
+	keypair, err := crypto.KeypairFromSecretKeyB64(exported.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Encrypt(plaintext, keypair.PublicKey, serverPrivateKey, []byte(inbox.InboxHash()))
+}
@@ -0,0 +1,146 @@
+package vaultsandboxtest
+
+import (
+	"encoding/json"
+	"testing"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+	"github.com/vaultsandbox/client-go/internal/api"
+	"github.com/vaultsandbox/client-go/internal/crypto"
+)
+
+func TestNewMockInbox(t *testing.T) {
+	t.Parallel()
+	m, err := NewMockInbox("mock@example.com")
+	if err != nil {
+		t.Fatalf("NewMockInbox() error = %v", err)
+	}
+
+	inbox, exported := m.Inbox, m.Export
+	if !inbox.Detached() || !inbox.Encrypted() {
+		t.Errorf("Detached() = %v, Encrypted() = %v; want both true", inbox.Detached(), inbox.Encrypted())
+	}
+	if inbox.EmailAddress() != "mock@example.com" || inbox.InboxHash() != exported.InboxHash {
+		t.Errorf("inbox = %s/%s, export = %s/%s", inbox.EmailAddress(), inbox.InboxHash(), exported.EmailAddress, exported.InboxHash)
+	}
+	if err := exported.Validate(); err != nil {
+		t.Errorf("exported.Validate() error = %v", err)
+	}
+
+	if _, err := NewMockInbox("not-an-address"); err == nil {
+		t.Error("NewMockInbox() with invalid address: expected error")
+	}
+}
+
+func TestMockInbox_EmailJSON(t *testing.T) {
+	t.Parallel()
+	m, err := NewMockInbox("mock@example.com")
+	if err != nil {
+		t.Fatalf("NewMockInbox() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		parsed     any
+		wantParsed bool
+	}{
+		{name: "full", parsed: map[string]string{"text": "Mock body"}, wantParsed: true},
+		{name: "metadata only"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			body, err := m.EmailJSON("email-1", map[string]string{"subject": "Hello"}, tt.parsed)
+			if err != nil {
+				t.Fatalf("EmailJSON() error = %v", err)
+			}
+			var raw api.RawEmail
+			if err := json.Unmarshal(body, &raw); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if raw.ID != "email-1" || raw.InboxID != m.Inbox.InboxHash() {
+				t.Errorf("ID = %q, InboxID = %q; want email-1, %q", raw.ID, raw.InboxID, m.Inbox.InboxHash())
+			}
+			if (raw.EncryptedParsed != nil) != tt.wantParsed {
+				t.Errorf("EncryptedParsed set = %v, want %v", raw.EncryptedParsed != nil, tt.wantParsed)
+			}
+
+			metadata, err := m.Inbox.DecryptPayload(raw.EncryptedMetadata)
+			if err != nil {
+				t.Fatalf("DecryptPayload(metadata) error = %v", err)
+			}
+			if string(metadata) != `{"subject":"Hello"}` {
+				t.Errorf("metadata = %s", metadata)
+			}
+			if tt.wantParsed {
+				parsed, err := m.Inbox.DecryptPayload(raw.EncryptedParsed)
+				if err != nil {
+					t.Fatalf("DecryptPayload(parsed) error = %v", err)
+				}
+				if string(parsed) != `{"text":"Mock body"}` {
+					t.Errorf("parsed = %s", parsed)
+				}
+			}
+		})
+	}
+}
+
+func TestEncryptPayload(t *testing.T) {
+	t.Parallel()
+	serverPk, serverPriv, err := GenerateServerSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateServerSigningKey() error = %v", err)
+	}
+	m, err := NewMockInbox("harness@example.com")
+	if err != nil {
+		t.Fatalf("NewMockInbox() error = %v", err)
+	}
+	// Pin the harness's own server key, as a custom test server would.
+	exported := *m.Export
+	exported.ServerSigPk = crypto.ToBase64URL(serverPk)
+	inbox, err := vaultsandbox.NewDetachedInbox(&exported)
+	if err != nil {
+		t.Fatalf("NewDetachedInbox() error = %v", err)
+	}
+
+	payload, err := EncryptPayload(inbox, []byte(`{"subject":"From the harness"}`), serverPriv)
+	if err != nil {
+		t.Fatalf("EncryptPayload() error = %v", err)
+	}
+	if err := inbox.VerifyEmailSignature(payload); err != nil {
+		t.Errorf("VerifyEmailSignature() error = %v", err)
+	}
+	// The mock inbox pins a different server key and must reject it.
+	if err := m.Inbox.VerifyEmailSignature(payload); err == nil {
+		t.Error("VerifyEmailSignature() with another server key: expected error")
+	}
+
+	plain, err := vaultsandbox.NewDetachedInbox(&vaultsandbox.ExportedInbox{
+		Version:      vaultsandbox.ExportVersion,
+		EmailAddress: "plain@example.com",
+		ExpiresAt:    exported.ExpiresAt,
+		InboxHash:    "plain-hash",
+		ExportedAt:   exported.ExportedAt,
+	})
+	if err != nil {
+		t.Fatalf("NewDetachedInbox(plain) error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		inbox      *vaultsandbox.Inbox
+		serverPriv []byte
+	}{
+		{"nil inbox", nil, serverPriv},
+		{"plain inbox", plain, serverPriv},
+		{"malformed server key", inbox, []byte("short")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := EncryptPayload(tt.inbox, []byte("x"), tt.serverPriv); err == nil {
+				t.Error("EncryptPayload() error = nil, want error")
+			}
+		})
+	}
+}
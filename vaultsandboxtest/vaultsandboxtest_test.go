@@ -0,0 +1,284 @@
+package vaultsandboxtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	vaultsandbox "github.com/vaultsandbox/client-go"
+)
+
+func TestInbox_ImplementsInboxAPI(t *testing.T) {
+	t.Parallel()
+	inbox, _ := NewClient().CreateInbox(context.Background())
+	var api vaultsandbox.InboxAPI = inbox
+	if api.EmailAddress() != inbox.EmailAddress() {
+		t.Errorf("EmailAddress() = %q, want %q", api.EmailAddress(), inbox.EmailAddress())
+	}
+}
+
+// setupInbox is written against ClientAPI, as code under test would be, so
+// it accepts the real client as well as the fake.
+func setupInbox[I vaultsandbox.InboxAPI](ctx context.Context, c vaultsandbox.ClientAPI[I]) (I, error) {
+	if err := c.CheckKey(ctx); err != nil {
+		var zero I
+		return zero, err
+	}
+	return c.CreateInbox(ctx)
+}
+
+func TestClient_ImplementsClientAPI(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := NewClient(WithDomain("example.com"))
+	inbox, err := setupInbox(ctx, client)
+	if err != nil {
+		t.Fatalf("setupInbox() error = %v", err)
+	}
+	if !strings.HasSuffix(inbox.EmailAddress(), "@example.com") {
+		t.Errorf("EmailAddress() = %q, want domain example.com", inbox.EmailAddress())
+	}
+	if info := client.ServerInfo(); len(info.AllowedDomains) != 1 || info.AllowedDomains[0] != "example.com" {
+		t.Errorf("ServerInfo().AllowedDomains = %v, want [example.com]", info.AllowedDomains)
+	}
+
+	client.Close()
+	if _, err := setupInbox(ctx, client); !errors.Is(err, vaultsandbox.ErrClientClosed) {
+		t.Errorf("setupInbox() after Close error = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestClient_ImportInbox(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := NewClient()
+	expiresAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+	data := &vaultsandbox.ExportedInbox{
+		EmailAddress: "imported@example.com",
+		InboxHash:    "imported-hash",
+		ExpiresAt:    expiresAt,
+	}
+
+	inbox, err := client.ImportInbox(ctx, data)
+	if err != nil {
+		t.Fatalf("ImportInbox() error = %v", err)
+	}
+	if inbox.InboxHash() != "imported-hash" || !inbox.ExpiresAt().Equal(expiresAt) || !inbox.IsExpired() {
+		t.Errorf("inbox = %s, expires %v, want imported-hash, %v", inbox.InboxHash(), inbox.ExpiresAt(), expiresAt)
+	}
+	if got, ok := client.GetInbox("imported@example.com"); !ok || got != inbox {
+		t.Errorf("GetInbox() = %v, %v; want the imported inbox", got, ok)
+	}
+
+	if _, err := client.ImportInbox(ctx, data); !errors.Is(err, vaultsandbox.ErrInboxAlreadyExists) {
+		t.Errorf("ImportInbox() twice error = %v, want ErrInboxAlreadyExists", err)
+	}
+	if _, err := client.ImportInbox(ctx, nil); err == nil {
+		t.Error("ImportInbox(nil) error = nil, want error")
+	}
+}
+
+func TestWaitForEmail_ReturnsDeliveredEmail(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := NewClient()
+	inbox, err := client.CreateInbox(ctx)
+	if err != nil {
+		t.Fatalf("CreateInbox() error = %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		inbox.Deliver(&vaultsandbox.Email{Subject: "Newsletter"})
+		inbox.Deliver(&vaultsandbox.Email{Subject: "Reset your password", From: "noreply@example.com"})
+	}()
+
+	email, err := inbox.WaitForEmail(ctx,
+		vaultsandbox.WithSubject("Reset your password"),
+		vaultsandbox.WithWaitTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("WaitForEmail() error = %v", err)
+	}
+	if email.From != "noreply@example.com" {
+		t.Errorf("From = %q, want noreply@example.com", email.From)
+	}
+	if email.ID == "" || email.ReceivedAt.IsZero() {
+		t.Errorf("delivered email has ID %q and ReceivedAt %v, want them set", email.ID, email.ReceivedAt)
+	}
+	if len(email.To) != 1 || email.To[0] != inbox.EmailAddress() {
+		t.Errorf("To = %v, want [%s]", email.To, inbox.EmailAddress())
+	}
+}
+
+func TestWaitForEmail_AlreadyDelivered(t *testing.T) {
+	t.Parallel()
+	inbox, _ := NewClient().AddInbox("user@example.com")
+	inbox.Deliver(&vaultsandbox.Email{ID: "e1", Subject: "Hello"})
+
+	email, err := inbox.WaitForEmail(context.Background(), vaultsandbox.WithWaitTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("WaitForEmail() error = %v", err)
+	}
+	if email.ID != "e1" {
+		t.Errorf("ID = %q, want e1", email.ID)
+	}
+}
+
+func TestWaitForEmail_Timeout(t *testing.T) {
+	t.Parallel()
+	inbox, _ := NewClient().CreateInbox(context.Background())
+	inbox.Deliver(&vaultsandbox.Email{Subject: "Other"})
+
+	_, err := inbox.WaitForEmail(context.Background(),
+		vaultsandbox.WithSubject("Missing"),
+		vaultsandbox.WithWaitTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForEmail() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWaitForEmail_EndsWhenInboxDeletedOrClientClosed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		end     func(*Client, *Inbox)
+		wantErr error
+	}{
+		{"inbox deleted", func(_ *Client, inbox *Inbox) { inbox.Delete(context.Background()) }, vaultsandbox.ErrInboxNotFound},
+		{"client closed", func(c *Client, _ *Inbox) { c.Close() }, vaultsandbox.ErrClientClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := NewClient()
+			inbox, _ := client.CreateInbox(context.Background())
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				tt.end(client, inbox)
+			}()
+			if _, err := inbox.WaitForEmail(context.Background()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("WaitForEmail() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForEmail_PredicateErrAborts(t *testing.T) {
+	t.Parallel()
+	inbox, _ := NewClient().CreateInbox(context.Background())
+	inbox.Deliver(&vaultsandbox.Email{Subject: "Broken"})
+	errBroken := errors.New("broken")
+
+	_, err := inbox.WaitForEmail(context.Background(), vaultsandbox.WithPredicateErr(func(*vaultsandbox.Email) (bool, error) {
+		return false, errBroken
+	}))
+	if !errors.Is(err, errBroken) {
+		t.Errorf("WaitForEmail() error = %v, want the predicate error", err)
+	}
+}
+
+func TestWaitForEmailCount(t *testing.T) {
+	t.Parallel()
+	inbox, _ := NewClient().CreateInbox(context.Background())
+	for _, subject := range []string{"a", "b", "c"} {
+		inbox.Deliver(&vaultsandbox.Email{Subject: subject})
+	}
+
+	emails, err := inbox.WaitForEmailCount(context.Background(), 2, vaultsandbox.WithWaitTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("WaitForEmailCount() error = %v", err)
+	}
+	if len(emails) != 2 || emails[0].Subject != "a" || emails[1].Subject != "b" {
+		t.Errorf("WaitForEmailCount() = %v, want emails a and b", emails)
+	}
+}
+
+func TestClient_DeletedInboxes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := NewClient(WithDomain("example.org"))
+	inbox1, _ := client.CreateInbox(ctx)
+	inbox2, _ := client.CreateInbox(ctx)
+	inbox3, _ := client.CreateInbox(ctx)
+
+	if !strings.HasSuffix(inbox1.EmailAddress(), "@example.org") {
+		t.Errorf("EmailAddress() = %q, want domain example.org", inbox1.EmailAddress())
+	}
+	if err := inbox2.Delete(ctx); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := client.DeleteInbox(ctx, inbox2.EmailAddress()); !errors.Is(err, vaultsandbox.ErrInboxNotFound) {
+		t.Errorf("second DeleteInbox() error = %v, want ErrInboxNotFound", err)
+	}
+	if n, err := client.DeleteAllInboxes(ctx); err != nil || n != 2 {
+		t.Errorf("DeleteAllInboxes() = %d, %v, want 2, nil", n, err)
+	}
+
+	got := client.DeletedInboxes()
+	want := []string{inbox2.EmailAddress(), inbox1.EmailAddress(), inbox3.EmailAddress()}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("DeletedInboxes() = %v, want %v", got, want)
+	}
+	if !inbox1.Deleted() || len(client.Inboxes()) != 0 {
+		t.Error("inboxes should all be deleted")
+	}
+	if _, err := inbox1.GetEmails(ctx); !errors.Is(err, vaultsandbox.ErrInboxNotFound) {
+		t.Errorf("GetEmails() on deleted inbox error = %v, want ErrInboxNotFound", err)
+	}
+}
+
+func TestInbox_EmailOperations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	inbox, _ := NewClient().CreateInbox(ctx)
+	delivered := inbox.Deliver(&vaultsandbox.Email{Subject: "Hi"})
+
+	if err := inbox.MarkEmailAsRead(ctx, delivered.ID); err != nil {
+		t.Fatalf("MarkEmailAsRead() error = %v", err)
+	}
+	email, err := inbox.GetEmail(ctx, delivered.ID)
+	if err != nil {
+		t.Fatalf("GetEmail() error = %v", err)
+	}
+	if !email.IsRead {
+		t.Error("IsRead = false after MarkEmailAsRead")
+	}
+
+	// Returned emails are copies.
+	email.Subject = "changed"
+	if again, _ := inbox.GetEmail(ctx, delivered.ID); again.Subject != "Hi" {
+		t.Errorf("stored Subject = %q, want Hi", again.Subject)
+	}
+
+	if err := inbox.DeleteEmail(ctx, delivered.ID); err != nil {
+		t.Fatalf("DeleteEmail() error = %v", err)
+	}
+	if _, err := inbox.GetEmail(ctx, delivered.ID); !errors.Is(err, vaultsandbox.ErrEmailNotFound) {
+		t.Errorf("GetEmail() after delete error = %v, want ErrEmailNotFound", err)
+	}
+}
+
+func TestClient_InjectError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := NewClient()
+	injected := errors.New("boom")
+
+	client.InjectError(injected)
+	if _, err := client.CreateInbox(ctx); !errors.Is(err, injected) {
+		t.Errorf("CreateInbox() error = %v, want injected error", err)
+	}
+	if _, err := client.CreateInbox(ctx); err != nil {
+		t.Errorf("CreateInbox() after injected error = %v, want nil", err)
+	}
+
+	if _, err := client.AddInbox("dup@example.com"); err != nil {
+		t.Fatalf("AddInbox() error = %v", err)
+	}
+	if _, err := client.AddInbox("dup@example.com"); !errors.Is(err, vaultsandbox.ErrInboxAlreadyExists) {
+		t.Errorf("duplicate AddInbox() error = %v, want ErrInboxAlreadyExists", err)
+	}
+}
@@ -0,0 +1,10 @@
+package vaultsandbox
+
+import "github.com/vaultsandbox/client-go/internal/version"
+
+// Version is the SDK version.
+const Version = version.Version
+
+// DefaultUserAgent is the User-Agent sent with every API request unless
+// overridden with [WithUserAgent].
+const DefaultUserAgent = version.UserAgent
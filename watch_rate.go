@@ -0,0 +1,84 @@
+package vaultsandbox
+
+import (
+	"context"
+	"time"
+)
+
+// defaultRateWindow is the WatchRate window used when none is given.
+const defaultRateWindow = time.Minute
+
+// RateSample summarizes email arrivals during one WatchRate window.
+type RateSample struct {
+	// Window is the start of the window the sample covers.
+	Window time.Time
+	// Duration is the length of the window. It is shorter than the configured
+	// window only for the final sample flushed on cancellation.
+	Duration time.Duration
+	// Count is the number of emails that arrived across all inboxes.
+	Count int
+	// ByInbox maps each inbox email address to its arrivals. Inboxes with no
+	// arrivals in the window are omitted.
+	ByInbox map[string]int
+}
+
+// WatchRate reports email arrival rates across the given inboxes, emitting
+// one [RateSample] per window instead of individual emails. Samples are sent
+// for every window, including empty ones, so dashboards get a continuous
+// series. A non-positive window defaults to one minute.
+//
+// When ctx is cancelled the current partial window is flushed as a final
+// sample and the channel is closed, so consumers should range over the
+// channel until it closes.
+//
+// Example:
+//
+//	for s := range client.WatchRate(ctx, []*vaultsandbox.Inbox{inbox1, inbox2}, 10*time.Second) {
+//	    fmt.Printf("%s: %d emails\n", s.Window.Format(time.TimeOnly), s.Count)
+//	}
+func (c *Client) WatchRate(ctx context.Context, inboxes []*Inbox, window time.Duration) <-chan RateSample {
+	if window <= 0 {
+		window = defaultRateWindow
+	}
+	out := make(chan RateSample, 1)
+	events := c.WatchInboxes(ctx, inboxes...)
+
+	go func() {
+		defer close(out)
+
+		clk := c.clock()
+		current := RateSample{Window: clk.Now(), ByInbox: make(map[string]int)}
+		tick := clk.After(window)
+		for {
+			select {
+			case <-ctx.Done():
+				current.Duration = clk.Now().Sub(current.Window)
+				out <- current
+				return
+			case now := <-tick:
+				current.Duration = now.Sub(current.Window)
+				select {
+				case out <- current:
+				case <-ctx.Done():
+					// Fold the unsent window into the final flush.
+					current.Duration = clk.Now().Sub(current.Window)
+					out <- current
+					return
+				}
+				current = RateSample{Window: now, ByInbox: make(map[string]int)}
+				tick = clk.After(window)
+			case event, ok := <-events:
+				if !ok {
+					events = nil // No inboxes; keep emitting empty windows.
+					continue
+				}
+				if event != nil {
+					current.Count++
+					current.ByInbox[event.Inbox.emailAddress]++
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,87 @@
+package vaultsandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vaultsandbox/client-go/internal/clock"
+)
+
+func TestClient_WatchRate(t *testing.T) {
+	t.Parallel()
+	c := &Client{subs: newSubscriptionManager()}
+	a := &Inbox{emailAddress: "a@example.com", inboxHash: "hash-a", client: c}
+	b := &Inbox{emailAddress: "b@example.com", inboxHash: "hash-b", client: c}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	samples := c.WatchRate(ctx, []*Inbox{a, b}, time.Hour)
+
+	waitForSubscribers(t, c.subs, a.inboxHash, 1)
+	waitForSubscribers(t, c.subs, b.inboxHash, 1)
+	c.subs.notify(a.inboxHash, &Email{ID: "1"})
+	c.subs.notify(a.inboxHash, &Email{ID: "2"})
+	c.subs.notify(b.inboxHash, &Email{ID: "3"})
+
+	// Let the aggregator receive the events before flushing.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	var got []RateSample
+	for s := range samples {
+		got = append(got, s)
+	}
+	if len(got) != 1 {
+		t.Fatalf("received %d samples, want 1 flushed partial window", len(got))
+	}
+	s := got[0]
+	if s.Count != 3 || s.ByInbox["a@example.com"] != 2 || s.ByInbox["b@example.com"] != 1 {
+		t.Errorf("sample = %+v, want Count 3 (a:2, b:1)", s)
+	}
+	if s.Duration <= 0 || s.Duration >= time.Hour {
+		t.Errorf("Duration = %v, want partial window", s.Duration)
+	}
+}
+
+func TestClient_WatchRate_EmitsEmptyWindows(t *testing.T) {
+	t.Parallel()
+	c := &Client{subs: newSubscriptionManager()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	samples := c.WatchRate(ctx, nil, 10*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case s := <-samples:
+			if s.Count != 0 || len(s.ByInbox) != 0 {
+				t.Errorf("sample %d = %+v, want empty", i, s)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a sample")
+		}
+	}
+}
+
+func TestClient_WatchRate_FakeClock(t *testing.T) {
+	t.Parallel()
+	fake := clock.NewFake(testEpoch)
+	c := &Client{subs: newSubscriptionManager(), clk: fake}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	samples := c.WatchRate(ctx, nil, time.Minute)
+
+	for i := range 2 {
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute)
+		select {
+		case s := <-samples:
+			if want := testEpoch.Add(time.Duration(i) * time.Minute); !s.Window.Equal(want) || s.Duration != time.Minute {
+				t.Errorf("sample %d = %v for %v, want %v for 1m", i, s.Window, s.Duration, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no sample %d after advancing the fake clock", i)
+		}
+	}
+}